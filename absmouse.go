@@ -0,0 +1,293 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// An AbsoluteMouse is a pointer device that reports absolute screen coordinates, just like the pointer devices that
+// hypervisors and remote desktop software use to inject the cursor position of the host. The axes of the device are
+// calibrated to the size of the screen upon creation, so that the coordinates are given in pixel.
+type AbsoluteMouse interface {
+	// MoveTo will move the cursor to the given position on the screen, where (0, 0) is the upper left corner.
+	MoveTo(x int32, y int32) error
+
+	// ClickAt will move the cursor to the given position and issue a single left click there.
+	ClickAt(x int32, y int32) error
+
+	// LeftClick will issue a single left click.
+	LeftClick() error
+
+	// RightClick will issue a right click.
+	RightClick() error
+
+	// MiddleClick will issue a middle click.
+	MiddleClick() error
+
+	// LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
+	// LeftRelease is invoked.
+	LeftPress() error
+
+	// LeftRelease will simulate the release of the left mouse button.
+	LeftRelease() error
+
+	// RightPress will simulate the press of the right mouse button. Note that the button will not be released until
+	// RightRelease is invoked.
+	RightPress() error
+
+	// RightRelease will simulate the release of the right mouse button.
+	RightRelease() error
+
+	// MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
+	// MiddleRelease is invoked.
+	MiddlePress() error
+
+	// MiddleRelease will simulate the release of the middle mouse button.
+	MiddleRelease() error
+
+	Device
+}
+
+type vAbsoluteMouse struct {
+	name       []byte
+	deviceFile *uinputDevice
+	width      int32
+	height     int32
+}
+
+// CreateAbsoluteMouse will create a new absolute pointer device for a screen of the given size (in pixel). The device
+// is marked as a direct input device, meaning that consumers map its coordinates to the screen as is.
+func CreateAbsoluteMouse(path string, name []byte, width int32, height int32, opts ...Option) (AbsoluteMouse, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errorf(ErrInvalidArgument, "screen size %dx%d is out of range. Expected a positive width and height", width, height)
+	}
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateAbsoluteMouse", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createAbsoluteMouse(path, name, width, height, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vAbsoluteMouse{name: name, deviceFile: fd, width: width, height: height}, nil
+}
+
+// MoveTo will move the cursor to the given position on the screen.
+func (vAbs vAbsoluteMouse) MoveTo(x int32, y int32) error {
+	if err := vAbs.assertOnScreen(x, y); err != nil {
+		return err
+	}
+	return sendAbsEvent(vAbs.deviceFile, x, y)
+}
+
+// ClickAt will move the cursor to the given position and issue a left click there.
+func (vAbs vAbsoluteMouse) ClickAt(x int32, y int32) error {
+	err := vAbs.MoveTo(x, y)
+	if err != nil {
+		return fmt.Errorf("failed to move the cursor: %w", err)
+	}
+	return vAbs.LeftClick()
+}
+
+// LeftClick will issue a LeftClick.
+func (vAbs vAbsoluteMouse) LeftClick() error {
+	err := sendBtnEvent(vAbs.deviceFile, []int{evBtnLeft}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("Failed to issue the LeftClick event: %w", err)
+	}
+
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnLeft}, btnStateReleased)
+}
+
+// RightClick will issue a RightClick.
+func (vAbs vAbsoluteMouse) RightClick() error {
+	err := sendBtnEvent(vAbs.deviceFile, []int{evBtnRight}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("Failed to issue the RightClick event: %w", err)
+	}
+
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnRight}, btnStateReleased)
+}
+
+// MiddleClick will issue a MiddleClick.
+func (vAbs vAbsoluteMouse) MiddleClick() error {
+	err := sendBtnEvent(vAbs.deviceFile, []int{evBtnMiddle}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("Failed to issue the MiddleClick event: %w", err)
+	}
+
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnMiddle}, btnStateReleased)
+}
+
+// LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
+// LeftRelease is invoked.
+func (vAbs vAbsoluteMouse) LeftPress() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnLeft}, btnStatePressed)
+}
+
+// LeftRelease will simulate the release of the left mouse button.
+func (vAbs vAbsoluteMouse) LeftRelease() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnLeft}, btnStateReleased)
+}
+
+// RightPress will simulate the press of the right mouse button. Note that the button will not be released until
+// RightRelease is invoked.
+func (vAbs vAbsoluteMouse) RightPress() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnRight}, btnStatePressed)
+}
+
+// RightRelease will simulate the release of the right mouse button.
+func (vAbs vAbsoluteMouse) RightRelease() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnRight}, btnStateReleased)
+}
+
+// MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
+// MiddleRelease is invoked.
+func (vAbs vAbsoluteMouse) MiddlePress() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnMiddle}, btnStatePressed)
+}
+
+// MiddleRelease will simulate the release of the middle mouse button.
+func (vAbs vAbsoluteMouse) MiddleRelease() error {
+	return sendBtnEvent(vAbs.deviceFile, []int{evBtnMiddle}, btnStateReleased)
+}
+
+func (vAbs vAbsoluteMouse) Name() string {
+	return string(vAbs.name)
+}
+
+func (vAbs vAbsoluteMouse) Path() string {
+	return devicePath(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) Fd() uintptr {
+	return deviceFd(vAbs.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vAbs vAbsoluteMouse) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vAbs.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vAbs vAbsoluteMouse) EmitEvents(events []InputEvent) error {
+	return emitEvents(vAbs.deviceFile, events)
+}
+
+func (vAbs vAbsoluteMouse) Sync() error {
+	return writeSyncEvent(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) SysPath() (string, error) {
+	return sysPath(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) EventPath() (string, error) {
+	return eventPath(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) Stats() Stats {
+	return deviceStats(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) State() State {
+	return deviceState(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) ResetAll() error {
+	return resetDevice(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) Disconnect() error {
+	return disconnectDevice(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) Reconnect() error {
+	return reconnectDevice(vAbs.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vAbs vAbsoluteMouse) Close() error {
+	return closeDevice(vAbs.deviceFile)
+}
+
+func (vAbs vAbsoluteMouse) assertOnScreen(x int32, y int32) error {
+	if x < 0 || x >= vAbs.width || y < 0 || y >= vAbs.height {
+		return errorf(ErrInvalidArgument, "position (%d, %d) is out of range. Expected a position within the screen of %dx%d pixel", x, y, vAbs.width, vAbs.height)
+	}
+	return nil
+}
+
+func createAbsoluteMouse(path string, name []byte, width int32, height int32, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create absolute pointer device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	for _, event := range []int{evBtnLeft, evBtnRight, evBtnMiddle} {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register click event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+	for _, event := range []int{absX, absY} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropDirect))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register direct input property: %w", err)
+	}
+
+	// the axes cover the screen pixel by pixel
+	var absMax [absSize]int32
+	absMax[absX] = width - 1
+	absMax[absY] = height - 1
+
+	fd, err = createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0819,
+				Version: 1},
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	fd.state.setPositions(absX, absY)
+	return fd, nil
+}
@@ -0,0 +1,89 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicAbsoluteMouseMoves(t *testing.T) {
+	absDev, err := CreateAbsoluteMouse("/dev/uinput", []byte("Test Absolute Mouse"), 1920, 1080)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual absolute mouse. Last error was: %s\n", err)
+	}
+
+	err = absDev.MoveTo(100, 200)
+	if err != nil {
+		t.Fatalf("Failed to move cursor to position x:100, y:200. Last error was: %s\n", err)
+	}
+
+	err = absDev.ClickAt(1919, 1079)
+	if err != nil {
+		t.Fatalf("Failed to click at the lower right corner. Last error was: %s\n", err)
+	}
+
+	err = absDev.RightClick()
+	if err != nil {
+		t.Fatalf("Failed to perform right click. Last error was: %s\n", err)
+	}
+
+	err = absDev.MiddleClick()
+	if err != nil {
+		t.Fatalf("Failed to perform middle click. Last error was: %s\n", err)
+	}
+
+	err = absDev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestAbsoluteMouseCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateAbsoluteMouse("", []byte("AbsoluteMouse"), 1920, 1080)
+	assertPathDiscovered(t, device, err)
+}
+
+func TestAbsoluteMouseCreationFailsOnInvalidScreenSize(t *testing.T) {
+	_, err := CreateAbsoluteMouse("/dev/uinput", []byte("AbsoluteMouse"), 0, 1080)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+}
+
+func TestClickAtMovesBeforeClicking(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-absmouse-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	absDev := vAbsoluteMouse{name: []byte("Test Absolute Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), width: 800, height: 600}
+	err = absDev.ClickAt(400, 300)
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 400},
+		{Type: evAbs, Code: absY, Value: 300},
+		{Type: evKey, Code: evBtnLeft, Value: btnStatePressed},
+		{Type: evKey, Code: evBtnLeft, Value: btnStateReleased},
+	}
+	events := append(readEvents(t, file.Name(), evAbs), readEvents(t, file.Name(), evKey)...)
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestClickAtFailsOutsideOfScreen(t *testing.T) {
+	absDev := vAbsoluteMouse{name: []byte("Test Absolute Mouse"), width: 800, height: 600}
+	for _, pos := range [][2]int32{{800, 0}, {0, 600}, {-1, 10}} {
+		err := absDev.ClickAt(pos[0], pos[1])
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %v, got %v", pos, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the range and resolution of accelerometers. Accelerations of up to 8g in either direction may be reported.
+const (
+	accelResolution = 1024 // units per g
+	accelRange      = 8 * accelResolution
+)
+
+// An Accelerometer is a motion sensor that reports the acceleration along the x, y and z axes, like the sensors of
+// tablets and convertibles. Consumers like iio-sensor-proxy use accelerometers to determine the orientation of the
+// device (e.g. for rotating the screen). At rest, the sensor reports the gravity of 1g pointing towards the ground.
+type Accelerometer interface {
+	// SetAcceleration will report the given acceleration (in g) along the x, y and z axes. Accelerations beyond 8g
+	// are capped.
+	SetAcceleration(x, y, z float64) error
+
+	Device
+}
+
+type vAccelerometer struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreateAccelerometer will create a new accelerometer device.
+func CreateAccelerometer(path string, name []byte, opts ...Option) (Accelerometer, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateAccelerometer", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createAccelerometer(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vAccelerometer{name: name, deviceFile: fd}, nil
+}
+
+// SetAcceleration will report the given acceleration (in g).
+func (va vAccelerometer) SetAcceleration(x, y, z float64) error {
+	return sendMotionEvent(va.deviceFile, absX, accelResolution, accelRange, x, y, z)
+}
+
+func (va vAccelerometer) Name() string {
+	return string(va.name)
+}
+
+func (va vAccelerometer) Path() string {
+	return devicePath(va.deviceFile)
+}
+
+func (va vAccelerometer) Fd() uintptr {
+	return deviceFd(va.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (va vAccelerometer) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(va.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (va vAccelerometer) EmitEvents(events []InputEvent) error {
+	return emitEvents(va.deviceFile, events)
+}
+
+func (va vAccelerometer) Sync() error {
+	return writeSyncEvent(va.deviceFile)
+}
+
+func (va vAccelerometer) SysPath() (string, error) {
+	return sysPath(va.deviceFile)
+}
+
+func (va vAccelerometer) EventPath() (string, error) {
+	return eventPath(va.deviceFile)
+}
+
+func (va vAccelerometer) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, va.deviceFile)
+}
+
+func (va vAccelerometer) Stats() Stats {
+	return deviceStats(va.deviceFile)
+}
+
+func (va vAccelerometer) State() State {
+	return deviceState(va.deviceFile)
+}
+
+func (va vAccelerometer) ResetAll() error {
+	return resetDevice(va.deviceFile)
+}
+
+func (va vAccelerometer) Disconnect() error {
+	return disconnectDevice(va.deviceFile)
+}
+
+func (va vAccelerometer) Reconnect() error {
+	return reconnectDevice(va.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (va vAccelerometer) Close() error {
+	return closeDevice(va.deviceFile)
+}
+
+func createAccelerometer(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create accelerometer device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	for _, event := range []int{absX, absY, absZ} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropAccelerometer))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register accelerometer input property: %w", err)
+	}
+
+	var absMin, absMax [absSize]int32
+	for _, axis := range []uint16{absX, absY, absZ} {
+		absMin[axis] = -accelRange
+		absMax[axis] = accelRange
+		cfg = cfg.withDefaultTuning(axis, AxisTuning{Resolution: accelResolution})
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: BusVirtual,
+				Vendor:  0x4711,
+				Product: 0x081e,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+}
@@ -0,0 +1,54 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicAccelerometer(t *testing.T) {
+	sensor, err := CreateAccelerometer("/dev/uinput", []byte("Test Accelerometer"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual accelerometer. Last error was: %s\n", err)
+	}
+
+	err = sensor.SetAcceleration(0, -1, 0)
+	if err != nil {
+		t.Fatalf("Failed to report acceleration. Last error was: %s\n", err)
+	}
+
+	err = sensor.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestAccelerometerCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateAccelerometer("", []byte("Accelerometer"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestAccelerationIsScaledAndCapped(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-accelerometer-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	sensor := vAccelerometer{name: []byte("Test Accelerometer"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	err = sensor.SetAcceleration(0.5, -1, 20)
+	if err != nil {
+		t.Fatalf("Failed to report acceleration: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 512},
+		{Type: evAbs, Code: absY, Value: -1024},
+		{Type: evAbs, Code: absZ, Value: accelRange},
+	}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
@@ -0,0 +1,58 @@
+package uinput
+
+import (
+	"fmt"
+	"os"
+)
+
+// the suggestions of access errors, which tell how the uinput device file may be made accessible
+const (
+	// SuggestNone means that no fix is known, e.g. because the path does not point to the uinput device file.
+	SuggestNone = iota
+	// SuggestLoadModule means that the uinput module needs to be loaded (modprobe uinput).
+	SuggestLoadModule
+	// SuggestUdevRule means that a udev rule is needed that grants access to the device file (see UdevRule).
+	SuggestUdevRule
+	// SuggestJoinGroup means that the device file is accessible by its group, which the current user needs to join
+	// (and log in again afterwards).
+	SuggestJoinGroup
+)
+
+// An AccessError describes why the uinput device file may not be used by the current process, as returned by
+// CheckAccess. Suggestion tells how to fix it (one of the Suggest* constants), while Hint describes the same
+// in a human-readable way. Missing access rights may be checked using errors.Is(err, ErrPermissionDenied).
+type AccessError struct {
+	Path string
+	// Exists reports whether the device file exists.
+	Exists bool
+	// ModuleLoaded reports whether the uinput module is loaded (or built into the kernel).
+	ModuleLoaded bool
+	// Mode, Owner and Group describe the device file, if it exists. Owner and Group are names if they can be looked
+	// up, and ids otherwise.
+	Mode  os.FileMode
+	Owner string
+	Group string
+	// InGroup reports whether the current user is a member of the group of the device file.
+	InGroup bool
+
+	Suggestion int
+	Hint       string
+	Err        error
+}
+
+func (e *AccessError) Error() string {
+	if e.Hint == "" {
+		return fmt.Sprintf("cannot access %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("cannot access %s: %v (%s)", e.Path, e.Err, e.Hint)
+}
+
+func (e *AccessError) Unwrap() error {
+	return e.Err
+}
+
+// UdevRule returns a udev rule that grants the given group read and write access to the uinput device file. The rule
+// is meant to be placed in /etc/udev/rules.d, followed by udevadm trigger.
+func UdevRule(group string) string {
+	return fmt.Sprintf(`KERNEL=="uinput", GROUP="%s", MODE:="0660", OPTIONS+="static_node=uinput"`, group)
+}
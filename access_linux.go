@@ -0,0 +1,124 @@
+package uinput
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// miscDevicePath is the sysfs entry of the uinput misc device, which exists as soon as the module has been loaded.
+const miscDevicePath = "/sys/class/misc/uinput"
+
+// defaultPaths are the locations of the uinput device file, in the order they are probed by DefaultPath. Most
+// distributions use /dev/uinput, while some older ones use /dev/input/uinput.
+var defaultPaths = []string{"/dev/uinput", "/dev/input/uinput"}
+
+// DefaultPath returns the path of the uinput device file, which is looked up at the locations used by common
+// distributions. Devices are created using this path if an empty path is passed to a Create* function. If no device
+// file exists, a *AccessError wrapping ErrInvalidPath is returned, which suggests loading the uinput module if it has
+// not been loaded yet.
+func DefaultPath() (string, error) {
+	for _, path := range defaultPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	accessErr := &AccessError{
+		Path:         defaultPaths[0],
+		ModuleLoaded: moduleLoaded(),
+		Err:          errorf(ErrInvalidPath, "no uinput device file found at %s", strings.Join(defaultPaths, " or ")),
+	}
+	if !accessErr.ModuleLoaded {
+		accessErr.Suggestion = SuggestLoadModule
+		accessErr.Hint = "the uinput module is not loaded, run: modprobe uinput"
+	}
+	return "", accessErr
+}
+
+// CheckAccess checks whether devices may be created using the uinput device file at the given path, without creating
+// one. If not, a *AccessError is returned, which tells what is missing and how to fix it. This allows applications
+// to guide their users instead of failing with a bare EACCES.
+func CheckAccess(path string) error {
+	if path == "" {
+		return errorf(ErrInvalidPath, "device path must not be empty")
+	}
+	accessErr := &AccessError{Path: path, ModuleLoaded: moduleLoaded()}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		accessErr.Err = err
+		if os.IsNotExist(err) && !accessErr.ModuleLoaded {
+			accessErr.Suggestion = SuggestLoadModule
+			accessErr.Hint = "the uinput module is not loaded, run: modprobe uinput"
+		}
+		return accessErr
+	}
+	accessErr.Exists = true
+	accessErr.Mode = info.Mode()
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		accessErr.Owner = userName(stat.Uid)
+		accessErr.Group = groupName(stat.Gid)
+		accessErr.InGroup = inGroup(stat.Gid)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err == nil {
+		file.Close()
+		return nil
+	}
+	if !os.IsPermission(err) {
+		accessErr.Err = err
+		return accessErr
+	}
+
+	accessErr.Err = errorf(ErrPermissionDenied, "%w", err)
+	if accessErr.Mode&0060 == 0060 && !accessErr.InGroup && accessErr.Group != "" && accessErr.Group != "root" {
+		accessErr.Suggestion = SuggestJoinGroup
+		accessErr.Hint = "the device file is accessible by group " + accessErr.Group + ", which the current user needs to join"
+	} else {
+		accessErr.Suggestion = SuggestUdevRule
+		accessErr.Hint = "a udev rule is needed that grants access to the device file, e.g. " + UdevRule("input")
+	}
+	return accessErr
+}
+
+func moduleLoaded() bool {
+	_, err := os.Stat(miscDevicePath)
+	return err == nil
+}
+
+func userName(uid uint32) string {
+	id := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(id); err == nil {
+		return u.Username
+	}
+	return id
+}
+
+func groupName(gid uint32) string {
+	id := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(id); err == nil {
+		return g.Name
+	}
+	return id
+}
+
+// inGroup reports whether the current process is a member of the given group. The groups of the process are checked
+// rather than the groups of the user in the group database, as group changes only apply after logging in again.
+func inGroup(gid uint32) bool {
+	if uint32(os.Getegid()) == gid {
+		return true
+	}
+	groups, err := os.Getgroups()
+	if err != nil {
+		return false
+	}
+	for _, g := range groups {
+		if uint32(g) == gid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestDefaultPathProbesKnownLocations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uinput-path-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(paths []string) { defaultPaths = paths }(defaultPaths)
+
+	defaultPaths = []string{dir + "/uinput", dir + "/input-uinput"}
+	_, err = DefaultPath()
+	var accessErr *AccessError
+	if !errors.Is(err, ErrInvalidPath) || !errors.As(err, &accessErr) || accessErr.Path != defaultPaths[0] {
+		t.Fatalf("Expected an access error wrapping ErrInvalidPath, got %v", err)
+	}
+
+	err = ioutil.WriteFile(defaultPaths[1], nil, 0600)
+	if err != nil {
+		t.Fatalf("Failed to create device file: %v", err)
+	}
+	path, err := DefaultPath()
+	if err != nil || path != defaultPaths[1] {
+		t.Fatalf("Expected %s to be found, got %q (%v)", defaultPaths[1], path, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package uinput
+
+// CheckAccess checks whether devices may be created using the uinput device file at the given path. uinput is only
+// available on Linux, so ErrUnsupportedPlatform is returned on other platforms.
+func CheckAccess(path string) error {
+	if path == "" {
+		return errorf(ErrInvalidPath, "device path must not be empty")
+	}
+	return validatePlatform()
+}
+
+// DefaultPath returns the path of the uinput device file. uinput is only available on Linux, so
+// ErrUnsupportedPlatform is returned on other platforms.
+func DefaultPath() (string, error) {
+	return "", validatePlatform()
+}
@@ -0,0 +1,71 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckAccessMissingFile(t *testing.T) {
+	err := CheckAccess("/some/bogus/path")
+	var accessErr *AccessError
+	if !errors.As(err, &accessErr) {
+		t.Fatalf("Expected an AccessError, got %v", err)
+	}
+	if accessErr.Exists || !os.IsNotExist(accessErr.Err) {
+		t.Fatalf("Expected the file to be reported missing, got %+v", accessErr)
+	}
+	if !accessErr.ModuleLoaded && accessErr.Suggestion != SuggestLoadModule {
+		t.Fatalf("Expected loading the module to be suggested, got %+v", accessErr)
+	}
+}
+
+func TestCheckAccessEmptyPath(t *testing.T) {
+	if err := CheckAccess(""); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("Expected ErrInvalidPath, got %v", err)
+	}
+}
+
+func TestCheckAccessAccessibleFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uinput-access-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "uinput")
+	err = ioutil.WriteFile(path, nil, 0600)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create file: %v", err)
+	}
+
+	if err := CheckAccess(path); err != nil {
+		t.Fatalf("Expected the file to be accessible, got %v", err)
+	}
+	if os.Geteuid() == 0 {
+		// root may open any file
+		return
+	}
+
+	err = os.Chmod(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to change mode: %v", err)
+	}
+	err = CheckAccess(path)
+	var accessErr *AccessError
+	if !errors.As(err, &accessErr) || !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("Expected a permission error, got %v", err)
+	}
+	if accessErr.Suggestion != SuggestUdevRule || !strings.Contains(accessErr.Hint, "udev") {
+		t.Fatalf("Expected a udev rule to be suggested, got %+v", accessErr)
+	}
+}
+
+func TestUdevRule(t *testing.T) {
+	expected := `KERNEL=="uinput", GROUP="input", MODE:="0660", OPTIONS+="static_node=uinput"`
+	if rule := UdevRule("input"); rule != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, rule)
+	}
+}
@@ -0,0 +1,17 @@
+package uinput
+
+// the relative axis codes as defined in input-event-codes.h. They may be registered in addition to the default axes of a
+// device using WithRelativeAxes.
+const (
+	RelX      = relX
+	RelY      = relY
+	RelZ      = 0x02
+	RelRX     = 0x03
+	RelRY     = 0x04
+	RelRZ     = 0x05
+	RelHWheel = relHWheel
+	RelDial   = relDial
+	RelWheel  = relWheel
+	RelMisc   = 0x09
+	relMax    = 0x0f
+)
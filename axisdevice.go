@@ -0,0 +1,85 @@
+package uinput
+
+import (
+	"fmt"
+	"math"
+)
+
+// createAxisDevice creates a device that provides the given buttons and absolute axes. Presets like space mice or
+// flight sticks are based on such devices. The ids and axis ranges are taken from dev as is. If no axes are given,
+// the device provides buttons only, and vice versa.
+func createAxisDevice(path string, buttons []int, axes []int, ff *ForceFeedback, dev uinputUserDev, cfg deviceConfig) (fd *uinputDevice, err error) {
+	err = validateCodes(buttons, axes)
+	if err != nil {
+		return nil, err
+	}
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create absolute axis input device: %w", err)
+	}
+
+	if len(buttons) > 0 {
+		err = registerDevice(deviceFile, uintptr(evKey))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register key device: %w", err)
+		}
+	}
+	for _, button := range buttons {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(button))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", button, err)
+		}
+	}
+
+	if len(axes) > 0 {
+		err = registerDevice(deviceFile, uintptr(evAbs))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+		}
+	}
+	for _, axis := range axes {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(axis))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", axis, err)
+		}
+	}
+
+	if ff != nil {
+		err = registerForceFeedback(deviceFile, ff)
+		if err != nil {
+			deviceFile.Close()
+			return nil, err
+		}
+		dev.EffectsMax = maxEffects(ff)
+	}
+
+	return createUsbDevice(deviceFile, dev, cfg)
+}
+
+// validateCodes checks the codes of the buttons and axes before the device is created, so that invalid codes are
+// reported by number rather than failing with EINVAL upon registration.
+func validateCodes(buttons []int, axes []int) error {
+	for _, button := range buttons {
+		if button < 0 || button > keyCodeMax {
+			return errorf(ErrInvalidArgument, "button %d is out of range. Expected a code up to %d", button, keyCodeMax)
+		}
+	}
+	for _, axis := range axes {
+		if axis < 0 || axis >= absSize {
+			return errorf(ErrInvalidArgument, "axis %d is out of range. Expected a code below %d", axis, absSize)
+		}
+	}
+	return nil
+}
+
+// scaleAxis maps the normalized value (-1 to 1) to the range of ±max.
+func scaleAxis(value float64, max int32) (int32, error) {
+	if value < -1 || value > 1 || value != value {
+		return 0, errorf(ErrInvalidArgument, "%v is out of range. Expected a value between -1 and 1", value)
+	}
+	return int32(math.Round(value * float64(max))), nil
+}
@@ -0,0 +1,41 @@
+package uinput
+
+import (
+	"io"
+	"unsafe"
+)
+
+// A Backend opens the files that devices are created on. By default, the uinput device file is opened and all
+// requests are issued as system calls. Alternative backends (see WithBackend) allow to redirect devices to other
+// transports, like a bridge to a remote machine, or to mocks in tests.
+type Backend interface {
+	// Open opens the device file at the given path. The path is the one passed to the Create* functions.
+	Open(path string) (DeviceFile, error)
+}
+
+// A DeviceFile is a device file opened by a Backend. Events are written (and read, see WithEventHandler) in the
+// layout of struct input_event, and requests are passed on as ioctls of the uinput module (see linux/uinput.h).
+type DeviceFile interface {
+	io.ReadWriteCloser
+
+	// Name returns the path the file has been opened with.
+	Name() string
+
+	// Fd returns the file descriptor of the file, or ^uintptr(0) if it is not backed by a file descriptor.
+	Fd() uintptr
+
+	// Ioctl issues a request that takes an integer argument (or none at all).
+	Ioctl(cmd uintptr, arg uintptr) error
+
+	// IoctlPtr issues a request that takes a pointer to a struct or buffer, which may be read and written by the
+	// request.
+	IoctlPtr(cmd uintptr, arg unsafe.Pointer) error
+}
+
+// WithBackend makes the device use the given backend instead of the uinput device file. The path passed to the
+// Create* function is handed to the backend as is and is not checked to exist.
+func WithBackend(backend Backend) Option {
+	return func(cfg *deviceConfig) {
+		cfg.backend = backend
+	}
+}
@@ -0,0 +1,96 @@
+package uinput
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// validatePlatform reports whether the default backend is available, which it is on Linux.
+func validatePlatform() error {
+	return nil
+}
+
+// osBackend is the default backend, which opens the uinput device file.
+type osBackend struct{}
+
+func (osBackend) Open(path string) (DeviceFile, error) {
+	file, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NONBLOCK, 0660)
+	if err != nil {
+		return nil, err
+	}
+	return osFile{file}, nil
+}
+
+// openEvdev opens the evdev device at the given path (e.g. /dev/input/event3). The file is opened for writing as well,
+// so that events like LED changes may be sent to the device.
+func openEvdev(path string) (DeviceFile, error) {
+	file, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, errorf(ErrPermissionDenied, "could not open evdev device: %w", err)
+		}
+		return nil, err
+	}
+	return osFile{file}, nil
+}
+
+// osFile issues the requests of a device as system calls on the underlying file.
+type osFile struct {
+	*os.File
+}
+
+// Fd returns the file descriptor of the file. The raw connection is used instead of os.File.Fd, as the latter would
+// put the file into blocking mode (see Ioctl).
+func (f osFile) Fd() uintptr {
+	fd := ^uintptr(0)
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return fd
+	}
+	_ = conn.Control(func(rawFd uintptr) {
+		fd = rawFd
+	})
+	return fd
+}
+
+// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
+// Note that the raw connection is used instead of Fd(), as the latter would put the file into blocking mode. This in
+// turn would prevent Close from interrupting pending reads (see forcefeedback.go).
+func (f osFile) Ioctl(cmd uintptr, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errorCode syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errorCode = syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, arg)
+	})
+	if err != nil {
+		// the raw connection only fails if the file has been closed
+		return errorf(ErrDeviceClosed, "%w", err)
+	}
+	if errorCode != 0 {
+		return errorCode
+	}
+	return nil
+}
+
+// IoctlPtr is the equivalent of Ioctl for requests that pass a pointer to a struct.
+func (f osFile) IoctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errorCode syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errorCode = syscall.Syscall(syscall.SYS_IOCTL, fd, cmd, uintptr(arg))
+	})
+	if err != nil {
+		return errorf(ErrDeviceClosed, "%w", err)
+	}
+	if errorCode != 0 {
+		return errorCode
+	}
+	return nil
+}
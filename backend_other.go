@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package uinput
+
+import "runtime"
+
+// validatePlatform reports whether the default backend is available. uinput is a Linux kernel module, so devices may
+// only be created on other platforms using a custom backend (see WithBackend).
+func validatePlatform() error {
+	return errorf(ErrUnsupportedPlatform, "uinput is not available on %s", runtime.GOOS)
+}
+
+// osBackend is the default backend, which is not available on this platform.
+type osBackend struct{}
+
+func (osBackend) Open(path string) (DeviceFile, error) {
+	return nil, validatePlatform()
+}
+
+// openEvdev opens the evdev device at the given path, which is not possible on this platform.
+func openEvdev(path string) (DeviceFile, error) {
+	return nil, validatePlatform()
+}
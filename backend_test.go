@@ -0,0 +1,215 @@
+package uinput
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// mockBackend records all requests that devices issue on the files it opens.
+type mockBackend struct {
+	file *mockFile
+}
+
+func (b *mockBackend) Open(path string) (DeviceFile, error) {
+	b.file = &mockFile{name: path, done: make(chan struct{})}
+	return b.file, nil
+}
+
+type mockFile struct {
+	name string
+	done chan struct{}
+
+	mu     sync.Mutex
+	ioctls []uintptr
+	writes bytes.Buffer
+	closed bool
+}
+
+func (f *mockFile) Read([]byte) (int, error) {
+	<-f.done
+	return 0, os.ErrClosed
+}
+
+func (f *mockFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	return f.writes.Write(b)
+}
+
+func (f *mockFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	close(f.done)
+	return nil
+}
+
+func (f *mockFile) Name() string {
+	return f.name
+}
+
+func (f *mockFile) Fd() uintptr {
+	return ^uintptr(0)
+}
+
+func (f *mockFile) Ioctl(cmd uintptr, arg uintptr) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ioctls = append(f.ioctls, cmd)
+	return nil
+}
+
+func (f *mockFile) IoctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ioctls = append(f.ioctls, cmd)
+	return syscall.ENOTTY
+}
+
+func (f *mockFile) issued(cmd uintptr) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.ioctls {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// tempFile is the file of devices that are backed by a temporary file (or a pipe), which allows to read back the
+// written events on all platforms. Requests fail with ENOTTY, just like ioctls on regular files do on Linux, unless
+// the file has been closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f tempFile) Ioctl(cmd uintptr, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	err = conn.Control(func(uintptr) {})
+	if err != nil {
+		return errorf(ErrDeviceClosed, "%w", err)
+	}
+	return syscall.ENOTTY
+}
+
+func (f tempFile) IoctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	return f.Ioctl(cmd, uintptr(arg))
+}
+
+func TestCustomBackendReceivesAllRequests(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	if kbd.Path() != "mock" {
+		t.Fatalf("Expected the path to be passed to the backend, got %q", kbd.Path())
+	}
+	if !backend.file.issued(uiDevCreate) {
+		t.Fatalf("Expected the device to be created via the backend")
+	}
+
+	// the mock does not support UI_GET_VERSION, which is why the legacy uinput_user_dev struct is written first
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = kbd.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+	backend.file.mu.Lock()
+	written := backend.file.writes.Len()
+	backend.file.mu.Unlock()
+	if written != 4*inputEventSize {
+		t.Fatalf("Expected 4 events to be written, got %d bytes", written)
+	}
+
+	err = kbd.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the keyboard: %v", err)
+	}
+	if !backend.file.issued(uiDevDestroy) {
+		t.Fatalf("Expected the device to be destroyed via the backend")
+	}
+	err = kbd.KeyPress(KeyA)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected ErrDeviceClosed after closing, got %v", err)
+	}
+}
+
+func TestCustomBackendSkipsPathValidation(t *testing.T) {
+	err := validateDevicePath("/some/bogus/path", WithBackend(&mockBackend{}))
+	if err != nil {
+		t.Fatalf("Expected the path to be left to the backend, got %v", err)
+	}
+	err = validateDevicePath("", WithBackend(&mockBackend{}))
+	if !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("Expected ErrInvalidPath for an empty path, got %v", err)
+	}
+}
+
+// flakyBackend opens files that fail the given number of writes with a transient error.
+type flakyBackend struct {
+	mockBackend
+	failures int
+}
+
+func (b *flakyBackend) Open(path string) (DeviceFile, error) {
+	file, _ := b.mockBackend.Open(path)
+	return &flakyFile{mockFile: file.(*mockFile), failures: &b.failures}, nil
+}
+
+type flakyFile struct {
+	*mockFile
+	failures *int
+}
+
+func (f *flakyFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	fail := *f.failures > 0 && f.writes.Len() > 0 // the device setup is written first
+	if fail {
+		*f.failures--
+	}
+	f.mu.Unlock()
+	if fail {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EAGAIN}
+	}
+	return f.mockFile.Write(b)
+}
+
+func TestTransientWriteErrorsAreRetried(t *testing.T) {
+	backend := &flakyBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithWriteRetry(2, 0))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	backend.file.mu.Lock()
+	backend.failures = 2
+	backend.file.mu.Unlock()
+	err = kbd.KeyDown(KeyA)
+	if err != nil {
+		t.Fatalf("Expected the write to be retried, got %v", err)
+	}
+
+	backend.file.mu.Lock()
+	backend.failures = 3
+	backend.file.mu.Unlock()
+	err = kbd.KeyUp(KeyA)
+	if !errors.Is(err, syscall.EAGAIN) {
+		t.Fatalf("Expected EAGAIN once the retries are used up, got %v", err)
+	}
+}
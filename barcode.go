@@ -0,0 +1,85 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// the delay between keys of a barcode scanner, unless configured otherwise using SetKeyDelay. HID scanners type far
+// faster than humans, which is how point of sale software tells scans from typing.
+const defaultScanKeyDelay = 2 * time.Millisecond
+
+// A BarcodeScanner emulates a HID barcode scanner on a keyboard: scanned codes are typed as a rapid burst of keys,
+// surrounded by the prefix and suffix keys of the scanner (just Enter as the suffix, by default). This allows to test
+// point of sale software without a physical scanner.
+type BarcodeScanner struct {
+	keyboard Keyboard
+	delay    time.Duration
+	prefix   []int
+	suffix   []int
+}
+
+// NewBarcodeScanner returns a scanner that types on the given keyboard, using the layout of the keyboard.
+func NewBarcodeScanner(keyboard Keyboard) *BarcodeScanner {
+	return &BarcodeScanner{keyboard: keyboard, delay: defaultScanKeyDelay, suffix: []int{KeyEnter}}
+}
+
+// SetKeyDelay sets the delay between the characters (and keys) of a scan. A delay of zero types as fast as possible.
+func (s *BarcodeScanner) SetKeyDelay(delay time.Duration) *BarcodeScanner {
+	s.delay = delay
+	return s
+}
+
+// SetPrefix sets the keys that are pressed before every scan, like the F-keys some scanners are programmed to send.
+func (s *BarcodeScanner) SetPrefix(keys ...int) *BarcodeScanner {
+	s.prefix = keys
+	return s
+}
+
+// SetSuffix sets the keys that are pressed after every scan. Calling SetSuffix without any keys removes the default
+// suffix (Enter).
+func (s *BarcodeScanner) SetSuffix(keys ...int) *BarcodeScanner {
+	s.suffix = keys
+	return s
+}
+
+// Scan types the given code, along with the prefix and suffix of the scanner.
+func (s *BarcodeScanner) Scan(code string) error {
+	return s.ScanContext(context.Background(), code)
+}
+
+// ScanContext types the given code just like Scan, but stops once the context is done. Keys that have been typed by
+// then are not undone.
+func (s *BarcodeScanner) ScanContext(ctx context.Context, code string) error {
+	if code == "" {
+		return errorf(ErrInvalidArgument, "failed to scan. The code must not be empty")
+	}
+	var steps []func() error
+	for _, key := range s.prefix {
+		key := key
+		steps = append(steps, func() error { return s.keyboard.KeyPress(key) })
+	}
+	for _, r := range code {
+		c := string(r)
+		steps = append(steps, func() error { return s.keyboard.TypeContext(ctx, c) })
+	}
+	for _, key := range s.suffix {
+		key := key
+		steps = append(steps, func() error { return s.keyboard.KeyPress(key) })
+	}
+
+	next := time.Now()
+	for i, step := range steps {
+		if i > 0 {
+			next = next.Add(s.delay)
+			if err := sleepUntil(ctx, next); err != nil {
+				return fmt.Errorf("failed to scan: %w", err)
+			}
+		}
+		if err := step(); err != nil {
+			return fmt.Errorf("failed to scan: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestBarcodeScannerTypesPrefixCodeAndSuffix(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Scanner"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = NewBarcodeScanner(kbd).SetKeyDelay(0).SetPrefix(KeyF12).Scan("4A")
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	buf := backend.file.writes.Bytes()
+	var pressed []uint16
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		if iev.Type == evKey && iev.Value == btnStatePressed {
+			pressed = append(pressed, iev.Code)
+		}
+	}
+	backend.file.mu.Unlock()
+	expected := []uint16{KeyF12, Key4, KeyLeftshift, KeyA, KeyEnter}
+	if len(pressed) != len(expected) {
+		t.Fatalf("Expected the keys %v to be pressed, got %v", expected, pressed)
+	}
+	for i := range expected {
+		if pressed[i] != expected[i] {
+			t.Fatalf("Expected the keys %v to be pressed, got %v", expected, pressed)
+		}
+	}
+}
+
+func TestBarcodeScannerStopsWithContext(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Scanner"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = NewBarcodeScanner(kbd).SetKeyDelay(time.Second).ScanContext(ctx, "12345")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the scan to stop with the context, got %v", err)
+	}
+	err = NewBarcodeScanner(kbd).Scan("")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument for an empty code, got %v", err)
+	}
+}
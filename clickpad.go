@@ -0,0 +1,141 @@
+package uinput
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// the buttons of clickpads: the pad itself is the only physical button, while the tool buttons report how many fingers
+// rest on the pad
+var clickPadButtons = []int{evBtnLeft, evBtnTouch, evBtnToolFinger, BtnToolDoubletap}
+
+// the tool buttons that report one and two fingers on the pad
+var clickPadTools = []int{evBtnToolFinger, BtnToolDoubletap}
+
+// A ClickPad is a touch pad without separate buttons, like the touch pads of most current laptops. The whole pad is
+// pressed in order to click, which is reported as BTN_LEFT along with INPUT_PROP_BUTTONPAD. libinput then decides on
+// the button by the position of the fingers (software button areas) or their number (clickfinger). Two fingers are
+// reported as BTN_TOOL_DOUBLETAP, which allows to exercise two-finger scrolling.
+type ClickPad interface {
+	// MoveTo will move the fingers to the specified position on the pad.
+	MoveTo(x int32, y int32) error
+
+	// MoveToSmooth will move the fingers to the specified position in steps that are spread evenly across the
+	// duration, starting from the position of the last move (see TouchPad).
+	MoveToSmooth(x int32, y int32, duration time.Duration) error
+
+	// TouchDown will place a single finger on the pad. Use TouchUp to lift it again.
+	TouchDown() error
+
+	// TouchUp will lift all fingers off the pad.
+	TouchUp() error
+
+	// SetFingers will set the number of fingers that rest on the pad, which is 0 (no finger), 1 or 2. Moving two
+	// fingers scrolls, if two-finger scrolling is enabled.
+	SetFingers(fingers int) error
+
+	// Click will press the pad and immediately release it.
+	Click() error
+
+	// Press will press the pad. Note that the pad will not be released until Release is invoked.
+	Press() error
+
+	// Release will release the pad.
+	Release() error
+
+	Device
+}
+
+type vClickPad struct {
+	vTouchPad
+
+	mu      sync.Mutex
+	fingers int
+}
+
+// CreateClickPad will create a new clickpad device. Just like for touch pads, the x and y axis boundaries (min and
+// max) need to be defined upon creation.
+func CreateClickPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...Option) (ClickPad, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateClickPad", opts, optHumanizedMovement)
+	if err != nil {
+		return nil, err
+	}
+	cfg.props = append(cfg.props[:len(cfg.props):len(cfg.props)], inputPropPointer, inputPropButtonpad)
+	fd, err := createTouchPad(path, name, clickPadButtons, 0x0824, minX, maxX, minY, maxY, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vClickPad{vTouchPad: vTouchPad{name: name, deviceFile: fd, position: &pointerPosition{}, human: newHumanizer(cfg)}}, nil
+}
+
+func (vClick *vClickPad) TouchDown() error {
+	return vClick.SetFingers(1)
+}
+
+func (vClick *vClickPad) TouchUp() error {
+	return vClick.SetFingers(0)
+}
+
+// SetFingers will report the given number of fingers in a single frame. BTN_TOUCH is pressed as long as any finger
+// rests on the pad, while the tool button of the former number of fingers is released in favor of the new one.
+func (vClick *vClickPad) SetFingers(fingers int) error {
+	if fingers < 0 || fingers > len(clickPadTools) {
+		return errorf(ErrInvalidArgument, "failed to perform SetFingers. %d is not a valid number of fingers. Expected 0 to %d", fingers, len(clickPadTools))
+	}
+	vClick.mu.Lock()
+	defer vClick.mu.Unlock()
+	if fingers == vClick.fingers {
+		return nil
+	}
+
+	var events []inputEvent
+	if vClick.fingers > 0 {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(clickPadTools[vClick.fingers-1]), Value: btnStateReleased})
+	}
+	if fingers > 0 {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(clickPadTools[fingers-1]), Value: btnStatePressed})
+	}
+	if (vClick.fingers == 0) != (fingers == 0) {
+		state := int32(btnStatePressed)
+		if fingers == 0 {
+			state = btnStateReleased
+		}
+		events = append(events, inputEvent{Type: evKey, Code: evBtnTouch, Value: state})
+	}
+	err := sendEvents(vClick.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to set the number of fingers: %w", err)
+	}
+	vClick.fingers = fingers
+	return nil
+}
+
+func (vClick *vClickPad) Click() error {
+	err := vClick.Press()
+	if err != nil {
+		return fmt.Errorf("Failed to issue the Click event: %w", err)
+	}
+
+	return vClick.Release()
+}
+
+// Press will press the pad. Note that the pad will not be released until Release is invoked.
+func (vClick *vClickPad) Press() error {
+	return sendBtnEvent(vClick.deviceFile, []int{evBtnLeft}, btnStatePressed)
+}
+
+// Release will release the pad.
+func (vClick *vClickPad) Release() error {
+	return sendBtnEvent(vClick.deviceFile, []int{evBtnLeft}, btnStateReleased)
+}
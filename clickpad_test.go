@@ -0,0 +1,95 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicClickPad(t *testing.T) {
+	clickPad, err := CreateClickPad("/dev/uinput", []byte("Test ClickPad"), 0, 1024, 0, 768)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual clickpad. Last error was: %s\n", err)
+	}
+
+	err = clickPad.SetFingers(2)
+	if err != nil {
+		t.Fatalf("Failed to place two fingers. Last error was: %s\n", err)
+	}
+	err = clickPad.MoveTo(512, 300)
+	if err != nil {
+		t.Fatalf("Failed to move the fingers. Last error was: %s\n", err)
+	}
+	err = clickPad.Click()
+	if err != nil {
+		t.Fatalf("Failed to click. Last error was: %s\n", err)
+	}
+	err = clickPad.TouchUp()
+	if err != nil {
+		t.Fatalf("Failed to lift the fingers. Last error was: %s\n", err)
+	}
+
+	err = clickPad.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestClickPadCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateClickPad("", []byte("ClickPad"), 0, 1024, 0, 768)
+	assertPathDiscovered(t, device, err)
+}
+
+func TestClickPadRegistersButtonpad(t *testing.T) {
+	backend := &mockBackend{}
+	clickPad, err := CreateClickPad("mock", []byte("Test ClickPad"), 0, 1024, 0, 768, WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the clickpad: %v", err)
+	}
+	defer clickPad.Close()
+
+	if !backend.file.issued(uiSetPropBit) {
+		t.Fatalf("Expected INPUT_PROP_BUTTONPAD to be registered")
+	}
+}
+
+func TestClickPadReportsFingers(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-clickpad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	clickPad := &vClickPad{vTouchPad: vTouchPad{name: []byte("Test ClickPad"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}}
+	for _, fingers := range []int{1, 2, 2, 0} {
+		err = clickPad.SetFingers(fingers)
+		if err != nil {
+			t.Fatalf("Failed to set %d fingers: %v", fingers, err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStatePressed},
+		{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStateReleased},
+		{Type: evKey, Code: BtnToolDoubletap, Value: btnStatePressed},
+		{Type: evKey, Code: BtnToolDoubletap, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestClickPadRejectsInvalidFingers(t *testing.T) {
+	clickPad := &vClickPad{}
+	for _, fingers := range []int{-1, 3} {
+		err := clickPad.SetFingers(fingers)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %d fingers, got: %v", fingers, err)
+		}
+	}
+}
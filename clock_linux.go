@@ -0,0 +1,23 @@
+package uinput
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// the clock that evdev stamps events with by default, as defined in time.h
+const clockMonotonic = 1
+
+// MonotonicClock returns the current CLOCK_MONOTONIC time, which is the clock the kernel expects the timestamps of
+// uinput events to be based on. It may be passed to WithClock (see WithMonotonicClock). Note that the time is not
+// related to the wall clock, it counts from an arbitrary point (usually the boot of the system). The zero time is
+// returned if the clock cannot be read, which leaves stamping the events to the kernel.
+func MonotonicClock() time.Time {
+	var ts syscall.Timespec
+	_, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&ts)), 0)
+	if errno != 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(ts.Sec), int64(ts.Nsec))
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package uinput
+
+import "time"
+
+// processStart is the reference point of the monotonic clock on platforms other than Linux
+var processStart = time.Now()
+
+// MonotonicClock returns the current CLOCK_MONOTONIC time on Linux. Since uinput is not available on other platforms,
+// the time since the start of the process is returned there.
+func MonotonicClock() time.Time {
+	return time.Unix(0, int64(time.Since(processStart)))
+}
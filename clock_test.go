@@ -0,0 +1,19 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonotonicClockAdvancesWithRealTime(t *testing.T) {
+	start, wallStart := MonotonicClock(), time.Now()
+	time.Sleep(20 * time.Millisecond)
+	elapsed, wallElapsed := MonotonicClock().Sub(start), time.Since(wallStart)
+
+	if start.IsZero() || start.After(wallStart) {
+		t.Fatalf("Expected a time since boot, got %v", start)
+	}
+	if diff := elapsed - wallElapsed; diff < -5*time.Millisecond || diff > 5*time.Millisecond {
+		t.Fatalf("Expected the clock to advance by %v, got %v", wallElapsed, elapsed)
+	}
+}
@@ -0,0 +1,284 @@
+// Command uinputctl creates virtual input devices and emits events on them from the command line. Devices are either
+// created ad hoc (a keyboard or mouse) or from a JSON device config (see uinput.CreateFromConfig), in which case
+// events are given as macro scripts (see uinput.RunScript).
+//
+// Examples:
+//
+//	uinputctl keyboard type "hello"
+//	uinputctl keyboard combo KEY_LEFTCTRL KEY_C
+//	uinputctl mouse move 100 -50
+//	uinputctl mouse click right
+//	uinputctl run devices.json macro.txt
+//	echo "tap BTN_A; wait 1s; tap BTN_B" | uinputctl run -device "My Pad" devices.json
+//
+// When reading a script from stdin, every line is run as soon as it has been read, so that events may be streamed
+// into the device by other programs.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+// readyTimeout is how long to wait for the event node of a new device, so that consumers do not miss the first events
+const readyTimeout = 2 * time.Second
+
+// settleDelay is how long devices are kept after the last event before closing them, as consumers may drop the events
+// of devices that are gone before the events have been read
+const settleDelay = 100 * time.Millisecond
+
+// errUsage is returned for invalid command lines, which are answered by printing the usage
+var errUsage = errors.New("invalid usage")
+
+const usage = `usage: uinputctl [-path PATH] COMMAND [ARGUMENTS]
+
+commands:
+  keyboard type TEXT             type the text on a virtual keyboard
+  keyboard combo KEY...          press the keys as a combo (e.g. KEY_LEFTCTRL KEY_C)
+  mouse move X Y                 move the pointer relative to its position
+  mouse click [left|right|middle]
+  run [-device NAME] CONFIG [SCRIPT]
+                                 create the devices of the JSON config and run the script
+                                 on one of them (stdin if SCRIPT is omitted or "-")
+
+flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("uinputctl", flag.ExitOnError)
+	path := flags.String("path", "", "the path of the uinput device file, which is discovered if empty (devices of configs use the path given by their spec)")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	err := run(*path, flags.Args(), os.Stdin)
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(flags.Output(), "uinputctl: %v\n\n", err)
+			flags.Usage()
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "uinputctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command given by args.
+func run(path string, args []string, stdin io.Reader) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: no command given", errUsage)
+	}
+	switch args[0] {
+	case "keyboard":
+		return runKeyboard(path, args[1:])
+	case "mouse":
+		return runMouse(path, args[1:])
+	case "run":
+		return runScript(args[1:], stdin)
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+}
+
+func runKeyboard(path string, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: keyboard requires a subcommand and arguments", errUsage)
+	}
+	var action func(uinput.Keyboard) error
+	switch args[0] {
+	case "type":
+		text := strings.Join(args[1:], " ")
+		action = func(kbd uinput.Keyboard) error {
+			return kbd.Type(text)
+		}
+	case "combo":
+		keys, err := parseCodes(args[1:], "KEY_")
+		if err != nil {
+			return err
+		}
+		action = func(kbd uinput.Keyboard) error {
+			return kbd.Combo(keys...)
+		}
+	default:
+		return fmt.Errorf("%w: unknown keyboard subcommand %q", errUsage, args[0])
+	}
+
+	kbd, err := uinput.CreateKeyboard(path, []byte("uinputctl keyboard"))
+	if err != nil {
+		return err
+	}
+	defer closeDevice(kbd)
+	waitReady(kbd)
+	return action(kbd)
+}
+
+func runMouse(path string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: mouse requires a subcommand", errUsage)
+	}
+	var action func(uinput.Mouse) error
+	switch args[0] {
+	case "move":
+		if len(args) != 3 {
+			return fmt.Errorf("%w: mouse move requires X and Y", errUsage)
+		}
+		x, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("%w: invalid X %q", errUsage, args[1])
+		}
+		y, err := strconv.ParseInt(args[2], 10, 32)
+		if err != nil {
+			return fmt.Errorf("%w: invalid Y %q", errUsage, args[2])
+		}
+		action = func(m uinput.Mouse) error {
+			return m.Move(int32(x), int32(y))
+		}
+	case "click":
+		button := "left"
+		if len(args) > 1 {
+			button = args[1]
+		}
+		switch button {
+		case "left":
+			action = uinput.Mouse.LeftClick
+		case "right":
+			action = uinput.Mouse.RightClick
+		case "middle":
+			action = uinput.Mouse.MiddleClick
+		default:
+			return fmt.Errorf("%w: unknown button %q", errUsage, button)
+		}
+	default:
+		return fmt.Errorf("%w: unknown mouse subcommand %q", errUsage, args[0])
+	}
+
+	m, err := uinput.CreateMouse(path, []byte("uinputctl mouse"))
+	if err != nil {
+		return err
+	}
+	defer closeDevice(m)
+	waitReady(m)
+	return action(m)
+}
+
+func runScript(args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	name := flags.String("device", "", "the name of the device to run the script on (the first device by default)")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	args = flags.Args()
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("%w: run requires a config and an optional script", errUsage)
+	}
+
+	var script []byte
+	streaming := len(args) == 1 || args[1] == "-"
+	if !streaming {
+		var err error
+		script, err = ioutil.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	devices, err := uinput.CreateFromConfig(config)
+	config.Close()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		time.Sleep(settleDelay)
+		for _, device := range devices {
+			device.Close()
+		}
+	}()
+
+	device, err := selectDevice(devices, *name)
+	if err != nil {
+		return err
+	}
+	waitReady(device)
+
+	if !streaming {
+		return uinput.RunScript(device, string(script))
+	}
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		err = uinput.RunScript(device, scanner.Text())
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// selectDevice returns the device with the given name, or the first device if name is empty.
+func selectDevice(devices []uinput.Device, name string) (uinput.Device, error) {
+	if len(devices) == 0 {
+		return nil, errors.New("the config does not contain any devices")
+	}
+	if name == "" {
+		return devices[0], nil
+	}
+	for _, device := range devices {
+		if device.Name() == name {
+			return device, nil
+		}
+	}
+	return nil, fmt.Errorf("the config does not contain a device named %q", name)
+}
+
+// parseCodes looks up the codes of the given names, which must start with one of the prefixes.
+func parseCodes(names []string, prefixes ...string) ([]int, error) {
+	codes := make([]int, len(names))
+	for i, name := range names {
+		code, ok := uinput.CodeFromName(name)
+		if !ok || !hasAnyPrefix(name, prefixes) {
+			return nil, fmt.Errorf("%w: unknown code %q", errUsage, name)
+		}
+		codes[i] = int(code)
+	}
+	return codes, nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitReady waits for the event node of the device, so that consumers see the first events. Failures are ignored,
+// as the events are emitted nevertheless (e.g. on kernels without UI_GET_SYSNAME).
+func waitReady(device uinput.Device) {
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	defer cancel()
+	_ = device.WaitReady(ctx)
+}
+
+// closeDevice closes the device once consumers had the chance to read the last events.
+func closeDevice(device uinput.Device) {
+	time.Sleep(settleDelay)
+	device.Close()
+}
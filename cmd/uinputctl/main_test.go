@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bendahl/uinput"
+	"github.com/bendahl/uinput/uinputtest"
+)
+
+func TestInvalidCommandLinesAreUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"joystick"},
+		{"keyboard", "type"},
+		{"keyboard", "combo", "BTN_A"},
+		{"keyboard", "combo", "KEY_BOGUS"},
+		{"mouse", "wiggle"},
+		{"mouse", "move", "1"},
+		{"mouse", "move", "a", "1"},
+		{"mouse", "click", "fourth"},
+		{"run"},
+		{"run", "-bogus", "devices.json"},
+		{"run", "devices.json", "script.txt", "extra"},
+	} {
+		err := run("/dev/uinput", args, strings.NewReader(""))
+		if !errors.Is(err, errUsage) {
+			t.Errorf("Expected a usage error for %q, got %v", args, err)
+		}
+	}
+}
+
+func TestParseCodes(t *testing.T) {
+	codes, err := parseCodes([]string{"KEY_LEFTCTRL", "KEY_C"}, "KEY_")
+	if err != nil {
+		t.Fatalf("Failed to parse codes: %v", err)
+	}
+	if len(codes) != 2 || codes[0] != uinput.KeyLeftctrl || codes[1] != uinput.KeyC {
+		t.Fatalf("Expected KEY_LEFTCTRL and KEY_C, got %v", codes)
+	}
+}
+
+func TestSelectDevice(t *testing.T) {
+	devices := []uinput.Device{uinputtest.NewFake("first"), uinputtest.NewFake("second")}
+
+	device, err := selectDevice(devices, "")
+	if err != nil || device.Name() != "first" {
+		t.Fatalf("Expected the first device by default, got %v (%v)", device, err)
+	}
+	device, err = selectDevice(devices, "second")
+	if err != nil || device.Name() != "second" {
+		t.Fatalf("Expected the device named second, got %v (%v)", device, err)
+	}
+	_, err = selectDevice(devices, "third")
+	if err == nil {
+		t.Fatalf("Expected an error for an unknown device")
+	}
+}
@@ -0,0 +1,16 @@
+package uinput
+
+// CodeName returns the kernel name (e.g. "BTN_SOUTH" or "ABS_RX") of the code of the given event type. An empty
+// string is returned if the code is unknown. If several names refer to the same code, the most specific one is
+// returned (e.g. "BTN_SOUTH" rather than "BTN_A" or "BTN_GAMEPAD").
+func CodeName(evType uint16, code uint16) string {
+	return codeNames[evType][code]
+}
+
+// CodeFromName returns the code with the given kernel name (e.g. "BTN_SOUTH" or "ABS_RX"). Aliases (like "BTN_A")
+// are resolved as well. This allows configuration files and logs to refer to codes by name. The event type of the
+// code is given by the prefix of the name (e.g. EvKey for "KEY_" and "BTN_").
+func CodeFromName(name string) (uint16, bool) {
+	code, ok := codeValues[name]
+	return code, ok
+}
@@ -0,0 +1,47 @@
+package uinput
+
+import "testing"
+
+func TestCodeName(t *testing.T) {
+	tests := []struct {
+		evType   uint16
+		code     uint16
+		expected string
+	}{
+		{EvKey, ButtonSouth, "BTN_SOUTH"},
+		{EvKey, KeyA, "KEY_A"},
+		{EvKey, BtnLeft, "BTN_LEFT"},
+		{EvAbs, AbsRX, "ABS_RX"},
+		{EvRel, RelWheel, "REL_WHEEL"},
+		{EvSyn, synReport, "SYN_REPORT"},
+		{EvAbs, 0x3f, ""},
+		{EvFF, 0x50, ""},
+	}
+	for _, test := range tests {
+		if name := CodeName(test.evType, test.code); name != test.expected {
+			t.Fatalf("Expected: %q\nActual: %q", test.expected, name)
+		}
+	}
+}
+
+func TestCodeFromName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected uint16
+	}{
+		{"BTN_SOUTH", ButtonSouth},
+		{"BTN_A", ButtonSouth},
+		{"KEY_ESC", KeyEsc},
+		{"ABS_MT_SLOT", absMtSlot},
+	}
+	for _, test := range tests {
+		code, ok := CodeFromName(test.name)
+		if !ok || code != test.expected {
+			t.Fatalf("%s: Expected: %#x\nActual: %#x (found: %v)", test.name, test.expected, code, ok)
+		}
+	}
+
+	if _, ok := CodeFromName("KEY_DOES_NOT_EXIST"); ok {
+		t.Fatalf("Expected unknown name not to be found")
+	}
+}
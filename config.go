@@ -0,0 +1,263 @@
+package uinput
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the names of the input properties as used in configs
+var propNames = map[string]uint16{
+	"INPUT_PROP_POINTER":        PropPointer,
+	"INPUT_PROP_DIRECT":         PropDirect,
+	"INPUT_PROP_BUTTONPAD":      PropButtonpad,
+	"INPUT_PROP_SEMI_MT":        PropSemiMT,
+	"INPUT_PROP_TOPBUTTONPAD":   PropTopButtonpad,
+	"INPUT_PROP_POINTING_STICK": PropPointingStick,
+	"INPUT_PROP_ACCELEROMETER":  PropAccelerometer,
+}
+
+// A Config describes a set of devices, which may be created using CreateFromConfig. Configs are read from JSON, for
+// example:
+//
+//	{"devices": [{
+//		"name": "Arcade Stick",
+//		"vendor": "0x4711", "product": "0x0900",
+//		"keys": ["BTN_SOUTH", "BTN_EAST", "BTN_START"],
+//		"absAxes": [{"code": "ABS_X", "min": -1, "max": 1}, {"code": "ABS_Y", "min": -1, "max": 1}]
+//	}]}
+type Config struct {
+	Devices []DeviceSpec `json:"devices"`
+}
+
+// A DeviceSpec describes a single device by its identity and the codes it provides. All codes are given by their
+// kernel names (see CodeFromName), properties by their names as well (e.g. "INPUT_PROP_DIRECT"). Ids may be given as
+// numbers or as strings, which allows to use hexadecimal ids (e.g. "0x045e"). If no path is given, the uinput device
+// file is discovered (see DefaultPath).
+type DeviceSpec struct {
+	Name       string     `json:"name"`
+	Path       string     `json:"path,omitempty"`
+	Bustype    ConfigID   `json:"bustype,omitempty"`
+	Vendor     ConfigID   `json:"vendor,omitempty"`
+	Product    ConfigID   `json:"product,omitempty"`
+	Version    ConfigID   `json:"version,omitempty"`
+	Keys       []string   `json:"keys,omitempty"`
+	RelAxes    []string   `json:"relAxes,omitempty"`
+	AbsAxes    []AxisSpec `json:"absAxes,omitempty"`
+	Properties []string   `json:"properties,omitempty"`
+}
+
+// An AxisSpec describes an absolute axis of a device, along with its range and tuning (see AxisTuning).
+type AxisSpec struct {
+	Code       string `json:"code"`
+	Min        int32  `json:"min"`
+	Max        int32  `json:"max"`
+	Fuzz       int32  `json:"fuzz,omitempty"`
+	Flat       int32  `json:"flat,omitempty"`
+	Resolution int32  `json:"resolution,omitempty"`
+}
+
+// A ConfigID is an id within a config, which may be given as a number or as a string (e.g. "0x045e").
+type ConfigID uint16
+
+// UnmarshalJSON reads the id from a number or a string.
+func (id *ConfigID) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	value, err := strconv.ParseUint(text, 0, 16)
+	if err != nil {
+		return errorf(ErrInvalidArgument, "%s is not a valid id. Expected a number between 0 and 0xffff", data)
+	}
+	*id = ConfigID(value)
+	return nil
+}
+
+// CreateFromConfig will read a config (see Config) from r and create all devices it describes. The given options
+// apply to all devices, but are overridden by the settings of the config. If any device cannot be created, the devices
+// that have been created already are closed.
+func CreateFromConfig(r io.Reader, opts ...Option) ([]Device, error) {
+	var config Config
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&config)
+	if err != nil {
+		return nil, errorf(ErrInvalidArgument, "failed to read device config: %w", err)
+	}
+
+	devices := make([]Device, 0, len(config.Devices))
+	for _, spec := range config.Devices {
+		device, err := CreateFromSpec(spec, opts...)
+		if err != nil {
+			for _, d := range devices {
+				d.Close()
+			}
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// CreateFromSpec will create the device described by the given spec. The device only provides the methods of Device,
+// so events are sent using EmitEvent and EmitEvents. The spec is validated before the device is created.
+func CreateFromSpec(spec DeviceSpec, opts ...Option) (Device, error) {
+	path := spec.Path
+	name := []byte(spec.Name)
+	keys, err := specCodes(spec.Name, spec.Keys, "KEY_", "BTN_")
+	if err != nil {
+		return nil, err
+	}
+	relAxes, err := specCodes(spec.Name, spec.RelAxes, "REL_")
+	if err != nil {
+		return nil, err
+	}
+	var axes []int
+	var dev uinputUserDev
+	cfg, err := applyOptions("CreateFromSpec", opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, axis := range spec.AbsAxes {
+		codes, err := specCodes(spec.Name, []string{axis.Code}, "ABS_")
+		if err != nil {
+			return nil, err
+		}
+		code := codes[0]
+		if axis.Min >= axis.Max {
+			return nil, errorf(ErrInvalidArgument, "failed to create device %q. The range of axis %s is empty", spec.Name, axis.Code)
+		}
+		axes = append(axes, code)
+		dev.Absmin[code] = axis.Min
+		dev.Absmax[code] = axis.Max
+		cfg = cfg.withDefaultTuning(uint16(code), AxisTuning{Fuzz: axis.Fuzz, Flat: axis.Flat, Resolution: axis.Resolution})
+	}
+	for _, prop := range spec.Properties {
+		code, ok := propNames[prop]
+		if !ok {
+			return nil, errorf(ErrInvalidArgument, "failed to create device %q. %s is not a known property", spec.Name, prop)
+		}
+		cfg.props = append(cfg.props, code)
+	}
+	for _, code := range relAxes {
+		cfg.relAxes = append(cfg.relAxes, uint16(code))
+	}
+	if len(keys) == 0 && len(axes) == 0 && len(relAxes) == 0 {
+		return nil, errorf(ErrInvalidArgument, "failed to create device %q. At least one key or axis is required", spec.Name)
+	}
+
+	dev.Name = toUinputName(name)
+	dev.ID = inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0821, Version: 1}
+	for _, id := range []struct {
+		value  ConfigID
+		target *uint16
+	}{
+		{spec.Bustype, &dev.ID.Bustype}, {spec.Vendor, &dev.ID.Vendor}, {spec.Product, &dev.ID.Product},
+		{spec.Version, &dev.ID.Version},
+	} {
+		if id.value != 0 {
+			*id.target = uint16(id.value)
+		}
+	}
+
+	err = validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createAxisDevice(path, keys, axes, nil, dev, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return vConfiguredDevice{name: name, deviceFile: fd}, nil
+}
+
+// specCodes resolves the given code names, which are expected to start with one of the given prefixes.
+func specCodes(device string, names []string, prefixes ...string) ([]int, error) {
+	codes := make([]int, 0, len(names))
+	for _, name := range names {
+		code, ok := CodeFromName(name)
+		valid := false
+		for _, prefix := range prefixes {
+			valid = valid || strings.HasPrefix(name, prefix)
+		}
+		if !ok || !valid {
+			return nil, errorf(ErrInvalidArgument, "failed to create device %q. %s is not a known %s code", device, name, strings.Join(prefixes, " or "))
+		}
+		codes = append(codes, int(code))
+	}
+	return codes, nil
+}
+
+// vConfiguredDevice is a device created from a config
+type vConfiguredDevice struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+func (vc vConfiguredDevice) Name() string {
+	return string(vc.name)
+}
+
+func (vc vConfiguredDevice) Path() string {
+	return devicePath(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) Fd() uintptr {
+	return deviceFd(vc.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vc vConfiguredDevice) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vc.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vc vConfiguredDevice) EmitEvents(events []InputEvent) error {
+	return emitEvents(vc.deviceFile, events)
+}
+
+func (vc vConfiguredDevice) Sync() error {
+	return writeSyncEvent(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) SysPath() (string, error) {
+	return sysPath(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) EventPath() (string, error) {
+	return eventPath(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) Stats() Stats {
+	return deviceStats(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) State() State {
+	return deviceState(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) ResetAll() error {
+	return resetDevice(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) Disconnect() error {
+	return disconnectDevice(vc.deviceFile)
+}
+
+func (vc vConfiguredDevice) Reconnect() error {
+	return reconnectDevice(vc.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vc vConfiguredDevice) Close() error {
+	return closeDevice(vc.deviceFile)
+}
@@ -0,0 +1,70 @@
+package uinput
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBasicDeviceFromConfig(t *testing.T) {
+	config := `{"devices": [{
+		"name": "Test Configured Device",
+		"vendor": "0x045e", "product": 654,
+		"keys": ["BTN_SOUTH", "BTN_EAST"],
+		"absAxes": [{"code": "ABS_X", "min": -32768, "max": 32767, "flat": 128}],
+		"properties": ["INPUT_PROP_POINTER"]
+	}]}`
+	devices, err := CreateFromConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Failed to create the configured devices. Last error was: %s\n", err)
+	}
+
+	err = devices[0].EmitEvent(EvKey, BtnSouth, 1)
+	if err != nil {
+		t.Fatalf("Failed to emit event. Last error was: %s\n", err)
+	}
+
+	err = devices[0].Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestCreateFromSpecDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateFromSpec(DeviceSpec{Name: "Test Configured Device", Keys: []string{"KEY_A"}})
+	assertPathDiscovered(t, device, err)
+}
+
+func TestConfigIDAcceptsNumbersAndStrings(t *testing.T) {
+	var spec DeviceSpec
+	err := json.Unmarshal([]byte(`{"vendor": "0x045e", "product": 654, "version": "272"}`), &spec)
+	if err != nil {
+		t.Fatalf("Failed to read spec: %v", err)
+	}
+	if spec.Vendor != 0x045e || spec.Product != 654 || spec.Version != 272 {
+		t.Fatalf("Expected: 0x045e, 654, 272\nActual: %#x, %d, %d", spec.Vendor, spec.Product, spec.Version)
+	}
+
+	err = json.Unmarshal([]byte(`{"vendor": "0x10000"}`), &spec)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an id out of range, got: %v", err)
+	}
+}
+
+func TestCreateFromConfigRejectsInvalidConfigs(t *testing.T) {
+	for _, config := range []string{
+		`{"devices": [{"name": "Device", "keys": ["ABS_X"]}]}`,
+		`{"devices": [{"name": "Device", "keys": ["KEY_BOGUS"]}]}`,
+		`{"devices": [{"name": "Device", "absAxes": [{"code": "ABS_X", "min": 1, "max": 1}]}]}`,
+		`{"devices": [{"name": "Device", "keys": ["KEY_A"], "properties": ["INPUT_PROP_UNKNOWN"]}]}`,
+		`{"devices": [{"name": "Device"}]}`,
+		`{"devices": [{"name": "Device", "buttons": ["BTN_SOUTH"]}]}`,
+		`{"devices": [`,
+	} {
+		_, err := CreateFromConfig(strings.NewReader(config))
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for config %s, got: %v", config, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package uinput
+
+// A Contact is a handle to a contact that rests on a touch screen, as returned by Touch. The slot and the tracking id
+// of the contact are managed by the touch screen: the tracking id is allocated when the contact is placed and
+// released when it is lifted, after which the slot is reused by later contacts. Once lifted, the handle is stale and
+// all of its methods fail with an error wrapping ErrInvalidArgument, so it never affects the contacts that reuse the
+// slot.
+type Contact struct {
+	screen     *vTouchScreen
+	slot       int
+	trackingID int32
+}
+
+// Touch will place a new contact at the given position using the lowest free slot and returns a handle to it. An
+// error wrapping ErrInvalidArgument is returned if all slots are in use.
+func (vTouch *vTouchScreen) Touch(x int32, y int32) (*Contact, error) {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	for slot, trackingID := range vTouch.trackingIDs {
+		if trackingID != -1 {
+			continue
+		}
+		err := vTouch.sendContactsLocked([]contactChange{{kind: contactDown, slot: slot, x: x, y: y}})
+		if err != nil {
+			return nil, err
+		}
+		return &Contact{screen: vTouch, slot: slot, trackingID: vTouch.trackingIDs[slot]}, nil
+	}
+	return nil, errorf(ErrInvalidArgument, "failed to perform Touch. All %d slots are in use", len(vTouch.trackingIDs))
+}
+
+// Slot returns the slot of the contact.
+func (c *Contact) Slot() int {
+	return c.slot
+}
+
+// TrackingID returns the tracking id that has been reported for the contact.
+func (c *Contact) TrackingID() int32 {
+	return c.trackingID
+}
+
+// Move will move the contact to the given position.
+func (c *Contact) Move(x int32, y int32) error {
+	return c.send("Move", contactChange{kind: contactMove, slot: c.slot, x: x, y: y})
+}
+
+// SetShape will change the tool type and touch area of the contact (see SetContactShape).
+func (c *Contact) SetShape(shape ContactShape) error {
+	return c.send("SetShape", contactChange{kind: contactReshape, slot: c.slot, shape: shape})
+}
+
+// Lift will lift the contact, which frees its slot for new contacts.
+func (c *Contact) Lift() error {
+	return c.send("Lift", contactChange{kind: contactUp, slot: c.slot})
+}
+
+// send applies the change, provided that the contact still rests on the screen.
+func (c *Contact) send(op string, change contactChange) error {
+	c.screen.mu.Lock()
+	defer c.screen.mu.Unlock()
+	if c.trackingID == -1 || c.screen.trackingIDs[c.slot] != c.trackingID {
+		return errorf(ErrInvalidArgument, "failed to perform %s. Contact %d has been lifted already", op, c.trackingID)
+	}
+	return c.screen.sendContactsLocked([]contactChange{change})
+}
+
+// A TouchFrame batches changes to the contacts of a touch screen, which are addressed by ids of the caller's choice.
+// The changes are reported as a single frame once Commit is called, so that all contacts change simultaneously. The
+// ids remain valid across frames until the contact is lifted, while the slots and tracking ids are managed by the
+// touch screen. A TouchFrame must not be used concurrently.
+type TouchFrame struct {
+	screen  *vTouchScreen
+	changes []contactChange
+	placed  map[int]*Contact // the contacts placed by this frame
+	lifted  map[int]bool     // the ids of the contacts lifted by this frame
+}
+
+// NewFrame returns an empty frame of changes to the contacts of the touch screen.
+func (vTouch *vTouchScreen) NewFrame() *TouchFrame {
+	return &TouchFrame{screen: vTouch, placed: map[int]*Contact{}, lifted: map[int]bool{}}
+}
+
+// TouchDown will place a new contact with the given id at the given position, using the lowest free slot that has not
+// been taken by the frame already. The returned handle becomes valid once the frame has been committed.
+func (f *TouchFrame) TouchDown(id int, x int32, y int32) (*Contact, error) {
+	vTouch := f.screen
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	if f.placed[id] != nil || (vTouch.activeContactLocked(id) != nil && !f.lifted[id]) {
+		return nil, errorf(ErrInvalidArgument, "failed to perform TouchDown. Contact id %d is in use already", id)
+	}
+	reserved := map[int]bool{}
+	for _, c := range f.placed {
+		reserved[c.slot] = true
+	}
+	for slot, trackingID := range vTouch.trackingIDs {
+		if trackingID != -1 || reserved[slot] {
+			continue
+		}
+		c := &Contact{screen: vTouch, slot: slot, trackingID: -1}
+		f.placed[id] = c
+		f.changes = append(f.changes, contactChange{kind: contactDown, slot: slot, x: x, y: y})
+		return c, nil
+	}
+	return nil, errorf(ErrInvalidArgument, "failed to perform TouchDown. All %d slots are in use", len(vTouch.trackingIDs))
+}
+
+// TouchMove will move the contact with the given id to the given position.
+func (f *TouchFrame) TouchMove(id int, x int32, y int32) error {
+	c, err := f.contact("TouchMove", id)
+	if err != nil {
+		return err
+	}
+	f.changes = append(f.changes, contactChange{kind: contactMove, slot: c.slot, x: x, y: y})
+	return nil
+}
+
+// TouchUp will lift the contact with the given id, which frees the id for new contacts.
+func (f *TouchFrame) TouchUp(id int) error {
+	c, err := f.contact("TouchUp", id)
+	if err != nil {
+		return err
+	}
+	f.changes = append(f.changes, contactChange{kind: contactUp, slot: c.slot})
+	delete(f.placed, id)
+	f.lifted[id] = true
+	return nil
+}
+
+// Commit will report all changes as a single frame and clears the frame for subsequent changes. If any change turns
+// out to be invalid or the frame cannot be sent, none of the changes are applied.
+func (f *TouchFrame) Commit() error {
+	vTouch := f.screen
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	changes, placed, lifted := f.changes, f.placed, f.lifted
+	f.changes, f.placed, f.lifted = nil, map[int]*Contact{}, map[int]bool{}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	err := vTouch.sendContactsLocked(changes)
+	if err != nil {
+		return err
+	}
+	if vTouch.contacts == nil {
+		vTouch.contacts = map[int]*Contact{}
+	}
+	for id := range lifted {
+		delete(vTouch.contacts, id)
+	}
+	for id, c := range placed {
+		c.trackingID = vTouch.trackingIDs[c.slot]
+		vTouch.contacts[id] = c
+	}
+	return nil
+}
+
+// contact returns the contact with the given id, which is either placed by the frame or rests on the screen.
+func (f *TouchFrame) contact(op string, id int) (*Contact, error) {
+	if c := f.placed[id]; c != nil {
+		return c, nil
+	}
+	f.screen.mu.Lock()
+	defer f.screen.mu.Unlock()
+	if c := f.screen.activeContactLocked(id); c != nil && !f.lifted[id] {
+		return c, nil
+	}
+	return nil, errorf(ErrInvalidArgument, "failed to perform %s. There is no contact with id %d", op, id)
+}
+
+// activeContactLocked returns the contact with the given id, unless it has been lifted in the meantime (e.g. using
+// Contact.Lift). The caller needs to hold mu.
+func (vTouch *vTouchScreen) activeContactLocked(id int) *Contact {
+	c := vTouch.contacts[id]
+	if c == nil || vTouch.trackingIDs[c.slot] != c.trackingID {
+		return nil
+	}
+	return c
+}
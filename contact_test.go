@@ -0,0 +1,178 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestTouchAllocatesLowestFreeSlot(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	first, err := vTouch.Touch(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	second, err := vTouch.Touch(20, 20)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if first.Slot() != 0 || second.Slot() != 1 || first.TrackingID() == second.TrackingID() {
+		t.Fatalf("Expected the contacts to use slots 0 and 1 with distinct tracking ids, got %+v and %+v", first, second)
+	}
+	_, err = vTouch.Touch(30, 30)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error without free slots, got: %v", err)
+	}
+
+	err = first.Lift()
+	if err != nil {
+		t.Fatalf("Failed to lift contact: %v", err)
+	}
+	third, err := vTouch.Touch(30, 30)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if third.Slot() != 0 || third.TrackingID() == first.TrackingID() {
+		t.Fatalf("Expected the contact to reuse slot 0 with a new tracking id, got %+v", third)
+	}
+}
+
+func TestStaleContactDoesNotAffectSlotReuse(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 1)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	stale, err := vTouch.Touch(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if err = stale.Lift(); err != nil {
+		t.Fatalf("Failed to lift contact: %v", err)
+	}
+	current, err := vTouch.Touch(20, 20)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+
+	for _, op := range []func() error{
+		func() error { return stale.Move(30, 30) },
+		func() error { return stale.SetShape(ContactShape{Tool: MtToolPalm}) },
+		stale.Lift,
+	} {
+		if err := op(); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for a stale contact, got: %v", err)
+		}
+	}
+	if vTouch.positions[0] != (Point{X: 20, Y: 20}) || vTouch.trackingIDs[0] != current.TrackingID() {
+		t.Fatalf("Expected the current contact to remain unchanged")
+	}
+	if err = current.Move(30, 30); err != nil {
+		t.Fatalf("Failed to move contact: %v", err)
+	}
+}
+
+func TestTrackingIDsSkipContactsInUse(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	long, err := vTouch.Touch(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	// let the tracking ids wrap around while the first contact rests on the screen
+	vTouch.nextTrackingID = long.TrackingID()
+	next, err := vTouch.Touch(20, 20)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if next.TrackingID() == long.TrackingID() {
+		t.Fatalf("Expected a tracking id that is not in use, got %d", next.TrackingID())
+	}
+}
+
+func TestTouchFrameBatchesContactsById(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 3)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	frame := vTouch.NewFrame()
+	first, err := frame.TouchDown(7, 100, 100)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	second, err := frame.TouchDown(3, 200, 200)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if first.Slot() == second.Slot() {
+		t.Fatalf("Expected the contacts of a frame to use distinct slots, got %d", first.Slot())
+	}
+	if err = frame.Commit(); err != nil {
+		t.Fatalf("Failed to commit frame: %v", err)
+	}
+
+	if err = frame.TouchMove(7, 150, 100); err != nil {
+		t.Fatalf("Failed to move contact: %v", err)
+	}
+	if err = frame.TouchUp(3); err != nil {
+		t.Fatalf("Failed to lift contact: %v", err)
+	}
+	if err = frame.Commit(); err != nil {
+		t.Fatalf("Failed to commit frame: %v", err)
+	}
+
+	if frames := len(readEvents(t, file.Name(), evSyn)); frames != 2 {
+		t.Fatalf("Expected the changes to be reported as 2 frames, got %d", frames)
+	}
+	if vTouch.positions[first.Slot()] != (Point{X: 150, Y: 100}) || vTouch.activeContacts != 1 {
+		t.Fatalf("Expected contact 7 to have moved and contact 3 to have been lifted")
+	}
+	// the handles of committed contacts may be used directly
+	if err = first.Move(160, 100); err != nil {
+		t.Fatalf("Failed to move contact using its handle: %v", err)
+	}
+	if err = second.Move(160, 100); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a lifted contact, got: %v", err)
+	}
+}
+
+func TestTouchFrameRejectsUnknownAndDuplicateIds(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	frame := vTouch.NewFrame()
+	if err := frame.TouchMove(1, 10, 10); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unknown id, got: %v", err)
+	}
+	if _, err := frame.TouchDown(1, 10, 10); err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if _, err := frame.TouchDown(1, 20, 20); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a duplicate id, got: %v", err)
+	}
+	if err := frame.Commit(); err != nil {
+		t.Fatalf("Failed to commit frame: %v", err)
+	}
+	if _, err := frame.TouchDown(1, 20, 20); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an id that is in use, got: %v", err)
+	}
+
+	// lifting and placing a contact with the same id within a frame reuses the id
+	if err := frame.TouchUp(1); err != nil {
+		t.Fatalf("Failed to lift contact: %v", err)
+	}
+	if _, err := frame.TouchDown(1, 30, 30); err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	if err := frame.Commit(); err != nil {
+		t.Fatalf("Failed to commit frame: %v", err)
+	}
+	if err := frame.TouchMove(1, 40, 40); err != nil {
+		t.Fatalf("Failed to move contact: %v", err)
+	}
+}
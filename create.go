@@ -0,0 +1,109 @@
+package uinput
+
+// an absAxis is an absolute axis that has been added using WithAxis
+type absAxis struct {
+	code     uint16
+	min, max int32
+}
+
+// WithName sets the name of devices created using CreateDevice.
+func WithName(name string) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optName)
+		cfg.name = []byte(name)
+	}
+}
+
+// WithKeys adds the given keys or buttons (see the Key and Btn constants) to devices created using CreateDevice.
+func WithKeys(codes ...uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optKeys)
+		cfg.keys = append(cfg.keys, codes...)
+	}
+}
+
+// WithAxis adds the absolute axis with the given code and range to devices created using CreateDevice. Adding an axis
+// again replaces its range. The axis may be tuned using WithAxisTuning.
+func WithAxis(code uint16, min int32, max int32) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optAxis)
+		for i := range cfg.absAxes {
+			if cfg.absAxes[i].code == code {
+				cfg.absAxes[i].min, cfg.absAxes[i].max = min, max
+				return
+			}
+		}
+		cfg.absAxes = append(cfg.absAxes, absAxis{code: code, min: min, max: max})
+	}
+}
+
+// WithFF makes devices created using CreateDevice announce the given force feedback capabilities, just like
+// CreateGamepadWithForceFeedback does.
+func WithFF(ff ForceFeedback) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optFF)
+		cfg.ff = &ff
+	}
+}
+
+// CreateDevice will create a device that is described by options only: the name is set using WithName, while the
+// codes it provides are added using WithKeys, WithAxis and WithRelativeAxes. All other options (like WithVendor,
+// WithProperties or WithFF) apply as usual, except for the options that only apply to presets (like WithLayout). Unlike
+// the constructors of the presets, new capabilities are added as options, so that the signature does not change. The
+// device only provides the methods of Device, so events are sent using EmitEvent and EmitEvents (or a Frame).
+//
+// The positional constructors of the presets (like CreateKeyboard) are kept on purpose rather than deprecated: their
+// arguments are the properties every device of the kind needs (like the ranges of a touch pad), and they return the
+// interface of the preset, which CreateDevice cannot provide. Optional properties of presets are added as options as
+// well, so that their signatures do not change either.
+func CreateDevice(path string, opts ...Option) (Device, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := applyOptions("CreateDevice", opts, optName, optKeys, optAxis, optFF)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.name) == 0 {
+		return nil, errorf(ErrInvalidArgument, "failed to create device. A name is required (see WithName)")
+	}
+	err = validateUinputName(cfg.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.keys) == 0 && len(cfg.absAxes) == 0 && len(cfg.relAxes) == 0 {
+		return nil, errorf(ErrInvalidArgument, "failed to create device %q. At least one key or axis is required", cfg.name)
+	}
+
+	keys := make([]int, 0, len(cfg.keys))
+	for _, key := range cfg.keys {
+		keys = append(keys, int(key))
+	}
+	var axes []int
+	dev := uinputUserDev{Name: toUinputName(cfg.name), ID: inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0821, Version: 1}}
+	for _, axis := range cfg.absAxes {
+		if int(axis.code) >= absSize {
+			return nil, errorf(ErrInvalidArgument, "failed to create device %q. Axis %d is out of range. Expected a code below %d", cfg.name, axis.code, absSize)
+		}
+		if axis.min >= axis.max {
+			return nil, errorf(ErrInvalidArgument, "failed to create device %q. The range of axis %d is empty", cfg.name, axis.code)
+		}
+		axes = append(axes, int(axis.code))
+		dev.Absmin[axis.code] = axis.min
+		dev.Absmax[axis.code] = axis.max
+	}
+	if cfg.ff != nil {
+		err = validateForceFeedback(*cfg.ff)
+		if err != nil {
+			return nil, err
+		}
+		cfg = cfg.withForceFeedback(cfg.ff)
+	}
+
+	fd, err := createAxisDevice(path, keys, axes, cfg.ff, dev, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return vConfiguredDevice{name: cfg.name, deviceFile: fd}, nil
+}
@@ -0,0 +1,75 @@
+package uinput
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+func TestCreateDeviceFromOptions(t *testing.T) {
+	backend := &mockBackend{}
+	device, err := CreateDevice("mock", WithBackend(backend), WithName("Arcade Stick"), WithKeys(BtnSouth, BtnEast),
+		WithAxis(AbsX, -1, 1), WithAxis(AbsY, -1, 1), WithAxis(AbsX, -100, 100), WithVendor(0x1234))
+	if err != nil {
+		t.Fatalf("Failed to create the device: %v", err)
+	}
+	defer device.Close()
+
+	if device.Name() != "Arcade Stick" {
+		t.Fatalf("Expected the name to be set by WithName, got %q", device.Name())
+	}
+	if !backend.file.issued(uiSetKeyBit) || !backend.file.issued(uiSetAbsBit) {
+		t.Fatalf("Expected the keys and axes to be registered")
+	}
+
+	backend.file.mu.Lock()
+	buf := backend.file.writes.Bytes()
+	backend.file.mu.Unlock()
+	if len(buf) < int(unsafe.Sizeof(uinputUserDev{})) {
+		t.Fatalf("Expected the device setup to be written, got %d bytes", len(buf))
+	}
+	dev := (*uinputUserDev)(unsafe.Pointer(&buf[0]))
+	if dev.Absmin[AbsX] != -100 || dev.Absmax[AbsX] != 100 || dev.Absmax[AbsY] != 1 {
+		t.Fatalf("Expected adding an axis again to replace its range, got %d to %d", dev.Absmin[AbsX], dev.Absmax[AbsX])
+	}
+	if dev.ID.Vendor != 0x1234 || dev.ID.Product != 0x0821 {
+		t.Fatalf("Expected the vendor to be overridden, got %+v", dev.ID)
+	}
+}
+
+func TestCreateDeviceRejectsIncompleteOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"no name", []Option{WithKeys(KeyA)}},
+		{"no codes", []Option{WithName("Empty")}},
+		{"empty range", []Option{WithName("Stick"), WithAxis(AbsX, 1, 1)}},
+		{"unknown axis", []Option{WithName("Stick"), WithAxis(absSize, 0, 1)}},
+		{"unsupported effect", []Option{WithName("Pad"), WithKeys(BtnSouth), WithFF(ForceFeedback{Effects: []uint16{0x60}})}},
+	} {
+		opts := append([]Option{WithBackend(&mockBackend{})}, tc.opts...)
+		_, err := CreateDevice("mock", opts...)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %s, got: %v", tc.name, err)
+		}
+	}
+}
+
+func TestDeviceSpecificOptionsAreRejectedByOtherDevices(t *testing.T) {
+	_, err := CreateMouse("mock", []byte("Test Mouse"), WithBackend(&mockBackend{}), WithKeys(KeyA))
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected WithKeys to be rejected by CreateMouse, got %v", err)
+	}
+	_, err = CreateDevice("mock", WithBackend(&mockBackend{}), WithName("Test Device"), WithKeys(KeyA), WithLayout(LayoutDE))
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected WithLayout to be rejected by CreateDevice, got %v", err)
+	}
+
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(&mockBackend{}), WithLayout(LayoutDE),
+		WithStickyKeys(), WithVendor(0x1234))
+	if err != nil {
+		t.Fatalf("Expected the keyboard options to be accepted, got %v", err)
+	}
+	kbd.Close()
+}
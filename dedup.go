@@ -0,0 +1,53 @@
+package uinput
+
+import (
+	"unsafe"
+)
+
+// WithDeduplication makes the device skip events that would not change the value of a key, button or absolute axis,
+// as the value has been written last already. Frames that only consist of skipped events are not written at all,
+// which saves a write for every tick of sources that are polled and report their whole state each time (like bridges
+// of physical controllers). Only presses and releases of keys are deduplicated, repeats (value 2) are always written.
+// Relative axes and all other events are written as is, as are multitouch axes, since their values depend on the slot
+// that has been selected.
+func WithDeduplication() Option {
+	return func(cfg *deviceConfig) {
+		cfg.dedup = true
+	}
+}
+
+// dropUnchanged removes the events that would not change the state of the device from the encoded frame. The frame is
+// modified in place. The caller must hold the lock of the device.
+func (d *uinputDevice) dropUnchanged(buf []byte) []byte {
+	n := 0
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[i]))
+		if d.unchanged(iev) {
+			continue
+		}
+		copy(buf[n:], buf[i:i+inputEventSize])
+		n += inputEventSize
+	}
+	return buf[:n]
+}
+
+// unchanged reports whether the event would set its key or axis to the value it has already. Axis movements that
+// are pending in the rate limiter take precedence over the values that have been written.
+func (d *uinputDevice) unchanged(iev inputEvent) bool {
+	if iev.Type != evKey && (iev.Type != evAbs || iev.Code >= absMtSlot) {
+		return false
+	}
+	if iev.Type == evKey && iev.Value != btnStateReleased && iev.Value != btnStatePressed {
+		// repeats (and any other values) are events of their own rather than a state
+		return false
+	}
+	if d.limiter != nil {
+		for _, pending := range d.limiter.pending {
+			if pending.Type == iev.Type && pending.Code == iev.Code {
+				return pending.Value == iev.Value
+			}
+		}
+	}
+	value, ok := d.state.value(iev.Type, iev.Code)
+	return ok && value == iev.Value
+}
@@ -0,0 +1,146 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// takeWrites returns the events that have been written to the mock file since the last call and discards them.
+func takeWrites(backend *mockBackend) []inputEvent {
+	backend.file.mu.Lock()
+	defer backend.file.mu.Unlock()
+	buf := backend.file.writes.Bytes()
+	var events []inputEvent
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		events = append(events, inputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+	}
+	backend.file.writes.Reset()
+	return events
+}
+
+func TestDeduplicationSkipsUnchangedValues(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend), WithDeduplication(),
+		WithRelativeAxes(RelWheel))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	for i := 0; i < 3; i++ {
+		err = gamepad.SetAxes(map[uint16]int32{AbsX: 100, AbsY: -100})
+		if err != nil {
+			t.Fatalf("Failed to set the axes: %v", err)
+		}
+		err = gamepad.SetButtons(map[int]bool{ButtonSouth: true})
+		if err != nil {
+			t.Fatalf("Failed to set the buttons: %v", err)
+		}
+	}
+	events := takeWrites(backend)
+	if len(events) != 5 {
+		t.Fatalf("Expected the axes and the button to be written once, got %v", events)
+	}
+
+	err = gamepad.SetAxes(map[uint16]int32{AbsX: 100, AbsY: 50})
+	if err != nil {
+		t.Fatalf("Failed to set the axes: %v", err)
+	}
+	events = takeWrites(backend)
+	expected := []inputEvent{{Type: evAbs, Code: AbsY, Value: 50}, syncEvent()}
+	if len(events) != len(expected) || events[0] != expected[0] || events[1] != expected[1] {
+		t.Fatalf("Expected only the changed axis to be written, got %v", events)
+	}
+
+	// relative axes are written every time
+	for i := 0; i < 2; i++ {
+		err = gamepad.MoveAxis(RelWheel, 1)
+		if err != nil {
+			t.Fatalf("Failed to move the wheel: %v", err)
+		}
+	}
+	if events = takeWrites(backend); len(events) != 4 {
+		t.Fatalf("Expected both wheel movements to be written, got %v", events)
+	}
+}
+
+func TestDeduplicationIsOptIn(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	takeWrites(backend)
+	for i := 0; i < 2; i++ {
+		err = gamepad.SetAxes(map[uint16]int32{AbsX: 100})
+		if err != nil {
+			t.Fatalf("Failed to set the axes: %v", err)
+		}
+	}
+	if events := takeWrites(backend); len(events) != 4 {
+		t.Fatalf("Expected every frame to be written, got %v", events)
+	}
+}
+
+func TestDeduplicationRespectsPendingMovements(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend), WithDeduplication(),
+		WithRateLimit(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	takeWrites(backend)
+	// the first frame is written right away, while the others are coalesced into a pending frame, which moves the axis
+	// back to the value that has been written
+	for _, value := range []int32{0, 500, 0} {
+		err = gamepad.SetAxes(map[uint16]int32{AbsX: value})
+		if err != nil {
+			t.Fatalf("Failed to set the axes: %v", err)
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for gamepad.Stats().Frames < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if value := gamepad.State().Axes[AbsX]; gamepad.Stats().Frames != 2 || value != 0 {
+		t.Fatalf("Expected the pending frame to move the axis back to 0, got %d (%v)", value, takeWrites(backend))
+	}
+}
+
+func TestDeduplicationPassesRepeats(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithDeduplication())
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	takeWrites(backend)
+	for _, value := range []int32{1, 1, 2, 2, 1} {
+		err = kbd.EmitEvent(EvKey, KeyA, value)
+		if err != nil {
+			t.Fatalf("Failed to emit the key: %v", err)
+		}
+	}
+	var values []int32
+	for _, iev := range takeWrites(backend) {
+		if iev.Type == evKey {
+			values = append(values, iev.Value)
+		}
+	}
+	// the second press is skipped, while the press after the repeats is written, as they have changed the value
+	expected := []int32{1, 2, 2, 1}
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("Expected the key values %v, got %v", expected, values)
+	}
+}
@@ -0,0 +1,111 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// A Device is a virtual input device. All devices of this package are devices, which allows to manage devices of
+// different kinds uniformly. Note that for devices that consist of several input devices (like the DualShock 4),
+// these methods refer to the main device.
+type Device interface {
+	// Name will return the name the device has been created with.
+	Name() string
+
+	// Path will return the path of the uinput device the device has been created with (e.g. /dev/uinput).
+	Path() string
+
+	// Fd will return the file descriptor of the device. The descriptor is owned by the device and becomes invalid
+	// once the device is closed.
+	Fd() uintptr
+
+	// EmitEvent will emit a raw event, followed by a sync event. This allows to emit events that are not covered by
+	// the API of the device.
+	EmitEvent(evType uint16, code uint16, value int32) error
+
+	EventEmitter
+
+	// Sync will issue a sync event, which reports all pending events to consumers. This is only needed for
+	// devices that have been created using WithManualSync.
+	Sync() error
+
+	// SysPath will return the sysfs directory of the device (e.g. /sys/devices/virtual/input/input23).
+	SysPath() (string, error)
+
+	// EventPath will return the event node of the device (e.g. /dev/input/event7), which allows to read the emitted
+	// events.
+	EventPath() (string, error)
+
+	// WaitReady will block until the event node of the device exists and may be opened, or until the context is done.
+	// Events that are sent before may be missed by consumers.
+	WaitReady(ctx context.Context) error
+
+	// Stats will return the counters of the device, like the number of events that have been written.
+	Stats() Stats
+
+	// State will return a snapshot of the keys, buttons and axes of the device, as they have been written last.
+	State() State
+
+	// ResetAll will release all keys and buttons, lift all contacts and move all axes to their rest position (like
+	// centered sticks and released triggers) within a single frame. Positions (like the pointer of absolute mice) are
+	// left as they are. The state that is kept by the methods of the device (like held sticky modifiers) is not reset.
+	// Close resets the device as well before it is destroyed, so that consumers are not stuck with held buttons.
+	ResetAll() error
+
+	// Disconnect will destroy the device as if it had been unplugged, while keeping its configuration for Reconnect.
+	// Events that are emitted while the device is disconnected fail with ErrDisconnected.
+	Disconnect() error
+
+	// Reconnect will create a disconnected device again, using the same identity and capabilities, as if it had been
+	// plugged in again.
+	Reconnect() error
+
+	io.Closer
+}
+
+// devicePath returns the path the device file has been opened with.
+func devicePath(deviceFile *uinputDevice) string {
+	return deviceFile.file.Name()
+}
+
+// deviceFd returns the file descriptor of the device.
+func deviceFd(deviceFile *uinputDevice) uintptr {
+	return deviceFile.file.Fd()
+}
+
+// disconnectDevice destroys the device, which may be created again using reconnectDevice.
+func disconnectDevice(deviceFile *uinputDevice) error {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	r, ok := deviceFile.file.(*recreatableFile)
+	if !ok {
+		return errorf(ErrInvalidArgument, "failed to disconnect device. The device does not support reconnecting")
+	}
+	if deviceFile.limiter != nil {
+		// pending axis movements are written before the device is gone
+		_ = deviceFile.limiter.flush(deviceFile, nil)
+	}
+	return r.disconnect()
+}
+
+// reconnectDevice creates a disconnected device again.
+func reconnectDevice(deviceFile *uinputDevice) error {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	r, ok := deviceFile.file.(*recreatableFile)
+	if !ok {
+		return errorf(ErrInvalidArgument, "failed to reconnect device. The device does not support reconnecting")
+	}
+	r.mu.Lock()
+	disconnected := r.disconnected
+	r.mu.Unlock()
+	if !disconnected {
+		return errorf(ErrInvalidArgument, "failed to reconnect device. The device is connected")
+	}
+	err := r.recreate()
+	if err != nil {
+		return fmt.Errorf("failed to reconnect device: %w", err)
+	}
+	return nil
+}
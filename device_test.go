@@ -0,0 +1,54 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// all devices are expected to implement the common Device interface
+var (
+	_ Device = Keyboard(nil)
+	_ Device = Mouse(nil)
+	_ Device = TouchPad(nil)
+	_ Device = TouchScreen(nil)
+	_ Device = Dial(nil)
+	_ Device = Gamepad(nil)
+	_ Device = DualShock4(nil)
+	_ Device = AbsoluteMouse(nil)
+	_ Device = Pen(nil)
+	_ Device = SurfaceDial(nil)
+	_ Device = SwitchDevice(nil)
+	_ Device = PowerKeys(nil)
+	_ Device = MediaRemote(nil)
+	_ Device = Accelerometer(nil)
+	_ Device = SpaceMouse(nil)
+	_ Device = RacingWheel(nil)
+	_ Device = FlightStick(nil)
+	_ Device = FootPedal(nil)
+	_ Device = Numpad(nil)
+	_ Device = KeyboardMouse(nil)
+	_ Device = Trackball(nil)
+	_ Device = Trackpoint(nil)
+	_ Device = ClickPad(nil)
+)
+
+func TestDeviceMetadata(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-device-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dev := vKeyboard{name: []byte("Test Keyboard"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	if dev.Name() != "Test Keyboard" {
+		t.Fatalf("Expected: %s\nActual: %s", "Test Keyboard", dev.Name())
+	}
+	if dev.Path() != file.Name() {
+		t.Fatalf("Expected: %s\nActual: %s", file.Name(), dev.Path())
+	}
+	if dev.Fd() == ^uintptr(0) {
+		t.Fatalf("Expected a valid file descriptor")
+	}
+}
@@ -1,9 +1,8 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"os"
 	"syscall"
 )
 
@@ -13,17 +12,17 @@ type Dial interface {
 	// Turn will simulate a dial movement.
 	Turn(delta int32) error
 
-	io.Closer
+	Device
 }
 
 type vDial struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *uinputDevice
 }
 
 // CreateDial will create a new dial input device. A dial is a device that can trigger rotation events.
-func CreateDial(path string, name []byte) (Dial, error) {
-	err := validateDevicePath(path)
+func CreateDial(path string, name []byte, opts ...Option) (Dial, error) {
+	err := validateDevicePath(path, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +31,11 @@ func CreateDial(path string, name []byte) (Dial, error) {
 		return nil, err
 	}
 
-	fd, err := createDial(path, name)
+	cfg, err := applyOptions("CreateDial", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createDial(path, name, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -45,28 +48,86 @@ func (vRel vDial) Turn(delta int32) error {
 	return sendDialEvent(vRel.deviceFile, delta)
 }
 
+func (vRel vDial) Name() string {
+	return string(vRel.name)
+}
+
+func (vRel vDial) Path() string {
+	return devicePath(vRel.deviceFile)
+}
+
+func (vRel vDial) Fd() uintptr {
+	return deviceFd(vRel.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vRel vDial) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vRel.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vRel vDial) EmitEvents(events []InputEvent) error {
+	return emitEvents(vRel.deviceFile, events)
+}
+
+func (vRel vDial) Sync() error {
+	return writeSyncEvent(vRel.deviceFile)
+}
+
+func (vRel vDial) SysPath() (string, error) {
+	return sysPath(vRel.deviceFile)
+}
+
+func (vRel vDial) EventPath() (string, error) {
+	return eventPath(vRel.deviceFile)
+}
+
+func (vRel vDial) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vRel.deviceFile)
+}
+
+func (vRel vDial) Stats() Stats {
+	return deviceStats(vRel.deviceFile)
+}
+
+func (vRel vDial) State() State {
+	return deviceState(vRel.deviceFile)
+}
+
+func (vRel vDial) ResetAll() error {
+	return resetDevice(vRel.deviceFile)
+}
+
+func (vRel vDial) Disconnect() error {
+	return disconnectDevice(vRel.deviceFile)
+}
+
+func (vRel vDial) Reconnect() error {
+	return reconnectDevice(vRel.deviceFile)
+}
+
 // Close closes the device and releases the device.
 func (vRel vDial) Close() error {
 	return closeDevice(vRel.deviceFile)
 }
 
-func createDial(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createDial(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not create dial input device: %v", err)
+		return nil, fmt.Errorf("could not create dial input device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evRel))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial input device: %v", err)
+		return nil, fmt.Errorf("failed to register dial input device: %w", err)
 	}
 
 	// register dial events
 	err = ioctl(deviceFile, uiSetRelBit, uintptr(relDial))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial events: %v", err)
+		return nil, fmt.Errorf("failed to register dial events: %w", err)
 	}
 
 	return createUsbDevice(deviceFile,
@@ -76,25 +137,16 @@ func createDial(path string, name []byte) (fd *os.File, err error) {
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0816,
-				Version: 1}})
+				Version: 1}},
+		cfg)
 }
 
-func sendDialEvent(deviceFile *os.File, delta int32) error {
+func sendDialEvent(deviceFile *uinputDevice, delta int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
 		Code:  relDial,
 		Value: delta}
 
-	buf, err := inputEventToBuffer(iev)
-	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
-	}
-
-	_, err = deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
-	}
-
-	return syncEvents(deviceFile)
+	return sendEvents(deviceFile, []inputEvent{iev})
 }
@@ -37,12 +37,9 @@ func TestDialFailsIfDeviceIsClosed(t *testing.T) {
 	}
 }
 
-func TestDialCreationFailsOnEmptyPath(t *testing.T) {
-	expected := "device path must not be empty"
-	_, err := CreateDial("", []byte("DialDevice"))
-	if err.Error() != expected {
-		t.Fatalf("Expected: %s\nActual: %s", expected, err)
-	}
+func TestDialCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateDial("", []byte("DialDevice"))
+	assertPathDiscovered(t, device, err)
 }
 
 func TestDialCreationFailsOnNonExistentPathName(t *testing.T) {
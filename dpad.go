@@ -0,0 +1,63 @@
+package uinput
+
+// the d-pad buttons of gamepads created using WithDpadButtons, in the order up, down, left and right
+var dpadButtons = []int{BtnDpadUp, BtnDpadDown, BtnDpadLeft, BtnDpadRight}
+
+// WithDpadButtons makes gamepads report the d-pad as the buttons BTN_DPAD_UP, BTN_DPAD_DOWN, BTN_DPAD_LEFT and
+// BTN_DPAD_RIGHT instead of the hat axes ABS_HAT0X and ABS_HAT0Y. Both conventions are used by kernel drivers (xpad
+// reports the d-pad of dance pads as buttons, for example), so consumers are expected to support either of them.
+// SetDpad and SetHat work with both layouts. The option applies to gamepads created using CreateGamepad and
+// CreateGamepadWithForceFeedback only.
+func WithDpadButtons() Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optDpadButtons)
+		cfg.dpadButtons = true
+	}
+}
+
+// SetDpad will press the given directions of the d-pad and release all others within a single frame. Opposing
+// directions (like up and down) cancel each other out if the d-pad is reported as hat axes.
+func (vg vGamepad) SetDpad(up, down, left, right bool) error {
+	if vg.dpadButtons {
+		events := make([]inputEvent, 0, len(dpadButtons))
+		for i, pressed := range []bool{up, down, left, right} {
+			state := int32(btnStateReleased)
+			if pressed {
+				state = btnStatePressed
+			}
+			events = append(events, inputEvent{Type: evKey, Code: uint16(dpadButtons[i]), Value: state})
+		}
+		return sendEvents(vg.deviceFile, events)
+	}
+
+	var x, y int32
+	if left {
+		x--
+	}
+	if right {
+		x++
+	}
+	if up {
+		y--
+	}
+	if down {
+		y++
+	}
+	return sendEvents(vg.deviceFile, []inputEvent{
+		{Type: evAbs, Code: absHat0X, Value: x},
+		{Type: evAbs, Code: absHat0Y, Value: y},
+	})
+}
+
+// SetHat will move the hat switch to the given direction. Both hat axes (or all d-pad buttons) are updated within a
+// single frame.
+func (vg vGamepad) SetHat(direction HatDirection) error {
+	if !vg.dpadButtons {
+		return sendHatEvent(vg.deviceFile, absHat0X, direction)
+	}
+	x, y, err := hatValues(direction)
+	if err != nil {
+		return err
+	}
+	return vg.SetDpad(y < 0, y > 0, x < 0, x > 0)
+}
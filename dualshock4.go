@@ -0,0 +1,488 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the layout and identity of the DualShock 4 (v2) controller, as reported by the hid-sony kernel driver
+const (
+	ds4Vendor          = 0x054c
+	ds4Product         = 0x09cc
+	ds4Version         = 0x8111
+	ds4StickMax        = 255
+	ds4TouchpadMaxX    = 1919
+	ds4TouchpadMaxY    = 941
+	ds4MotionRange     = 32767
+	ds4AccelResolution = 8192 // units per g
+	ds4GyroResolution  = 1024 // units per degree per second
+)
+
+var ds4Buttons = []int{ButtonSouth, ButtonEast, ButtonNorth, ButtonWest, ButtonBumperLeft, ButtonBumperRight,
+	ButtonTriggerLeft, ButtonTriggerRight, ButtonSelect, ButtonStart, ButtonMode, ButtonThumbLeft, ButtonThumbRight}
+
+// A DualShock4 is a gamepad that mimics a Sony DualShock 4 controller, including its touchpad button and motion
+// sensors. Just like the kernel driver does for the real controller, the touchpad and the motion sensors are exposed
+// as separate input devices (with " Touchpad" and " Motion Sensors" appended to the name), which allows PS4-aware
+// software to map the controller automatically. The methods of Device (like EmitEvent) refer to the controller device,
+// as opposed to the touchpad or motion sensor devices.
+type DualShock4 interface {
+	// ButtonPress will cause the button to be pressed and immediately released.
+	ButtonPress(button int) error
+
+	// ButtonDown will send a button-press event to an existing controller device.
+	// Note that the button will be "held down" until "ButtonUp" is called.
+	ButtonDown(button int) error
+
+	// ButtonUp will send a button-release event to an existing controller device.
+	ButtonUp(button int) error
+
+	// LeftStickMove will move the left stick to the given position. Both values are expected to be in the
+	// range of -1 to 1, where (0, 0) is the neutral position.
+	LeftStickMove(x, y float32) error
+
+	// RightStickMove will move the right stick to the given position. Both values are expected to be in the
+	// range of -1 to 1, where (0, 0) is the neutral position.
+	RightStickMove(x, y float32) error
+
+	// SetLeftTrigger will move the left analog trigger (L2) to the given position, which is expected to be in the range
+	// of 0 (released) to 1 (fully pressed). Just like the real controller, the digital trigger button
+	// (ButtonTriggerLeft) is reported as pressed as long as the trigger is not released.
+	SetLeftTrigger(value float64) error
+
+	// SetRightTrigger will move the right analog trigger (R2) to the given position, just like SetLeftTrigger.
+	SetRightTrigger(value float64) error
+
+	// SetHat will move the hat switch (d-pad) to the given direction. Use HatCenter to release it.
+	SetHat(direction HatDirection) error
+
+	// TouchpadPress will press the touchpad button. Note that the button will not be released until
+	// TouchpadRelease is invoked.
+	TouchpadPress() error
+
+	// TouchpadRelease will release the touchpad button.
+	TouchpadRelease() error
+
+	// SetAcceleration will report the given acceleration (in g) on the motion sensors.
+	SetAcceleration(x, y, z float64) error
+
+	// SetAngularVelocity will report the given angular velocity (in degrees per second) on the motion sensors.
+	SetAngularVelocity(x, y, z float64) error
+
+	Device
+}
+
+// SetHat will move the hat switch to the given direction. Both hat axes are updated within a single frame.
+func (vds vDualShock4) SetHat(direction HatDirection) error {
+	return sendHatEvent(vds.deviceFile, absHat0X, direction)
+}
+
+// SetLeftTrigger will move the left analog trigger along with the digital trigger button within a single frame.
+func (vds vDualShock4) SetLeftTrigger(value float64) error {
+	return sendTriggerEvent(vds.deviceFile, absZ, ds4StickMax, value, ButtonTriggerLeft)
+}
+
+// SetRightTrigger will move the right analog trigger along with the digital trigger button within a single frame.
+func (vds vDualShock4) SetRightTrigger(value float64) error {
+	return sendTriggerEvent(vds.deviceFile, absRZ, ds4StickMax, value, ButtonTriggerRight)
+}
+
+type vDualShock4 struct {
+	name         []byte
+	deviceFile   *uinputDevice
+	touchpadFile *uinputDevice
+	motionFile   *uinputDevice
+}
+
+// CreateDualShock4 will create a new gamepad that uses the layout and identity of a DualShock 4 controller.
+func CreateDualShock4(path string, name []byte, opts ...Option) (DualShock4, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	touchpadName := append(append([]byte{}, name...), " Touchpad"...)
+	motionName := append(append([]byte{}, name...), " Motion Sensors"...)
+	for _, n := range [][]byte{name, touchpadName, motionName} {
+		err = validateUinputName(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := applyOptions("CreateDualShock4", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createDualShock4(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+	// axis tunings, properties, additional axes and event handlers refer to the controller itself, which is why they are not passed to the sub devices
+	subCfg := cfg
+	subCfg.tunings = nil
+	subCfg.props = nil
+	subCfg.relAxes = nil
+	subCfg.handlers = eventHandlers{}
+	subCfg.readCodes = nil
+	touchpadFd, err := createDualShock4Touchpad(path, touchpadName, subCfg)
+	if err != nil {
+		closeDevice(fd)
+		return nil, err
+	}
+	motionFd, err := createDualShock4Motion(path, motionName, subCfg)
+	if err != nil {
+		closeDevice(touchpadFd)
+		closeDevice(fd)
+		return nil, err
+	}
+
+	return vDualShock4{name: name, deviceFile: fd, touchpadFile: touchpadFd, motionFile: motionFd}, nil
+}
+
+// ButtonPress will issue a single button press (push down a button and then immediately release it).
+func (vds vDualShock4) ButtonPress(button int) error {
+	if !codeSupported(ds4Buttons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonPress. Code %d is not a DualShock 4 button", button)
+	}
+	err := sendBtnEvent(vds.deviceFile, []int{button}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the ButtonDown event: %w", err)
+	}
+
+	return sendBtnEvent(vds.deviceFile, []int{button}, btnStateReleased)
+}
+
+// ButtonDown will press the given button. Note that the button will remain pressed until "ButtonUp" is called.
+func (vds vDualShock4) ButtonDown(button int) error {
+	if !codeSupported(ds4Buttons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a DualShock 4 button", button)
+	}
+	return sendBtnEvent(vds.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vds vDualShock4) ButtonUp(button int) error {
+	if !codeSupported(ds4Buttons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a DualShock 4 button", button)
+	}
+	return sendBtnEvent(vds.deviceFile, []int{button}, btnStateReleased)
+}
+
+// LeftStickMove will move the left stick to the given position. Both axes are updated within a single frame.
+func (vds vDualShock4) LeftStickMove(x, y float32) error {
+	return sendDualShock4StickEvent(vds.deviceFile, absX, absY, x, y)
+}
+
+// RightStickMove will move the right stick to the given position. Both axes are updated within a single frame.
+func (vds vDualShock4) RightStickMove(x, y float32) error {
+	return sendDualShock4StickEvent(vds.deviceFile, absRX, absRY, x, y)
+}
+
+// TouchpadPress will press the touchpad button.
+func (vds vDualShock4) TouchpadPress() error {
+	return sendBtnEvent(vds.touchpadFile, []int{evBtnLeft}, btnStatePressed)
+}
+
+// TouchpadRelease will release the touchpad button.
+func (vds vDualShock4) TouchpadRelease() error {
+	return sendBtnEvent(vds.touchpadFile, []int{evBtnLeft}, btnStateReleased)
+}
+
+// SetAcceleration will report the given acceleration (in g) on the motion sensors.
+func (vds vDualShock4) SetAcceleration(x, y, z float64) error {
+	return sendMotionEvent(vds.motionFile, absX, ds4AccelResolution, ds4MotionRange, x, y, z)
+}
+
+// SetAngularVelocity will report the given angular velocity (in degrees per second) on the motion sensors.
+func (vds vDualShock4) SetAngularVelocity(x, y, z float64) error {
+	return sendMotionEvent(vds.motionFile, absRX, ds4GyroResolution, ds4MotionRange, x, y, z)
+}
+
+func (vds vDualShock4) Name() string {
+	return string(vds.name)
+}
+
+func (vds vDualShock4) Path() string {
+	return devicePath(vds.deviceFile)
+}
+
+func (vds vDualShock4) Fd() uintptr {
+	return deviceFd(vds.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vds vDualShock4) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vds.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vds vDualShock4) EmitEvents(events []InputEvent) error {
+	return emitEvents(vds.deviceFile, events)
+}
+
+func (vds vDualShock4) Sync() error {
+	return writeSyncEvent(vds.deviceFile)
+}
+
+func (vds vDualShock4) SysPath() (string, error) {
+	return sysPath(vds.deviceFile)
+}
+
+func (vds vDualShock4) EventPath() (string, error) {
+	return eventPath(vds.deviceFile)
+}
+
+func (vds vDualShock4) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vds.deviceFile)
+}
+
+func (vds vDualShock4) Stats() Stats {
+	return deviceStats(vds.deviceFile)
+}
+
+// State will return the state of the controller itself. The touchpad and the motion sensors are left out, as their axes
+// share the codes of the sticks.
+func (vds vDualShock4) State() State {
+	return deviceState(vds.deviceFile)
+}
+
+// ResetAll will reset all underlying devices, which lifts all fingers from the touchpad as well.
+func (vds vDualShock4) ResetAll() error {
+	for _, deviceFile := range []*uinputDevice{vds.deviceFile, vds.touchpadFile, vds.motionFile} {
+		err := resetDevice(deviceFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Disconnect will disconnect all underlying devices, just like unplugging the controller would.
+func (vds vDualShock4) Disconnect() error {
+	for _, deviceFile := range []*uinputDevice{vds.motionFile, vds.touchpadFile, vds.deviceFile} {
+		err := disconnectDevice(deviceFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reconnect will create all underlying devices again.
+func (vds vDualShock4) Reconnect() error {
+	for _, deviceFile := range []*uinputDevice{vds.deviceFile, vds.touchpadFile, vds.motionFile} {
+		err := reconnectDevice(deviceFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close will close all underlying devices and free resources.
+func (vds vDualShock4) Close() error {
+	errMotion := closeDevice(vds.motionFile)
+	errTouchpad := closeDevice(vds.touchpadFile)
+	err := closeDevice(vds.deviceFile)
+	if err != nil {
+		return err
+	}
+	if errTouchpad != nil {
+		return errTouchpad
+	}
+	return errMotion
+}
+
+func createDualShock4(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create DualShock 4 input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+
+	for _, event := range ds4Buttons {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	for _, event := range []int{absX, absY, absZ, absRX, absRY, absRZ, absHat0X, absHat0Y} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	var absMin, absMax [absSize]int32
+	for _, axis := range []int{absX, absY, absZ, absRX, absRY, absRZ} {
+		absMax[axis] = ds4StickMax
+	}
+	for _, hat := range []int{absHat0X, absHat0Y} {
+		absMin[hat] = -1
+		absMax[hat] = 1
+	}
+
+	fd, err = createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     inputID{Bustype: busUsb, Vendor: ds4Vendor, Product: ds4Product, Version: ds4Version},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	// the sticks rest in the middle of their range
+	fd.state.setRest(ds4StickMax/2, absX, absY, absRX, absRY)
+	return fd, nil
+}
+
+func createDualShock4Touchpad(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create DualShock 4 touchpad device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+
+	for _, event := range []int{evBtnLeft, evBtnTouch, evBtnToolFinger} {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	for _, event := range []int{absX, absY} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	for _, prop := range []int{inputPropPointer, inputPropButtonpad} {
+		err = ioctl(deviceFile, uiSetPropBit, uintptr(prop))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register input property %v: %w", prop, err)
+		}
+	}
+
+	var absMax [absSize]int32
+	absMax[absX] = ds4TouchpadMaxX
+	absMax[absY] = ds4TouchpadMaxY
+
+	fd, err = createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     inputID{Bustype: busUsb, Vendor: ds4Vendor, Product: ds4Product, Version: ds4Version},
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	fd.state.setPositions(absX, absY)
+	return fd, nil
+}
+
+func createDualShock4Motion(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create DualShock 4 motion sensor device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	for _, event := range []int{absX, absY, absZ, absRX, absRY, absRZ} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropAccelerometer))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register accelerometer input property: %w", err)
+	}
+
+	var absMin, absMax [absSize]int32
+	for _, axis := range []int{absX, absY, absZ, absRX, absRY, absRZ} {
+		absMin[axis] = -ds4MotionRange
+		absMax[axis] = ds4MotionRange
+	}
+	for _, axis := range []uint16{absX, absY, absZ} {
+		cfg = cfg.withDefaultTuning(axis, AxisTuning{Resolution: ds4AccelResolution})
+	}
+	for _, axis := range []uint16{absRX, absRY, absRZ} {
+		cfg = cfg.withDefaultTuning(axis, AxisTuning{Resolution: ds4GyroResolution})
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     inputID{Bustype: busUsb, Vendor: ds4Vendor, Product: ds4Product, Version: ds4Version},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+}
+
+func sendDualShock4StickEvent(deviceFile *uinputDevice, codeX uint16, codeY uint16, x float32, y float32) error {
+	if err := assertNormalized(x); err != nil {
+		return err
+	}
+	if err := assertNormalized(y); err != nil {
+		return err
+	}
+
+	return sendEvents(deviceFile, []inputEvent{
+		{Type: evAbs, Code: codeX, Value: int32((x + 1) / 2 * ds4StickMax)},
+		{Type: evAbs, Code: codeY, Value: int32((y + 1) / 2 * ds4StickMax)},
+	})
+}
+
+// sendMotionEvent reports the three given values on the consecutive axes starting at firstCode, scaled by the
+// resolution of the sensor. Values exceeding the sensor's range (-max to max) are capped.
+func sendMotionEvent(deviceFile *uinputDevice, firstCode uint16, resolution float64, max float64, x, y, z float64) error {
+	var events []inputEvent
+	for i, value := range []float64{x, y, z} {
+		scaled := value * resolution
+		if scaled > max {
+			scaled = max
+		} else if scaled < -max {
+			scaled = -max
+		}
+		events = append(events, inputEvent{Type: evAbs, Code: firstCode + uint16(i), Value: int32(scaled)})
+	}
+	return sendEvents(deviceFile, events)
+}
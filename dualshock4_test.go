@@ -0,0 +1,150 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicDualShock4Events(t *testing.T) {
+	dev, err := CreateDualShock4("/dev/uinput", []byte("Test DualShock4"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual controller. Last error was: %s\n", err)
+	}
+
+	err = dev.ButtonPress(ButtonTriggerLeft)
+	if err != nil {
+		t.Fatalf("Failed to send button press. Last error was: %s\n", err)
+	}
+
+	err = dev.LeftStickMove(1, -1)
+	if err != nil {
+		t.Fatalf("Failed to move left stick. Last error was: %s\n", err)
+	}
+
+	err = dev.RightStickMove(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to move right stick. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchpadPress()
+	if err != nil {
+		t.Fatalf("Failed to press touchpad button. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchpadRelease()
+	if err != nil {
+		t.Fatalf("Failed to release touchpad button. Last error was: %s\n", err)
+	}
+
+	err = dev.SetAcceleration(0, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to set acceleration. Last error was: %s\n", err)
+	}
+
+	err = dev.SetAngularVelocity(90, 0, -90)
+	if err != nil {
+		t.Fatalf("Failed to set angular velocity. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestDualShock4CreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateDualShock4("", []byte("DualShock4Device"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestDualShock4CreationFailsOnNonExistentPathName(t *testing.T) {
+	path := "/some/bogus/path"
+	_, err := CreateDualShock4(path, []byte("DualShock4Device"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	}
+}
+
+func TestDualShock4CreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-dualshock4-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateDualShock4(file.Name(), []byte("DualShock4Device"))
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestDualShock4CreationFailsIfSubDeviceNameIsTooLong(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-dualshock4-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfew"
+	expected := fmt.Sprintf("device name %s Motion Sensors is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err = CreateDualShock4(file.Name(), []byte(name))
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestDualShock4UnsupportedButtonFails(t *testing.T) {
+	dev, err := CreateDualShock4("/dev/uinput", []byte("Test DualShock4"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual controller. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.ButtonPress(KeyA); err == nil {
+		t.Fatalf("Expected button press to fail due to unsupported button, but got no error.")
+	}
+}
+
+func TestDualShock4TouchpadPressFailsIfDeviceIsClosed(t *testing.T) {
+	dev, err := CreateDualShock4("/dev/uinput", []byte("Test DualShock4"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual controller. Last error was: %s\n", err)
+	}
+	dev.Close()
+
+	err = dev.TouchpadPress()
+	if err == nil {
+		t.Fatalf("Expected TouchpadPress to fail, but no error was returned.")
+	}
+}
+
+func TestDualShock4TriggersPressTriggerButtons(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-ds4-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	vds := vDualShock4{name: []byte("Test DualShock4"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for _, value := range []float64{0.5, 0} {
+		if err := vds.SetLeftTrigger(value); err != nil {
+			t.Fatalf("Failed to move trigger: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStatePressed},
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+	if events := readEvents(t, file.Name(), evAbs); len(events) != 2 || events[0].Value != 128 {
+		t.Fatalf("Expected the trigger to be reported at 128, got %+v", events)
+	}
+}
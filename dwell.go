@@ -0,0 +1,105 @@
+package uinput
+
+import (
+	"time"
+	"unsafe"
+)
+
+// WithDwellClick makes a pointer device (like a mouse or an absolute mouse) click on its own once the pointer rests,
+// which is what dwell clicking in assistive technology does: after the pointer has been moved, a left click is issued
+// as soon as it stays within the given radius (in units of the pointer axes) for the given duration. Further clicks
+// require the pointer to leave the radius again. Only movements emitted by the device itself are taken into
+// account, as the device does not know about other pointers.
+func WithDwellClick(radius int32, dwell time.Duration) Option {
+	return func(cfg *deviceConfig) {
+		cfg.dwell = &dwellClick{radius: radius, delay: dwell}
+	}
+}
+
+// dwellClick is the configuration of the dwell click engine of a device
+type dwellClick struct {
+	radius int32
+	delay  time.Duration
+}
+
+// A dwellEngine follows the pointer of a device and clicks once the pointer rests. All methods expect the lock of the
+// device to be held.
+type dwellEngine struct {
+	radius int64
+	delay  time.Duration
+
+	x, y             int64 // the position of the pointer, relative to the creation of the device for relative axes
+	anchorX, anchorY int64 // the position at which the pointer started to rest
+	clicked          bool  // whether the pointer clicked at the anchor already
+	timer            *time.Timer
+}
+
+// newDwellEngine returns the dwell click engine of a device with the given config, if any.
+func newDwellEngine(cfg deviceConfig) *dwellEngine {
+	if cfg.dwell == nil || cfg.dwell.delay <= 0 {
+		return nil
+	}
+	return &dwellEngine{radius: int64(cfg.dwell.radius), delay: cfg.dwell.delay}
+}
+
+// observe follows the pointer movements of the written events. Once the pointer leaves the radius around the
+// position it started to rest at, the click is rescheduled.
+func (e *dwellEngine) observe(deviceFile *uinputDevice, written []byte) {
+	moved := false
+	for i := 0; i+inputEventSize <= len(written); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&written[i]))
+		switch {
+		case iev.Type == evRel && iev.Code == relX:
+			e.x += int64(iev.Value)
+		case iev.Type == evRel && iev.Code == relY:
+			e.y += int64(iev.Value)
+		case iev.Type == evAbs && iev.Code == absX:
+			e.x = int64(iev.Value)
+		case iev.Type == evAbs && iev.Code == absY:
+			e.y = int64(iev.Value)
+		default:
+			continue
+		}
+		moved = true
+	}
+	if !moved {
+		return
+	}
+	dx, dy := e.x-e.anchorX, e.y-e.anchorY
+	if dx*dx+dy*dy <= e.radius*e.radius && (e.timer != nil || e.clicked) {
+		return
+	}
+	e.anchorX, e.anchorY, e.clicked = e.x, e.y, false
+	e.stop()
+	var timer *time.Timer
+	timer = time.AfterFunc(e.delay, func() {
+		deviceFile.mu.Lock()
+		defer deviceFile.mu.Unlock()
+		if e.timer != timer {
+			// the pointer has moved on in the meantime
+			return
+		}
+		e.timer, e.clicked = nil, true
+		e.click(deviceFile)
+	})
+	e.timer = timer
+}
+
+// click issues a left click. Errors are of no concern, as there is no caller to return them to. They are reported to
+// the OnWriteError hook of the device nonetheless.
+func (e *dwellEngine) click(deviceFile *uinputDevice) {
+	for _, value := range []int32{btnStatePressed, btnStateReleased} {
+		buf := appendInputEvent(nil, inputEvent{Type: evKey, Code: evBtnLeft, Value: value})
+		if writeFrame(deviceFile, buf) != nil {
+			return
+		}
+	}
+}
+
+// stop cancels a scheduled click.
+func (e *dwellEngine) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+}
@@ -0,0 +1,70 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// buttonEvents returns the BTN_LEFT events that have been written to the file.
+func buttonEvents(f *mockFile) []int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	buf := f.writes.Bytes()
+	var values []int32
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		if iev.Type == evKey && iev.Code == evBtnLeft {
+			values = append(values, iev.Value)
+		}
+	}
+	return values
+}
+
+func TestDwellClickClicksOnceThePointerRests(t *testing.T) {
+	backend := &mockBackend{}
+	mouse, err := CreateMouse("mock", []byte("Test Mouse"), WithBackend(backend), WithDwellClick(5, 50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create the mouse: %v", err)
+	}
+	defer mouse.Close()
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+
+	err = mouse.Move(20, 0)
+	if err != nil {
+		t.Fatalf("Failed to move the mouse: %v", err)
+	}
+	// small movements within the radius do not delay the click
+	time.Sleep(10 * time.Millisecond)
+	err = mouse.Move(2, 2)
+	if err != nil {
+		t.Fatalf("Failed to move the mouse: %v", err)
+	}
+	if clicks := buttonEvents(backend.file); len(clicks) != 0 {
+		t.Fatalf("Expected no click while the pointer moves, got %v", clicks)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if clicks := buttonEvents(backend.file); len(clicks) != 2 || clicks[0] != btnStatePressed || clicks[1] != btnStateReleased {
+		t.Fatalf("Expected a single click once the pointer rests, got %v", clicks)
+	}
+
+	// the pointer needs to leave the radius before it may click again
+	err = mouse.Move(-1, 0)
+	if err != nil {
+		t.Fatalf("Failed to move the mouse: %v", err)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if clicks := buttonEvents(backend.file); len(clicks) != 2 {
+		t.Fatalf("Expected no further click within the radius, got %v", clicks)
+	}
+	err = mouse.Move(0, 10)
+	if err != nil {
+		t.Fatalf("Failed to move the mouse: %v", err)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if clicks := buttonEvents(backend.file); len(clicks) != 4 {
+		t.Fatalf("Expected a second click after leaving the radius, got %v", clicks)
+	}
+}
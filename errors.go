@@ -0,0 +1,60 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors below classify the errors returned by this package. They may be checked using errors.Is, which allows
+// callers to distinguish missing access rights from invalid arguments, for example. Errors caused by the kernel are
+// wrapped as well, so that errors.Is(err, syscall.EINVAL) works as expected.
+var (
+	// ErrInvalidPath is returned if the given path to the uinput device is empty.
+	ErrInvalidPath = errors.New("invalid device path")
+
+	// ErrInvalidName is returned if the given device name is empty or too long.
+	ErrInvalidName = errors.New("invalid device name")
+
+	// ErrPermissionDenied is returned if the uinput device may not be opened by the current user. This usually means
+	// that a udev rule is needed (see the README).
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrDeviceClosed is returned if events are sent to a device that has already been closed.
+	ErrDeviceClosed = errors.New("device closed")
+
+	// ErrDisconnected is returned if events are sent to a device that has been disconnected (see Device.Disconnect).
+	ErrDisconnected = errors.New("device disconnected")
+
+	// ErrInvalidArgument is returned if a method is called with an argument that is not valid for the device, like
+	// a key code that is out of range or an axis that has not been registered.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrUnsupportedPlatform is returned if a device is created on a platform other than Linux without a custom
+	// backend (see WithBackend).
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+)
+
+// kindError is an error that belongs to one of the error kinds above. The message is kept as is, which means that
+// the kind only shows when using errors.Is.
+type kindError struct {
+	kind error
+	err  error
+}
+
+// errorf formats an error just like fmt.Errorf (including support for %w), but additionally marks it as an error of
+// the given kind.
+func errorf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return errors.Unwrap(e.err)
+}
@@ -0,0 +1,75 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestErrorsCanBeClassified(t *testing.T) {
+	var dev uinputUserDev
+	_, tuningErr := newDeviceConfig([]Option{WithAxisTuning(absX, AxisTuning{})}).applyTunings(&dev)
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{validateDevicePath("", WithBackend(&mockBackend{})), ErrInvalidPath},
+		{validateUinputName(nil), ErrInvalidName},
+		{validateUinputName(make([]byte, uinputMaxNameSize+1)), ErrInvalidName},
+		{tuningErr, ErrInvalidArgument},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.err, test.kind) {
+			t.Fatalf("Expected error %q to be of kind %q", test.err, test.kind)
+		}
+	}
+}
+
+func TestErrorKindDoesNotAlterMessage(t *testing.T) {
+	err := errorf(ErrInvalidArgument, "failed to do something: %w", syscall.EINVAL)
+	expected := "failed to do something: invalid argument"
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Fatalf("Expected the cause of the error to be preserved")
+	}
+}
+
+func TestSendingEventsToClosedDeviceFailsWithErrDeviceClosed(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-closed-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	_ = file.Close()
+
+	err = sendEvents(dev, []inputEvent{{Type: evKey, Code: Key1, Value: btnStatePressed}})
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrDeviceClosed, err)
+	}
+
+	err = closeDevice(dev)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrDeviceClosed, err)
+	}
+}
+
+func TestWaitReadyFailsImmediatelyOnClosedDevice(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-ready-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	_ = file.Close()
+
+	err = waitReady(context.Background(), dev)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrDeviceClosed, err)
+	}
+}
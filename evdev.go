@@ -0,0 +1,160 @@
+package uinput
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// the ioctls of evdev devices, as computed by the _IOC macros of input.h
+const (
+	eviocgID   = 0x80084502
+	eviocgRab  = 0x40044590
+	eviocgName = 0x80004506 // EVIOCGNAME(len), the length is added in bits 16 to 29
+	eviocgProp = 0x80004509 // EVIOCGPROP(len)
+	eviocgBit  = 0x80004520 // EVIOCGBIT(ev, len), the event type is added to the number
+	eviocgAbs  = 0x80184540 // EVIOCGABS(abs), the axis is added to the number
+)
+
+// the number of codes of the event types that are cloned (see input-event-codes.h)
+var evdevCodeCounts = map[uint16]int{
+	evKey: 0x300,
+	evRel: relMax + 1,
+	evAbs: absSize,
+	EvMsc: 0x08,
+	EvSw:  swMax + 1,
+	EvLed: ledMax + 1,
+	EvSnd: sndMax + 1,
+}
+
+// the uinput ioctls that register the codes of the respective event types
+var evdevSetBits = map[uint16]uintptr{
+	evKey: uiSetKeyBit,
+	evRel: uiSetRelBit,
+	evAbs: uiSetAbsBit,
+	EvMsc: uiSetMscBit,
+	EvSw:  uiSetSwBit,
+	EvLed: uiSetLedBit,
+	EvSnd: uiSetSndBit,
+}
+
+// evdevCaps are the capabilities of an evdev device, which are needed in order to create a virtual clone of it
+type evdevCaps struct {
+	name  []byte
+	id    inputID
+	types []uint16
+	codes map[uint16][]uint16
+	abs   map[uint16]inputAbsinfo
+	props []uint16
+}
+
+// queryCaps queries the capabilities of the evdev device. Force feedback is not part of the capabilities, as effects
+// would have to be forwarded to the physical device.
+func queryCaps(f DeviceFile) (evdevCaps, error) {
+	caps := evdevCaps{codes: map[uint16][]uint16{}, abs: map[uint16]inputAbsinfo{}}
+
+	var name [uinputMaxNameSize]byte
+	err := ioctlPtr(f, eviocgName|uintptr(len(name)-1)<<16, unsafe.Pointer(&name[0]))
+	if err != nil {
+		return caps, fmt.Errorf("failed to query the name of %s: %w", f.Name(), err)
+	}
+	for i, b := range name {
+		if b == 0 {
+			caps.name = append([]byte(nil), name[:i]...)
+			break
+		}
+	}
+
+	err = ioctlPtr(f, eviocgID, unsafe.Pointer(&caps.id))
+	if err != nil {
+		return caps, fmt.Errorf("failed to query the ids of %s: %w", f.Name(), err)
+	}
+
+	types, err := queryBits(f, eviocgBit, EvFF+1)
+	if err != nil {
+		return caps, fmt.Errorf("failed to query the event types of %s: %w", f.Name(), err)
+	}
+	for _, evType := range types {
+		count, ok := evdevCodeCounts[evType]
+		if !ok {
+			// EV_REP needs no codes, while other types (like EV_FF) are not cloned
+			if evType == EvRep {
+				caps.types = append(caps.types, evType)
+			}
+			continue
+		}
+		codes, err := queryBits(f, eviocgBit+uintptr(evType), count)
+		if err != nil {
+			return caps, fmt.Errorf("failed to query the codes of event type %d of %s: %w", evType, f.Name(), err)
+		}
+		caps.types = append(caps.types, evType)
+		caps.codes[evType] = codes
+	}
+
+	for _, code := range caps.codes[evAbs] {
+		var info inputAbsinfo
+		err = ioctlPtr(f, eviocgAbs+uintptr(code), unsafe.Pointer(&info))
+		if err != nil {
+			return caps, fmt.Errorf("failed to query absolute axis %d of %s: %w", code, f.Name(), err)
+		}
+		caps.abs[code] = info
+	}
+
+	caps.props, err = queryBits(f, eviocgProp, inputPropMax+1)
+	if err != nil {
+		return caps, fmt.Errorf("failed to query the properties of %s: %w", f.Name(), err)
+	}
+	return caps, nil
+}
+
+// queryBits issues the given EVIOCG* request for a bitmask of count bits and returns the numbers of the set bits. The
+// request is expected to lack the length, which is added here.
+func queryBits(f DeviceFile, request uintptr, count int) ([]uint16, error) {
+	bits := make([]byte, (count+7)/8)
+	err := ioctlPtr(f, request|uintptr(len(bits))<<16, unsafe.Pointer(&bits[0]))
+	if err != nil {
+		return nil, err
+	}
+	var set []uint16
+	for i := 0; i < count; i++ {
+		if bits[i/8]&(1<<uint(i%8)) != 0 {
+			set = append(set, uint16(i))
+		}
+	}
+	return set, nil
+}
+
+// createClone creates a virtual device with the given capabilities.
+func createClone(path string, name []byte, caps evdevCaps, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create clone device: %w", err)
+	}
+
+	dev := uinputUserDev{Name: toUinputName(name), ID: caps.id}
+	for _, evType := range caps.types {
+		err = registerDevice(deviceFile, uintptr(evType))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register event type %d: %w", evType, err)
+		}
+		for _, code := range caps.codes[evType] {
+			err = ioctl(deviceFile, evdevSetBits[evType], uintptr(code))
+			if err != nil {
+				deviceFile.Close()
+				return nil, fmt.Errorf("failed to register code %d of event type %d: %w", code, evType, err)
+			}
+		}
+	}
+	for code, info := range caps.abs {
+		if info.Minimum == 0 && info.Maximum == 0 {
+			// unused axes report no range and are registered only, as axes without a range cannot be tuned (see applyTunings)
+			continue
+		}
+		dev.Absmin[code] = info.Minimum
+		dev.Absmax[code] = info.Maximum
+		cfg = cfg.withDefaultTuning(code, AxisTuning{Fuzz: info.Fuzz, Flat: info.Flat, Resolution: info.Resolution})
+	}
+	cfg.props = append(cfg.props[:len(cfg.props):len(cfg.props)], caps.props...)
+
+	return createUsbDevice(deviceFile, dev, cfg)
+}
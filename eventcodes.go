@@ -0,0 +1,1978 @@
+// Code generated by gencodes.go from input-event-codes.h; DO NOT EDIT.
+
+package uinput
+
+// the key codes as defined in input-event-codes.h
+const (
+	KeyHanguel                 = 122 // alias of KEY_HANGEUL
+	KeyScreenlock              = 152 // alias of KEY_COFFEE
+	KeyOk                      = 0x160
+	KeySelect                  = 0x161
+	KeyGoto                    = 0x162
+	KeyClear                   = 0x163
+	KeyPower2                  = 0x164
+	KeyOption                  = 0x165
+	KeyInfo                    = 0x166
+	KeyTime                    = 0x167
+	KeyVendor                  = 0x168
+	KeyArchive                 = 0x169
+	KeyProgram                 = 0x16a
+	KeyChannel                 = 0x16b
+	KeyFavorites               = 0x16c
+	KeyEpg                     = 0x16d
+	KeyPvr                     = 0x16e
+	KeyMhp                     = 0x16f
+	KeyLanguage                = 0x170
+	KeyTitle                   = 0x171
+	KeySubtitle                = 0x172
+	KeyAngle                   = 0x173
+	KeyFullScreen              = 0x174
+	KeyZoom                    = 0x174 // alias of KEY_FULL_SCREEN
+	KeyMode                    = 0x175
+	KeyKeyboard                = 0x176
+	KeyAspectRatio             = 0x177
+	KeyScreen                  = 0x177 // alias of KEY_ASPECT_RATIO
+	KeyPc                      = 0x178
+	KeyTv                      = 0x179
+	KeyTv2                     = 0x17a
+	KeyVcr                     = 0x17b
+	KeyVcr2                    = 0x17c
+	KeySat                     = 0x17d
+	KeySat2                    = 0x17e
+	KeyCd                      = 0x17f
+	KeyTape                    = 0x180
+	KeyRadio                   = 0x181
+	KeyTuner                   = 0x182
+	KeyPlayer                  = 0x183
+	KeyText                    = 0x184
+	KeyDvd                     = 0x185
+	KeyAux                     = 0x186
+	KeyMp3                     = 0x187
+	KeyAudio                   = 0x188
+	KeyVideo                   = 0x189
+	KeyDirectory               = 0x18a
+	KeyList                    = 0x18b
+	KeyMemo                    = 0x18c
+	KeyCalendar                = 0x18d
+	KeyRed                     = 0x18e
+	KeyGreen                   = 0x18f
+	KeyYellow                  = 0x190
+	KeyBlue                    = 0x191
+	KeyChannelup               = 0x192
+	KeyChanneldown             = 0x193
+	KeyFirst                   = 0x194
+	KeyLast                    = 0x195
+	KeyAb                      = 0x196
+	KeyNext                    = 0x197
+	KeyRestart                 = 0x198
+	KeySlow                    = 0x199
+	KeyShuffle                 = 0x19a
+	KeyBreak                   = 0x19b
+	KeyPrevious                = 0x19c
+	KeyDigits                  = 0x19d
+	KeyTeen                    = 0x19e
+	KeyTwen                    = 0x19f
+	KeyVideophone              = 0x1a0
+	KeyGames                   = 0x1a1
+	KeyZoomin                  = 0x1a2
+	KeyZoomout                 = 0x1a3
+	KeyZoomreset               = 0x1a4
+	KeyWordprocessor           = 0x1a5
+	KeyEditor                  = 0x1a6
+	KeySpreadsheet             = 0x1a7
+	KeyGraphicseditor          = 0x1a8
+	KeyPresentation            = 0x1a9
+	KeyDatabase                = 0x1aa
+	KeyNews                    = 0x1ab
+	KeyVoicemail               = 0x1ac
+	KeyAddressbook             = 0x1ad
+	KeyMessenger               = 0x1ae
+	KeyDisplaytoggle           = 0x1af
+	KeyBrightnessToggle        = 0x1af // alias of KEY_DISPLAYTOGGLE
+	KeySpellcheck              = 0x1b0
+	KeyLogoff                  = 0x1b1
+	KeyDollar                  = 0x1b2
+	KeyEuro                    = 0x1b3
+	KeyFrameback               = 0x1b4
+	KeyFrameforward            = 0x1b5
+	KeyContextMenu             = 0x1b6
+	KeyMediaRepeat             = 0x1b7
+	Key10channelsup            = 0x1b8
+	Key10channelsdown          = 0x1b9
+	KeyImages                  = 0x1ba
+	KeyNotificationCenter      = 0x1bc
+	KeyPickupPhone             = 0x1bd
+	KeyHangupPhone             = 0x1be
+	KeyLinkPhone               = 0x1bf
+	KeyDelEol                  = 0x1c0
+	KeyDelEos                  = 0x1c1
+	KeyInsLine                 = 0x1c2
+	KeyDelLine                 = 0x1c3
+	KeyFn                      = 0x1d0
+	KeyFnEsc                   = 0x1d1
+	KeyFnF1                    = 0x1d2
+	KeyFnF2                    = 0x1d3
+	KeyFnF3                    = 0x1d4
+	KeyFnF4                    = 0x1d5
+	KeyFnF5                    = 0x1d6
+	KeyFnF6                    = 0x1d7
+	KeyFnF7                    = 0x1d8
+	KeyFnF8                    = 0x1d9
+	KeyFnF9                    = 0x1da
+	KeyFnF10                   = 0x1db
+	KeyFnF11                   = 0x1dc
+	KeyFnF12                   = 0x1dd
+	KeyFn1                     = 0x1de
+	KeyFn2                     = 0x1df
+	KeyFnD                     = 0x1e0
+	KeyFnE                     = 0x1e1
+	KeyFnF                     = 0x1e2
+	KeyFnS                     = 0x1e3
+	KeyFnB                     = 0x1e4
+	KeyFnRightShift            = 0x1e5
+	KeyBrlDot1                 = 0x1f1
+	KeyBrlDot2                 = 0x1f2
+	KeyBrlDot3                 = 0x1f3
+	KeyBrlDot4                 = 0x1f4
+	KeyBrlDot5                 = 0x1f5
+	KeyBrlDot6                 = 0x1f6
+	KeyBrlDot7                 = 0x1f7
+	KeyBrlDot8                 = 0x1f8
+	KeyBrlDot9                 = 0x1f9
+	KeyBrlDot10                = 0x1fa
+	KeyNumeric0                = 0x200
+	KeyNumeric1                = 0x201
+	KeyNumeric2                = 0x202
+	KeyNumeric3                = 0x203
+	KeyNumeric4                = 0x204
+	KeyNumeric5                = 0x205
+	KeyNumeric6                = 0x206
+	KeyNumeric7                = 0x207
+	KeyNumeric8                = 0x208
+	KeyNumeric9                = 0x209
+	KeyNumericStar             = 0x20a
+	KeyNumericPound            = 0x20b
+	KeyNumericA                = 0x20c
+	KeyNumericB                = 0x20d
+	KeyNumericC                = 0x20e
+	KeyNumericD                = 0x20f
+	KeyCameraFocus             = 0x210
+	KeyWpsButton               = 0x211
+	KeyTouchpadToggle          = 0x212
+	KeyTouchpadOn              = 0x213
+	KeyTouchpadOff             = 0x214
+	KeyCameraZoomin            = 0x215
+	KeyCameraZoomout           = 0x216
+	KeyCameraUp                = 0x217
+	KeyCameraDown              = 0x218
+	KeyCameraLeft              = 0x219
+	KeyCameraRight             = 0x21a
+	KeyAttendantOn             = 0x21b
+	KeyAttendantOff            = 0x21c
+	KeyAttendantToggle         = 0x21d
+	KeyLightsToggle            = 0x21e
+	KeyAlsToggle               = 0x230
+	KeyRotateLockToggle        = 0x231
+	KeyRefreshRateToggle       = 0x232
+	KeyButtonconfig            = 0x240
+	KeyTaskmanager             = 0x241
+	KeyJournal                 = 0x242
+	KeyControlpanel            = 0x243
+	KeyAppselect               = 0x244
+	KeyScreensaver             = 0x245
+	KeyVoicecommand            = 0x246
+	KeyAssistant               = 0x247
+	KeyKbdLayoutNext           = 0x248
+	KeyEmojiPicker             = 0x249
+	KeyDictate                 = 0x24a
+	KeyBrightnessMin           = 0x250
+	KeyKbdinputassistPrev      = 0x260
+	KeyKbdinputassistNext      = 0x261
+	KeyKbdinputassistPrevgroup = 0x262
+	KeyKbdinputassistNextgroup = 0x263
+	KeyKbdinputassistAccept    = 0x264
+	KeyKbdinputassistCancel    = 0x265
+	KeyRightUp                 = 0x266
+	KeyRightDown               = 0x267
+	KeyLeftUp                  = 0x268
+	KeyLeftDown                = 0x269
+	KeyRootMenu                = 0x26a
+	KeyMediaTopMenu            = 0x26b
+	KeyNumeric11               = 0x26c
+	KeyNumeric12               = 0x26d
+	KeyAudioDesc               = 0x26e
+	Key3dMode                  = 0x26f
+	KeyNextFavorite            = 0x270
+	KeyStopRecord              = 0x271
+	KeyPauseRecord             = 0x272
+	KeyVod                     = 0x273
+	KeyUnmute                  = 0x274
+	KeyFastreverse             = 0x275
+	KeySlowreverse             = 0x276
+	KeyData                    = 0x277
+	KeyOnscreenKeyboard        = 0x278
+	KeyPrivacyScreenToggle     = 0x279
+	KeySelectiveScreenshot     = 0x27a
+	KeyNextElement             = 0x27b
+	KeyPreviousElement         = 0x27c
+	KeyAutopilotEngageToggle   = 0x27d
+	KeyMarkWaypoint            = 0x27e
+	KeySos                     = 0x27f
+	KeyNavChart                = 0x280
+	KeyFishingChart            = 0x281
+	KeySingleRangeRadar        = 0x282
+	KeyDualRangeRadar          = 0x283
+	KeyRadarOverlay            = 0x284
+	KeyTraditionalSonar        = 0x285
+	KeyClearvuSonar            = 0x286
+	KeySidevuSonar             = 0x287
+	KeyNavInfo                 = 0x288
+	KeyBrightnessMenu          = 0x289
+	KeyMacro1                  = 0x290
+	KeyMacro2                  = 0x291
+	KeyMacro3                  = 0x292
+	KeyMacro4                  = 0x293
+	KeyMacro5                  = 0x294
+	KeyMacro6                  = 0x295
+	KeyMacro7                  = 0x296
+	KeyMacro8                  = 0x297
+	KeyMacro9                  = 0x298
+	KeyMacro10                 = 0x299
+	KeyMacro11                 = 0x29a
+	KeyMacro12                 = 0x29b
+	KeyMacro13                 = 0x29c
+	KeyMacro14                 = 0x29d
+	KeyMacro15                 = 0x29e
+	KeyMacro16                 = 0x29f
+	KeyMacro17                 = 0x2a0
+	KeyMacro18                 = 0x2a1
+	KeyMacro19                 = 0x2a2
+	KeyMacro20                 = 0x2a3
+	KeyMacro21                 = 0x2a4
+	KeyMacro22                 = 0x2a5
+	KeyMacro23                 = 0x2a6
+	KeyMacro24                 = 0x2a7
+	KeyMacro25                 = 0x2a8
+	KeyMacro26                 = 0x2a9
+	KeyMacro27                 = 0x2aa
+	KeyMacro28                 = 0x2ab
+	KeyMacro29                 = 0x2ac
+	KeyMacro30                 = 0x2ad
+	KeyMacroRecordStart        = 0x2b0
+	KeyMacroRecordStop         = 0x2b1
+	KeyMacroPresetCycle        = 0x2b2
+	KeyMacroPreset1            = 0x2b3
+	KeyMacroPreset2            = 0x2b4
+	KeyMacroPreset3            = 0x2b5
+	KeyKbdLcdMenu1             = 0x2b8
+	KeyKbdLcdMenu2             = 0x2b9
+	KeyKbdLcdMenu3             = 0x2ba
+	KeyKbdLcdMenu4             = 0x2bb
+	KeyKbdLcdMenu5             = 0x2bc
+	KeyMinInteresting          = 113 // alias of KEY_MUTE
+)
+
+// the button codes as defined in input-event-codes.h
+const (
+	BtnMisc           = 0x100
+	Btn0              = 0x100
+	Btn1              = 0x101
+	Btn2              = 0x102
+	Btn3              = 0x103
+	Btn4              = 0x104
+	Btn5              = 0x105
+	Btn6              = 0x106
+	Btn7              = 0x107
+	Btn8              = 0x108
+	Btn9              = 0x109
+	BtnMouse          = 0x110
+	BtnLeft           = 0x110
+	BtnRight          = 0x111
+	BtnMiddle         = 0x112
+	BtnSide           = 0x113
+	BtnExtra          = 0x114
+	BtnForward        = 0x115
+	BtnBack           = 0x116
+	BtnTask           = 0x117
+	BtnJoystick       = 0x120
+	BtnTrigger        = 0x120
+	BtnThumb          = 0x121
+	BtnThumb2         = 0x122
+	BtnTop            = 0x123
+	BtnTop2           = 0x124
+	BtnPinkie         = 0x125
+	BtnBase           = 0x126
+	BtnBase2          = 0x127
+	BtnBase3          = 0x128
+	BtnBase4          = 0x129
+	BtnBase5          = 0x12a
+	BtnBase6          = 0x12b
+	BtnDead           = 0x12f
+	BtnGamepad        = 0x130
+	BtnSouth          = 0x130
+	BtnA              = 0x130 // alias of BTN_SOUTH
+	BtnEast           = 0x131
+	BtnB              = 0x131 // alias of BTN_EAST
+	BtnC              = 0x132
+	BtnNorth          = 0x133
+	BtnX              = 0x133 // alias of BTN_NORTH
+	BtnWest           = 0x134
+	BtnY              = 0x134 // alias of BTN_WEST
+	BtnZ              = 0x135
+	BtnTL             = 0x136
+	BtnTR             = 0x137
+	BtnTL2            = 0x138
+	BtnTR2            = 0x139
+	BtnSelect         = 0x13a
+	BtnStart          = 0x13b
+	BtnMode           = 0x13c
+	BtnThumbl         = 0x13d
+	BtnThumbr         = 0x13e
+	BtnDigi           = 0x140
+	BtnToolPen        = 0x140
+	BtnToolRubber     = 0x141
+	BtnToolBrush      = 0x142
+	BtnToolPencil     = 0x143
+	BtnToolAirbrush   = 0x144
+	BtnToolFinger     = 0x145
+	BtnToolMouse      = 0x146
+	BtnToolLens       = 0x147
+	BtnToolQuinttap   = 0x148
+	BtnStylus3        = 0x149
+	BtnTouch          = 0x14a
+	BtnStylus         = 0x14b
+	BtnStylus2        = 0x14c
+	BtnToolDoubletap  = 0x14d
+	BtnToolTripletap  = 0x14e
+	BtnToolQuadtap    = 0x14f
+	BtnWheel          = 0x150
+	BtnGearDown       = 0x150
+	BtnGearUp         = 0x151
+	BtnDpadUp         = 0x220
+	BtnDpadDown       = 0x221
+	BtnDpadLeft       = 0x222
+	BtnDpadRight      = 0x223
+	BtnTriggerHappy   = 0x2c0
+	BtnTriggerHappy1  = 0x2c0
+	BtnTriggerHappy2  = 0x2c1
+	BtnTriggerHappy3  = 0x2c2
+	BtnTriggerHappy4  = 0x2c3
+	BtnTriggerHappy5  = 0x2c4
+	BtnTriggerHappy6  = 0x2c5
+	BtnTriggerHappy7  = 0x2c6
+	BtnTriggerHappy8  = 0x2c7
+	BtnTriggerHappy9  = 0x2c8
+	BtnTriggerHappy10 = 0x2c9
+	BtnTriggerHappy11 = 0x2ca
+	BtnTriggerHappy12 = 0x2cb
+	BtnTriggerHappy13 = 0x2cc
+	BtnTriggerHappy14 = 0x2cd
+	BtnTriggerHappy15 = 0x2ce
+	BtnTriggerHappy16 = 0x2cf
+	BtnTriggerHappy17 = 0x2d0
+	BtnTriggerHappy18 = 0x2d1
+	BtnTriggerHappy19 = 0x2d2
+	BtnTriggerHappy20 = 0x2d3
+	BtnTriggerHappy21 = 0x2d4
+	BtnTriggerHappy22 = 0x2d5
+	BtnTriggerHappy23 = 0x2d6
+	BtnTriggerHappy24 = 0x2d7
+	BtnTriggerHappy25 = 0x2d8
+	BtnTriggerHappy26 = 0x2d9
+	BtnTriggerHappy27 = 0x2da
+	BtnTriggerHappy28 = 0x2db
+	BtnTriggerHappy29 = 0x2dc
+	BtnTriggerHappy30 = 0x2dd
+	BtnTriggerHappy31 = 0x2de
+	BtnTriggerHappy32 = 0x2df
+	BtnTriggerHappy33 = 0x2e0
+	BtnTriggerHappy34 = 0x2e1
+	BtnTriggerHappy35 = 0x2e2
+	BtnTriggerHappy36 = 0x2e3
+	BtnTriggerHappy37 = 0x2e4
+	BtnTriggerHappy38 = 0x2e5
+	BtnTriggerHappy39 = 0x2e6
+	BtnTriggerHappy40 = 0x2e7
+)
+
+// the absolute axis codes as defined in input-event-codes.h
+const (
+	AbsX             = 0x00
+	AbsY             = 0x01
+	AbsZ             = 0x02
+	AbsRX            = 0x03
+	AbsRY            = 0x04
+	AbsRZ            = 0x05
+	AbsThrottle      = 0x06
+	AbsRudder        = 0x07
+	AbsWheel         = 0x08
+	AbsGas           = 0x09
+	AbsBrake         = 0x0a
+	AbsHat0X         = 0x10
+	AbsHat0Y         = 0x11
+	AbsHat1X         = 0x12
+	AbsHat1Y         = 0x13
+	AbsHat2X         = 0x14
+	AbsHat2Y         = 0x15
+	AbsHat3X         = 0x16
+	AbsHat3Y         = 0x17
+	AbsPressure      = 0x18
+	AbsDistance      = 0x19
+	AbsTiltX         = 0x1a
+	AbsTiltY         = 0x1b
+	AbsToolWidth     = 0x1c
+	AbsVolume        = 0x20
+	AbsProfile       = 0x21
+	AbsMisc          = 0x28
+	AbsReserved      = 0x2e
+	AbsMtSlot        = 0x2f
+	AbsMtTouchMajor  = 0x30
+	AbsMtTouchMinor  = 0x31
+	AbsMtWidthMajor  = 0x32
+	AbsMtWidthMinor  = 0x33
+	AbsMtOrientation = 0x34
+	AbsMtPositionX   = 0x35
+	AbsMtPositionY   = 0x36
+	AbsMtToolType    = 0x37
+	AbsMtBlobID      = 0x38
+	AbsMtTrackingID  = 0x39
+	AbsMtPressure    = 0x3a
+	AbsMtDistance    = 0x3b
+	AbsMtToolX       = 0x3c
+	AbsMtToolY       = 0x3d
+)
+
+// the relative axis codes as defined in input-event-codes.h
+const (
+	RelReserved    = 0x0a
+	RelWheelHiRes  = 0x0b
+	RelHWheelHiRes = 0x0c
+)
+
+// the miscellaneous event codes as defined in input-event-codes.h
+const (
+	MscSerial    = 0x00
+	MscPulseled  = 0x01
+	MscGesture   = 0x02
+	MscRaw       = 0x03
+	MscScan      = 0x04
+	MscTimestamp = 0x05
+)
+
+// the switch codes as defined in input-event-codes.h
+const (
+	SwLid                = 0x00
+	SwTabletMode         = 0x01
+	SwHeadphoneInsert    = 0x02
+	SwRfkillAll          = 0x03
+	SwRadio              = 0x03 // alias of SW_RFKILL_ALL
+	SwMicrophoneInsert   = 0x04
+	SwDock               = 0x05
+	SwLineoutInsert      = 0x06
+	SwJackPhysicalInsert = 0x07
+	SwVideooutInsert     = 0x08
+	SwCameraLensCover    = 0x09
+	SwKeypadSlide        = 0x0a
+	SwFrontProximity     = 0x0b
+	SwRotateLock         = 0x0c
+	SwLineinInsert       = 0x0d
+	SwMuteDevice         = 0x0e
+	SwPenInserted        = 0x0f
+	SwMachineCover       = 0x10
+)
+
+// the LED codes as defined in input-event-codes.h
+const (
+	LedNuml     = 0x00
+	LedCapsl    = 0x01
+	LedScrolll  = 0x02
+	LedCompose  = 0x03
+	LedKana     = 0x04
+	LedSleep    = 0x05
+	LedSuspend  = 0x06
+	LedMute     = 0x07
+	LedMisc     = 0x08
+	LedMail     = 0x09
+	LedCharging = 0x0a
+)
+
+// the sound codes as defined in input-event-codes.h
+const (
+	SndClick = 0x00
+	SndBell  = 0x01
+	SndTone  = 0x02
+)
+
+// the autorepeat codes as defined in input-event-codes.h
+const (
+	RepDelay  = 0x00
+	RepPeriod = 0x01
+)
+
+// codeValues maps the kernel names of all codes to their values
+var codeValues = map[string]uint16{
+	"SYN_REPORT":                   0,
+	"SYN_CONFIG":                   1,
+	"SYN_MT_REPORT":                2,
+	"SYN_DROPPED":                  3,
+	"KEY_RESERVED":                 0,
+	"KEY_ESC":                      1,
+	"KEY_1":                        2,
+	"KEY_2":                        3,
+	"KEY_3":                        4,
+	"KEY_4":                        5,
+	"KEY_5":                        6,
+	"KEY_6":                        7,
+	"KEY_7":                        8,
+	"KEY_8":                        9,
+	"KEY_9":                        10,
+	"KEY_0":                        11,
+	"KEY_MINUS":                    12,
+	"KEY_EQUAL":                    13,
+	"KEY_BACKSPACE":                14,
+	"KEY_TAB":                      15,
+	"KEY_Q":                        16,
+	"KEY_W":                        17,
+	"KEY_E":                        18,
+	"KEY_R":                        19,
+	"KEY_T":                        20,
+	"KEY_Y":                        21,
+	"KEY_U":                        22,
+	"KEY_I":                        23,
+	"KEY_O":                        24,
+	"KEY_P":                        25,
+	"KEY_LEFTBRACE":                26,
+	"KEY_RIGHTBRACE":               27,
+	"KEY_ENTER":                    28,
+	"KEY_LEFTCTRL":                 29,
+	"KEY_A":                        30,
+	"KEY_S":                        31,
+	"KEY_D":                        32,
+	"KEY_F":                        33,
+	"KEY_G":                        34,
+	"KEY_H":                        35,
+	"KEY_J":                        36,
+	"KEY_K":                        37,
+	"KEY_L":                        38,
+	"KEY_SEMICOLON":                39,
+	"KEY_APOSTROPHE":               40,
+	"KEY_GRAVE":                    41,
+	"KEY_LEFTSHIFT":                42,
+	"KEY_BACKSLASH":                43,
+	"KEY_Z":                        44,
+	"KEY_X":                        45,
+	"KEY_C":                        46,
+	"KEY_V":                        47,
+	"KEY_B":                        48,
+	"KEY_N":                        49,
+	"KEY_M":                        50,
+	"KEY_COMMA":                    51,
+	"KEY_DOT":                      52,
+	"KEY_SLASH":                    53,
+	"KEY_RIGHTSHIFT":               54,
+	"KEY_KPASTERISK":               55,
+	"KEY_LEFTALT":                  56,
+	"KEY_SPACE":                    57,
+	"KEY_CAPSLOCK":                 58,
+	"KEY_F1":                       59,
+	"KEY_F2":                       60,
+	"KEY_F3":                       61,
+	"KEY_F4":                       62,
+	"KEY_F5":                       63,
+	"KEY_F6":                       64,
+	"KEY_F7":                       65,
+	"KEY_F8":                       66,
+	"KEY_F9":                       67,
+	"KEY_F10":                      68,
+	"KEY_NUMLOCK":                  69,
+	"KEY_SCROLLLOCK":               70,
+	"KEY_KP7":                      71,
+	"KEY_KP8":                      72,
+	"KEY_KP9":                      73,
+	"KEY_KPMINUS":                  74,
+	"KEY_KP4":                      75,
+	"KEY_KP5":                      76,
+	"KEY_KP6":                      77,
+	"KEY_KPPLUS":                   78,
+	"KEY_KP1":                      79,
+	"KEY_KP2":                      80,
+	"KEY_KP3":                      81,
+	"KEY_KP0":                      82,
+	"KEY_KPDOT":                    83,
+	"KEY_ZENKAKUHANKAKU":           85,
+	"KEY_102ND":                    86,
+	"KEY_F11":                      87,
+	"KEY_F12":                      88,
+	"KEY_RO":                       89,
+	"KEY_KATAKANA":                 90,
+	"KEY_HIRAGANA":                 91,
+	"KEY_HENKAN":                   92,
+	"KEY_KATAKANAHIRAGANA":         93,
+	"KEY_MUHENKAN":                 94,
+	"KEY_KPJPCOMMA":                95,
+	"KEY_KPENTER":                  96,
+	"KEY_RIGHTCTRL":                97,
+	"KEY_KPSLASH":                  98,
+	"KEY_SYSRQ":                    99,
+	"KEY_RIGHTALT":                 100,
+	"KEY_LINEFEED":                 101,
+	"KEY_HOME":                     102,
+	"KEY_UP":                       103,
+	"KEY_PAGEUP":                   104,
+	"KEY_LEFT":                     105,
+	"KEY_RIGHT":                    106,
+	"KEY_END":                      107,
+	"KEY_DOWN":                     108,
+	"KEY_PAGEDOWN":                 109,
+	"KEY_INSERT":                   110,
+	"KEY_DELETE":                   111,
+	"KEY_MACRO":                    112,
+	"KEY_MUTE":                     113,
+	"KEY_VOLUMEDOWN":               114,
+	"KEY_VOLUMEUP":                 115,
+	"KEY_POWER":                    116,
+	"KEY_KPEQUAL":                  117,
+	"KEY_KPPLUSMINUS":              118,
+	"KEY_PAUSE":                    119,
+	"KEY_SCALE":                    120,
+	"KEY_KPCOMMA":                  121,
+	"KEY_HANGEUL":                  122,
+	"KEY_HANGUEL":                  122,
+	"KEY_HANJA":                    123,
+	"KEY_YEN":                      124,
+	"KEY_LEFTMETA":                 125,
+	"KEY_RIGHTMETA":                126,
+	"KEY_COMPOSE":                  127,
+	"KEY_STOP":                     128,
+	"KEY_AGAIN":                    129,
+	"KEY_PROPS":                    130,
+	"KEY_UNDO":                     131,
+	"KEY_FRONT":                    132,
+	"KEY_COPY":                     133,
+	"KEY_OPEN":                     134,
+	"KEY_PASTE":                    135,
+	"KEY_FIND":                     136,
+	"KEY_CUT":                      137,
+	"KEY_HELP":                     138,
+	"KEY_MENU":                     139,
+	"KEY_CALC":                     140,
+	"KEY_SETUP":                    141,
+	"KEY_SLEEP":                    142,
+	"KEY_WAKEUP":                   143,
+	"KEY_FILE":                     144,
+	"KEY_SENDFILE":                 145,
+	"KEY_DELETEFILE":               146,
+	"KEY_XFER":                     147,
+	"KEY_PROG1":                    148,
+	"KEY_PROG2":                    149,
+	"KEY_WWW":                      150,
+	"KEY_MSDOS":                    151,
+	"KEY_COFFEE":                   152,
+	"KEY_SCREENLOCK":               152,
+	"KEY_ROTATE_DISPLAY":           153,
+	"KEY_DIRECTION":                153,
+	"KEY_CYCLEWINDOWS":             154,
+	"KEY_MAIL":                     155,
+	"KEY_BOOKMARKS":                156,
+	"KEY_COMPUTER":                 157,
+	"KEY_BACK":                     158,
+	"KEY_FORWARD":                  159,
+	"KEY_CLOSECD":                  160,
+	"KEY_EJECTCD":                  161,
+	"KEY_EJECTCLOSECD":             162,
+	"KEY_NEXTSONG":                 163,
+	"KEY_PLAYPAUSE":                164,
+	"KEY_PREVIOUSSONG":             165,
+	"KEY_STOPCD":                   166,
+	"KEY_RECORD":                   167,
+	"KEY_REWIND":                   168,
+	"KEY_PHONE":                    169,
+	"KEY_ISO":                      170,
+	"KEY_CONFIG":                   171,
+	"KEY_HOMEPAGE":                 172,
+	"KEY_REFRESH":                  173,
+	"KEY_EXIT":                     174,
+	"KEY_MOVE":                     175,
+	"KEY_EDIT":                     176,
+	"KEY_SCROLLUP":                 177,
+	"KEY_SCROLLDOWN":               178,
+	"KEY_KPLEFTPAREN":              179,
+	"KEY_KPRIGHTPAREN":             180,
+	"KEY_NEW":                      181,
+	"KEY_REDO":                     182,
+	"KEY_F13":                      183,
+	"KEY_F14":                      184,
+	"KEY_F15":                      185,
+	"KEY_F16":                      186,
+	"KEY_F17":                      187,
+	"KEY_F18":                      188,
+	"KEY_F19":                      189,
+	"KEY_F20":                      190,
+	"KEY_F21":                      191,
+	"KEY_F22":                      192,
+	"KEY_F23":                      193,
+	"KEY_F24":                      194,
+	"KEY_PLAYCD":                   200,
+	"KEY_PAUSECD":                  201,
+	"KEY_PROG3":                    202,
+	"KEY_PROG4":                    203,
+	"KEY_ALL_APPLICATIONS":         204,
+	"KEY_DASHBOARD":                204,
+	"KEY_SUSPEND":                  205,
+	"KEY_CLOSE":                    206,
+	"KEY_PLAY":                     207,
+	"KEY_FASTFORWARD":              208,
+	"KEY_BASSBOOST":                209,
+	"KEY_PRINT":                    210,
+	"KEY_HP":                       211,
+	"KEY_CAMERA":                   212,
+	"KEY_SOUND":                    213,
+	"KEY_QUESTION":                 214,
+	"KEY_EMAIL":                    215,
+	"KEY_CHAT":                     216,
+	"KEY_SEARCH":                   217,
+	"KEY_CONNECT":                  218,
+	"KEY_FINANCE":                  219,
+	"KEY_SPORT":                    220,
+	"KEY_SHOP":                     221,
+	"KEY_ALTERASE":                 222,
+	"KEY_CANCEL":                   223,
+	"KEY_BRIGHTNESSDOWN":           224,
+	"KEY_BRIGHTNESSUP":             225,
+	"KEY_MEDIA":                    226,
+	"KEY_SWITCHVIDEOMODE":          227,
+	"KEY_KBDILLUMTOGGLE":           228,
+	"KEY_KBDILLUMDOWN":             229,
+	"KEY_KBDILLUMUP":               230,
+	"KEY_SEND":                     231,
+	"KEY_REPLY":                    232,
+	"KEY_FORWARDMAIL":              233,
+	"KEY_SAVE":                     234,
+	"KEY_DOCUMENTS":                235,
+	"KEY_BATTERY":                  236,
+	"KEY_BLUETOOTH":                237,
+	"KEY_WLAN":                     238,
+	"KEY_UWB":                      239,
+	"KEY_UNKNOWN":                  240,
+	"KEY_VIDEO_NEXT":               241,
+	"KEY_VIDEO_PREV":               242,
+	"KEY_BRIGHTNESS_CYCLE":         243,
+	"KEY_BRIGHTNESS_AUTO":          244,
+	"KEY_BRIGHTNESS_ZERO":          244,
+	"KEY_DISPLAY_OFF":              245,
+	"KEY_WWAN":                     246,
+	"KEY_WIMAX":                    246,
+	"KEY_RFKILL":                   247,
+	"KEY_MICMUTE":                  248,
+	"KEY_OK":                       0x160,
+	"KEY_SELECT":                   0x161,
+	"KEY_GOTO":                     0x162,
+	"KEY_CLEAR":                    0x163,
+	"KEY_POWER2":                   0x164,
+	"KEY_OPTION":                   0x165,
+	"KEY_INFO":                     0x166,
+	"KEY_TIME":                     0x167,
+	"KEY_VENDOR":                   0x168,
+	"KEY_ARCHIVE":                  0x169,
+	"KEY_PROGRAM":                  0x16a,
+	"KEY_CHANNEL":                  0x16b,
+	"KEY_FAVORITES":                0x16c,
+	"KEY_EPG":                      0x16d,
+	"KEY_PVR":                      0x16e,
+	"KEY_MHP":                      0x16f,
+	"KEY_LANGUAGE":                 0x170,
+	"KEY_TITLE":                    0x171,
+	"KEY_SUBTITLE":                 0x172,
+	"KEY_ANGLE":                    0x173,
+	"KEY_FULL_SCREEN":              0x174,
+	"KEY_ZOOM":                     0x174,
+	"KEY_MODE":                     0x175,
+	"KEY_KEYBOARD":                 0x176,
+	"KEY_ASPECT_RATIO":             0x177,
+	"KEY_SCREEN":                   0x177,
+	"KEY_PC":                       0x178,
+	"KEY_TV":                       0x179,
+	"KEY_TV2":                      0x17a,
+	"KEY_VCR":                      0x17b,
+	"KEY_VCR2":                     0x17c,
+	"KEY_SAT":                      0x17d,
+	"KEY_SAT2":                     0x17e,
+	"KEY_CD":                       0x17f,
+	"KEY_TAPE":                     0x180,
+	"KEY_RADIO":                    0x181,
+	"KEY_TUNER":                    0x182,
+	"KEY_PLAYER":                   0x183,
+	"KEY_TEXT":                     0x184,
+	"KEY_DVD":                      0x185,
+	"KEY_AUX":                      0x186,
+	"KEY_MP3":                      0x187,
+	"KEY_AUDIO":                    0x188,
+	"KEY_VIDEO":                    0x189,
+	"KEY_DIRECTORY":                0x18a,
+	"KEY_LIST":                     0x18b,
+	"KEY_MEMO":                     0x18c,
+	"KEY_CALENDAR":                 0x18d,
+	"KEY_RED":                      0x18e,
+	"KEY_GREEN":                    0x18f,
+	"KEY_YELLOW":                   0x190,
+	"KEY_BLUE":                     0x191,
+	"KEY_CHANNELUP":                0x192,
+	"KEY_CHANNELDOWN":              0x193,
+	"KEY_FIRST":                    0x194,
+	"KEY_LAST":                     0x195,
+	"KEY_AB":                       0x196,
+	"KEY_NEXT":                     0x197,
+	"KEY_RESTART":                  0x198,
+	"KEY_SLOW":                     0x199,
+	"KEY_SHUFFLE":                  0x19a,
+	"KEY_BREAK":                    0x19b,
+	"KEY_PREVIOUS":                 0x19c,
+	"KEY_DIGITS":                   0x19d,
+	"KEY_TEEN":                     0x19e,
+	"KEY_TWEN":                     0x19f,
+	"KEY_VIDEOPHONE":               0x1a0,
+	"KEY_GAMES":                    0x1a1,
+	"KEY_ZOOMIN":                   0x1a2,
+	"KEY_ZOOMOUT":                  0x1a3,
+	"KEY_ZOOMRESET":                0x1a4,
+	"KEY_WORDPROCESSOR":            0x1a5,
+	"KEY_EDITOR":                   0x1a6,
+	"KEY_SPREADSHEET":              0x1a7,
+	"KEY_GRAPHICSEDITOR":           0x1a8,
+	"KEY_PRESENTATION":             0x1a9,
+	"KEY_DATABASE":                 0x1aa,
+	"KEY_NEWS":                     0x1ab,
+	"KEY_VOICEMAIL":                0x1ac,
+	"KEY_ADDRESSBOOK":              0x1ad,
+	"KEY_MESSENGER":                0x1ae,
+	"KEY_DISPLAYTOGGLE":            0x1af,
+	"KEY_BRIGHTNESS_TOGGLE":        0x1af,
+	"KEY_SPELLCHECK":               0x1b0,
+	"KEY_LOGOFF":                   0x1b1,
+	"KEY_DOLLAR":                   0x1b2,
+	"KEY_EURO":                     0x1b3,
+	"KEY_FRAMEBACK":                0x1b4,
+	"KEY_FRAMEFORWARD":             0x1b5,
+	"KEY_CONTEXT_MENU":             0x1b6,
+	"KEY_MEDIA_REPEAT":             0x1b7,
+	"KEY_10CHANNELSUP":             0x1b8,
+	"KEY_10CHANNELSDOWN":           0x1b9,
+	"KEY_IMAGES":                   0x1ba,
+	"KEY_NOTIFICATION_CENTER":      0x1bc,
+	"KEY_PICKUP_PHONE":             0x1bd,
+	"KEY_HANGUP_PHONE":             0x1be,
+	"KEY_LINK_PHONE":               0x1bf,
+	"KEY_DEL_EOL":                  0x1c0,
+	"KEY_DEL_EOS":                  0x1c1,
+	"KEY_INS_LINE":                 0x1c2,
+	"KEY_DEL_LINE":                 0x1c3,
+	"KEY_FN":                       0x1d0,
+	"KEY_FN_ESC":                   0x1d1,
+	"KEY_FN_F1":                    0x1d2,
+	"KEY_FN_F2":                    0x1d3,
+	"KEY_FN_F3":                    0x1d4,
+	"KEY_FN_F4":                    0x1d5,
+	"KEY_FN_F5":                    0x1d6,
+	"KEY_FN_F6":                    0x1d7,
+	"KEY_FN_F7":                    0x1d8,
+	"KEY_FN_F8":                    0x1d9,
+	"KEY_FN_F9":                    0x1da,
+	"KEY_FN_F10":                   0x1db,
+	"KEY_FN_F11":                   0x1dc,
+	"KEY_FN_F12":                   0x1dd,
+	"KEY_FN_1":                     0x1de,
+	"KEY_FN_2":                     0x1df,
+	"KEY_FN_D":                     0x1e0,
+	"KEY_FN_E":                     0x1e1,
+	"KEY_FN_F":                     0x1e2,
+	"KEY_FN_S":                     0x1e3,
+	"KEY_FN_B":                     0x1e4,
+	"KEY_FN_RIGHT_SHIFT":           0x1e5,
+	"KEY_BRL_DOT1":                 0x1f1,
+	"KEY_BRL_DOT2":                 0x1f2,
+	"KEY_BRL_DOT3":                 0x1f3,
+	"KEY_BRL_DOT4":                 0x1f4,
+	"KEY_BRL_DOT5":                 0x1f5,
+	"KEY_BRL_DOT6":                 0x1f6,
+	"KEY_BRL_DOT7":                 0x1f7,
+	"KEY_BRL_DOT8":                 0x1f8,
+	"KEY_BRL_DOT9":                 0x1f9,
+	"KEY_BRL_DOT10":                0x1fa,
+	"KEY_NUMERIC_0":                0x200,
+	"KEY_NUMERIC_1":                0x201,
+	"KEY_NUMERIC_2":                0x202,
+	"KEY_NUMERIC_3":                0x203,
+	"KEY_NUMERIC_4":                0x204,
+	"KEY_NUMERIC_5":                0x205,
+	"KEY_NUMERIC_6":                0x206,
+	"KEY_NUMERIC_7":                0x207,
+	"KEY_NUMERIC_8":                0x208,
+	"KEY_NUMERIC_9":                0x209,
+	"KEY_NUMERIC_STAR":             0x20a,
+	"KEY_NUMERIC_POUND":            0x20b,
+	"KEY_NUMERIC_A":                0x20c,
+	"KEY_NUMERIC_B":                0x20d,
+	"KEY_NUMERIC_C":                0x20e,
+	"KEY_NUMERIC_D":                0x20f,
+	"KEY_CAMERA_FOCUS":             0x210,
+	"KEY_WPS_BUTTON":               0x211,
+	"KEY_TOUCHPAD_TOGGLE":          0x212,
+	"KEY_TOUCHPAD_ON":              0x213,
+	"KEY_TOUCHPAD_OFF":             0x214,
+	"KEY_CAMERA_ZOOMIN":            0x215,
+	"KEY_CAMERA_ZOOMOUT":           0x216,
+	"KEY_CAMERA_UP":                0x217,
+	"KEY_CAMERA_DOWN":              0x218,
+	"KEY_CAMERA_LEFT":              0x219,
+	"KEY_CAMERA_RIGHT":             0x21a,
+	"KEY_ATTENDANT_ON":             0x21b,
+	"KEY_ATTENDANT_OFF":            0x21c,
+	"KEY_ATTENDANT_TOGGLE":         0x21d,
+	"KEY_LIGHTS_TOGGLE":            0x21e,
+	"KEY_ALS_TOGGLE":               0x230,
+	"KEY_ROTATE_LOCK_TOGGLE":       0x231,
+	"KEY_REFRESH_RATE_TOGGLE":      0x232,
+	"KEY_BUTTONCONFIG":             0x240,
+	"KEY_TASKMANAGER":              0x241,
+	"KEY_JOURNAL":                  0x242,
+	"KEY_CONTROLPANEL":             0x243,
+	"KEY_APPSELECT":                0x244,
+	"KEY_SCREENSAVER":              0x245,
+	"KEY_VOICECOMMAND":             0x246,
+	"KEY_ASSISTANT":                0x247,
+	"KEY_KBD_LAYOUT_NEXT":          0x248,
+	"KEY_EMOJI_PICKER":             0x249,
+	"KEY_DICTATE":                  0x24a,
+	"KEY_BRIGHTNESS_MIN":           0x250,
+	"KEY_KBDINPUTASSIST_PREV":      0x260,
+	"KEY_KBDINPUTASSIST_NEXT":      0x261,
+	"KEY_KBDINPUTASSIST_PREVGROUP": 0x262,
+	"KEY_KBDINPUTASSIST_NEXTGROUP": 0x263,
+	"KEY_KBDINPUTASSIST_ACCEPT":    0x264,
+	"KEY_KBDINPUTASSIST_CANCEL":    0x265,
+	"KEY_RIGHT_UP":                 0x266,
+	"KEY_RIGHT_DOWN":               0x267,
+	"KEY_LEFT_UP":                  0x268,
+	"KEY_LEFT_DOWN":                0x269,
+	"KEY_ROOT_MENU":                0x26a,
+	"KEY_MEDIA_TOP_MENU":           0x26b,
+	"KEY_NUMERIC_11":               0x26c,
+	"KEY_NUMERIC_12":               0x26d,
+	"KEY_AUDIO_DESC":               0x26e,
+	"KEY_3D_MODE":                  0x26f,
+	"KEY_NEXT_FAVORITE":            0x270,
+	"KEY_STOP_RECORD":              0x271,
+	"KEY_PAUSE_RECORD":             0x272,
+	"KEY_VOD":                      0x273,
+	"KEY_UNMUTE":                   0x274,
+	"KEY_FASTREVERSE":              0x275,
+	"KEY_SLOWREVERSE":              0x276,
+	"KEY_DATA":                     0x277,
+	"KEY_ONSCREEN_KEYBOARD":        0x278,
+	"KEY_PRIVACY_SCREEN_TOGGLE":    0x279,
+	"KEY_SELECTIVE_SCREENSHOT":     0x27a,
+	"KEY_NEXT_ELEMENT":             0x27b,
+	"KEY_PREVIOUS_ELEMENT":         0x27c,
+	"KEY_AUTOPILOT_ENGAGE_TOGGLE":  0x27d,
+	"KEY_MARK_WAYPOINT":            0x27e,
+	"KEY_SOS":                      0x27f,
+	"KEY_NAV_CHART":                0x280,
+	"KEY_FISHING_CHART":            0x281,
+	"KEY_SINGLE_RANGE_RADAR":       0x282,
+	"KEY_DUAL_RANGE_RADAR":         0x283,
+	"KEY_RADAR_OVERLAY":            0x284,
+	"KEY_TRADITIONAL_SONAR":        0x285,
+	"KEY_CLEARVU_SONAR":            0x286,
+	"KEY_SIDEVU_SONAR":             0x287,
+	"KEY_NAV_INFO":                 0x288,
+	"KEY_BRIGHTNESS_MENU":          0x289,
+	"KEY_MACRO1":                   0x290,
+	"KEY_MACRO2":                   0x291,
+	"KEY_MACRO3":                   0x292,
+	"KEY_MACRO4":                   0x293,
+	"KEY_MACRO5":                   0x294,
+	"KEY_MACRO6":                   0x295,
+	"KEY_MACRO7":                   0x296,
+	"KEY_MACRO8":                   0x297,
+	"KEY_MACRO9":                   0x298,
+	"KEY_MACRO10":                  0x299,
+	"KEY_MACRO11":                  0x29a,
+	"KEY_MACRO12":                  0x29b,
+	"KEY_MACRO13":                  0x29c,
+	"KEY_MACRO14":                  0x29d,
+	"KEY_MACRO15":                  0x29e,
+	"KEY_MACRO16":                  0x29f,
+	"KEY_MACRO17":                  0x2a0,
+	"KEY_MACRO18":                  0x2a1,
+	"KEY_MACRO19":                  0x2a2,
+	"KEY_MACRO20":                  0x2a3,
+	"KEY_MACRO21":                  0x2a4,
+	"KEY_MACRO22":                  0x2a5,
+	"KEY_MACRO23":                  0x2a6,
+	"KEY_MACRO24":                  0x2a7,
+	"KEY_MACRO25":                  0x2a8,
+	"KEY_MACRO26":                  0x2a9,
+	"KEY_MACRO27":                  0x2aa,
+	"KEY_MACRO28":                  0x2ab,
+	"KEY_MACRO29":                  0x2ac,
+	"KEY_MACRO30":                  0x2ad,
+	"KEY_MACRO_RECORD_START":       0x2b0,
+	"KEY_MACRO_RECORD_STOP":        0x2b1,
+	"KEY_MACRO_PRESET_CYCLE":       0x2b2,
+	"KEY_MACRO_PRESET1":            0x2b3,
+	"KEY_MACRO_PRESET2":            0x2b4,
+	"KEY_MACRO_PRESET3":            0x2b5,
+	"KEY_KBD_LCD_MENU1":            0x2b8,
+	"KEY_KBD_LCD_MENU2":            0x2b9,
+	"KEY_KBD_LCD_MENU3":            0x2ba,
+	"KEY_KBD_LCD_MENU4":            0x2bb,
+	"KEY_KBD_LCD_MENU5":            0x2bc,
+	"KEY_MIN_INTERESTING":          113,
+	"BTN_MISC":                     0x100,
+	"BTN_0":                        0x100,
+	"BTN_1":                        0x101,
+	"BTN_2":                        0x102,
+	"BTN_3":                        0x103,
+	"BTN_4":                        0x104,
+	"BTN_5":                        0x105,
+	"BTN_6":                        0x106,
+	"BTN_7":                        0x107,
+	"BTN_8":                        0x108,
+	"BTN_9":                        0x109,
+	"BTN_MOUSE":                    0x110,
+	"BTN_LEFT":                     0x110,
+	"BTN_RIGHT":                    0x111,
+	"BTN_MIDDLE":                   0x112,
+	"BTN_SIDE":                     0x113,
+	"BTN_EXTRA":                    0x114,
+	"BTN_FORWARD":                  0x115,
+	"BTN_BACK":                     0x116,
+	"BTN_TASK":                     0x117,
+	"BTN_JOYSTICK":                 0x120,
+	"BTN_TRIGGER":                  0x120,
+	"BTN_THUMB":                    0x121,
+	"BTN_THUMB2":                   0x122,
+	"BTN_TOP":                      0x123,
+	"BTN_TOP2":                     0x124,
+	"BTN_PINKIE":                   0x125,
+	"BTN_BASE":                     0x126,
+	"BTN_BASE2":                    0x127,
+	"BTN_BASE3":                    0x128,
+	"BTN_BASE4":                    0x129,
+	"BTN_BASE5":                    0x12a,
+	"BTN_BASE6":                    0x12b,
+	"BTN_DEAD":                     0x12f,
+	"BTN_GAMEPAD":                  0x130,
+	"BTN_SOUTH":                    0x130,
+	"BTN_A":                        0x130,
+	"BTN_EAST":                     0x131,
+	"BTN_B":                        0x131,
+	"BTN_C":                        0x132,
+	"BTN_NORTH":                    0x133,
+	"BTN_X":                        0x133,
+	"BTN_WEST":                     0x134,
+	"BTN_Y":                        0x134,
+	"BTN_Z":                        0x135,
+	"BTN_TL":                       0x136,
+	"BTN_TR":                       0x137,
+	"BTN_TL2":                      0x138,
+	"BTN_TR2":                      0x139,
+	"BTN_SELECT":                   0x13a,
+	"BTN_START":                    0x13b,
+	"BTN_MODE":                     0x13c,
+	"BTN_THUMBL":                   0x13d,
+	"BTN_THUMBR":                   0x13e,
+	"BTN_DIGI":                     0x140,
+	"BTN_TOOL_PEN":                 0x140,
+	"BTN_TOOL_RUBBER":              0x141,
+	"BTN_TOOL_BRUSH":               0x142,
+	"BTN_TOOL_PENCIL":              0x143,
+	"BTN_TOOL_AIRBRUSH":            0x144,
+	"BTN_TOOL_FINGER":              0x145,
+	"BTN_TOOL_MOUSE":               0x146,
+	"BTN_TOOL_LENS":                0x147,
+	"BTN_TOOL_QUINTTAP":            0x148,
+	"BTN_STYLUS3":                  0x149,
+	"BTN_TOUCH":                    0x14a,
+	"BTN_STYLUS":                   0x14b,
+	"BTN_STYLUS2":                  0x14c,
+	"BTN_TOOL_DOUBLETAP":           0x14d,
+	"BTN_TOOL_TRIPLETAP":           0x14e,
+	"BTN_TOOL_QUADTAP":             0x14f,
+	"BTN_WHEEL":                    0x150,
+	"BTN_GEAR_DOWN":                0x150,
+	"BTN_GEAR_UP":                  0x151,
+	"BTN_DPAD_UP":                  0x220,
+	"BTN_DPAD_DOWN":                0x221,
+	"BTN_DPAD_LEFT":                0x222,
+	"BTN_DPAD_RIGHT":               0x223,
+	"BTN_TRIGGER_HAPPY":            0x2c0,
+	"BTN_TRIGGER_HAPPY1":           0x2c0,
+	"BTN_TRIGGER_HAPPY2":           0x2c1,
+	"BTN_TRIGGER_HAPPY3":           0x2c2,
+	"BTN_TRIGGER_HAPPY4":           0x2c3,
+	"BTN_TRIGGER_HAPPY5":           0x2c4,
+	"BTN_TRIGGER_HAPPY6":           0x2c5,
+	"BTN_TRIGGER_HAPPY7":           0x2c6,
+	"BTN_TRIGGER_HAPPY8":           0x2c7,
+	"BTN_TRIGGER_HAPPY9":           0x2c8,
+	"BTN_TRIGGER_HAPPY10":          0x2c9,
+	"BTN_TRIGGER_HAPPY11":          0x2ca,
+	"BTN_TRIGGER_HAPPY12":          0x2cb,
+	"BTN_TRIGGER_HAPPY13":          0x2cc,
+	"BTN_TRIGGER_HAPPY14":          0x2cd,
+	"BTN_TRIGGER_HAPPY15":          0x2ce,
+	"BTN_TRIGGER_HAPPY16":          0x2cf,
+	"BTN_TRIGGER_HAPPY17":          0x2d0,
+	"BTN_TRIGGER_HAPPY18":          0x2d1,
+	"BTN_TRIGGER_HAPPY19":          0x2d2,
+	"BTN_TRIGGER_HAPPY20":          0x2d3,
+	"BTN_TRIGGER_HAPPY21":          0x2d4,
+	"BTN_TRIGGER_HAPPY22":          0x2d5,
+	"BTN_TRIGGER_HAPPY23":          0x2d6,
+	"BTN_TRIGGER_HAPPY24":          0x2d7,
+	"BTN_TRIGGER_HAPPY25":          0x2d8,
+	"BTN_TRIGGER_HAPPY26":          0x2d9,
+	"BTN_TRIGGER_HAPPY27":          0x2da,
+	"BTN_TRIGGER_HAPPY28":          0x2db,
+	"BTN_TRIGGER_HAPPY29":          0x2dc,
+	"BTN_TRIGGER_HAPPY30":          0x2dd,
+	"BTN_TRIGGER_HAPPY31":          0x2de,
+	"BTN_TRIGGER_HAPPY32":          0x2df,
+	"BTN_TRIGGER_HAPPY33":          0x2e0,
+	"BTN_TRIGGER_HAPPY34":          0x2e1,
+	"BTN_TRIGGER_HAPPY35":          0x2e2,
+	"BTN_TRIGGER_HAPPY36":          0x2e3,
+	"BTN_TRIGGER_HAPPY37":          0x2e4,
+	"BTN_TRIGGER_HAPPY38":          0x2e5,
+	"BTN_TRIGGER_HAPPY39":          0x2e6,
+	"BTN_TRIGGER_HAPPY40":          0x2e7,
+	"ABS_X":                        0x00,
+	"ABS_Y":                        0x01,
+	"ABS_Z":                        0x02,
+	"ABS_RX":                       0x03,
+	"ABS_RY":                       0x04,
+	"ABS_RZ":                       0x05,
+	"ABS_THROTTLE":                 0x06,
+	"ABS_RUDDER":                   0x07,
+	"ABS_WHEEL":                    0x08,
+	"ABS_GAS":                      0x09,
+	"ABS_BRAKE":                    0x0a,
+	"ABS_HAT0X":                    0x10,
+	"ABS_HAT0Y":                    0x11,
+	"ABS_HAT1X":                    0x12,
+	"ABS_HAT1Y":                    0x13,
+	"ABS_HAT2X":                    0x14,
+	"ABS_HAT2Y":                    0x15,
+	"ABS_HAT3X":                    0x16,
+	"ABS_HAT3Y":                    0x17,
+	"ABS_PRESSURE":                 0x18,
+	"ABS_DISTANCE":                 0x19,
+	"ABS_TILT_X":                   0x1a,
+	"ABS_TILT_Y":                   0x1b,
+	"ABS_TOOL_WIDTH":               0x1c,
+	"ABS_VOLUME":                   0x20,
+	"ABS_PROFILE":                  0x21,
+	"ABS_MISC":                     0x28,
+	"ABS_RESERVED":                 0x2e,
+	"ABS_MT_SLOT":                  0x2f,
+	"ABS_MT_TOUCH_MAJOR":           0x30,
+	"ABS_MT_TOUCH_MINOR":           0x31,
+	"ABS_MT_WIDTH_MAJOR":           0x32,
+	"ABS_MT_WIDTH_MINOR":           0x33,
+	"ABS_MT_ORIENTATION":           0x34,
+	"ABS_MT_POSITION_X":            0x35,
+	"ABS_MT_POSITION_Y":            0x36,
+	"ABS_MT_TOOL_TYPE":             0x37,
+	"ABS_MT_BLOB_ID":               0x38,
+	"ABS_MT_TRACKING_ID":           0x39,
+	"ABS_MT_PRESSURE":              0x3a,
+	"ABS_MT_DISTANCE":              0x3b,
+	"ABS_MT_TOOL_X":                0x3c,
+	"ABS_MT_TOOL_Y":                0x3d,
+	"REL_X":                        0x00,
+	"REL_Y":                        0x01,
+	"REL_Z":                        0x02,
+	"REL_RX":                       0x03,
+	"REL_RY":                       0x04,
+	"REL_RZ":                       0x05,
+	"REL_HWHEEL":                   0x06,
+	"REL_DIAL":                     0x07,
+	"REL_WHEEL":                    0x08,
+	"REL_MISC":                     0x09,
+	"REL_RESERVED":                 0x0a,
+	"REL_WHEEL_HI_RES":             0x0b,
+	"REL_HWHEEL_HI_RES":            0x0c,
+	"MSC_SERIAL":                   0x00,
+	"MSC_PULSELED":                 0x01,
+	"MSC_GESTURE":                  0x02,
+	"MSC_RAW":                      0x03,
+	"MSC_SCAN":                     0x04,
+	"MSC_TIMESTAMP":                0x05,
+	"SW_LID":                       0x00,
+	"SW_TABLET_MODE":               0x01,
+	"SW_HEADPHONE_INSERT":          0x02,
+	"SW_RFKILL_ALL":                0x03,
+	"SW_RADIO":                     0x03,
+	"SW_MICROPHONE_INSERT":         0x04,
+	"SW_DOCK":                      0x05,
+	"SW_LINEOUT_INSERT":            0x06,
+	"SW_JACK_PHYSICAL_INSERT":      0x07,
+	"SW_VIDEOOUT_INSERT":           0x08,
+	"SW_CAMERA_LENS_COVER":         0x09,
+	"SW_KEYPAD_SLIDE":              0x0a,
+	"SW_FRONT_PROXIMITY":           0x0b,
+	"SW_ROTATE_LOCK":               0x0c,
+	"SW_LINEIN_INSERT":             0x0d,
+	"SW_MUTE_DEVICE":               0x0e,
+	"SW_PEN_INSERTED":              0x0f,
+	"SW_MACHINE_COVER":             0x10,
+	"LED_NUML":                     0x00,
+	"LED_CAPSL":                    0x01,
+	"LED_SCROLLL":                  0x02,
+	"LED_COMPOSE":                  0x03,
+	"LED_KANA":                     0x04,
+	"LED_SLEEP":                    0x05,
+	"LED_SUSPEND":                  0x06,
+	"LED_MUTE":                     0x07,
+	"LED_MISC":                     0x08,
+	"LED_MAIL":                     0x09,
+	"LED_CHARGING":                 0x0a,
+	"SND_CLICK":                    0x00,
+	"SND_BELL":                     0x01,
+	"SND_TONE":                     0x02,
+	"REP_DELAY":                    0x00,
+	"REP_PERIOD":                   0x01,
+}
+
+// codeNames maps the codes of all event types to their kernel names
+var codeNames = map[uint16]map[uint16]string{
+	EvSyn: {
+		0x0: "SYN_REPORT",
+		0x1: "SYN_CONFIG",
+		0x2: "SYN_MT_REPORT",
+		0x3: "SYN_DROPPED",
+	},
+	EvKey: {
+		0x0:   "KEY_RESERVED",
+		0x1:   "KEY_ESC",
+		0x2:   "KEY_1",
+		0x3:   "KEY_2",
+		0x4:   "KEY_3",
+		0x5:   "KEY_4",
+		0x6:   "KEY_5",
+		0x7:   "KEY_6",
+		0x8:   "KEY_7",
+		0x9:   "KEY_8",
+		0xa:   "KEY_9",
+		0xb:   "KEY_0",
+		0xc:   "KEY_MINUS",
+		0xd:   "KEY_EQUAL",
+		0xe:   "KEY_BACKSPACE",
+		0xf:   "KEY_TAB",
+		0x10:  "KEY_Q",
+		0x11:  "KEY_W",
+		0x12:  "KEY_E",
+		0x13:  "KEY_R",
+		0x14:  "KEY_T",
+		0x15:  "KEY_Y",
+		0x16:  "KEY_U",
+		0x17:  "KEY_I",
+		0x18:  "KEY_O",
+		0x19:  "KEY_P",
+		0x1a:  "KEY_LEFTBRACE",
+		0x1b:  "KEY_RIGHTBRACE",
+		0x1c:  "KEY_ENTER",
+		0x1d:  "KEY_LEFTCTRL",
+		0x1e:  "KEY_A",
+		0x1f:  "KEY_S",
+		0x20:  "KEY_D",
+		0x21:  "KEY_F",
+		0x22:  "KEY_G",
+		0x23:  "KEY_H",
+		0x24:  "KEY_J",
+		0x25:  "KEY_K",
+		0x26:  "KEY_L",
+		0x27:  "KEY_SEMICOLON",
+		0x28:  "KEY_APOSTROPHE",
+		0x29:  "KEY_GRAVE",
+		0x2a:  "KEY_LEFTSHIFT",
+		0x2b:  "KEY_BACKSLASH",
+		0x2c:  "KEY_Z",
+		0x2d:  "KEY_X",
+		0x2e:  "KEY_C",
+		0x2f:  "KEY_V",
+		0x30:  "KEY_B",
+		0x31:  "KEY_N",
+		0x32:  "KEY_M",
+		0x33:  "KEY_COMMA",
+		0x34:  "KEY_DOT",
+		0x35:  "KEY_SLASH",
+		0x36:  "KEY_RIGHTSHIFT",
+		0x37:  "KEY_KPASTERISK",
+		0x38:  "KEY_LEFTALT",
+		0x39:  "KEY_SPACE",
+		0x3a:  "KEY_CAPSLOCK",
+		0x3b:  "KEY_F1",
+		0x3c:  "KEY_F2",
+		0x3d:  "KEY_F3",
+		0x3e:  "KEY_F4",
+		0x3f:  "KEY_F5",
+		0x40:  "KEY_F6",
+		0x41:  "KEY_F7",
+		0x42:  "KEY_F8",
+		0x43:  "KEY_F9",
+		0x44:  "KEY_F10",
+		0x45:  "KEY_NUMLOCK",
+		0x46:  "KEY_SCROLLLOCK",
+		0x47:  "KEY_KP7",
+		0x48:  "KEY_KP8",
+		0x49:  "KEY_KP9",
+		0x4a:  "KEY_KPMINUS",
+		0x4b:  "KEY_KP4",
+		0x4c:  "KEY_KP5",
+		0x4d:  "KEY_KP6",
+		0x4e:  "KEY_KPPLUS",
+		0x4f:  "KEY_KP1",
+		0x50:  "KEY_KP2",
+		0x51:  "KEY_KP3",
+		0x52:  "KEY_KP0",
+		0x53:  "KEY_KPDOT",
+		0x55:  "KEY_ZENKAKUHANKAKU",
+		0x56:  "KEY_102ND",
+		0x57:  "KEY_F11",
+		0x58:  "KEY_F12",
+		0x59:  "KEY_RO",
+		0x5a:  "KEY_KATAKANA",
+		0x5b:  "KEY_HIRAGANA",
+		0x5c:  "KEY_HENKAN",
+		0x5d:  "KEY_KATAKANAHIRAGANA",
+		0x5e:  "KEY_MUHENKAN",
+		0x5f:  "KEY_KPJPCOMMA",
+		0x60:  "KEY_KPENTER",
+		0x61:  "KEY_RIGHTCTRL",
+		0x62:  "KEY_KPSLASH",
+		0x63:  "KEY_SYSRQ",
+		0x64:  "KEY_RIGHTALT",
+		0x65:  "KEY_LINEFEED",
+		0x66:  "KEY_HOME",
+		0x67:  "KEY_UP",
+		0x68:  "KEY_PAGEUP",
+		0x69:  "KEY_LEFT",
+		0x6a:  "KEY_RIGHT",
+		0x6b:  "KEY_END",
+		0x6c:  "KEY_DOWN",
+		0x6d:  "KEY_PAGEDOWN",
+		0x6e:  "KEY_INSERT",
+		0x6f:  "KEY_DELETE",
+		0x70:  "KEY_MACRO",
+		0x71:  "KEY_MUTE",
+		0x72:  "KEY_VOLUMEDOWN",
+		0x73:  "KEY_VOLUMEUP",
+		0x74:  "KEY_POWER",
+		0x75:  "KEY_KPEQUAL",
+		0x76:  "KEY_KPPLUSMINUS",
+		0x77:  "KEY_PAUSE",
+		0x78:  "KEY_SCALE",
+		0x79:  "KEY_KPCOMMA",
+		0x7a:  "KEY_HANGEUL",
+		0x7b:  "KEY_HANJA",
+		0x7c:  "KEY_YEN",
+		0x7d:  "KEY_LEFTMETA",
+		0x7e:  "KEY_RIGHTMETA",
+		0x7f:  "KEY_COMPOSE",
+		0x80:  "KEY_STOP",
+		0x81:  "KEY_AGAIN",
+		0x82:  "KEY_PROPS",
+		0x83:  "KEY_UNDO",
+		0x84:  "KEY_FRONT",
+		0x85:  "KEY_COPY",
+		0x86:  "KEY_OPEN",
+		0x87:  "KEY_PASTE",
+		0x88:  "KEY_FIND",
+		0x89:  "KEY_CUT",
+		0x8a:  "KEY_HELP",
+		0x8b:  "KEY_MENU",
+		0x8c:  "KEY_CALC",
+		0x8d:  "KEY_SETUP",
+		0x8e:  "KEY_SLEEP",
+		0x8f:  "KEY_WAKEUP",
+		0x90:  "KEY_FILE",
+		0x91:  "KEY_SENDFILE",
+		0x92:  "KEY_DELETEFILE",
+		0x93:  "KEY_XFER",
+		0x94:  "KEY_PROG1",
+		0x95:  "KEY_PROG2",
+		0x96:  "KEY_WWW",
+		0x97:  "KEY_MSDOS",
+		0x98:  "KEY_COFFEE",
+		0x99:  "KEY_ROTATE_DISPLAY",
+		0x9a:  "KEY_CYCLEWINDOWS",
+		0x9b:  "KEY_MAIL",
+		0x9c:  "KEY_BOOKMARKS",
+		0x9d:  "KEY_COMPUTER",
+		0x9e:  "KEY_BACK",
+		0x9f:  "KEY_FORWARD",
+		0xa0:  "KEY_CLOSECD",
+		0xa1:  "KEY_EJECTCD",
+		0xa2:  "KEY_EJECTCLOSECD",
+		0xa3:  "KEY_NEXTSONG",
+		0xa4:  "KEY_PLAYPAUSE",
+		0xa5:  "KEY_PREVIOUSSONG",
+		0xa6:  "KEY_STOPCD",
+		0xa7:  "KEY_RECORD",
+		0xa8:  "KEY_REWIND",
+		0xa9:  "KEY_PHONE",
+		0xaa:  "KEY_ISO",
+		0xab:  "KEY_CONFIG",
+		0xac:  "KEY_HOMEPAGE",
+		0xad:  "KEY_REFRESH",
+		0xae:  "KEY_EXIT",
+		0xaf:  "KEY_MOVE",
+		0xb0:  "KEY_EDIT",
+		0xb1:  "KEY_SCROLLUP",
+		0xb2:  "KEY_SCROLLDOWN",
+		0xb3:  "KEY_KPLEFTPAREN",
+		0xb4:  "KEY_KPRIGHTPAREN",
+		0xb5:  "KEY_NEW",
+		0xb6:  "KEY_REDO",
+		0xb7:  "KEY_F13",
+		0xb8:  "KEY_F14",
+		0xb9:  "KEY_F15",
+		0xba:  "KEY_F16",
+		0xbb:  "KEY_F17",
+		0xbc:  "KEY_F18",
+		0xbd:  "KEY_F19",
+		0xbe:  "KEY_F20",
+		0xbf:  "KEY_F21",
+		0xc0:  "KEY_F22",
+		0xc1:  "KEY_F23",
+		0xc2:  "KEY_F24",
+		0xc8:  "KEY_PLAYCD",
+		0xc9:  "KEY_PAUSECD",
+		0xca:  "KEY_PROG3",
+		0xcb:  "KEY_PROG4",
+		0xcc:  "KEY_ALL_APPLICATIONS",
+		0xcd:  "KEY_SUSPEND",
+		0xce:  "KEY_CLOSE",
+		0xcf:  "KEY_PLAY",
+		0xd0:  "KEY_FASTFORWARD",
+		0xd1:  "KEY_BASSBOOST",
+		0xd2:  "KEY_PRINT",
+		0xd3:  "KEY_HP",
+		0xd4:  "KEY_CAMERA",
+		0xd5:  "KEY_SOUND",
+		0xd6:  "KEY_QUESTION",
+		0xd7:  "KEY_EMAIL",
+		0xd8:  "KEY_CHAT",
+		0xd9:  "KEY_SEARCH",
+		0xda:  "KEY_CONNECT",
+		0xdb:  "KEY_FINANCE",
+		0xdc:  "KEY_SPORT",
+		0xdd:  "KEY_SHOP",
+		0xde:  "KEY_ALTERASE",
+		0xdf:  "KEY_CANCEL",
+		0xe0:  "KEY_BRIGHTNESSDOWN",
+		0xe1:  "KEY_BRIGHTNESSUP",
+		0xe2:  "KEY_MEDIA",
+		0xe3:  "KEY_SWITCHVIDEOMODE",
+		0xe4:  "KEY_KBDILLUMTOGGLE",
+		0xe5:  "KEY_KBDILLUMDOWN",
+		0xe6:  "KEY_KBDILLUMUP",
+		0xe7:  "KEY_SEND",
+		0xe8:  "KEY_REPLY",
+		0xe9:  "KEY_FORWARDMAIL",
+		0xea:  "KEY_SAVE",
+		0xeb:  "KEY_DOCUMENTS",
+		0xec:  "KEY_BATTERY",
+		0xed:  "KEY_BLUETOOTH",
+		0xee:  "KEY_WLAN",
+		0xef:  "KEY_UWB",
+		0xf0:  "KEY_UNKNOWN",
+		0xf1:  "KEY_VIDEO_NEXT",
+		0xf2:  "KEY_VIDEO_PREV",
+		0xf3:  "KEY_BRIGHTNESS_CYCLE",
+		0xf4:  "KEY_BRIGHTNESS_AUTO",
+		0xf5:  "KEY_DISPLAY_OFF",
+		0xf6:  "KEY_WWAN",
+		0xf7:  "KEY_RFKILL",
+		0xf8:  "KEY_MICMUTE",
+		0x160: "KEY_OK",
+		0x161: "KEY_SELECT",
+		0x162: "KEY_GOTO",
+		0x163: "KEY_CLEAR",
+		0x164: "KEY_POWER2",
+		0x165: "KEY_OPTION",
+		0x166: "KEY_INFO",
+		0x167: "KEY_TIME",
+		0x168: "KEY_VENDOR",
+		0x169: "KEY_ARCHIVE",
+		0x16a: "KEY_PROGRAM",
+		0x16b: "KEY_CHANNEL",
+		0x16c: "KEY_FAVORITES",
+		0x16d: "KEY_EPG",
+		0x16e: "KEY_PVR",
+		0x16f: "KEY_MHP",
+		0x170: "KEY_LANGUAGE",
+		0x171: "KEY_TITLE",
+		0x172: "KEY_SUBTITLE",
+		0x173: "KEY_ANGLE",
+		0x174: "KEY_FULL_SCREEN",
+		0x175: "KEY_MODE",
+		0x176: "KEY_KEYBOARD",
+		0x177: "KEY_ASPECT_RATIO",
+		0x178: "KEY_PC",
+		0x179: "KEY_TV",
+		0x17a: "KEY_TV2",
+		0x17b: "KEY_VCR",
+		0x17c: "KEY_VCR2",
+		0x17d: "KEY_SAT",
+		0x17e: "KEY_SAT2",
+		0x17f: "KEY_CD",
+		0x180: "KEY_TAPE",
+		0x181: "KEY_RADIO",
+		0x182: "KEY_TUNER",
+		0x183: "KEY_PLAYER",
+		0x184: "KEY_TEXT",
+		0x185: "KEY_DVD",
+		0x186: "KEY_AUX",
+		0x187: "KEY_MP3",
+		0x188: "KEY_AUDIO",
+		0x189: "KEY_VIDEO",
+		0x18a: "KEY_DIRECTORY",
+		0x18b: "KEY_LIST",
+		0x18c: "KEY_MEMO",
+		0x18d: "KEY_CALENDAR",
+		0x18e: "KEY_RED",
+		0x18f: "KEY_GREEN",
+		0x190: "KEY_YELLOW",
+		0x191: "KEY_BLUE",
+		0x192: "KEY_CHANNELUP",
+		0x193: "KEY_CHANNELDOWN",
+		0x194: "KEY_FIRST",
+		0x195: "KEY_LAST",
+		0x196: "KEY_AB",
+		0x197: "KEY_NEXT",
+		0x198: "KEY_RESTART",
+		0x199: "KEY_SLOW",
+		0x19a: "KEY_SHUFFLE",
+		0x19b: "KEY_BREAK",
+		0x19c: "KEY_PREVIOUS",
+		0x19d: "KEY_DIGITS",
+		0x19e: "KEY_TEEN",
+		0x19f: "KEY_TWEN",
+		0x1a0: "KEY_VIDEOPHONE",
+		0x1a1: "KEY_GAMES",
+		0x1a2: "KEY_ZOOMIN",
+		0x1a3: "KEY_ZOOMOUT",
+		0x1a4: "KEY_ZOOMRESET",
+		0x1a5: "KEY_WORDPROCESSOR",
+		0x1a6: "KEY_EDITOR",
+		0x1a7: "KEY_SPREADSHEET",
+		0x1a8: "KEY_GRAPHICSEDITOR",
+		0x1a9: "KEY_PRESENTATION",
+		0x1aa: "KEY_DATABASE",
+		0x1ab: "KEY_NEWS",
+		0x1ac: "KEY_VOICEMAIL",
+		0x1ad: "KEY_ADDRESSBOOK",
+		0x1ae: "KEY_MESSENGER",
+		0x1af: "KEY_DISPLAYTOGGLE",
+		0x1b0: "KEY_SPELLCHECK",
+		0x1b1: "KEY_LOGOFF",
+		0x1b2: "KEY_DOLLAR",
+		0x1b3: "KEY_EURO",
+		0x1b4: "KEY_FRAMEBACK",
+		0x1b5: "KEY_FRAMEFORWARD",
+		0x1b6: "KEY_CONTEXT_MENU",
+		0x1b7: "KEY_MEDIA_REPEAT",
+		0x1b8: "KEY_10CHANNELSUP",
+		0x1b9: "KEY_10CHANNELSDOWN",
+		0x1ba: "KEY_IMAGES",
+		0x1bc: "KEY_NOTIFICATION_CENTER",
+		0x1bd: "KEY_PICKUP_PHONE",
+		0x1be: "KEY_HANGUP_PHONE",
+		0x1bf: "KEY_LINK_PHONE",
+		0x1c0: "KEY_DEL_EOL",
+		0x1c1: "KEY_DEL_EOS",
+		0x1c2: "KEY_INS_LINE",
+		0x1c3: "KEY_DEL_LINE",
+		0x1d0: "KEY_FN",
+		0x1d1: "KEY_FN_ESC",
+		0x1d2: "KEY_FN_F1",
+		0x1d3: "KEY_FN_F2",
+		0x1d4: "KEY_FN_F3",
+		0x1d5: "KEY_FN_F4",
+		0x1d6: "KEY_FN_F5",
+		0x1d7: "KEY_FN_F6",
+		0x1d8: "KEY_FN_F7",
+		0x1d9: "KEY_FN_F8",
+		0x1da: "KEY_FN_F9",
+		0x1db: "KEY_FN_F10",
+		0x1dc: "KEY_FN_F11",
+		0x1dd: "KEY_FN_F12",
+		0x1de: "KEY_FN_1",
+		0x1df: "KEY_FN_2",
+		0x1e0: "KEY_FN_D",
+		0x1e1: "KEY_FN_E",
+		0x1e2: "KEY_FN_F",
+		0x1e3: "KEY_FN_S",
+		0x1e4: "KEY_FN_B",
+		0x1e5: "KEY_FN_RIGHT_SHIFT",
+		0x1f1: "KEY_BRL_DOT1",
+		0x1f2: "KEY_BRL_DOT2",
+		0x1f3: "KEY_BRL_DOT3",
+		0x1f4: "KEY_BRL_DOT4",
+		0x1f5: "KEY_BRL_DOT5",
+		0x1f6: "KEY_BRL_DOT6",
+		0x1f7: "KEY_BRL_DOT7",
+		0x1f8: "KEY_BRL_DOT8",
+		0x1f9: "KEY_BRL_DOT9",
+		0x1fa: "KEY_BRL_DOT10",
+		0x200: "KEY_NUMERIC_0",
+		0x201: "KEY_NUMERIC_1",
+		0x202: "KEY_NUMERIC_2",
+		0x203: "KEY_NUMERIC_3",
+		0x204: "KEY_NUMERIC_4",
+		0x205: "KEY_NUMERIC_5",
+		0x206: "KEY_NUMERIC_6",
+		0x207: "KEY_NUMERIC_7",
+		0x208: "KEY_NUMERIC_8",
+		0x209: "KEY_NUMERIC_9",
+		0x20a: "KEY_NUMERIC_STAR",
+		0x20b: "KEY_NUMERIC_POUND",
+		0x20c: "KEY_NUMERIC_A",
+		0x20d: "KEY_NUMERIC_B",
+		0x20e: "KEY_NUMERIC_C",
+		0x20f: "KEY_NUMERIC_D",
+		0x210: "KEY_CAMERA_FOCUS",
+		0x211: "KEY_WPS_BUTTON",
+		0x212: "KEY_TOUCHPAD_TOGGLE",
+		0x213: "KEY_TOUCHPAD_ON",
+		0x214: "KEY_TOUCHPAD_OFF",
+		0x215: "KEY_CAMERA_ZOOMIN",
+		0x216: "KEY_CAMERA_ZOOMOUT",
+		0x217: "KEY_CAMERA_UP",
+		0x218: "KEY_CAMERA_DOWN",
+		0x219: "KEY_CAMERA_LEFT",
+		0x21a: "KEY_CAMERA_RIGHT",
+		0x21b: "KEY_ATTENDANT_ON",
+		0x21c: "KEY_ATTENDANT_OFF",
+		0x21d: "KEY_ATTENDANT_TOGGLE",
+		0x21e: "KEY_LIGHTS_TOGGLE",
+		0x230: "KEY_ALS_TOGGLE",
+		0x231: "KEY_ROTATE_LOCK_TOGGLE",
+		0x232: "KEY_REFRESH_RATE_TOGGLE",
+		0x240: "KEY_BUTTONCONFIG",
+		0x241: "KEY_TASKMANAGER",
+		0x242: "KEY_JOURNAL",
+		0x243: "KEY_CONTROLPANEL",
+		0x244: "KEY_APPSELECT",
+		0x245: "KEY_SCREENSAVER",
+		0x246: "KEY_VOICECOMMAND",
+		0x247: "KEY_ASSISTANT",
+		0x248: "KEY_KBD_LAYOUT_NEXT",
+		0x249: "KEY_EMOJI_PICKER",
+		0x24a: "KEY_DICTATE",
+		0x250: "KEY_BRIGHTNESS_MIN",
+		0x260: "KEY_KBDINPUTASSIST_PREV",
+		0x261: "KEY_KBDINPUTASSIST_NEXT",
+		0x262: "KEY_KBDINPUTASSIST_PREVGROUP",
+		0x263: "KEY_KBDINPUTASSIST_NEXTGROUP",
+		0x264: "KEY_KBDINPUTASSIST_ACCEPT",
+		0x265: "KEY_KBDINPUTASSIST_CANCEL",
+		0x266: "KEY_RIGHT_UP",
+		0x267: "KEY_RIGHT_DOWN",
+		0x268: "KEY_LEFT_UP",
+		0x269: "KEY_LEFT_DOWN",
+		0x26a: "KEY_ROOT_MENU",
+		0x26b: "KEY_MEDIA_TOP_MENU",
+		0x26c: "KEY_NUMERIC_11",
+		0x26d: "KEY_NUMERIC_12",
+		0x26e: "KEY_AUDIO_DESC",
+		0x26f: "KEY_3D_MODE",
+		0x270: "KEY_NEXT_FAVORITE",
+		0x271: "KEY_STOP_RECORD",
+		0x272: "KEY_PAUSE_RECORD",
+		0x273: "KEY_VOD",
+		0x274: "KEY_UNMUTE",
+		0x275: "KEY_FASTREVERSE",
+		0x276: "KEY_SLOWREVERSE",
+		0x277: "KEY_DATA",
+		0x278: "KEY_ONSCREEN_KEYBOARD",
+		0x279: "KEY_PRIVACY_SCREEN_TOGGLE",
+		0x27a: "KEY_SELECTIVE_SCREENSHOT",
+		0x27b: "KEY_NEXT_ELEMENT",
+		0x27c: "KEY_PREVIOUS_ELEMENT",
+		0x27d: "KEY_AUTOPILOT_ENGAGE_TOGGLE",
+		0x27e: "KEY_MARK_WAYPOINT",
+		0x27f: "KEY_SOS",
+		0x280: "KEY_NAV_CHART",
+		0x281: "KEY_FISHING_CHART",
+		0x282: "KEY_SINGLE_RANGE_RADAR",
+		0x283: "KEY_DUAL_RANGE_RADAR",
+		0x284: "KEY_RADAR_OVERLAY",
+		0x285: "KEY_TRADITIONAL_SONAR",
+		0x286: "KEY_CLEARVU_SONAR",
+		0x287: "KEY_SIDEVU_SONAR",
+		0x288: "KEY_NAV_INFO",
+		0x289: "KEY_BRIGHTNESS_MENU",
+		0x290: "KEY_MACRO1",
+		0x291: "KEY_MACRO2",
+		0x292: "KEY_MACRO3",
+		0x293: "KEY_MACRO4",
+		0x294: "KEY_MACRO5",
+		0x295: "KEY_MACRO6",
+		0x296: "KEY_MACRO7",
+		0x297: "KEY_MACRO8",
+		0x298: "KEY_MACRO9",
+		0x299: "KEY_MACRO10",
+		0x29a: "KEY_MACRO11",
+		0x29b: "KEY_MACRO12",
+		0x29c: "KEY_MACRO13",
+		0x29d: "KEY_MACRO14",
+		0x29e: "KEY_MACRO15",
+		0x29f: "KEY_MACRO16",
+		0x2a0: "KEY_MACRO17",
+		0x2a1: "KEY_MACRO18",
+		0x2a2: "KEY_MACRO19",
+		0x2a3: "KEY_MACRO20",
+		0x2a4: "KEY_MACRO21",
+		0x2a5: "KEY_MACRO22",
+		0x2a6: "KEY_MACRO23",
+		0x2a7: "KEY_MACRO24",
+		0x2a8: "KEY_MACRO25",
+		0x2a9: "KEY_MACRO26",
+		0x2aa: "KEY_MACRO27",
+		0x2ab: "KEY_MACRO28",
+		0x2ac: "KEY_MACRO29",
+		0x2ad: "KEY_MACRO30",
+		0x2b0: "KEY_MACRO_RECORD_START",
+		0x2b1: "KEY_MACRO_RECORD_STOP",
+		0x2b2: "KEY_MACRO_PRESET_CYCLE",
+		0x2b3: "KEY_MACRO_PRESET1",
+		0x2b4: "KEY_MACRO_PRESET2",
+		0x2b5: "KEY_MACRO_PRESET3",
+		0x2b8: "KEY_KBD_LCD_MENU1",
+		0x2b9: "KEY_KBD_LCD_MENU2",
+		0x2ba: "KEY_KBD_LCD_MENU3",
+		0x2bb: "KEY_KBD_LCD_MENU4",
+		0x2bc: "KEY_KBD_LCD_MENU5",
+		0x100: "BTN_0",
+		0x101: "BTN_1",
+		0x102: "BTN_2",
+		0x103: "BTN_3",
+		0x104: "BTN_4",
+		0x105: "BTN_5",
+		0x106: "BTN_6",
+		0x107: "BTN_7",
+		0x108: "BTN_8",
+		0x109: "BTN_9",
+		0x110: "BTN_LEFT",
+		0x111: "BTN_RIGHT",
+		0x112: "BTN_MIDDLE",
+		0x113: "BTN_SIDE",
+		0x114: "BTN_EXTRA",
+		0x115: "BTN_FORWARD",
+		0x116: "BTN_BACK",
+		0x117: "BTN_TASK",
+		0x120: "BTN_TRIGGER",
+		0x121: "BTN_THUMB",
+		0x122: "BTN_THUMB2",
+		0x123: "BTN_TOP",
+		0x124: "BTN_TOP2",
+		0x125: "BTN_PINKIE",
+		0x126: "BTN_BASE",
+		0x127: "BTN_BASE2",
+		0x128: "BTN_BASE3",
+		0x129: "BTN_BASE4",
+		0x12a: "BTN_BASE5",
+		0x12b: "BTN_BASE6",
+		0x12f: "BTN_DEAD",
+		0x130: "BTN_SOUTH",
+		0x131: "BTN_EAST",
+		0x132: "BTN_C",
+		0x133: "BTN_NORTH",
+		0x134: "BTN_WEST",
+		0x135: "BTN_Z",
+		0x136: "BTN_TL",
+		0x137: "BTN_TR",
+		0x138: "BTN_TL2",
+		0x139: "BTN_TR2",
+		0x13a: "BTN_SELECT",
+		0x13b: "BTN_START",
+		0x13c: "BTN_MODE",
+		0x13d: "BTN_THUMBL",
+		0x13e: "BTN_THUMBR",
+		0x140: "BTN_TOOL_PEN",
+		0x141: "BTN_TOOL_RUBBER",
+		0x142: "BTN_TOOL_BRUSH",
+		0x143: "BTN_TOOL_PENCIL",
+		0x144: "BTN_TOOL_AIRBRUSH",
+		0x145: "BTN_TOOL_FINGER",
+		0x146: "BTN_TOOL_MOUSE",
+		0x147: "BTN_TOOL_LENS",
+		0x148: "BTN_TOOL_QUINTTAP",
+		0x149: "BTN_STYLUS3",
+		0x14a: "BTN_TOUCH",
+		0x14b: "BTN_STYLUS",
+		0x14c: "BTN_STYLUS2",
+		0x14d: "BTN_TOOL_DOUBLETAP",
+		0x14e: "BTN_TOOL_TRIPLETAP",
+		0x14f: "BTN_TOOL_QUADTAP",
+		0x150: "BTN_GEAR_DOWN",
+		0x151: "BTN_GEAR_UP",
+		0x220: "BTN_DPAD_UP",
+		0x221: "BTN_DPAD_DOWN",
+		0x222: "BTN_DPAD_LEFT",
+		0x223: "BTN_DPAD_RIGHT",
+		0x2c0: "BTN_TRIGGER_HAPPY1",
+		0x2c1: "BTN_TRIGGER_HAPPY2",
+		0x2c2: "BTN_TRIGGER_HAPPY3",
+		0x2c3: "BTN_TRIGGER_HAPPY4",
+		0x2c4: "BTN_TRIGGER_HAPPY5",
+		0x2c5: "BTN_TRIGGER_HAPPY6",
+		0x2c6: "BTN_TRIGGER_HAPPY7",
+		0x2c7: "BTN_TRIGGER_HAPPY8",
+		0x2c8: "BTN_TRIGGER_HAPPY9",
+		0x2c9: "BTN_TRIGGER_HAPPY10",
+		0x2ca: "BTN_TRIGGER_HAPPY11",
+		0x2cb: "BTN_TRIGGER_HAPPY12",
+		0x2cc: "BTN_TRIGGER_HAPPY13",
+		0x2cd: "BTN_TRIGGER_HAPPY14",
+		0x2ce: "BTN_TRIGGER_HAPPY15",
+		0x2cf: "BTN_TRIGGER_HAPPY16",
+		0x2d0: "BTN_TRIGGER_HAPPY17",
+		0x2d1: "BTN_TRIGGER_HAPPY18",
+		0x2d2: "BTN_TRIGGER_HAPPY19",
+		0x2d3: "BTN_TRIGGER_HAPPY20",
+		0x2d4: "BTN_TRIGGER_HAPPY21",
+		0x2d5: "BTN_TRIGGER_HAPPY22",
+		0x2d6: "BTN_TRIGGER_HAPPY23",
+		0x2d7: "BTN_TRIGGER_HAPPY24",
+		0x2d8: "BTN_TRIGGER_HAPPY25",
+		0x2d9: "BTN_TRIGGER_HAPPY26",
+		0x2da: "BTN_TRIGGER_HAPPY27",
+		0x2db: "BTN_TRIGGER_HAPPY28",
+		0x2dc: "BTN_TRIGGER_HAPPY29",
+		0x2dd: "BTN_TRIGGER_HAPPY30",
+		0x2de: "BTN_TRIGGER_HAPPY31",
+		0x2df: "BTN_TRIGGER_HAPPY32",
+		0x2e0: "BTN_TRIGGER_HAPPY33",
+		0x2e1: "BTN_TRIGGER_HAPPY34",
+		0x2e2: "BTN_TRIGGER_HAPPY35",
+		0x2e3: "BTN_TRIGGER_HAPPY36",
+		0x2e4: "BTN_TRIGGER_HAPPY37",
+		0x2e5: "BTN_TRIGGER_HAPPY38",
+		0x2e6: "BTN_TRIGGER_HAPPY39",
+		0x2e7: "BTN_TRIGGER_HAPPY40",
+	},
+	EvAbs: {
+		0x0:  "ABS_X",
+		0x1:  "ABS_Y",
+		0x2:  "ABS_Z",
+		0x3:  "ABS_RX",
+		0x4:  "ABS_RY",
+		0x5:  "ABS_RZ",
+		0x6:  "ABS_THROTTLE",
+		0x7:  "ABS_RUDDER",
+		0x8:  "ABS_WHEEL",
+		0x9:  "ABS_GAS",
+		0xa:  "ABS_BRAKE",
+		0x10: "ABS_HAT0X",
+		0x11: "ABS_HAT0Y",
+		0x12: "ABS_HAT1X",
+		0x13: "ABS_HAT1Y",
+		0x14: "ABS_HAT2X",
+		0x15: "ABS_HAT2Y",
+		0x16: "ABS_HAT3X",
+		0x17: "ABS_HAT3Y",
+		0x18: "ABS_PRESSURE",
+		0x19: "ABS_DISTANCE",
+		0x1a: "ABS_TILT_X",
+		0x1b: "ABS_TILT_Y",
+		0x1c: "ABS_TOOL_WIDTH",
+		0x20: "ABS_VOLUME",
+		0x21: "ABS_PROFILE",
+		0x28: "ABS_MISC",
+		0x2e: "ABS_RESERVED",
+		0x2f: "ABS_MT_SLOT",
+		0x30: "ABS_MT_TOUCH_MAJOR",
+		0x31: "ABS_MT_TOUCH_MINOR",
+		0x32: "ABS_MT_WIDTH_MAJOR",
+		0x33: "ABS_MT_WIDTH_MINOR",
+		0x34: "ABS_MT_ORIENTATION",
+		0x35: "ABS_MT_POSITION_X",
+		0x36: "ABS_MT_POSITION_Y",
+		0x37: "ABS_MT_TOOL_TYPE",
+		0x38: "ABS_MT_BLOB_ID",
+		0x39: "ABS_MT_TRACKING_ID",
+		0x3a: "ABS_MT_PRESSURE",
+		0x3b: "ABS_MT_DISTANCE",
+		0x3c: "ABS_MT_TOOL_X",
+		0x3d: "ABS_MT_TOOL_Y",
+	},
+	EvRel: {
+		0x0: "REL_X",
+		0x1: "REL_Y",
+		0x2: "REL_Z",
+		0x3: "REL_RX",
+		0x4: "REL_RY",
+		0x5: "REL_RZ",
+		0x6: "REL_HWHEEL",
+		0x7: "REL_DIAL",
+		0x8: "REL_WHEEL",
+		0x9: "REL_MISC",
+		0xa: "REL_RESERVED",
+		0xb: "REL_WHEEL_HI_RES",
+		0xc: "REL_HWHEEL_HI_RES",
+	},
+	EvMsc: {
+		0x0: "MSC_SERIAL",
+		0x1: "MSC_PULSELED",
+		0x2: "MSC_GESTURE",
+		0x3: "MSC_RAW",
+		0x4: "MSC_SCAN",
+		0x5: "MSC_TIMESTAMP",
+	},
+	EvSw: {
+		0x0:  "SW_LID",
+		0x1:  "SW_TABLET_MODE",
+		0x2:  "SW_HEADPHONE_INSERT",
+		0x3:  "SW_RFKILL_ALL",
+		0x4:  "SW_MICROPHONE_INSERT",
+		0x5:  "SW_DOCK",
+		0x6:  "SW_LINEOUT_INSERT",
+		0x7:  "SW_JACK_PHYSICAL_INSERT",
+		0x8:  "SW_VIDEOOUT_INSERT",
+		0x9:  "SW_CAMERA_LENS_COVER",
+		0xa:  "SW_KEYPAD_SLIDE",
+		0xb:  "SW_FRONT_PROXIMITY",
+		0xc:  "SW_ROTATE_LOCK",
+		0xd:  "SW_LINEIN_INSERT",
+		0xe:  "SW_MUTE_DEVICE",
+		0xf:  "SW_PEN_INSERTED",
+		0x10: "SW_MACHINE_COVER",
+	},
+	EvLed: {
+		0x0: "LED_NUML",
+		0x1: "LED_CAPSL",
+		0x2: "LED_SCROLLL",
+		0x3: "LED_COMPOSE",
+		0x4: "LED_KANA",
+		0x5: "LED_SLEEP",
+		0x6: "LED_SUSPEND",
+		0x7: "LED_MUTE",
+		0x8: "LED_MISC",
+		0x9: "LED_MAIL",
+		0xa: "LED_CHARGING",
+	},
+	EvSnd: {
+		0x0: "SND_CLICK",
+		0x1: "SND_BELL",
+		0x2: "SND_TONE",
+	},
+	EvRep: {
+		0x0: "REP_DELAY",
+		0x1: "REP_PERIOD",
+	},
+}
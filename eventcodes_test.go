@@ -0,0 +1,26 @@
+package uinput
+
+import "testing"
+
+func TestGeneratedCodesMatchHandWrittenCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		generated int
+		expected  int
+	}{
+		{"BtnSouth", BtnSouth, ButtonSouth},
+		{"BtnA", BtnA, ButtonSouth},
+		{"BtnLeft", BtnLeft, evBtnLeft},
+		{"BtnTouch", BtnTouch, evBtnTouch},
+		{"AbsX", AbsX, absX},
+		{"AbsHat0X", AbsHat0X, absHat0X},
+		{"AbsMtSlot", AbsMtSlot, absMtSlot},
+		{"AbsMtTrackingID", AbsMtTrackingID, absMtTrackingID},
+		{"KeyOk", KeyOk, 0x160},
+	}
+	for _, test := range tests {
+		if test.generated != test.expected {
+			t.Fatalf("%s: Expected: %#x\nActual: %#x", test.name, test.expected, test.generated)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package uinput
+
+import (
+	"syscall"
+	"time"
+)
+
+// the event types as defined in input-event-codes.h. They may be used to emit raw events via EmitEvent.
+const (
+	EvSyn = evSyn
+	EvKey = evKey
+	EvRel = evRel
+	EvAbs = evAbs
+	EvMsc = 0x04
+	EvSw  = 0x05
+	EvLed = 0x11
+	EvSnd = 0x12
+	EvRep = 0x14
+	EvFF  = evFF
+)
+
+// An InputEvent is a raw input event, as defined in input.h. Raw events allow to emit events that are not covered by
+// the typed API of a device (like EV_MSC scan codes). Note that the kernel will drop events with codes that have not
+// been registered on the device.
+type InputEvent struct {
+	Type  uint16
+	Code  uint16
+	Value int32
+	// Time optionally sets the timestamp of an emitted event. If it is zero, the clock of the device is used (see
+	// WithClock). Note that consumers see a single timestamp per frame, which is the last one given within the frame.
+	// Events that are passed to event handlers do not carry a timestamp.
+	Time time.Time
+}
+
+// emitEvents writes the given raw events to the device file, followed by a single sync event.
+func emitEvents(deviceFile *uinputDevice, events []InputEvent) error {
+	if len(events) == 0 {
+		return errorf(ErrInvalidArgument, "failed to emit events. At least one event is required")
+	}
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	buf := deviceFile.buf[:0]
+	for _, ev := range events {
+		err := validateCode(ev.Type, ev.Code)
+		if err != nil {
+			return err
+		}
+		iev := inputEvent{Type: ev.Type, Code: ev.Code, Value: ev.Value}
+		if !ev.Time.IsZero() {
+			iev.Time = syscall.NsecToTimeval(ev.Time.UnixNano())
+		}
+		value, err := deviceFile.applyRangePolicy(iev)
+		if err != nil {
+			return err
+		}
+		iev.Value = value
+		buf = appendInputEvent(buf, iev)
+	}
+	return writeFrame(deviceFile, buf)
+}
+
+// validateCode checks that the code does not exceed the highest code of its event type, since the kernel silently
+// drops events with such codes. Only the types that devices are created with by this package are checked.
+func validateCode(evType uint16, code uint16) error {
+	var max uint16
+	switch evType {
+	case evKey:
+		max = keyCodeMax
+	case evRel:
+		max = relMax
+	case evAbs:
+		max = absSize - 1
+	default:
+		return nil
+	}
+	if code > max {
+		return errorf(ErrInvalidArgument, "code %d of event type %d is out of range. Expected a code up to %d", code, evType, max)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package uinput
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmitEventsFailsWithoutEvents(t *testing.T) {
+	expected := "failed to emit events. At least one event is required"
+	err := emitEvents(nil, nil)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
+
+func TestEmitEventsRejectsCodesOutOfRange(t *testing.T) {
+	for _, ev := range []InputEvent{{Type: EvKey, Code: keyCodeMax + 1}, {Type: EvRel, Code: relMax + 1},
+		{Type: EvAbs, Code: absSize}} {
+		err := emitEvents(&uinputDevice{}, []InputEvent{ev})
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected ErrInvalidArgument for %+v, got %v", ev, err)
+		}
+	}
+}
+
+func TestCreateAxisDeviceRejectsCodesOutOfRange(t *testing.T) {
+	_, err := createAxisDevice("mock", []int{keyCodeMax + 1}, nil, nil, uinputUserDev{}, deviceConfig{backend: &mockBackend{}})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument for an invalid button, got %v", err)
+	}
+	_, err = createAxisDevice("mock", nil, []int{absSize}, nil, uinputUserDev{}, deviceConfig{backend: &mockBackend{}})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument for an invalid axis, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// the ids of the Thrustmaster T.16000M, a common stick of HOTAS setups
+const (
+	t16000mVendor  = 0x044f
+	t16000mProduct = 0xb10a
+)
+
+// the axis ranges of flight sticks. The stick axes are centered at 0, while the throttle is closed at 0.
+const (
+	flightStickMax = 32767
+	throttleMax    = 65535
+)
+
+// flightStickButtonCount is the number of buttons of flight sticks. The first 16 buttons are mapped to the joystick
+// button codes (BtnTrigger to 0x12f), the remaining ones to the trigger happy codes, just like the kernel maps the
+// buttons of HID joysticks.
+const flightStickButtonCount = 32
+
+// the axis codes of flight sticks
+const (
+	FlightStickAxisX        = absX
+	FlightStickAxisY        = absY
+	FlightStickAxisTwist    = absRZ
+	FlightStickAxisThrottle = AbsThrottle
+)
+
+var flightStickAxes = []int{FlightStickAxisX, FlightStickAxisY, FlightStickAxisTwist, FlightStickAxisThrottle,
+	AbsHat0X, AbsHat0Y, AbsHat1X, AbsHat1Y}
+
+// flightStickHats are the x axes of the hats of flight sticks (the y axes follow the x axes)
+var flightStickHats = []uint16{AbsHat0X, AbsHat1X}
+
+// A FlightStick is a joystick with a twist axis, a throttle, two hat switches and 32 buttons, matching the layout of
+// common HOTAS (hands on throttle and stick) setups used in flight sims.
+type FlightStick interface {
+	// SetStick will move the stick to the given position and twist it by the given amount within a single frame. All
+	// values are expected to be in the range of -1 to 1, where 0 is the neutral position. Positive values move the
+	// stick right and back (pulling up) and twist it clockwise.
+	SetStick(x, y, twist float64) error
+
+	// SetThrottle will set the throttle to the given position, ranging from 0 (idle) to 1 (full throttle).
+	SetThrottle(value float64) error
+
+	// SetHat will move the given hat switch (0 or 1) to the given direction. Use HatCenter to release it.
+	SetHat(hat int, direction HatDirection) error
+
+	// ButtonPress will press the given button (1 to 32) and immediately release it.
+	ButtonPress(button int) error
+
+	// ButtonDown will press the given button (1 to 32). Note that the button remains pressed until ButtonUp is
+	// called.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button (1 to 32).
+	ButtonUp(button int) error
+
+	Device
+}
+
+type vFlightStick struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreateFlightStick will create a new flight stick that uses the ids of the Thrustmaster T.16000M.
+func CreateFlightStick(path string, name []byte, opts ...Option) (FlightStick, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateFlightStick", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createFlightStick(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vFlightStick{name: name, deviceFile: fd}, nil
+}
+
+// SetStick will move and twist the stick.
+func (vf vFlightStick) SetStick(x, y, twist float64) error {
+	var events []inputEvent
+	for i, value := range []float64{x, y, twist} {
+		scaled, err := scaleAxis(value, flightStickMax)
+		if err != nil {
+			return fmt.Errorf("failed to perform SetStick: %w", err)
+		}
+		events = append(events, inputEvent{Type: evAbs, Code: uint16(flightStickAxes[i]), Value: scaled})
+	}
+	return sendEvents(vf.deviceFile, events)
+}
+
+// SetThrottle will set the throttle to the given position.
+func (vf vFlightStick) SetThrottle(value float64) error {
+	if math.IsNaN(value) || value < 0 || value > 1 {
+		return errorf(ErrInvalidArgument, "failed to perform SetThrottle. %v is out of range. Expected a value between 0 and 1", value)
+	}
+	return sendEvents(vf.deviceFile, []inputEvent{
+		{Type: evAbs, Code: FlightStickAxisThrottle, Value: int32(math.Round(value * throttleMax))},
+	})
+}
+
+// SetHat will move the given hat switch to the given direction.
+func (vf vFlightStick) SetHat(hat int, direction HatDirection) error {
+	if hat < 0 || hat >= len(flightStickHats) {
+		return errorf(ErrInvalidArgument, "failed to perform SetHat. Hat %d is out of range. Expected a value between 0 and %d", hat, len(flightStickHats)-1)
+	}
+	return sendHatEvent(vf.deviceFile, flightStickHats[hat], direction)
+}
+
+// ButtonPress will press the given button and immediately release it.
+func (vf vFlightStick) ButtonPress(button int) error {
+	err := vf.ButtonDown(button)
+	if err != nil {
+		return err
+	}
+	return vf.ButtonUp(button)
+}
+
+// ButtonDown will press the given button.
+func (vf vFlightStick) ButtonDown(button int) error {
+	code, err := flightStickButton(button)
+	if err != nil {
+		return err
+	}
+	return sendBtnEvent(vf.deviceFile, []int{code}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vf vFlightStick) ButtonUp(button int) error {
+	code, err := flightStickButton(button)
+	if err != nil {
+		return err
+	}
+	return sendBtnEvent(vf.deviceFile, []int{code}, btnStateReleased)
+}
+
+func (vf vFlightStick) Name() string {
+	return string(vf.name)
+}
+
+func (vf vFlightStick) Path() string {
+	return devicePath(vf.deviceFile)
+}
+
+func (vf vFlightStick) Fd() uintptr {
+	return deviceFd(vf.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vf vFlightStick) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vf.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vf vFlightStick) EmitEvents(events []InputEvent) error {
+	return emitEvents(vf.deviceFile, events)
+}
+
+func (vf vFlightStick) Sync() error {
+	return writeSyncEvent(vf.deviceFile)
+}
+
+func (vf vFlightStick) SysPath() (string, error) {
+	return sysPath(vf.deviceFile)
+}
+
+func (vf vFlightStick) EventPath() (string, error) {
+	return eventPath(vf.deviceFile)
+}
+
+func (vf vFlightStick) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vf.deviceFile)
+}
+
+func (vf vFlightStick) Stats() Stats {
+	return deviceStats(vf.deviceFile)
+}
+
+func (vf vFlightStick) State() State {
+	return deviceState(vf.deviceFile)
+}
+
+func (vf vFlightStick) ResetAll() error {
+	return resetDevice(vf.deviceFile)
+}
+
+func (vf vFlightStick) Disconnect() error {
+	return disconnectDevice(vf.deviceFile)
+}
+
+func (vf vFlightStick) Reconnect() error {
+	return reconnectDevice(vf.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vf vFlightStick) Close() error {
+	return closeDevice(vf.deviceFile)
+}
+
+// flightStickButton returns the code of the given button (1 to 32).
+func flightStickButton(button int) (int, error) {
+	switch {
+	case button >= 1 && button <= 16:
+		return BtnTrigger + button - 1, nil
+	case button > 16 && button <= flightStickButtonCount:
+		return BtnTriggerHappy1 + button - 17, nil
+	}
+	return 0, errorf(ErrInvalidArgument, "button %d is out of range. Expected a value between 1 and %d", button, flightStickButtonCount)
+}
+
+func createFlightStick(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	buttons := make([]int, flightStickButtonCount)
+	for i := range buttons {
+		buttons[i], _ = flightStickButton(i + 1)
+	}
+
+	var absMin, absMax [absSize]int32
+	for _, axis := range []int{FlightStickAxisX, FlightStickAxisY, FlightStickAxisTwist} {
+		absMin[axis] = -flightStickMax
+		absMax[axis] = flightStickMax
+	}
+	absMax[FlightStickAxisThrottle] = throttleMax
+	for _, hat := range []int{AbsHat0X, AbsHat0Y, AbsHat1X, AbsHat1Y} {
+		absMin[hat] = -1
+		absMax[hat] = 1
+	}
+
+	return createAxisDevice(path, buttons, flightStickAxes, nil,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  t16000mVendor,
+				Product: t16000mProduct,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+}
@@ -0,0 +1,99 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicFlightStick(t *testing.T) {
+	stick, err := CreateFlightStick("/dev/uinput", []byte("Test Flight Stick"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual flight stick. Last error was: %s\n", err)
+	}
+
+	err = stick.SetStick(0.5, -0.5, 0.1)
+	if err != nil {
+		t.Fatalf("Failed to move the stick. Last error was: %s\n", err)
+	}
+
+	err = stick.SetThrottle(0.8)
+	if err != nil {
+		t.Fatalf("Failed to set the throttle. Last error was: %s\n", err)
+	}
+
+	err = stick.SetHat(1, HatUpLeft)
+	if err != nil {
+		t.Fatalf("Failed to move the hat. Last error was: %s\n", err)
+	}
+
+	err = stick.ButtonPress(32)
+	if err != nil {
+		t.Fatalf("Failed to press the button. Last error was: %s\n", err)
+	}
+
+	err = stick.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestFlightStickCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateFlightStick("", []byte("FlightStick"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestFlightStickButtonsFollowKernelMapping(t *testing.T) {
+	for button, expected := range map[int]int{1: BtnTrigger, 13: 0x12c, 16: BtnDead, 17: BtnTriggerHappy1, 32: BtnTriggerHappy16} {
+		code, err := flightStickButton(button)
+		if err != nil {
+			t.Fatalf("Failed to map button %d: %v", button, err)
+		}
+		if code != expected {
+			t.Fatalf("Expected button %d to map to %#x, got %#x", button, expected, code)
+		}
+	}
+	for _, button := range []int{0, 33} {
+		if _, err := flightStickButton(button); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for button %d, got: %v", button, err)
+		}
+	}
+}
+
+func TestFlightStickHatsAreEncodedOnSeparateAxes(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-flightstick-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	stick := vFlightStick{name: []byte("Test Flight Stick"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for hat, direction := range []HatDirection{HatDownRight, HatUpLeft} {
+		err = stick.SetHat(hat, direction)
+		if err != nil {
+			t.Fatalf("Failed to move the hat: %v", err)
+		}
+	}
+	err = stick.SetHat(2, HatUp)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unknown hat, got: %v", err)
+	}
+	err = stick.SetThrottle(0.5)
+	if err != nil {
+		t.Fatalf("Failed to set the throttle: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: AbsHat0X, Value: 1},
+		{Type: evAbs, Code: AbsHat0Y, Value: 1},
+		{Type: evAbs, Code: AbsHat1X, Value: -1},
+		{Type: evAbs, Code: AbsHat1Y, Value: -1},
+		{Type: evAbs, Code: AbsThrottle, Value: 32768},
+	}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
@@ -0,0 +1,190 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the ids of the VEC Infinity USB foot pedal, which is supported by most transcription software
+const (
+	vecInfinityVendor  = 0x05f3
+	vecInfinityProduct = 0x00ff
+)
+
+// the buttons of foot pedals
+const (
+	FootPedalLeft   = Btn0
+	FootPedalCenter = Btn1
+	FootPedalRight  = Btn2
+)
+
+// FootPedalAxis is the axis of foot pedals with an expression pedal (see CreateFootPedal).
+const FootPedalAxis = absX
+
+var footPedalButtons = []int{FootPedalLeft, FootPedalCenter, FootPedalRight}
+
+// A FootPedal is a triple foot pedal like the ones used for transcription and dictation, which allows to control
+// playback hands-free. Optionally, the device provides an expression pedal, which reports how far it is pressed.
+type FootPedal interface {
+	// PedalPress will press the given pedal (FootPedalLeft, FootPedalCenter or FootPedalRight) and immediately
+	// release it.
+	PedalPress(pedal int) error
+
+	// PedalDown will press the given pedal. Note that the pedal remains pressed until PedalUp is called.
+	PedalDown(pedal int) error
+
+	// PedalUp will release the given pedal.
+	PedalUp(pedal int) error
+
+	// SetAxis will set the expression pedal to the given position, ranging from 0 (released) to 1 (fully pressed).
+	// Note that the expression pedal is only available on devices created with withAxis.
+	SetAxis(value float64) error
+
+	Device
+}
+
+type vFootPedal struct {
+	name       []byte
+	deviceFile *uinputDevice
+	axis       bool
+}
+
+// CreateFootPedal will create a new triple foot pedal. If withAxis is set, the device additionally provides an
+// expression pedal.
+func CreateFootPedal(path string, name []byte, withAxis bool, opts ...Option) (FootPedal, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateFootPedal", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createFootPedal(path, name, withAxis, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vFootPedal{name: name, deviceFile: fd, axis: withAxis}, nil
+}
+
+// PedalPress will press the given pedal and immediately release it.
+func (vp vFootPedal) PedalPress(pedal int) error {
+	err := vp.PedalDown(pedal)
+	if err != nil {
+		return fmt.Errorf("failed to issue the PedalDown event: %w", err)
+	}
+	return vp.PedalUp(pedal)
+}
+
+// PedalDown will press the given pedal.
+func (vp vFootPedal) PedalDown(pedal int) error {
+	if !codeSupported(footPedalButtons, pedal) {
+		return errorf(ErrInvalidArgument, "failed to perform PedalDown. Code %d is not a foot pedal", pedal)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{pedal}, btnStatePressed)
+}
+
+// PedalUp will release the given pedal.
+func (vp vFootPedal) PedalUp(pedal int) error {
+	if !codeSupported(footPedalButtons, pedal) {
+		return errorf(ErrInvalidArgument, "failed to perform PedalUp. Code %d is not a foot pedal", pedal)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{pedal}, btnStateReleased)
+}
+
+// SetAxis will set the expression pedal to the given position.
+func (vp vFootPedal) SetAxis(value float64) error {
+	if !vp.axis {
+		return errorf(ErrInvalidArgument, "failed to perform SetAxis. The foot pedal has no expression pedal")
+	}
+	return sendPedalEvent(vp.deviceFile, FootPedalAxis, value)
+}
+
+func (vp vFootPedal) Name() string {
+	return string(vp.name)
+}
+
+func (vp vFootPedal) Path() string {
+	return devicePath(vp.deviceFile)
+}
+
+func (vp vFootPedal) Fd() uintptr {
+	return deviceFd(vp.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vp vFootPedal) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vp.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vp vFootPedal) EmitEvents(events []InputEvent) error {
+	return emitEvents(vp.deviceFile, events)
+}
+
+func (vp vFootPedal) Sync() error {
+	return writeSyncEvent(vp.deviceFile)
+}
+
+func (vp vFootPedal) SysPath() (string, error) {
+	return sysPath(vp.deviceFile)
+}
+
+func (vp vFootPedal) EventPath() (string, error) {
+	return eventPath(vp.deviceFile)
+}
+
+func (vp vFootPedal) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vp.deviceFile)
+}
+
+func (vp vFootPedal) Stats() Stats {
+	return deviceStats(vp.deviceFile)
+}
+
+func (vp vFootPedal) State() State {
+	return deviceState(vp.deviceFile)
+}
+
+func (vp vFootPedal) ResetAll() error {
+	return resetDevice(vp.deviceFile)
+}
+
+func (vp vFootPedal) Disconnect() error {
+	return disconnectDevice(vp.deviceFile)
+}
+
+func (vp vFootPedal) Reconnect() error {
+	return reconnectDevice(vp.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vp vFootPedal) Close() error {
+	return closeDevice(vp.deviceFile)
+}
+
+func createFootPedal(path string, name []byte, withAxis bool, cfg deviceConfig) (fd *uinputDevice, err error) {
+	var axes []int
+	var absMax [absSize]int32
+	if withAxis {
+		axes = []int{FootPedalAxis}
+		absMax[FootPedalAxis] = pedalMax
+	}
+
+	return createAxisDevice(path, footPedalButtons, axes, nil,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  vecInfinityVendor,
+				Product: vecInfinityProduct,
+				Version: 1},
+			Absmax: absMax},
+		cfg)
+}
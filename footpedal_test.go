@@ -0,0 +1,79 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicFootPedal(t *testing.T) {
+	pedal, err := CreateFootPedal("/dev/uinput", []byte("Test Foot Pedal"), true)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual foot pedal. Last error was: %s\n", err)
+	}
+
+	err = pedal.PedalPress(FootPedalCenter)
+	if err != nil {
+		t.Fatalf("Failed to press the pedal. Last error was: %s\n", err)
+	}
+
+	err = pedal.SetAxis(0.5)
+	if err != nil {
+		t.Fatalf("Failed to set the expression pedal. Last error was: %s\n", err)
+	}
+
+	err = pedal.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestFootPedalCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateFootPedal("", []byte("FootPedal"), false)
+	assertPathDiscovered(t, device, err)
+}
+
+func TestFootPedalEvents(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-footpedal-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	pedal := vFootPedal{name: []byte("Test Foot Pedal"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), axis: true}
+	err = pedal.PedalPress(FootPedalRight)
+	if err != nil {
+		t.Fatalf("Failed to press the pedal: %v", err)
+	}
+	err = pedal.SetAxis(1)
+	if err != nil {
+		t.Fatalf("Failed to set the expression pedal: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: FootPedalRight, Value: btnStatePressed},
+		{Type: evKey, Code: FootPedalRight, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+	expected = []inputEvent{{Type: evAbs, Code: FootPedalAxis, Value: pedalMax}}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestFootPedalRejectsInvalidInput(t *testing.T) {
+	pedal := vFootPedal{name: []byte("Test Foot Pedal")}
+	err := pedal.PedalDown(Btn3)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unsupported pedal, got: %v", err)
+	}
+	err = pedal.SetAxis(0.5)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a pedal without axis, got: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package uinput
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// the force feedback effect types and waveforms that may be registered on a device, as defined in input.h
+const (
+	FFRumble   = ffRumble
+	FFPeriodic = ffPeriodic
+	FFConstant = ffConstant
+	FFSpring   = ffSpring
+	FFFriction = ffFriction
+	FFDamper   = ffDamper
+	FFSquare   = ffSquare
+	FFTriangle = ffTriangle
+	FFSine     = ffSine
+	FFSawUp    = ffSawUp
+	FFSawDown  = ffSawDown
+)
+
+// defaultMaxEffects is the number of effects that may be uploaded at the same time, unless specified otherwise.
+const defaultMaxEffects = 16
+
+// FFEnvelope describes the attack and fade phase of a periodic effect.
+type FFEnvelope struct {
+	AttackLength uint16
+	AttackLevel  uint16
+	FadeLength   uint16
+	FadeLevel    uint16
+}
+
+// FFRumbleEffect describes the magnitude of the two rumble motors (heavy and light) found in most gamepads.
+type FFRumbleEffect struct {
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+}
+
+// FFConstantEffect describes a constant force, like the pull of a racing wheel in a corner.
+type FFConstantEffect struct {
+	Level    int16
+	Envelope FFEnvelope
+}
+
+// FFPeriodicEffect describes a periodic effect. Waveform is one of FFSquare, FFTriangle, FFSine, FFSawUp or FFSawDown.
+type FFPeriodicEffect struct {
+	Waveform  uint16
+	Period    uint16
+	Magnitude int16
+	Offset    int16
+	Phase     uint16
+	Envelope  FFEnvelope
+}
+
+// An FFEffect is a force feedback effect that has been uploaded to a device by an application. Depending on Type,
+// either Rumble, Periodic or Constant holds the effect specific parameters. The parameters of condition effects
+// (FFSpring, FFFriction and FFDamper) are not converted. Length and Delay are given in milliseconds.
+type FFEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Length    uint16
+	Delay     uint16
+	Rumble    FFRumbleEffect
+	Periodic  FFPeriodicEffect
+	Constant  FFConstantEffect
+}
+
+// ForceFeedback describes the force feedback capabilities of a device along with the callbacks that are invoked
+// whenever an application uploads, erases or plays an effect. All callbacks are optional and are invoked from a
+// background goroutine that reads the requests from the uinput device.
+type ForceFeedback struct {
+	// Effects lists the supported effect types (FFRumble, FFPeriodic, FFConstant, FFSpring, FFFriction and/or
+	// FFDamper). Registering FFPeriodic implicitly registers all waveforms.
+	Effects []uint16
+
+	// MaxEffects is the number of effects that may be uploaded at the same time. Defaults to 16.
+	MaxEffects uint32
+
+	// OnUpload is called when an effect is uploaded or updated. Returning an error rejects the effect.
+	OnUpload func(effect FFEffect) error
+
+	// OnErase is called when the effect with the given id is removed. Returning an error rejects the request.
+	OnErase func(id int16) error
+
+	// OnPlay is called when the effect with the given id is started (count > 0 is the number of repetitions)
+	// or stopped (count == 0).
+	OnPlay func(id int16, count int32)
+
+	// OnGain is called when the overall force feedback gain (0 - 0xffff) is changed.
+	OnGain func(gain uint16)
+
+	// OnAutocenter is called when the strength of the autocenter spring (0 - 0xffff) is changed. Autocentering is
+	// only announced if this callback is set.
+	OnAutocenter func(strength uint16)
+}
+
+func validateForceFeedback(ff ForceFeedback) error {
+	for _, effect := range ff.Effects {
+		switch effect {
+		case FFRumble, FFPeriodic, FFConstant, FFSpring, FFFriction, FFDamper:
+		default:
+			return errorf(ErrInvalidArgument, "force feedback effect type %#x is not supported", effect)
+		}
+	}
+	return nil
+}
+
+func registerForceFeedback(deviceFile DeviceFile, ff *ForceFeedback) error {
+	err := ioctl(deviceFile, uiSetEvBit, uintptr(evFF))
+	if err != nil {
+		return fmt.Errorf("failed to register force feedback device: %w", err)
+	}
+
+	codes := []int{ffGain}
+	for _, effect := range ff.Effects {
+		switch effect {
+		case FFRumble:
+			codes = append(codes, ffRumble)
+		case FFPeriodic:
+			codes = append(codes, ffPeriodic, ffSquare, ffTriangle, ffSine, ffSawUp, ffSawDown)
+		case FFConstant, FFSpring, FFFriction, FFDamper:
+			codes = append(codes, int(effect))
+		}
+	}
+	if ff.OnAutocenter != nil {
+		codes = append(codes, ffAutocenter)
+	}
+
+	for _, code := range codes {
+		err = ioctl(deviceFile, uiSetFFBit, uintptr(code))
+		if err != nil {
+			return fmt.Errorf("failed to register force feedback effect %v: %w", code, err)
+		}
+	}
+	return nil
+}
+
+func maxEffects(ff *ForceFeedback) uint32 {
+	if ff == nil {
+		return 0
+	}
+	if ff.MaxEffects == 0 {
+		return defaultMaxEffects
+	}
+	return ff.MaxEffects
+}
+
+// withForceFeedback returns a copy of the config that handles the force feedback requests of applications. Upload
+// and erase requests need to be acknowledged, as the application issuing the request is blocked until then.
+func (cfg deviceConfig) withForceFeedback(ff *ForceFeedback) deviceConfig {
+	cfg = cfg.withHandler(evUinput, func(deviceFile DeviceFile, iev inputEvent) {
+		switch iev.Code {
+		case uiFFUpload:
+			handleFFUpload(deviceFile, ff, uint32(iev.Value))
+		case uiFFErase:
+			handleFFErase(deviceFile, ff, uint32(iev.Value))
+		}
+	})
+	return cfg.withHandler(evFF, func(_ DeviceFile, iev inputEvent) {
+		switch {
+		case iev.Code == ffGain:
+			if ff.OnGain != nil {
+				ff.OnGain(uint16(iev.Value))
+			}
+		case iev.Code == ffAutocenter:
+			if ff.OnAutocenter != nil {
+				ff.OnAutocenter(uint16(iev.Value))
+			}
+		case ff.OnPlay != nil:
+			ff.OnPlay(int16(iev.Code), iev.Value)
+		}
+	})
+}
+
+func handleFFUpload(deviceFile DeviceFile, ff *ForceFeedback, requestID uint32) {
+	upload := uinputFFUpload{RequestID: requestID}
+	if err := ioctlPtr(deviceFile, uiBeginFFUpload, unsafe.Pointer(&upload)); err != nil {
+		return
+	}
+	if ff.OnUpload != nil {
+		if err := ff.OnUpload(toFFEffect(upload.Effect)); err != nil {
+			upload.Retval = -int32(syscall.EINVAL)
+		}
+	}
+	_ = ioctlPtr(deviceFile, uiEndFFUpload, unsafe.Pointer(&upload))
+}
+
+func handleFFErase(deviceFile DeviceFile, ff *ForceFeedback, requestID uint32) {
+	erase := uinputFFErase{RequestID: requestID}
+	if err := ioctlPtr(deviceFile, uiBeginFFErase, unsafe.Pointer(&erase)); err != nil {
+		return
+	}
+	if ff.OnErase != nil {
+		if err := ff.OnErase(int16(erase.EffectID)); err != nil {
+			erase.Retval = -int32(syscall.EINVAL)
+		}
+	}
+	_ = ioctlPtr(deviceFile, uiEndFFErase, unsafe.Pointer(&erase))
+}
+
+func toFFEffect(effect ffEffect) FFEffect {
+	converted := FFEffect{
+		Type:      effect.Type,
+		ID:        effect.ID,
+		Direction: effect.Direction,
+		Length:    effect.Replay.Length,
+		Delay:     effect.Replay.Delay,
+	}
+	switch effect.Type {
+	case ffRumble:
+		rumble := (*ffRumbleEffect)(unsafe.Pointer(&effect.U))
+		converted.Rumble = FFRumbleEffect{StrongMagnitude: rumble.StrongMagnitude, WeakMagnitude: rumble.WeakMagnitude}
+	case ffPeriodic:
+		converted.Periodic = FFPeriodicEffect{
+			Waveform:  effect.U.Waveform,
+			Period:    effect.U.Period,
+			Magnitude: effect.U.Magnitude,
+			Offset:    effect.U.Offset,
+			Phase:     effect.U.Phase,
+			Envelope:  FFEnvelope(effect.U.Envelope),
+		}
+	case ffConstant:
+		constant := (*ffConstantEffect)(unsafe.Pointer(&effect.U))
+		converted.Constant = FFConstantEffect{Level: constant.Level, Envelope: FFEnvelope(constant.Envelope)}
+	}
+	return converted
+}
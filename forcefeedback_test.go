@@ -0,0 +1,36 @@
+package uinput
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestForceFeedbackStructsMatchKernelLayout(t *testing.T) {
+	// sizes as defined by the kernel headers on 64 bit platforms
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("layout check is only defined for 64 bit platforms")
+	}
+	if size := unsafe.Sizeof(ffEffect{}); size != 48 {
+		t.Fatalf("Expected: 48\nActual: %d", size)
+	}
+	if uiBeginFFUpload != 0xc06855c8 {
+		t.Fatalf("Expected: %#x\nActual: %#x", uintptr(0xc06855c8), uiBeginFFUpload)
+	}
+	if uiEndFFErase != 0x400c55cb {
+		t.Fatalf("Expected: %#x\nActual: %#x", 0x400c55cb, uiEndFFErase)
+	}
+}
+
+func TestToFFEffectConvertsRumble(t *testing.T) {
+	raw := ffEffect{Type: ffRumble, ID: 3, Replay: ffReplay{Length: 500, Delay: 10}}
+	rumble := (*ffRumbleEffect)(unsafe.Pointer(&raw.U))
+	rumble.StrongMagnitude = 0x8000
+	rumble.WeakMagnitude = 0x4000
+
+	effect := toFFEffect(raw)
+	expected := FFEffect{Type: FFRumble, ID: 3, Length: 500, Delay: 10,
+		Rumble: FFRumbleEffect{StrongMagnitude: 0x8000, WeakMagnitude: 0x4000}}
+	if effect != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, effect)
+	}
+}
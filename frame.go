@@ -0,0 +1,65 @@
+package uinput
+
+// An EventEmitter is a device that allows to emit raw events. All devices of this package are event emitters.
+type EventEmitter interface {
+	// EmitEvents will emit all given raw events, followed by a single sync event.
+	EmitEvents(events []InputEvent) error
+}
+
+// A Frame accumulates events that are meant to be reported to consumers as a whole, like the position of several
+// axes along with the state of some buttons. Once the frame is flushed, all events (including the terminating sync
+// event) are written to the device using a single write call. Frames may be reused after flushing.
+type Frame struct {
+	device EventEmitter
+	events []InputEvent
+}
+
+// NewFrame returns an empty frame that will be flushed to the given device.
+func NewFrame(device EventEmitter) *Frame {
+	return &Frame{device: device}
+}
+
+// Add appends a raw event to the frame.
+func (f *Frame) Add(evType uint16, code uint16, value int32) *Frame {
+	f.events = append(f.events, InputEvent{Type: evType, Code: code, Value: value})
+	return f
+}
+
+// Key appends a key (or button) event to the frame.
+func (f *Frame) Key(code uint16, pressed bool) *Frame {
+	var value int32
+	if pressed {
+		value = btnStatePressed
+	}
+	return f.Add(evKey, code, value)
+}
+
+// Rel appends a relative axis event to the frame.
+func (f *Frame) Rel(code uint16, delta int32) *Frame {
+	return f.Add(evRel, code, delta)
+}
+
+// Abs appends an absolute axis event to the frame.
+func (f *Frame) Abs(code uint16, value int32) *Frame {
+	return f.Add(evAbs, code, value)
+}
+
+// Len returns the number of events in the frame, not counting the sync event.
+func (f *Frame) Len() int {
+	return len(f.events)
+}
+
+// Reset discards all events of the frame.
+func (f *Frame) Reset() {
+	f.events = f.events[:0]
+}
+
+// Flush writes all events of the frame to the device and resets the frame. Flushing an empty frame is a no-op.
+func (f *Frame) Flush() error {
+	if len(f.events) == 0 {
+		return nil
+	}
+	err := f.device.EmitEvents(f.events)
+	f.Reset()
+	return err
+}
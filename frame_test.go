@@ -0,0 +1,49 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+type recordingEmitter struct {
+	frames [][]InputEvent
+}
+
+func (r *recordingEmitter) EmitEvents(events []InputEvent) error {
+	r.frames = append(r.frames, append([]InputEvent(nil), events...))
+	return nil
+}
+
+func TestFrameIsEmittedAsWhole(t *testing.T) {
+	emitter := &recordingEmitter{}
+	frame := NewFrame(emitter)
+	frame.Abs(AxisLeftX, 100).Abs(AxisLeftY, -100).Key(ButtonSouth, true)
+
+	err := frame.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush frame: %v", err)
+	}
+
+	expected := [][]InputEvent{{
+		{Type: EvAbs, Code: AxisLeftX, Value: 100},
+		{Type: EvAbs, Code: AxisLeftY, Value: -100},
+		{Type: EvKey, Code: ButtonSouth, Value: 1},
+	}}
+	if !reflect.DeepEqual(emitter.frames, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, emitter.frames)
+	}
+	if frame.Len() != 0 {
+		t.Fatalf("Expected frame to be reset after flushing, but it still contains %d events", frame.Len())
+	}
+}
+
+func TestFlushingEmptyFrameIsNoOp(t *testing.T) {
+	emitter := &recordingEmitter{}
+	err := NewFrame(emitter).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush frame: %v", err)
+	}
+	if len(emitter.frames) != 0 {
+		t.Fatalf("Expected no frames to be emitted, but got %d", len(emitter.frames))
+	}
+}
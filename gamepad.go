@@ -0,0 +1,530 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// the button codes that are available on gamepad devices, as defined in input-event-codes.h
+const (
+	ButtonSouth        = 0x130 // A on Xbox controllers
+	ButtonEast         = 0x131 // B on Xbox controllers
+	ButtonNorth        = 0x133 // X on Xbox controllers
+	ButtonWest         = 0x134 // Y on Xbox controllers
+	ButtonBumperLeft   = 0x136
+	ButtonBumperRight  = 0x137
+	ButtonTriggerLeft  = 0x138 // digital trigger button, e.g. L2 on DualShock controllers
+	ButtonTriggerRight = 0x139 // digital trigger button, e.g. R2 on DualShock controllers
+	ButtonSelect       = 0x13a // Back on Xbox controllers
+	ButtonStart        = 0x13b
+	ButtonMode         = 0x13c // Xbox Guide button
+	ButtonThumbLeft    = 0x13d
+	ButtonThumbRight   = 0x13e
+)
+
+// the axis codes that are available on gamepad devices, as defined in input-event-codes.h
+const (
+	AxisLeftX        = absX
+	AxisLeftY        = absY
+	AxisLeftTrigger  = absZ
+	AxisRightX       = absRX
+	AxisRightY       = absRY
+	AxisRightTrigger = absRZ
+	AxisHatX         = absHat0X
+	AxisHatY         = absHat0Y
+)
+
+// the axis ranges and identity used by the Xbox 360 controller (as reported by the xpad kernel driver)
+const (
+	stickMin       = -32768
+	stickMax       = 32767
+	stickFuzz      = 16
+	stickFlat      = 128
+	triggerMax     = 255
+	xbox360Vendor  = 0x045e
+	xbox360Product = 0x028e
+	xbox360Version = 0x0110
+)
+
+var gamepadButtons = []int{ButtonSouth, ButtonEast, ButtonNorth, ButtonWest, ButtonBumperLeft, ButtonBumperRight,
+	ButtonSelect, ButtonStart, ButtonMode, ButtonThumbLeft, ButtonThumbRight}
+
+var gamepadAxes = []int{AxisLeftX, AxisLeftY, AxisLeftTrigger, AxisRightX, AxisRightY, AxisRightTrigger, AxisHatX,
+	AxisHatY}
+
+// A Gamepad is an input device that mimics an Xbox 360 controller. It registers the same buttons, axes and USB ids
+// as the original controller, which allows applications like Steam or SDL based games to recognize it as a standard
+// gamepad.
+type Gamepad interface {
+	// ButtonPress will cause the button to be pressed and immediately released.
+	ButtonPress(button int) error
+
+	// ButtonDown will send a button-press event to an existing gamepad device.
+	// Note that the button will be "held down" until "ButtonUp" is called.
+	ButtonDown(button int) error
+
+	// ButtonUp will send a button-release event to an existing gamepad device.
+	ButtonUp(button int) error
+
+	// ButtonPressFor will press the button, hold it for the given duration and release it again (see PressFor).
+	ButtonPressFor(button int, d time.Duration) error
+
+	// ButtonPressForContext will press the button just like ButtonPressFor, but releases it early once the context is
+	// done.
+	ButtonPressForContext(ctx context.Context, button int, d time.Duration) error
+
+	// LeftStickMove will move the left stick to the given position. Both values are expected to be in the
+	// range of -1 to 1, where (0, 0) is the neutral position.
+	LeftStickMove(x, y float32) error
+
+	// RightStickMove will move the right stick to the given position. Both values are expected to be in the
+	// range of -1 to 1, where (0, 0) is the neutral position.
+	RightStickMove(x, y float32) error
+
+	// SetLeftTrigger will move the left analog trigger (AxisLeftTrigger) to the given position, which is expected to
+	// be in the range of 0 (released) to 1 (fully pressed).
+	SetLeftTrigger(value float64) error
+
+	// SetRightTrigger will move the right analog trigger (AxisRightTrigger) to the given position, which is expected
+	// to be in the range of 0 (released) to 1 (fully pressed).
+	SetRightTrigger(value float64) error
+
+	// SetHat will move the hat switch (d-pad) to the given direction. Use HatCenter to release it.
+	SetHat(direction HatDirection) error
+
+	// SetDpad will press the given directions of the d-pad and release all others. The d-pad is reported as hat axes
+	// or as buttons, depending on the layout chosen upon creation (see WithDpadButtons).
+	SetDpad(up, down, left, right bool) error
+
+	// SetAxes will set all given axes (see the Axis constants) to the given raw values at once, which means that
+	// consumers will receive all changes within a single frame. Sticks range from -32768 to 32767, triggers from 0 to
+	// 255 and the hat axes from -1 to 1.
+	SetAxes(axes map[uint16]int32) error
+
+	// SetButtons will press (true) or release (false) all given buttons at once, which means that consumers will
+	// receive all changes within a single frame.
+	SetButtons(buttons map[int]bool) error
+
+	// MoveAxis will move the relative axis with the given code by delta. Note that relative axes need to be registered
+	// upon creation using WithRelativeAxes.
+	MoveAxis(code uint16, delta int32) error
+
+	// Rumble returns a channel that receives all rumble effects played by applications, so that they can be
+	// forwarded to real hardware, for example. A stop request is represented by an effect with zero magnitudes.
+	// The channel is closed when the device is closed. Note that rumble effects are only available on gamepads created
+	// with FFRumble support (see CreateGamepadWithForceFeedback), otherwise nil is returned.
+	Rumble() <-chan RumbleEffect
+
+	Device
+}
+
+type vGamepad struct {
+	name        []byte
+	deviceFile  *uinputDevice
+	rumble      <-chan RumbleEffect
+	relAxes     []uint16
+	dpadButtons bool // whether the d-pad is reported as buttons rather than hat axes (see WithDpadButtons)
+}
+
+// CreateGamepad will create a new gamepad device that uses the layout and identity of an Xbox 360 controller.
+func CreateGamepad(path string, name []byte, opts ...Option) (Gamepad, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateGamepad", opts, optDpadButtons)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createGamepad(path, name, nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vGamepad{name: name, deviceFile: fd, relAxes: cfg.relAxes, dpadButtons: cfg.dpadButtons}, nil
+}
+
+// CreateGamepadWithForceFeedback will create a new gamepad device just like CreateGamepad, but will additionally
+// announce the given force feedback capabilities. This allows games to treat the device as rumble-capable. Effects
+// uploaded by applications are passed to the callbacks defined in ff. Additionally, rumble effects are available
+// via the Rumble channel.
+func CreateGamepadWithForceFeedback(path string, name []byte, ff ForceFeedback, opts ...Option) (Gamepad, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	err = validateForceFeedback(ff)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateGamepadWithForceFeedback", opts, optDpadButtons)
+	if err != nil {
+		return nil, err
+	}
+	var rumble <-chan RumbleEffect
+	for _, effect := range ff.Effects {
+		if effect == FFRumble {
+			var done func()
+			ff, rumble, done = withRumbleChannel(ff)
+			cfg = cfg.withCloser(done)
+			break
+		}
+	}
+	cfg = cfg.withForceFeedback(&ff)
+
+	fd, err := createGamepad(path, name, &ff, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vGamepad{name: name, deviceFile: fd, rumble: rumble, relAxes: cfg.relAxes, dpadButtons: cfg.dpadButtons}, nil
+}
+
+// ButtonPress will issue a single button press (push down a button and then immediately release it).
+func (vg vGamepad) ButtonPress(button int) error {
+	if !vg.buttonSupported(button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonPress. Code %d is not a gamepad button", button)
+	}
+	err := sendBtnEvent(vg.deviceFile, []int{button}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the ButtonDown event: %w", err)
+	}
+
+	return sendBtnEvent(vg.deviceFile, []int{button}, btnStateReleased)
+}
+
+// ButtonPressFor will press the given button, hold it for the duration and release it again.
+func (vg vGamepad) ButtonPressFor(button int, d time.Duration) error {
+	return vg.ButtonPressForContext(context.Background(), button, d)
+}
+
+// ButtonPressForContext will press the given button just like ButtonPressFor, but releases it early once the context
+// is done.
+func (vg vGamepad) ButtonPressForContext(ctx context.Context, button int, d time.Duration) error {
+	if !vg.buttonSupported(button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonPressFor. Code %d is not a gamepad button", button)
+	}
+	return PressForContext(ctx, vg, uint16(button), d)
+}
+
+// ButtonDown will press the given button. Note that the button will remain pressed until "ButtonUp" is called.
+func (vg vGamepad) ButtonDown(button int) error {
+	if !vg.buttonSupported(button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a gamepad button", button)
+	}
+	return sendBtnEvent(vg.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vg vGamepad) ButtonUp(button int) error {
+	if !vg.buttonSupported(button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a gamepad button", button)
+	}
+	return sendBtnEvent(vg.deviceFile, []int{button}, btnStateReleased)
+}
+
+// LeftStickMove will move the left stick to the given position. Both axes are updated within a single frame.
+func (vg vGamepad) LeftStickMove(x, y float32) error {
+	return sendStickEvent(vg.deviceFile, absX, absY, x, y)
+}
+
+// RightStickMove will move the right stick to the given position. Both axes are updated within a single frame.
+func (vg vGamepad) RightStickMove(x, y float32) error {
+	return sendStickEvent(vg.deviceFile, absRX, absRY, x, y)
+}
+
+// SetLeftTrigger will move the left analog trigger to the given position.
+func (vg vGamepad) SetLeftTrigger(value float64) error {
+	return sendTriggerEvent(vg.deviceFile, absZ, triggerMax, value)
+}
+
+// SetRightTrigger will move the right analog trigger to the given position.
+func (vg vGamepad) SetRightTrigger(value float64) error {
+	return sendTriggerEvent(vg.deviceFile, absRZ, triggerMax, value)
+}
+
+// SetAxes will set all given axes to the given raw values within a single frame.
+func (vg vGamepad) SetAxes(axes map[uint16]int32) error {
+	codes := make([]int, 0, len(axes))
+	for code := range axes {
+		if !vg.axisSupported(int(code)) {
+			return errorf(ErrInvalidArgument, "failed to perform SetAxes. Code %d is not a gamepad axis", code)
+		}
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+
+	events := make([]inputEvent, 0, len(codes))
+	for _, code := range codes {
+		events = append(events, inputEvent{Type: evAbs, Code: uint16(code), Value: axes[uint16(code)]})
+	}
+	return sendEvents(vg.deviceFile, events)
+}
+
+// SetButtons will press or release all given buttons within a single frame.
+func (vg vGamepad) SetButtons(buttons map[int]bool) error {
+	codes := make([]int, 0, len(buttons))
+	for button := range buttons {
+		if !vg.buttonSupported(button) {
+			return errorf(ErrInvalidArgument, "failed to perform SetButtons. Code %d is not a gamepad button", button)
+		}
+		codes = append(codes, button)
+	}
+	sort.Ints(codes)
+
+	events := make([]inputEvent, 0, len(codes))
+	for _, button := range codes {
+		state := btnStateReleased
+		if buttons[button] {
+			state = btnStatePressed
+		}
+		events = append(events, inputEvent{Type: evKey, Code: uint16(button), Value: int32(state)})
+	}
+	return sendEvents(vg.deviceFile, events)
+}
+
+// MoveAxis will move the relative axis with the given code by delta.
+func (vg vGamepad) MoveAxis(code uint16, delta int32) error {
+	for _, relAxis := range vg.relAxes {
+		if relAxis == code {
+			return sendRelEvent(vg.deviceFile, code, delta)
+		}
+	}
+	return errorf(ErrInvalidArgument, "failed to perform MoveAxis. Relative axis %d has not been registered", code)
+}
+
+func (vg vGamepad) buttonSupported(button int) bool {
+	return codeSupported(gamepadButtons, button) || (vg.dpadButtons && codeSupported(dpadButtons, button))
+}
+
+func (vg vGamepad) axisSupported(code int) bool {
+	if vg.dpadButtons && (code == absHat0X || code == absHat0Y) {
+		return false
+	}
+	return codeSupported(gamepadAxes, code)
+}
+
+// Rumble returns the channel that receives the rumble effects played by applications.
+func (vg vGamepad) Rumble() <-chan RumbleEffect {
+	return vg.rumble
+}
+
+func (vg vGamepad) Name() string {
+	return string(vg.name)
+}
+
+func (vg vGamepad) Path() string {
+	return devicePath(vg.deviceFile)
+}
+
+func (vg vGamepad) Fd() uintptr {
+	return deviceFd(vg.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vg vGamepad) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vg.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vg vGamepad) EmitEvents(events []InputEvent) error {
+	return emitEvents(vg.deviceFile, events)
+}
+
+func (vg vGamepad) Sync() error {
+	return writeSyncEvent(vg.deviceFile)
+}
+
+func (vg vGamepad) SysPath() (string, error) {
+	return sysPath(vg.deviceFile)
+}
+
+func (vg vGamepad) EventPath() (string, error) {
+	return eventPath(vg.deviceFile)
+}
+
+func (vg vGamepad) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vg.deviceFile)
+}
+
+func (vg vGamepad) Stats() Stats {
+	return deviceStats(vg.deviceFile)
+}
+
+func (vg vGamepad) State() State {
+	return deviceState(vg.deviceFile)
+}
+
+func (vg vGamepad) ResetAll() error {
+	return resetDevice(vg.deviceFile)
+}
+
+func (vg vGamepad) Disconnect() error {
+	return disconnectDevice(vg.deviceFile)
+}
+
+func (vg vGamepad) Reconnect() error {
+	return reconnectDevice(vg.deviceFile)
+}
+
+// Close will close the device and free resources.
+func (vg vGamepad) Close() error {
+	return closeDevice(vg.deviceFile)
+}
+
+func createGamepad(path string, name []byte, ff *ForceFeedback, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create gamepad input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+
+	buttons, axes := gamepadLayout(cfg)
+	for _, event := range buttons {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	for _, event := range axes {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	if ff != nil {
+		err = registerForceFeedback(deviceFile, ff)
+		if err != nil {
+			deviceFile.Close()
+			return nil, err
+		}
+	}
+
+	var absMin, absMax, absFuzz, absFlat [absSize]int32
+	for _, stick := range []int{absX, absY, absRX, absRY} {
+		absMin[stick] = stickMin
+		absMax[stick] = stickMax
+		absFuzz[stick] = stickFuzz
+		absFlat[stick] = stickFlat
+	}
+	absMax[absZ] = triggerMax
+	absMax[absRZ] = triggerMax
+	if !cfg.dpadButtons {
+		for _, hat := range []int{absHat0X, absHat0Y} {
+			absMin[hat] = -1
+			absMax[hat] = 1
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  xbox360Vendor,
+				Product: xbox360Product,
+				Version: xbox360Version},
+			EffectsMax: maxEffects(ff),
+			Absmin:     absMin,
+			Absmax:     absMax,
+			Absfuzz:    absFuzz,
+			Absflat:    absFlat},
+		cfg)
+}
+
+// gamepadLayout returns the buttons and axes of gamepads created with the given config, which report the d-pad either
+// as hat axes or as buttons.
+func gamepadLayout(cfg deviceConfig) (buttons []int, axes []int) {
+	if !cfg.dpadButtons {
+		return gamepadButtons, gamepadAxes
+	}
+	for _, axis := range gamepadAxes {
+		if axis != absHat0X && axis != absHat0Y {
+			axes = append(axes, axis)
+		}
+	}
+	return append(gamepadButtons[:len(gamepadButtons):len(gamepadButtons)], dpadButtons...), axes
+}
+
+func sendStickEvent(deviceFile *uinputDevice, codeX uint16, codeY uint16, x float32, y float32) error {
+	if err := assertNormalized(x); err != nil {
+		return err
+	}
+	if err := assertNormalized(y); err != nil {
+		return err
+	}
+
+	return sendEvents(deviceFile, []inputEvent{
+		{Type: evAbs, Code: codeX, Value: denormalizeStick(x)},
+		{Type: evAbs, Code: codeY, Value: denormalizeStick(y)},
+	})
+}
+
+// sendTriggerEvent moves the analog trigger with the given code, which ranges from 0 to max, to the given position.
+// Buttons that are given along are pressed along with the trigger and released once the trigger is released.
+func sendTriggerEvent(deviceFile *uinputDevice, code uint16, max int32, value float64, buttons ...int) error {
+	if math.IsNaN(value) || value < 0 || value > 1 {
+		return errorf(ErrInvalidArgument, "%v is out of range. Expected a value between 0 and 1", value)
+	}
+
+	events := []inputEvent{{Type: evAbs, Code: code, Value: int32(math.Round(value * float64(max)))}}
+	for _, button := range buttons {
+		state := int32(btnStateReleased)
+		if events[0].Value > 0 {
+			state = btnStatePressed
+		}
+		events = append(events, inputEvent{Type: evKey, Code: uint16(button), Value: state})
+	}
+	return sendEvents(deviceFile, events)
+}
+
+func denormalizeStick(value float32) int32 {
+	if value < 0 {
+		return int32(value * -stickMin)
+	}
+	return int32(value * stickMax)
+}
+
+func assertNormalized(value float32) error {
+	if value < -1 || value > 1 || value != value {
+		return errorf(ErrInvalidArgument, "%v is out of range. Expected a value between -1 and 1", value)
+	}
+	return nil
+}
+
+func codeSupported(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,388 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBasicGamepadEvents(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+
+	err = dev.ButtonPress(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to send button press. Last error was: %s\n", err)
+	}
+
+	err = dev.ButtonDown(ButtonStart)
+	if err != nil {
+		t.Fatalf("Failed to send button down event. Last error was: %s\n", err)
+	}
+
+	err = dev.ButtonUp(ButtonStart)
+	if err != nil {
+		t.Fatalf("Failed to send button up event. Last error was: %s\n", err)
+	}
+
+	err = dev.LeftStickMove(-1, 1)
+	if err != nil {
+		t.Fatalf("Failed to move left stick. Last error was: %s\n", err)
+	}
+
+	err = dev.RightStickMove(0.5, -0.5)
+	if err != nil {
+		t.Fatalf("Failed to move right stick. Last error was: %s\n", err)
+	}
+
+	err = dev.SetHat(HatUpLeft)
+	if err != nil {
+		t.Fatalf("Failed to move hat switch. Last error was: %s\n", err)
+	}
+
+	err = dev.SetHat(HatCenter)
+	if err != nil {
+		t.Fatalf("Failed to release hat switch. Last error was: %s\n", err)
+	}
+
+	err = dev.SetAxes(map[uint16]int32{AxisLeftX: stickMax, AxisLeftY: stickMin, AxisRightTrigger: triggerMax})
+	if err != nil {
+		t.Fatalf("Failed to set axes. Last error was: %s\n", err)
+	}
+
+	err = dev.SetButtons(map[int]bool{ButtonSouth: true, ButtonEast: true})
+	if err != nil {
+		t.Fatalf("Failed to press buttons. Last error was: %s\n", err)
+	}
+
+	err = dev.SetButtons(map[int]bool{ButtonSouth: false, ButtonEast: false})
+	if err != nil {
+		t.Fatalf("Failed to release buttons. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestGamepadCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateGamepad("", []byte("GamepadDevice"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestGamepadCreationFailsOnNonExistentPathName(t *testing.T) {
+	path := "/some/bogus/path"
+	_, err := CreateGamepad(path, []byte("GamepadDevice"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	}
+}
+
+func TestGamepadCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-gamepad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateGamepad(file.Name(), []byte("GamepadDevice"))
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGamepadCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateGamepad("/dev/uinput", []byte(name))
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGamepadUnsupportedButtonFails(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.ButtonPress(KeyA); err == nil {
+		t.Fatalf("Expected button press to fail due to unsupported button, but got no error.")
+	}
+}
+
+func TestGamepadUnsupportedAxisFails(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.SetAxes(map[uint16]int32{AxisLeftX: 0, 0x20: 1}); err == nil {
+		t.Fatalf("Expected SetAxes to fail due to unsupported axis, but got no error.")
+	}
+
+	if err = dev.SetButtons(map[int]bool{KeyA: true}); err == nil {
+		t.Fatalf("Expected SetButtons to fail due to unsupported button, but got no error.")
+	}
+}
+
+func TestGamepadStickOutOfRangeFails(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.LeftStickMove(1.1, 0); err == nil {
+		t.Fatalf("Expected stick move to fail due to out of range value, but got no error.")
+	}
+
+	if err = dev.RightStickMove(0, -1.1); err == nil {
+		t.Fatalf("Expected stick move to fail due to out of range value, but got no error.")
+	}
+}
+
+func TestGamepadStickRejectsNaN(t *testing.T) {
+	dev, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(&mockBackend{}))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	nan := float32(math.NaN())
+	if err = dev.LeftStickMove(nan, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+	if err = dev.RightStickMove(0, nan); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestGamepadButtonPressFailsIfDeviceIsClosed(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	dev.Close()
+
+	err = dev.ButtonPress(ButtonSouth)
+	if err == nil {
+		t.Fatalf("Expected ButtonPress to fail, but no error was returned.")
+	}
+}
+
+func TestGamepadStickMoveFailsIfDeviceIsClosed(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	dev.Close()
+
+	err = dev.LeftStickMove(0, 0)
+	if err == nil {
+		t.Fatalf("Expected LeftStickMove to fail, but no error was returned.")
+	}
+}
+
+func TestDenormalizeStickCoversFullRange(t *testing.T) {
+	for _, tc := range []struct {
+		in       float32
+		expected int32
+	}{{-1, stickMin}, {0, 0}, {1, stickMax}} {
+		if actual := denormalizeStick(tc.in); actual != tc.expected {
+			t.Fatalf("Expected: %d\nActual: %d", tc.expected, actual)
+		}
+	}
+}
+
+func TestGamepadTriggersMapOntoTriggerRange(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-gamepad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	vg := vGamepad{name: []byte("Test Gamepad"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for _, step := range []func() error{
+		func() error { return vg.SetLeftTrigger(1) },
+		func() error { return vg.SetRightTrigger(0.5) },
+		func() error { return vg.SetLeftTrigger(0) },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to move trigger: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: AxisLeftTrigger, Value: triggerMax},
+		{Type: evAbs, Code: AxisRightTrigger, Value: 128},
+		{Type: evAbs, Code: AxisLeftTrigger, Value: 0},
+	}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+
+	for _, value := range []float64{-0.1, 1.1, math.NaN()} {
+		if err := vg.SetRightTrigger(value); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %v, got: %v", value, err)
+		}
+	}
+}
+
+func TestGamepadDpadFollowsLayout(t *testing.T) {
+	for _, tc := range []struct {
+		dpadButtons bool
+		evType      uint16
+		expected    []inputEvent
+	}{
+		{false, evAbs, []inputEvent{
+			{Type: evAbs, Code: AxisHatX, Value: 0}, {Type: evAbs, Code: AxisHatY, Value: 0},
+			{Type: evAbs, Code: AxisHatX, Value: 1}, {Type: evAbs, Code: AxisHatY, Value: 1},
+		}},
+		{true, evKey, []inputEvent{
+			{Type: evKey, Code: BtnDpadUp, Value: 1}, {Type: evKey, Code: BtnDpadDown, Value: 1},
+			{Type: evKey, Code: BtnDpadLeft, Value: 0}, {Type: evKey, Code: BtnDpadRight, Value: 0},
+			{Type: evKey, Code: BtnDpadUp, Value: 0}, {Type: evKey, Code: BtnDpadDown, Value: 1},
+			{Type: evKey, Code: BtnDpadLeft, Value: 0}, {Type: evKey, Code: BtnDpadRight, Value: 1},
+		}},
+	} {
+		file, err := ioutil.TempFile(os.TempDir(), "uinput-gamepad-test-")
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		vg := vGamepad{name: []byte("Test Gamepad"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)),
+			dpadButtons: tc.dpadButtons}
+		// opposing directions cancel each other out on the hat axes
+		if err = vg.SetDpad(true, true, false, false); err != nil {
+			t.Fatalf("Failed to set d-pad: %v", err)
+		}
+		if err = vg.SetHat(HatDownRight); err != nil {
+			t.Fatalf("Failed to set hat: %v", err)
+		}
+		if events := readEvents(t, file.Name(), tc.evType); !reflect.DeepEqual(events, tc.expected) {
+			t.Fatalf("Expected: %+v\nActual: %+v", tc.expected, events)
+		}
+	}
+}
+
+func TestGamepadDpadButtonsReplaceHatAxes(t *testing.T) {
+	vg := vGamepad{name: []byte("Test Gamepad"), dpadButtons: true}
+	if err := vg.SetAxes(map[uint16]int32{AxisHatX: 1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a hat axis, got: %v", err)
+	}
+	if !vg.buttonSupported(BtnDpadUp) || (vGamepad{}).buttonSupported(BtnDpadUp) {
+		t.Fatalf("Expected the d-pad buttons to be available with WithDpadButtons only")
+	}
+
+	buttons, axes := gamepadLayout(newDeviceConfig([]Option{WithDpadButtons()}))
+	if !codeSupported(buttons, BtnDpadRight) || codeSupported(axes, AxisHatY) {
+		t.Fatalf("Expected the layout to contain the d-pad buttons instead of the hat axes, got %v and %v", buttons, axes)
+	}
+}
+
+func TestGamepadWithForceFeedbackCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-gamepad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateGamepadWithForceFeedback(file.Name(), []byte("GamepadDevice"), ForceFeedback{Effects: []uint16{FFRumble}})
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGamepadWithForceFeedbackCreationFailsOnUnsupportedEffect(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-gamepad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "force feedback effect type 0x56 is not supported"
+	_, err = CreateGamepadWithForceFeedback(file.Name(), []byte("GamepadDevice"), ForceFeedback{Effects: []uint16{0x56}})
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestGamepadWithForceFeedbackAcceptsConditionEffects(t *testing.T) {
+	for _, effect := range []uint16{FFConstant, FFSpring, FFFriction, FFDamper} {
+		dev, err := CreateGamepadWithForceFeedback("mock", []byte("GamepadDevice"), ForceFeedback{Effects: []uint16{effect}},
+			WithBackend(&mockBackend{}))
+		if err != nil {
+			t.Fatalf("Expected effect type %#x to be supported, got: %v", effect, err)
+		}
+		dev.Close()
+	}
+}
+
+func TestGamepadWithForceFeedback(t *testing.T) {
+	dev, err := CreateGamepadWithForceFeedback("/dev/uinput", []byte("Test Gamepad"), ForceFeedback{
+		Effects:  []uint16{FFRumble, FFPeriodic},
+		OnUpload: func(effect FFEffect) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+
+	err = dev.ButtonPress(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to send button press. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestGamepadWithRelativeAxes(t *testing.T) {
+	dev, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"), WithRelativeAxes(RelDial))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.MoveAxis(RelDial, -3)
+	if err != nil {
+		t.Fatalf("Failed to move relative axis. Last error was: %s\n", err)
+	}
+
+	if err = dev.MoveAxis(RelWheel, 1); err == nil {
+		t.Fatalf("Expected MoveAxis to fail due to unregistered axis, but got no error.")
+	}
+}
+
+func TestGamepadWaitReady(t *testing.T) {
+	vg, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = vg.WaitReady(ctx)
+	if err != nil {
+		t.Fatalf("Failed to wait for the gamepad to become ready. Last error was: %s\n", err)
+	}
+}
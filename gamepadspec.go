@@ -0,0 +1,215 @@
+package uinput
+
+import (
+	"fmt"
+)
+
+// the axes of the gamepad API of the kernel (see Documentation/input/gamepad.rst). The triggers are reported as ABS_Z
+// and ABS_RZ by most drivers (like xpad), while the documentation names ABS_HAT1* and ABS_HAT2*.
+var gamepadAPIAxes = map[uint16]bool{
+	AbsX: true, AbsY: true, AbsRX: true, AbsRY: true, AbsZ: true, AbsRZ: true, AbsHat0X: true, AbsHat0Y: true,
+	AbsHat1X: true, AbsHat1Y: true, AbsHat2X: true, AbsHat2Y: true,
+}
+
+// the axes that only make sense in pairs
+var gamepadAxisPairs = [][2]uint16{{AbsX, AbsY}, {AbsRX, AbsRY}, {AbsHat0X, AbsHat0Y}}
+
+// A GamepadBuilder assembles the layout of a gamepad that follows the gamepad API of the kernel (see
+// Documentation/input/gamepad.rst): the action pad is reported as BTN_SOUTH, BTN_EAST, BTN_NORTH and BTN_WEST, the
+// sticks as ABS_X/ABS_Y and ABS_RX/ABS_RY and the d-pad as ABS_HAT0X/ABS_HAT0Y (or the BTN_DPAD_* buttons). Devices
+// that follow these conventions are classified as joysticks by udev and detected as gamepads by consumers that look
+// for BTN_GAMEPAD (like SDL). Build rejects layouts that cannot be detected as gamepads and reports warnings for
+// layouts that deviate from the conventions otherwise.
+type GamepadBuilder struct {
+	name    string
+	buttons []uint16
+	axes    []AxisSpec
+	codes   map[uint16]bool // the axes that have been added already
+	err     error           // the first unknown code that has been added
+}
+
+// NewGamepadBuilder returns a builder for a gamepad with the given name and without any buttons or axes.
+func NewGamepadBuilder(name string) *GamepadBuilder {
+	return &GamepadBuilder{name: name, codes: map[uint16]bool{}}
+}
+
+// Buttons adds the given buttons. Buttons that have been added already are ignored.
+func (b *GamepadBuilder) Buttons(buttons ...uint16) *GamepadBuilder {
+	for _, button := range buttons {
+		if CodeName(evKey, button) == "" {
+			if b.err == nil {
+				b.err = errorf(ErrInvalidArgument, "failed to build gamepad %q. %d is not a known button", b.name, button)
+			}
+			continue
+		}
+		if !containsCode(b.buttons, button) {
+			b.buttons = append(b.buttons, button)
+		}
+	}
+	return b
+}
+
+// ActionPad adds the four buttons of the action pad (BTN_SOUTH, BTN_EAST, BTN_NORTH and BTN_WEST).
+func (b *GamepadBuilder) ActionPad() *GamepadBuilder {
+	return b.Buttons(BtnSouth, BtnEast, BtnNorth, BtnWest)
+}
+
+// Axis adds the absolute axis with the given code and range. Adding an axis again replaces its range.
+func (b *GamepadBuilder) Axis(code uint16, min int32, max int32) *GamepadBuilder {
+	return b.addAxis(AxisSpec{Code: CodeName(evAbs, code), Min: min, Max: max}, code)
+}
+
+// LeftStick adds ABS_X and ABS_Y along with the left thumb button, using the range of the Xbox 360 controller.
+func (b *GamepadBuilder) LeftStick() *GamepadBuilder {
+	return b.stick(AbsX, AbsY).Buttons(BtnThumbl)
+}
+
+// RightStick adds ABS_RX and ABS_RY along with the right thumb button, using the range of the Xbox 360 controller.
+func (b *GamepadBuilder) RightStick() *GamepadBuilder {
+	return b.stick(AbsRX, AbsRY).Buttons(BtnThumbr)
+}
+
+// Triggers adds analog triggers as ABS_Z (left) and ABS_RZ (right), ranging from 0 to 255.
+func (b *GamepadBuilder) Triggers() *GamepadBuilder {
+	return b.Axis(AbsZ, 0, triggerMax).Axis(AbsRZ, 0, triggerMax)
+}
+
+// Hat adds the d-pad as ABS_HAT0X and ABS_HAT0Y, ranging from -1 to 1 (see SetHat).
+func (b *GamepadBuilder) Hat() *GamepadBuilder {
+	return b.Axis(AbsHat0X, -1, 1).Axis(AbsHat0Y, -1, 1)
+}
+
+func (b *GamepadBuilder) stick(x uint16, y uint16) *GamepadBuilder {
+	for _, code := range []uint16{x, y} {
+		b.addAxis(AxisSpec{Code: CodeName(evAbs, code), Min: stickMin, Max: stickMax, Fuzz: stickFuzz, Flat: stickFlat}, code)
+	}
+	return b
+}
+
+func (b *GamepadBuilder) addAxis(axis AxisSpec, code uint16) *GamepadBuilder {
+	if axis.Code == "" {
+		if b.err == nil {
+			b.err = errorf(ErrInvalidArgument, "failed to build gamepad %q. %d is not a known axis", b.name, code)
+		}
+		return b
+	}
+	if b.codes[code] {
+		for i := range b.axes {
+			if b.axes[i].Code == axis.Code {
+				b.axes[i] = axis
+			}
+		}
+		return b
+	}
+	b.codes[code] = true
+	b.axes = append(b.axes, axis)
+	return b
+}
+
+// Build validates the layout and returns the spec of the gamepad, which may be created using CreateFromSpec. An
+// error wrapping ErrInvalidArgument is returned if the layout lacks BTN_SOUTH (which is what consumers detect
+// gamepads by), contains unknown codes or incomplete pairs of axes (like ABS_X without ABS_Y). All other deviations
+// from the gamepad API are returned as warnings, which do not prevent the gamepad from being created.
+func (b *GamepadBuilder) Build() (spec DeviceSpec, warnings []string, err error) {
+	spec = DeviceSpec{Name: b.name, AbsAxes: append([]AxisSpec(nil), b.axes...)}
+	if b.err != nil {
+		return spec, nil, b.err
+	}
+	for _, button := range b.buttons {
+		spec.Keys = append(spec.Keys, CodeName(evKey, button))
+	}
+	for _, axis := range b.axes {
+		if axis.Min >= axis.Max {
+			return spec, nil, errorf(ErrInvalidArgument, "failed to build gamepad %q. The range of axis %s is empty", b.name, axis.Code)
+		}
+	}
+	if !containsCode(b.buttons, BtnSouth) {
+		return spec, nil, errorf(ErrInvalidArgument, "failed to build gamepad %q. BTN_SOUTH is required, as gamepads are detected by it", b.name)
+	}
+	for _, pair := range gamepadAxisPairs {
+		if b.codes[pair[0]] != b.codes[pair[1]] {
+			return spec, nil, errorf(ErrInvalidArgument, "failed to build gamepad %q. %s and %s must be used together", b.name, CodeName(evAbs, pair[0]), CodeName(evAbs, pair[1]))
+		}
+	}
+	return spec, b.warnings(), nil
+}
+
+// Create builds the layout (see Build) and creates the gamepad using the uinput device at the given path. The
+// warnings are returned along with the device.
+func (b *GamepadBuilder) Create(path string, opts ...Option) (Device, []string, error) {
+	spec, warnings, err := b.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	spec.Path = path
+	device, err := CreateFromSpec(spec, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return device, warnings, nil
+}
+
+// warnings checks the layout for deviations from the gamepad API that do not prevent the gamepad from being detected.
+func (b *GamepadBuilder) warnings() []string {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	if !containsCode(b.buttons, BtnEast) {
+		warn("BTN_EAST is missing, gamepads are expected to provide at least two action buttons (BTN_SOUTH and BTN_EAST)")
+	}
+	if containsCode(b.buttons, BtnNorth) != containsCode(b.buttons, BtnWest) {
+		warn("BTN_NORTH and BTN_WEST are expected to be used together on four-button action pads")
+	}
+	dpadButtons := 0
+	for _, button := range []uint16{BtnDpadUp, BtnDpadDown, BtnDpadLeft, BtnDpadRight} {
+		if containsCode(b.buttons, button) {
+			dpadButtons++
+		}
+	}
+	if dpadButtons > 0 && dpadButtons < 4 {
+		warn("the d-pad is expected to provide all four BTN_DPAD_* buttons")
+	}
+	if dpadButtons == 0 && !b.codes[AbsHat0X] {
+		warn("the d-pad is missing, gamepads are expected to provide one as ABS_HAT0X/ABS_HAT0Y or BTN_DPAD_*")
+	}
+	if containsCode(b.buttons, BtnThumbl) && !b.codes[AbsX] {
+		warn("BTN_THUMBL is expected to be the button of the left stick (ABS_X/ABS_Y)")
+	}
+	if containsCode(b.buttons, BtnThumbr) && !b.codes[AbsRX] {
+		warn("BTN_THUMBR is expected to be the button of the right stick (ABS_RX/ABS_RY)")
+	}
+
+	for _, button := range b.buttons {
+		name := CodeName(evKey, button)
+		switch {
+		case button < BtnMisc:
+			warn("%s is a keyboard key, which causes udev to classify the gamepad as a keyboard as well", name)
+		case button >= BtnMouse && button < BtnJoystick:
+			warn("%s is a mouse button, which may cause consumers to treat the gamepad as a mouse", name)
+		case button == BtnC || button == BtnZ:
+			warn("%s is not part of the gamepad API, extra buttons are expected to be reported as BTN_TRIGGER_HAPPY*", name)
+		case button >= BtnSouth && button <= BtnThumbr, button >= BtnDpadUp && button <= BtnDpadRight,
+			button >= BtnTriggerHappy && button <= BtnTriggerHappy40:
+		default:
+			warn("%s is not part of the gamepad API, extra buttons are expected to be reported as BTN_TRIGGER_HAPPY*", name)
+		}
+	}
+	for _, axis := range b.axes {
+		code, _ := CodeFromName(axis.Code)
+		if !gamepadAPIAxes[code] {
+			warn("%s is not part of the gamepad API", axis.Code)
+		}
+	}
+	return warnings
+}
+
+func containsCode(codes []uint16, code uint16) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
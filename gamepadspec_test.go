@@ -0,0 +1,85 @@
+package uinput
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGamepadBuilderAcceptsStandardLayout(t *testing.T) {
+	spec, warnings, err := NewGamepadBuilder("Test Pad").ActionPad().LeftStick().RightStick().Triggers().Hat().
+		Buttons(BtnTL, BtnTR, BtnSelect, BtnStart, BtnMode).Build()
+	if err != nil {
+		t.Fatalf("Failed to build gamepad: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warnings, got %v", warnings)
+	}
+	if len(spec.Keys) != 11 || spec.Keys[0] != "BTN_SOUTH" {
+		t.Fatalf("Unexpected buttons %v", spec.Keys)
+	}
+	if len(spec.AbsAxes) != 8 || spec.AbsAxes[0].Code != "ABS_X" || spec.AbsAxes[0].Min != stickMin {
+		t.Fatalf("Unexpected axes %v", spec.AbsAxes)
+	}
+}
+
+func TestGamepadBuilderRejectsUndetectableLayouts(t *testing.T) {
+	builders := map[string]*GamepadBuilder{
+		"no south":     NewGamepadBuilder("pad").Buttons(BtnEast).Hat(),
+		"half stick":   NewGamepadBuilder("pad").ActionPad().Axis(AbsX, -1, 1),
+		"empty range":  NewGamepadBuilder("pad").ActionPad().Axis(AbsZ, 0, 0),
+		"unknown axis": NewGamepadBuilder("pad").ActionPad().Axis(absSize, 0, 1),
+	}
+	for name, builder := range builders {
+		_, _, err := builder.Build()
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%s: expected ErrInvalidArgument, got %v", name, err)
+		}
+	}
+}
+
+func TestGamepadBuilderSkipsUnknownButtons(t *testing.T) {
+	builder := NewGamepadBuilder("pad").ActionPad().Buttons(0x2ff, BtnStart)
+	if _, _, err := builder.Build(); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+	expected := []uint16{BtnSouth, BtnEast, BtnNorth, BtnWest, BtnStart}
+	if !reflect.DeepEqual(builder.buttons, expected) {
+		t.Fatalf("Expected buttons %v, got %v", expected, builder.buttons)
+	}
+}
+
+func TestGamepadBuilderWarnsOnNonconformingLayouts(t *testing.T) {
+	_, warnings, err := NewGamepadBuilder("pad").Buttons(BtnSouth, BtnNorth, BtnDpadUp, BtnTrigger, KeyA, BtnThumbl).
+		Axis(AbsThrottle, 0, 255).Build()
+	if err != nil {
+		t.Fatalf("Failed to build gamepad: %v", err)
+	}
+	for _, expected := range []string{"BTN_EAST", "BTN_WEST", "BTN_DPAD_*", "BTN_TRIGGER ", "KEY_A", "BTN_THUMBL",
+		"ABS_THROTTLE"} {
+		found := false
+		for _, warning := range warnings {
+			found = found || strings.Contains(warning, expected)
+		}
+		if !found {
+			t.Fatalf("Expected a warning about %s, got %v", expected, warnings)
+		}
+	}
+}
+
+func TestGamepadBuilderCreatesDevice(t *testing.T) {
+	backend := &mockBackend{}
+	device, warnings, err := NewGamepadBuilder("Test Pad").Buttons(BtnSouth, BtnEast).Hat().
+		Create("mock", WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create gamepad: %v", err)
+	}
+	defer device.Close()
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warnings, got %v", warnings)
+	}
+	if !backend.file.issued(uiDevCreate) {
+		t.Fatalf("Expected the device to be created")
+	}
+}
@@ -0,0 +1,241 @@
+//go:build ignore
+// +build ignore
+
+// gencodes generates eventcodes.go from the input-event-codes.h header of the kernel. Codes that are already defined
+// by hand (like the keys in keycodes.go) are skipped, so that existing names are kept.
+//
+// Usage: go run gencodes.go [-header /usr/include/linux/input-event-codes.h] [-o eventcodes.go]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// the prefixes of the codes that are generated, along with the prefix of the respective go constants and the event
+// type the codes belong to. Codes without a go prefix are only part of the name tables.
+var prefixes = []struct {
+	c      string
+	goName string
+	desc   string
+	evType string
+}{
+	{"SYN_", "", "", "EvSyn"},
+	{"KEY_", "Key", "key codes", "EvKey"},
+	{"BTN_", "Btn", "button codes", "EvKey"},
+	{"ABS_", "Abs", "absolute axis codes", "EvAbs"},
+	{"REL_", "Rel", "relative axis codes", "EvRel"},
+	{"MSC_", "Msc", "miscellaneous event codes", "EvMsc"},
+	{"SW_", "Sw", "switch codes", "EvSw"},
+	{"LED_", "Led", "LED codes", "EvLed"},
+	{"SND_", "Snd", "sound codes", "EvSnd"},
+	{"REP_", "Rep", "autorepeat codes", "EvRep"},
+}
+
+// words that are not title cased when converting the kernel names
+var acronyms = map[string]string{
+	"RX":     "RX",
+	"RY":     "RY",
+	"RZ":     "RZ",
+	"TL":     "TL",
+	"TR":     "TR",
+	"TL2":    "TL2",
+	"TR2":    "TR2",
+	"HWHEEL": "HWheel",
+	"ID":     "ID",
+}
+
+// the highest key code that is defined in keycodes.go
+const handWrittenKeyMax = 248
+
+var hatRegexp = regexp.MustCompile(`^HAT[0-9][XY]$`)
+
+var defineRegexp = regexp.MustCompile(`^#define\s+([A-Z][A-Z0-9_]*)\s+([A-Za-z0-9_]+)\b`)
+
+type code struct {
+	name  string
+	value string
+	alias string
+}
+
+func main() {
+	header := flag.String("header", "/usr/include/linux/input-event-codes.h", "path of input-event-codes.h")
+	out := flag.String("o", "eventcodes.go", "output file")
+	flag.Parse()
+
+	existing, err := declaredConstants(".", *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(*header)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	codes := make(map[string][]code)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := defineRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		name, value := match[1], match[2]
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(name, prefix.c) || strings.HasSuffix(name, "_MAX") || strings.HasSuffix(name, "_CNT") {
+				continue
+			}
+			c := code{name: name, value: value}
+			if _, err := strconv.ParseInt(value, 0, 32); err != nil {
+				resolved, ok := values[value]
+				if !ok {
+					break
+				}
+				c.value, c.alias = resolved, value
+			}
+			values[name] = c.value
+			codes[prefix.c] = append(codes[prefix.c], c)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gencodes.go from %s; DO NOT EDIT.\n\npackage uinput\n", filepath.Base(*header))
+	for _, prefix := range prefixes {
+		if prefix.goName == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n// the %s as defined in input-event-codes.h\nconst (\n", prefix.desc)
+		for _, c := range codes[prefix.c] {
+			goName := toGoName(prefix.goName, strings.TrimPrefix(c.name, prefix.c))
+			if existing[goName] {
+				continue
+			}
+			if prefix.c == "KEY_" && c.alias == "" {
+				if v, _ := strconv.ParseInt(c.value, 0, 32); v <= handWrittenKeyMax {
+					continue
+				}
+			}
+			if c.alias != "" {
+				fmt.Fprintf(&buf, "\t%s = %s // alias of %s\n", goName, c.value, c.alias)
+			} else {
+				fmt.Fprintf(&buf, "\t%s = %s\n", goName, c.value)
+			}
+		}
+		buf.WriteString(")\n")
+	}
+
+	// all names (including aliases) are resolvable, but only the last non-alias name of a code is used when looking up
+	// the name of a code. That way, BTN_SOUTH is preferred over the range marker BTN_GAMEPAD that precedes it.
+	buf.WriteString("\n// codeValues maps the kernel names of all codes to their values\nvar codeValues = map[string]uint16{\n")
+	for _, prefix := range prefixes {
+		for _, c := range codes[prefix.c] {
+			fmt.Fprintf(&buf, "\t%q: %s,\n", c.name, c.value)
+		}
+	}
+	buf.WriteString("}\n")
+	buf.WriteString("\n// codeNames maps the codes of all event types to their kernel names\nvar codeNames = map[uint16]map[uint16]string{\n")
+	var evTypes []string
+	byType := make(map[string][]code)
+	for _, prefix := range prefixes {
+		if _, ok := byType[prefix.evType]; !ok {
+			evTypes = append(evTypes, prefix.evType)
+		}
+		byType[prefix.evType] = append(byType[prefix.evType], codes[prefix.c]...)
+	}
+	for _, evType := range evTypes {
+		names := make(map[string]string)
+		var order []string
+		for _, c := range byType[evType] {
+			if c.alias != "" {
+				continue
+			}
+			v, _ := strconv.ParseInt(c.value, 0, 32)
+			key := fmt.Sprintf("%#x", v)
+			if _, ok := names[key]; !ok {
+				order = append(order, key)
+			}
+			names[key] = c.name
+		}
+		fmt.Fprintf(&buf, "\t%s: {\n", evType)
+		for _, key := range order {
+			fmt.Fprintf(&buf, "\t\t%s: %q,\n", key, names[key])
+		}
+		buf.WriteString("\t},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// toGoName converts the remainder of a kernel name (e.g. "MT_POSITION_X") to a go name (e.g. "AbsMtPositionX").
+func toGoName(prefix string, name string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, word := range strings.Split(name, "_") {
+		if acronym, ok := acronyms[word]; ok {
+			b.WriteString(acronym)
+			continue
+		}
+		if hatRegexp.MatchString(word) {
+			// keep the axis of hat switches upper case (e.g. "Hat0X")
+			b.WriteString("Hat" + word[3:])
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// declaredConstants returns the names of all constants that are declared in the package by hand.
+func declaredConstants(dir string, generated string) (map[string]bool, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		name := info.Name()
+		return name != generated && name != "gencodes.go" && !strings.HasSuffix(name, "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					for _, ident := range spec.(*ast.ValueSpec).Names {
+						names[ident.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
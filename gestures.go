@@ -0,0 +1,115 @@
+package uinput
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// A Point is a position on a touch device.
+type Point struct {
+	X int32
+	Y int32
+}
+
+// the distance of the fingers of gestures, relative to the smaller side of the touch screen. Swipes place the fingers
+// gestureSpacing apart and pinches and rotations place them gestureRadius away from the center.
+const (
+	gestureSpacing = 0.08
+	gestureRadius  = 0.15
+)
+
+// Swipe will swipe with the given number of fingers in timed steps.
+func (vTouch *vTouchScreen) Swipe(from Point, to Point, fingers int, duration time.Duration) error {
+	if fingers < 1 {
+		return errorf(ErrInvalidArgument, "failed to perform Swipe. %d is not a valid number of fingers", fingers)
+	}
+	spacing := vTouch.gestureDistance(gestureSpacing)
+	return vTouch.performGesture(fingers, duration, func(finger int, progress float64) Point {
+		// the fingers are centered around the path of the swipe
+		offset := (float64(finger) - float64(fingers-1)/2) * spacing
+		return Point{
+			X: int32(math.Round(float64(from.X) + float64(to.X-from.X)*progress + offset)),
+			Y: int32(math.Round(float64(from.Y) + float64(to.Y-from.Y)*progress)),
+		}
+	})
+}
+
+// Pinch will move two fingers apart or together in timed steps.
+func (vTouch *vTouchScreen) Pinch(center Point, scale float64, duration time.Duration) error {
+	if scale <= 0 {
+		return errorf(ErrInvalidArgument, "failed to perform Pinch. Scale %v is out of range. Expected a positive value", scale)
+	}
+	radius := vTouch.gestureDistance(gestureRadius)
+	return vTouch.performGesture(2, duration, func(finger int, progress float64) Point {
+		r := radius * (1 + (scale-1)*progress)
+		return pointOnCircle(center, r, float64(finger)*math.Pi)
+	})
+}
+
+// Rotate will rotate two fingers around the center in timed steps.
+func (vTouch *vTouchScreen) Rotate(center Point, degrees float64, duration time.Duration) error {
+	radius := vTouch.gestureDistance(gestureRadius)
+	return vTouch.performGesture(2, duration, func(finger int, progress float64) Point {
+		angle := float64(finger)*math.Pi + degrees*progress*math.Pi/180
+		return pointOnCircle(center, radius, angle)
+	})
+}
+
+// performGesture places the given number of fingers in the lowest free slots, moves them along the positions that
+// are returned for the progress (0 to 1) of the gesture and lifts them afterwards. All fingers are placed, moved and
+// lifted within the same frames. The fingers are lifted even if moving them fails, so that no contacts are left
+// behind.
+func (vTouch *vTouchScreen) performGesture(fingers int, duration time.Duration, position func(finger int, progress float64) Point) error {
+	vTouch.mu.Lock()
+	var slots []int
+	for slot, trackingID := range vTouch.trackingIDs {
+		if trackingID == -1 && len(slots) < fingers {
+			slots = append(slots, slot)
+		}
+	}
+	if len(slots) < fingers {
+		vTouch.mu.Unlock()
+		return errorf(ErrInvalidArgument, "failed to perform gesture. %d fingers are required, but only %d slots are free", fingers, len(slots))
+	}
+	err := vTouch.sendContactsLocked(gestureChanges(contactDown, slots, 0, position))
+	vTouch.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	steps := moveSteps(duration, 0)
+	err = moveTimed(context.Background(), duration, steps, func(step int) error {
+		return vTouch.sendContacts(gestureChanges(contactMove, slots, float64(step+1)/float64(steps), position))
+	})
+	upErr := vTouch.sendContacts(gestureChanges(contactUp, slots, 1, position))
+	if err != nil {
+		return err
+	}
+	return upErr
+}
+
+func gestureChanges(kind int, slots []int, progress float64, position func(finger int, progress float64) Point) []contactChange {
+	changes := make([]contactChange, len(slots))
+	for finger, slot := range slots {
+		p := position(finger, progress)
+		changes[finger] = contactChange{kind: kind, slot: slot, x: p.X, y: p.Y}
+	}
+	return changes
+}
+
+// gestureDistance returns the given fraction of the smaller side of the touch screen.
+func (vTouch *vTouchScreen) gestureDistance(fraction float64) float64 {
+	side := vTouch.maxX - vTouch.minX
+	if height := vTouch.maxY - vTouch.minY; height < side {
+		side = height
+	}
+	return float64(side) * fraction
+}
+
+func pointOnCircle(center Point, radius float64, angle float64) Point {
+	return Point{
+		X: int32(math.Round(float64(center.X) + radius*math.Cos(angle))),
+		Y: int32(math.Round(float64(center.Y) + radius*math.Sin(angle))),
+	}
+}
@@ -0,0 +1,126 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func newTestTouchScreen(t *testing.T, slots int) (*vTouchScreen, *os.File) {
+	t.Helper()
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-gestures-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+
+	trackingIDs := make([]int32, slots)
+	for i := range trackingIDs {
+		trackingIDs[i] = -1
+	}
+	return &vTouchScreen{
+		name:        []byte("Test Touch Screen"),
+		deviceFile:  newUinputDevice(tempFile{file}, newDeviceConfig(nil)),
+		maxX:        1000,
+		maxY:        1000,
+		trackingIDs: trackingIDs,
+		shapes:      make([]ContactShape, slots),
+		positions:   make([]Point, slots),
+	}, file
+}
+
+// lastPositions returns the last reported position of each slot.
+func lastPositions(events []inputEvent) map[int32]Point {
+	positions := make(map[int32]Point)
+	var slot int32
+	for _, iev := range events {
+		switch iev.Code {
+		case absMtSlot:
+			slot = iev.Value
+		case absMtPositionX:
+			p := positions[slot]
+			p.X = iev.Value
+			positions[slot] = p
+		case absMtPositionY:
+			p := positions[slot]
+			p.Y = iev.Value
+			positions[slot] = p
+		}
+	}
+	return positions
+}
+
+func TestSwipeMovesAllFingersAndLiftsThem(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 3)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	err := vTouch.Swipe(Point{X: 100, Y: 500}, Point{X: 700, Y: 500}, 3, 0)
+	if err != nil {
+		t.Fatalf("Failed to swipe: %v", err)
+	}
+
+	expected := map[int32]Point{0: {X: 620, Y: 500}, 1: {X: 700, Y: 500}, 2: {X: 780, Y: 500}}
+	if positions := lastPositions(readEvents(t, file.Name(), evAbs)); !reflect.DeepEqual(positions, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, positions)
+	}
+	keys := readEvents(t, file.Name(), evKey)
+	expectedKeys := []inputEvent{
+		{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+		{Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+	}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expectedKeys, keys)
+	}
+	if vTouch.activeContacts != 0 || vTouch.lowestActiveSlot() != -1 {
+		t.Fatalf("Expected all contacts to be lifted")
+	}
+}
+
+func TestPinchScalesDistanceOfFingers(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	err := vTouch.Pinch(Point{X: 500, Y: 500}, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to pinch: %v", err)
+	}
+
+	expected := map[int32]Point{0: {X: 800, Y: 500}, 1: {X: 200, Y: 500}}
+	if positions := lastPositions(readEvents(t, file.Name(), evAbs)); !reflect.DeepEqual(positions, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, positions)
+	}
+}
+
+func TestRotateMovesFingersAroundCenter(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	err := vTouch.Rotate(Point{X: 500, Y: 500}, 90, 0)
+	if err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+
+	expected := map[int32]Point{0: {X: 500, Y: 650}, 1: {X: 500, Y: 350}}
+	if positions := lastPositions(readEvents(t, file.Name(), evAbs)); !reflect.DeepEqual(positions, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, positions)
+	}
+}
+
+func TestGestureFailsWithoutFreeSlots(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	err := vTouch.TouchDown(0, 10, 10)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+
+	err = vTouch.Pinch(Point{X: 500, Y: 500}, 0.5, 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+	if vTouch.activeContacts != 1 {
+		t.Fatalf("Expected the contact state to remain unchanged")
+	}
+}
@@ -0,0 +1,52 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// A Grab holds the exclusive grab of the event node of a device (see GrabDevice). While it is held, no other consumer
+// (including the display server) receives the events of the device.
+type Grab struct {
+	mu   sync.Mutex
+	file DeviceFile
+}
+
+// GrabDevice will wait until the event node of the device is ready (see Device.WaitReady) and grab it exclusively
+// via EVIOCGRAB. This is used for loop prevention by remappers that read input devices and emit events on virtual
+// devices at the same time. The grab is held until Release is called. Note that the events of the device are not
+// read by the grab; they are discarded by the kernel instead.
+func GrabDevice(ctx context.Context, device Device) (*Grab, error) {
+	err := device.WaitReady(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grab device: %w", err)
+	}
+	path, err := device.EventPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to grab device: %w", err)
+	}
+	file, err := openEvdev(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grab device: %w", err)
+	}
+	err = ioctl(file, eviocgRab, 1)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to grab device %s: %w", path, err)
+	}
+	return &Grab{file: file}, nil
+}
+
+// Release releases the grab, so that all consumers receive the events of the device again.
+func (g *Grab) Release() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.file == nil {
+		return errorf(ErrDeviceClosed, "failed to release grab. The grab has already been released")
+	}
+	_ = ioctl(g.file, eviocgRab, 0)
+	err := g.file.Close()
+	g.file = nil
+	return err
+}
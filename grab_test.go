@@ -0,0 +1,71 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// nodeDevice is a device whose event node is the given file
+type nodeDevice struct {
+	fakeDevice
+	node string
+}
+
+func (d nodeDevice) EventPath() (string, error) {
+	return d.node, nil
+}
+
+// notReadyDevice is a device that never becomes ready
+type notReadyDevice struct {
+	fakeDevice
+}
+
+func (notReadyDevice) WaitReady(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGrabDeviceRequiresEvdevNode(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-grab-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	_, err = GrabDevice(context.Background(), nodeDevice{node: file.Name()})
+	if !errors.Is(err, syscall.ENOTTY) {
+		t.Fatalf("Expected ENOTTY for a regular file, got %v", err)
+	}
+}
+
+func TestGrabDeviceFailsIfNotReady(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := GrabDevice(ctx, notReadyDevice{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the error of the context, got %v", err)
+	}
+}
+
+func TestReleaseGrabTwice(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer writer.Close()
+
+	g := &Grab{file: tempFile{reader}}
+	err = g.Release()
+	if err != nil {
+		t.Fatalf("Failed to release grab: %v", err)
+	}
+	err = g.Release()
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected ErrDeviceClosed, got %v", err)
+	}
+}
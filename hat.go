@@ -0,0 +1,56 @@
+package uinput
+
+// HatDirection specifies the position of a hat switch (d-pad). Diagonal positions are encoded as a combination of the
+// x and y axis of the hat.
+type HatDirection int
+
+// the possible positions of a hat switch
+const (
+	HatCenter HatDirection = iota
+	HatUp
+	HatUpRight
+	HatRight
+	HatDownRight
+	HatDown
+	HatDownLeft
+	HatLeft
+	HatUpLeft
+)
+
+// hatValues translates the given direction into the values of the hat's x and y axis (-1 meaning left or up).
+func hatValues(direction HatDirection) (x int32, y int32, err error) {
+	switch direction {
+	case HatCenter:
+		return 0, 0, nil
+	case HatUp:
+		return 0, -1, nil
+	case HatUpRight:
+		return 1, -1, nil
+	case HatRight:
+		return 1, 0, nil
+	case HatDownRight:
+		return 1, 1, nil
+	case HatDown:
+		return 0, 1, nil
+	case HatDownLeft:
+		return -1, 1, nil
+	case HatLeft:
+		return -1, 0, nil
+	case HatUpLeft:
+		return -1, -1, nil
+	}
+	return 0, 0, errorf(ErrInvalidArgument, "%d is not a valid hat direction", direction)
+}
+
+// sendHatEvent updates both axes of the hat starting at codeX (the y axis always follows the x axis) within a single
+// frame.
+func sendHatEvent(deviceFile *uinputDevice, codeX uint16, direction HatDirection) error {
+	x, y, err := hatValues(direction)
+	if err != nil {
+		return err
+	}
+	return sendEvents(deviceFile, []inputEvent{
+		{Type: evAbs, Code: codeX, Value: x},
+		{Type: evAbs, Code: codeX + 1, Value: y},
+	})
+}
@@ -0,0 +1,30 @@
+package uinput
+
+import "testing"
+
+func TestHatValuesEncodeDiagonals(t *testing.T) {
+	for _, tc := range []struct {
+		direction HatDirection
+		x, y      int32
+	}{
+		{HatCenter, 0, 0},
+		{HatUp, 0, -1},
+		{HatUpRight, 1, -1},
+		{HatDownLeft, -1, 1},
+		{HatUpLeft, -1, -1},
+	} {
+		x, y, err := hatValues(tc.direction)
+		if err != nil {
+			t.Fatalf("Failed to translate hat direction %d: %v", tc.direction, err)
+		}
+		if x != tc.x || y != tc.y {
+			t.Fatalf("Expected: (%d, %d)\nActual: (%d, %d)", tc.x, tc.y, x, y)
+		}
+	}
+}
+
+func TestHatValuesFailOnInvalidDirection(t *testing.T) {
+	if _, _, err := hatValues(HatUpLeft + 1); err == nil {
+		t.Fatalf("Expected an error due to invalid hat direction, but got none.")
+	}
+}
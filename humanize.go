@@ -0,0 +1,83 @@
+package uinput
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// the shape of humanized movements. The control points of the curve deviate from the straight path by up to
+// humanCurvature times the distance, and all points but the target are shifted by up to humanJitter pixel.
+const (
+	humanCurvature = 0.2
+	humanJitter    = 1.0
+)
+
+// WithHumanizedMovement makes smooth movements (see Mouse.MoveSmooth and TouchPad.MoveToSmooth) follow a slightly
+// curved path with accelerating and decelerating pointer speed and small jitter, just like a hand moving a mouse
+// would. The target is reached exactly nevertheless. This is useful for realistic demos and for testing applications
+// that analyze pointer movements.
+func WithHumanizedMovement() Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optHumanizedMovement)
+		cfg.humanize = true
+	}
+}
+
+// humanizer generates humanized paths. A nil humanizer generates straight paths with constant speed.
+type humanizer struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newHumanizer(cfg deviceConfig) *humanizer {
+	if !cfg.humanize {
+		return nil
+	}
+	return &humanizer{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// interpolate splits the movement by dx and dy into the given number of steps and returns the relative movement of
+// each step (see interpolate).
+func (h *humanizer) interpolate(dx, dy int32, steps int) [][2]int32 {
+	if h == nil {
+		return interpolate(dx, dy, steps)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// the control points of a cubic bezier curve from (0, 0) to (dx, dy) are shifted perpendicular to the path
+	fx, fy := float64(dx), float64(dy)
+	dist := math.Hypot(fx, fy)
+	var nx, ny float64
+	if dist > 0 {
+		nx, ny = -fy/dist, fx/dist
+	}
+	offset1 := (h.rng.Float64()*2 - 1) * humanCurvature * dist
+	offset2 := (h.rng.Float64()*2 - 1) * humanCurvature * dist
+	c1x, c1y := fx/3+nx*offset1, fy/3+ny*offset1
+	c2x, c2y := fx*2/3+nx*offset2, fy*2/3+ny*offset2
+
+	deltas := make([][2]int32, steps)
+	var prevX, prevY int32
+	for i := range deltas {
+		var x, y int32
+		if i == steps-1 {
+			x, y = dx, dy
+		} else {
+			// ease in and out, so that the pointer accelerates at the beginning and decelerates at the end
+			t := float64(i+1) / float64(steps)
+			t = t * t * (3 - 2*t)
+			u := 1 - t
+			px := 3*u*u*t*c1x + 3*u*t*t*c2x + t*t*t*fx
+			py := 3*u*u*t*c1y + 3*u*t*t*c2y + t*t*t*fy
+			px += (h.rng.Float64()*2 - 1) * humanJitter
+			py += (h.rng.Float64()*2 - 1) * humanJitter
+			x, y = int32(math.Round(px)), int32(math.Round(py))
+		}
+		deltas[i] = [2]int32{x - prevX, y - prevY}
+		prevX, prevY = x, y
+	}
+	return deltas
+}
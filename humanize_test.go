@@ -0,0 +1,38 @@
+package uinput
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestHumanizedPathReachesTarget(t *testing.T) {
+	h := &humanizer{rng: rand.New(rand.NewSource(1))}
+	deltas := h.interpolate(300, -120, 25)
+	if len(deltas) != 25 {
+		t.Fatalf("Expected 25 steps, got %d", len(deltas))
+	}
+	var x, y int32
+	for _, d := range deltas {
+		x, y = x+d[0], y+d[1]
+	}
+	if x != 300 || y != -120 {
+		t.Fatalf("Expected the steps to add up to (300, -120), got (%d, %d)", x, y)
+	}
+	if reflect.DeepEqual(deltas, interpolate(300, -120, 25)) {
+		t.Fatalf("Expected the humanized path to differ from the straight path")
+	}
+}
+
+func TestHumanizedMovementIsOptional(t *testing.T) {
+	if h := newHumanizer(newDeviceConfig(nil)); h != nil {
+		t.Fatalf("Expected movements not to be humanized by default")
+	}
+	if h := newHumanizer(newDeviceConfig([]Option{WithHumanizedMovement()})); h == nil {
+		t.Fatalf("Expected movements to be humanized")
+	}
+	var h *humanizer
+	if deltas := h.interpolate(10, 5, 5); !reflect.DeepEqual(deltas, interpolate(10, 5, 5)) {
+		t.Fatalf("Expected a straight path, got %v", deltas)
+	}
+}
@@ -1,9 +1,9 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"os"
+	"time"
 )
 
 // A Keyboard is an key event output device. It is used to
@@ -21,18 +21,52 @@ type Keyboard interface {
 	// The key can be any of the predefined keycodes from keycodes.go.
 	KeyUp(key int) error
 
-	io.Closer
+	// Type will type the given string, using the layout of the keyboard (see WithLayout) to determine the keys
+	// that produce each character. Modifiers (like shift for upper case letters) are handled by the keyboard.
+	Type(s string) error
+
+	// TypeContext will type the given string just like Type, but stops once the context is done. Characters that
+	// have been typed by then are not undone.
+	TypeContext(ctx context.Context, s string) error
+
+	// Combo will press the given keys in order (e.g. KeyLeftctrl, KeyLeftshift, KeyT) and release them in reverse
+	// order afterwards. Keys that have been pressed are released even if a later key fails to be pressed.
+	Combo(keys ...int) error
+
+	// KeyPressFor will press the key, hold it for the given duration and release it again (see PressFor).
+	KeyPressFor(key int, d time.Duration) error
+
+	// KeyPressForContext will press the key just like KeyPressFor, but releases it early once the context is done.
+	KeyPressForContext(ctx context.Context, key int, d time.Duration) error
+
+	// SetRepeatRate will adjust the delay after which held down keys start to repeat and the period in which they
+	// repeat afterwards. This requires the keyboard to be created using WithKeyRepeat.
+	SetRepeatRate(delay time.Duration, period time.Duration) error
+
+	// Leds will return a channel that reports whenever an LED (like caps lock) of the keyboard is switched on or off
+	// by the consumers of the keyboard. Events are dropped if the channel is not drained. The channel is closed once
+	// the keyboard is closed.
+	Leds() <-chan LedEvent
+
+	// LedState will return the current state of the lock LEDs of the keyboard.
+	LedState() LedState
+
+	Device
 }
 
 type vKeyboard struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *uinputDevice
+	layout     Layout
+	repeat     bool
+	leds       *ledTracker
+	sticky     *stickyKeys // the latched modifiers, if the keyboard has been created using WithStickyKeys
 }
 
 // CreateKeyboard will create a new keyboard using the given uinput
 // device path of the uinput device.
-func CreateKeyboard(path string, name []byte) (Keyboard, error) {
-	err := validateDevicePath(path)
+func CreateKeyboard(path string, name []byte, opts ...Option) (Keyboard, error) {
+	err := validateDevicePath(path, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,22 +75,52 @@ func CreateKeyboard(path string, name []byte) (Keyboard, error) {
 		return nil, err
 	}
 
-	fd, err := createVKeyboardDevice(path, name)
+	cfg, err := applyOptions("CreateKeyboard", opts, optLayout, optKeyRepeat, optStickyKeys)
+	if err != nil {
+		return nil, err
+	}
+	leds := newLedTracker()
+	cfg = cfg.withHandler(EvLed, leds.handleEvent).withCloser(leds.close)
+	fd, err := createVKeyboardDevice(path, name, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return vKeyboard{name: name, deviceFile: fd}, nil
+	return newVKeyboard(name, fd, cfg, leds)
+}
+
+// newVKeyboard sets up the keyboard on the given device, which has been created with the given config. The device is
+// closed if the setup fails.
+func newVKeyboard(name []byte, fd *uinputDevice, cfg deviceConfig, leds *ledTracker) (vKeyboard, error) {
+	layout := cfg.layout
+	if layout == nil {
+		layout = LayoutUS
+	}
+	vk := vKeyboard{name: name, deviceFile: fd, layout: layout, repeat: cfg.keyRepeat != nil, leds: leds}
+	if cfg.stickyKeys {
+		vk.sticky = &stickyKeys{}
+	}
+	if vk.repeat {
+		err := vk.SetRepeatRate(cfg.keyRepeat.delay, cfg.keyRepeat.period)
+		if err != nil {
+			closeDevice(fd)
+			return vKeyboard{}, err
+		}
+	}
+	return vk, nil
 }
 
 // KeyPress will issue a single key press (push down a key and then immediately release it).
 func (vk vKeyboard) KeyPress(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyPress. Code %d is not in range", key)
+		return errorf(ErrInvalidArgument, "failed to perform KeyPress. Code %d is not in range", key)
+	}
+	if vk.sticky != nil {
+		return vk.sticky.press(vk.deviceFile, key)
 	}
 	err := sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the KeyDown event: %v", err)
+		return fmt.Errorf("failed to issue the KeyDown event: %w", err)
 	}
 
 	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
@@ -67,7 +131,7 @@ func (vk vKeyboard) KeyPress(key int) error {
 // do not forget to call "KeyUp" afterwards.
 func (vk vKeyboard) KeyDown(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyDown. Code %d is not in range", key)
+		return errorf(ErrInvalidArgument, "failed to perform KeyDown. Code %d is not in range", key)
 	}
 	return sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
 }
@@ -77,37 +141,215 @@ func (vk vKeyboard) KeyDown(key int) error {
 // single key press.
 func (vk vKeyboard) KeyUp(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyUp. Code %d is not in range", key)
+		return errorf(ErrInvalidArgument, "failed to perform KeyUp. Code %d is not in range", key)
 	}
 
 	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
 }
 
+// Type will type the given string. All characters are looked up in the layout of the keyboard before typing, so
+// that nothing is typed if a character is not available.
+func (vk vKeyboard) Type(s string) error {
+	return vk.TypeContext(context.Background(), s)
+}
+
+// TypeContext will type the given string until all characters have been typed or the context is done.
+func (vk vKeyboard) TypeContext(ctx context.Context, s string) error {
+	var strokes []KeyStroke
+	for _, r := range s {
+		rs, ok := vk.layout.Strokes(r)
+		if !ok {
+			return errorf(ErrInvalidArgument, "failed to perform Type. Character %q is not available in the keyboard layout", r)
+		}
+		for _, stroke := range rs {
+			for _, key := range append([]int{stroke.Key}, stroke.Modifiers...) {
+				if !keyCodeInRange(key) {
+					return errorf(ErrInvalidArgument, "failed to perform Type. Code %d of character %q is not in range", key, r)
+				}
+			}
+		}
+		strokes = append(strokes, rs...)
+	}
+
+	for _, stroke := range strokes {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to perform Type: %w", err)
+		}
+		err := typeStroke(vk.deviceFile, stroke)
+		if err != nil {
+			return fmt.Errorf("failed to perform Type: %w", err)
+		}
+	}
+	return nil
+}
+
+// KeyPressFor will press the given key, hold it for the duration and release it again.
+func (vk vKeyboard) KeyPressFor(key int, d time.Duration) error {
+	return vk.KeyPressForContext(context.Background(), key, d)
+}
+
+// KeyPressForContext will press the given key just like KeyPressFor, but releases it early once the context is done.
+func (vk vKeyboard) KeyPressForContext(ctx context.Context, key int, d time.Duration) error {
+	if !keyCodeInRange(key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyPressFor. Code %d is not in range", key)
+	}
+	return PressForContext(ctx, vk, uint16(key), d)
+}
+
+// Combo will press and release the given keys. Every transition is reported to consumers as a frame of its own, so
+// that applications see the modifiers being held down before the main key is pressed.
+func (vk vKeyboard) Combo(keys ...int) error {
+	if len(keys) == 0 {
+		return errorf(ErrInvalidArgument, "failed to perform Combo. At least one key is required")
+	}
+	for _, key := range keys {
+		if !keyCodeInRange(key) {
+			return errorf(ErrInvalidArgument, "failed to perform Combo. Code %d is not in range", key)
+		}
+	}
+	return pressCombo(vk.deviceFile, keys)
+}
+
+// pressCombo presses the given keys in order and releases them in reverse order. If a key cannot be pressed, the
+// keys that have already been pressed are released before returning.
+func pressCombo(deviceFile *uinputDevice, keys []int) (err error) {
+	for i, key := range keys {
+		err = sendBtnEvent(deviceFile, []int{key}, btnStatePressed)
+		if err != nil {
+			err = fmt.Errorf("failed to press key %d of combo: %w", key, err)
+			keys = keys[:i]
+			break
+		}
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		releaseErr := sendBtnEvent(deviceFile, []int{keys[i]}, btnStateReleased)
+		if releaseErr != nil && err == nil {
+			err = fmt.Errorf("failed to release key %d of combo: %w", keys[i], releaseErr)
+		}
+	}
+	return err
+}
+
+// SetRepeatRate will report the new repeat rate to the kernel, which then uses it for the autorepeat of held down
+// keys.
+func (vk vKeyboard) SetRepeatRate(delay time.Duration, period time.Duration) error {
+	if !vk.repeat {
+		return errorf(ErrInvalidArgument, "failed to perform SetRepeatRate. The keyboard has been created without key repeat")
+	}
+	if delay < 0 || period < 0 {
+		return errorf(ErrInvalidArgument, "failed to perform SetRepeatRate. Expected a positive or zero delay and period")
+	}
+	return sendEvents(vk.deviceFile, []inputEvent{
+		{Type: EvRep, Code: RepDelay, Value: int32(delay / time.Millisecond)},
+		{Type: EvRep, Code: RepPeriod, Value: int32(period / time.Millisecond)},
+	})
+}
+
+func (vk vKeyboard) Leds() <-chan LedEvent {
+	return vk.leds.events
+}
+
+func (vk vKeyboard) LedState() LedState {
+	return vk.leds.snapshot()
+}
+
+// typeStroke presses and releases the key of the stroke while holding down its modifiers. The modifiers are released
+// even if the key could not be pressed, so that they don't get stuck.
+func typeStroke(deviceFile *uinputDevice, stroke KeyStroke) (err error) {
+	if len(stroke.Modifiers) > 0 {
+		err = sendBtnEvent(deviceFile, stroke.Modifiers, btnStatePressed)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			releaseErr := sendBtnEvent(deviceFile, stroke.Modifiers, btnStateReleased)
+			if err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+
+	err = sendBtnEvent(deviceFile, []int{stroke.Key}, btnStatePressed)
+	if err != nil {
+		return err
+	}
+	return sendBtnEvent(deviceFile, []int{stroke.Key}, btnStateReleased)
+}
+
+func (vk vKeyboard) Name() string {
+	return string(vk.name)
+}
+
+func (vk vKeyboard) Path() string {
+	return devicePath(vk.deviceFile)
+}
+
+func (vk vKeyboard) Fd() uintptr {
+	return deviceFd(vk.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vk vKeyboard) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vk.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vk vKeyboard) EmitEvents(events []InputEvent) error {
+	return emitEvents(vk.deviceFile, events)
+}
+
+func (vk vKeyboard) Sync() error {
+	return writeSyncEvent(vk.deviceFile)
+}
+
+func (vk vKeyboard) SysPath() (string, error) {
+	return sysPath(vk.deviceFile)
+}
+
+func (vk vKeyboard) EventPath() (string, error) {
+	return eventPath(vk.deviceFile)
+}
+
+func (vk vKeyboard) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vk.deviceFile)
+}
+
+func (vk vKeyboard) Stats() Stats {
+	return deviceStats(vk.deviceFile)
+}
+
+func (vk vKeyboard) State() State {
+	return deviceState(vk.deviceFile)
+}
+
+func (vk vKeyboard) ResetAll() error {
+	return resetDevice(vk.deviceFile)
+}
+
+func (vk vKeyboard) Disconnect() error {
+	return disconnectDevice(vk.deviceFile)
+}
+
+func (vk vKeyboard) Reconnect() error {
+	return reconnectDevice(vk.deviceFile)
+}
+
 // Close will close the device and free resources.
 // It's usually a good idea to use defer to call this function.
 func (vk vKeyboard) Close() error {
 	return closeDevice(vk.deviceFile)
 }
 
-func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createVKeyboardDevice(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create virtual keyboard device: %v", err)
+		return nil, fmt.Errorf("failed to create virtual keyboard device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerKeyboard(deviceFile, cfg)
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register virtual keyboard device: %v", err)
-	}
-
-	// register key events
-	for i := 0; i <= keyMax; i++ {
-		err = ioctl(deviceFile, uiSetKeyBit, uintptr(i))
-		if err != nil {
-			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register key number %d: %v", i, err)
-		}
+		return nil, err
 	}
 
 	return createUsbDevice(deviceFile,
@@ -117,7 +359,38 @@ func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
 				Bustype: busUsb,
 				Vendor:  0x4711,
 				Product: 0x0815,
-				Version: 1}})
+				Version: 1}},
+		cfg)
+}
+
+// registerKeyboard registers the keys and LEDs of a keyboard (and key repeat, if configured) on the device file.
+func registerKeyboard(deviceFile DeviceFile, cfg deviceConfig) error {
+	err := registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		return fmt.Errorf("failed to register virtual keyboard device: %w", err)
+	}
+
+	// the kernel takes care of repeating held down keys, once EV_REP is registered
+	if cfg.keyRepeat != nil {
+		err = ioctl(deviceFile, uiSetEvBit, uintptr(EvRep))
+		if err != nil {
+			return fmt.Errorf("failed to register key repeat: %w", err)
+		}
+	}
+
+	err = registerLeds(deviceFile, keyboardLeds)
+	if err != nil {
+		return fmt.Errorf("failed to register keyboard leds: %w", err)
+	}
+
+	// register key events
+	for i := 0; i <= keyMax; i++ {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(i))
+		if err != nil {
+			return fmt.Errorf("failed to register key number %d: %w", i, err)
+		}
+	}
+	return nil
 }
 
 func keyCodeInRange(key int) bool {
@@ -1,10 +1,15 @@
 package uinput
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // This test will confirm that basic key events are working.
@@ -55,12 +60,9 @@ func TestKeysInValidRangeWork(t *testing.T) {
 	}
 }
 
-func TestKeyboardCreationFailsOnEmptyPath(t *testing.T) {
-	expected := "device path must not be empty"
-	_, err := CreateKeyboard("", []byte("KeyboardDevice"))
-	if err.Error() != expected {
-		t.Fatalf("Expected: %s\nActual: %s", expected, err)
-	}
+func TestKeyboardCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateKeyboard("", []byte("KeyboardDevice"))
+	assertPathDiscovered(t, device, err)
 }
 
 func TestKeyboardCreationFailsOnNonExistentPathName(t *testing.T) {
@@ -188,3 +190,169 @@ func TestKeyDownFailsIfDeviceIsClosed(t *testing.T) {
 		t.Fatalf("Expected KeyPress to fail, but no error was returned.")
 	}
 }
+
+func TestKeyboardWithPhysAndUniq(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"), WithPhys("uinput-test/input0"), WithUniq("test-keyboard-1"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestKeyboardEmitRawEvents(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.EmitEvent(EvKey, Key1, 1)
+	if err != nil {
+		t.Fatalf("Failed to emit raw event. Last error was: %s\n", err)
+	}
+
+	err = vk.EmitEvents([]InputEvent{{Type: EvKey, Code: Key1, Value: 0}, {Type: EvKey, Code: Key2, Value: 0}})
+	if err != nil {
+		t.Fatalf("Failed to emit raw events. Last error was: %s\n", err)
+	}
+}
+
+func TestKeyboardEventPathExists(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	sysPath, err := vk.SysPath()
+	if err != nil {
+		t.Fatalf("Failed to get sysfs path. Last error was: %s\n", err)
+	}
+	if !strings.HasPrefix(sysPath, "/sys/devices/virtual/input/input") {
+		t.Fatalf("Expected sysfs path below /sys/devices/virtual/input, but got %s", sysPath)
+	}
+
+	eventPath, err := vk.EventPath()
+	if err != nil {
+		t.Fatalf("Failed to get event path. Last error was: %s\n", err)
+	}
+	if _, err = os.Stat(eventPath); err != nil {
+		t.Fatalf("Expected event node %s to exist. Last error was: %s\n", eventPath, err)
+	}
+}
+
+func TestKeyboardTypesString(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Type("Hello, World!\n")
+	if err != nil {
+		t.Fatalf("Failed to type string. Last error was: %s\n", err)
+	}
+
+	err = vk.Type("Grüße")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected typing of unavailable characters to fail with ErrInvalidArgument, but got: %v", err)
+	}
+}
+
+func TestTypeContextStopsOnCancellation(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-type-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	vk := vKeyboard{name: []byte("Test Keyboard"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), layout: LayoutUS}
+	err = vk.TypeContext(ctx, "abc")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected: %v\nActual: %v", context.Canceled, err)
+	}
+	if events := readEvents(t, file.Name(), evKey); len(events) != 0 {
+		t.Fatalf("Expected nothing to be typed, got %v", events)
+	}
+}
+
+func TestComboReleasesKeysInReverseOrder(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-combo-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = pressCombo(newUinputDevice(tempFile{file}, newDeviceConfig(nil)), []int{KeyLeftctrl, KeyLeftshift, KeyT})
+	if err != nil {
+		t.Fatalf("Failed to press combo: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyT, Value: btnStatePressed},
+		{Type: evKey, Code: KeyT, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStateReleased},
+	}
+	if keys := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, keys)
+	}
+}
+
+func TestComboFailsOnInvalidKey(t *testing.T) {
+	vk := vKeyboard{name: []byte("Test Basic Keyboard")}
+	err := vk.Combo(KeyLeftctrl, -1)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrInvalidArgument, err)
+	}
+}
+
+func TestKeyboardWithKeyRepeat(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Basic Keyboard"), WithKeyRepeat(500*time.Millisecond, 50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.SetRepeatRate(250*time.Millisecond, 33*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to set repeat rate. Last error was: %s\n", err)
+	}
+}
+
+func TestSetRepeatRateReportsRateInMilliseconds(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-repeat-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	vk := vKeyboard{deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), repeat: true}
+	err = vk.SetRepeatRate(250*time.Millisecond, 33*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to set repeat rate: %v", err)
+	}
+
+	expected := []inputEvent{{Type: EvRep, Code: RepDelay, Value: 250}, {Type: EvRep, Code: RepPeriod, Value: 33}}
+	if events := readEvents(t, file.Name(), EvRep); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestSetRepeatRateFailsWithoutKeyRepeat(t *testing.T) {
+	err := vKeyboard{}.SetRepeatRate(time.Second, time.Second)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrInvalidArgument, err)
+	}
+}
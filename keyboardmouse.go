@@ -0,0 +1,210 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A KeyboardMouse is a single device that combines a keyboard and a mouse, like the receiver of a wireless keyboard
+// and mouse combo. It provides the methods of both devices on a single handle, while consumers see a single input
+// device that reports keys as well as pointer movements.
+type KeyboardMouse interface {
+	Keyboard
+
+	// MoveLeft will move the mouse cursor left by the given number of pixel.
+	MoveLeft(pixel int32) error
+
+	// MoveRight will move the mouse cursor right by the given number of pixel.
+	MoveRight(pixel int32) error
+
+	// MoveUp will move the mouse cursor up by the given number of pixel.
+	MoveUp(pixel int32) error
+
+	// MoveDown will move the mouse cursor down by the given number of pixel.
+	MoveDown(pixel int32) error
+
+	// Move will move the mouse pointer along the x and y axes relative to the current position (see Mouse.Move).
+	Move(x, y int32) error
+
+	// MoveSmooth will move the mouse pointer by x and y in steps spread across the duration (see Mouse.MoveSmooth).
+	MoveSmooth(x, y int32, duration time.Duration, steps int) error
+
+	// MoveSmoothContext will move the mouse pointer just like MoveSmooth, but stops once the context is done.
+	MoveSmoothContext(ctx context.Context, x, y int32, duration time.Duration, steps int) error
+
+	// LeftClick will issue a single left click.
+	LeftClick() error
+
+	// RightClick will issue a right click.
+	RightClick() error
+
+	// LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
+	// LeftRelease is invoked.
+	LeftPress() error
+
+	// LeftRelease will simulate the release of the left mouse button.
+	LeftRelease() error
+
+	// RightPress will simulate the press of the right mouse button. Note that the button will not be released until
+	// RightRelease is invoked.
+	RightPress() error
+
+	// RightRelease will simulate the release of the right mouse button.
+	RightRelease() error
+
+	// MiddleClick will issue a middle click.
+	MiddleClick() error
+
+	// MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
+	// MiddleRelease is invoked.
+	MiddlePress() error
+
+	// MiddleRelease will simulate the release of the middle mouse button.
+	MiddleRelease() error
+
+	// ButtonPressFor will press the given button (BtnLeft, BtnRight or BtnMiddle), hold it for the duration and
+	// release it again (see PressFor).
+	ButtonPressFor(button int, d time.Duration) error
+
+	// ButtonPressForContext will press the button just like ButtonPressFor, but releases it early once the context is
+	// done.
+	ButtonPressForContext(ctx context.Context, button int, d time.Duration) error
+
+	// Wheel will simulate a wheel movement.
+	Wheel(horizontal bool, delta int32) error
+
+	// Scroll will scroll by the given number of wheel notches (see Mouse.Scroll).
+	Scroll(vertical, horizontal float64) error
+}
+
+// vKeyboardMouse provides the methods of the keyboard and the mouse, which share the same device. The methods of
+// Device are ambiguous and therefore defined explicitly.
+type vKeyboardMouse struct {
+	vKeyboard
+	vMouse
+}
+
+// CreateKeyboardMouse will create a new device that combines a keyboard and a mouse. The options of both devices
+// (like WithLayout or WithHumanizedMovement) apply.
+func CreateKeyboardMouse(path string, name []byte, opts ...Option) (KeyboardMouse, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateKeyboardMouse", opts, optLayout, optKeyRepeat, optStickyKeys, optHumanizedMovement)
+	if err != nil {
+		return nil, err
+	}
+	leds := newLedTracker()
+	cfg = cfg.withHandler(EvLed, leds.handleEvent).withCloser(leds.close)
+	fd, err := createKeyboardMouse(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	vk, err := newVKeyboard(name, fd, cfg, leds)
+	if err != nil {
+		return nil, err
+	}
+	return vKeyboardMouse{
+		vKeyboard: vk,
+		vMouse:    vMouse{name: name, deviceFile: fd, wheel: &wheelState{}, human: newHumanizer(cfg)},
+	}, nil
+}
+
+func (vkm vKeyboardMouse) Name() string {
+	return string(vkm.vKeyboard.name)
+}
+
+func (vkm vKeyboardMouse) Path() string {
+	return devicePath(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) Fd() uintptr {
+	return deviceFd(vkm.vKeyboard.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vkm vKeyboardMouse) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vkm.vKeyboard.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vkm vKeyboardMouse) EmitEvents(events []InputEvent) error {
+	return emitEvents(vkm.vKeyboard.deviceFile, events)
+}
+
+func (vkm vKeyboardMouse) Sync() error {
+	return writeSyncEvent(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) SysPath() (string, error) {
+	return sysPath(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) EventPath() (string, error) {
+	return eventPath(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) Stats() Stats {
+	return deviceStats(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) State() State {
+	return deviceState(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) ResetAll() error {
+	return resetDevice(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) Disconnect() error {
+	return disconnectDevice(vkm.vKeyboard.deviceFile)
+}
+
+func (vkm vKeyboardMouse) Reconnect() error {
+	return reconnectDevice(vkm.vKeyboard.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vkm vKeyboardMouse) Close() error {
+	return closeDevice(vkm.vKeyboard.deviceFile)
+}
+
+func createKeyboardMouse(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create keyboard and mouse input device: %w", err)
+	}
+
+	err = registerKeyboard(deviceFile, cfg)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+	err = registerMouse(deviceFile)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0820,
+				Version: 1}},
+		cfg)
+}
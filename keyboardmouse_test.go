@@ -0,0 +1,79 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// the combined device is expected to be usable wherever a keyboard or a mouse is expected
+var (
+	_ Keyboard = KeyboardMouse(nil)
+	_ Mouse    = KeyboardMouse(nil)
+)
+
+func TestBasicKeyboardMouse(t *testing.T) {
+	combo, err := CreateKeyboardMouse("/dev/uinput", []byte("Test Keyboard Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard and mouse. Last error was: %s\n", err)
+	}
+
+	err = combo.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to press a key. Last error was: %s\n", err)
+	}
+
+	err = combo.Move(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to move the pointer. Last error was: %s\n", err)
+	}
+
+	err = combo.LeftClick()
+	if err != nil {
+		t.Fatalf("Failed to click. Last error was: %s\n", err)
+	}
+
+	err = combo.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestKeyboardMouseCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateKeyboardMouse("", []byte("KeyboardMouse"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestKeyboardMouseSharesSingleDevice(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-keyboardmouse-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	fd := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	combo := vKeyboardMouse{
+		vKeyboard: vKeyboard{name: []byte("Test Keyboard Mouse"), deviceFile: fd, layout: LayoutUS},
+		vMouse:    vMouse{name: []byte("Test Keyboard Mouse"), deviceFile: fd, wheel: &wheelState{}},
+	}
+	err = combo.Type("a")
+	if err != nil {
+		t.Fatalf("Failed to type: %v", err)
+	}
+	err = combo.LeftClick()
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnLeft, Value: btnStatePressed},
+		{Type: evKey, Code: evBtnLeft, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
@@ -1,5 +1,8 @@
 package uinput
 
+// The remaining codes of input-event-codes.h (like button and axis codes) are generated, see gencodes.go.
+//go:generate go run gencodes.go
+
 // the constants that are defined here relate 1:1 to the constants defined in input.h and represent actual
 // key codes that can be triggered as key events
 const (
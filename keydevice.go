@@ -0,0 +1,49 @@
+package uinput
+
+import "fmt"
+
+// createKeyDevice creates a device that provides the given keys only. Presets like power keys or media remotes are
+// based on such devices, as consumers classify devices by the keys they provide.
+func createKeyDevice(path string, name []byte, keys []int, product uint16, cfg deviceConfig) (fd *uinputDevice, err error) {
+	err = validateCodes(keys, nil)
+	if err != nil {
+		return nil, err
+	}
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create key input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	for _, key := range keys {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(key))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register key number %d: %w", key, err)
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: product,
+				Version: 1}},
+		cfg)
+}
+
+// pressKey presses the given key and then immediately releases it.
+func pressKey(deviceFile *uinputDevice, key int) error {
+	err := sendBtnEvent(deviceFile, []int{key}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the KeyDown event: %w", err)
+	}
+
+	return sendBtnEvent(deviceFile, []int{key}, btnStateReleased)
+}
@@ -0,0 +1,95 @@
+package uinput
+
+// A KeyStroke is a single key press along with the modifiers (like KeyLeftshift) that need to be held down while the
+// key is pressed.
+type KeyStroke struct {
+	Key       int
+	Modifiers []int
+}
+
+// A Layout maps characters to the key strokes that produce them. Since the kernel only knows about key codes, the
+// layout needs to match the layout that is configured in the session that consumes the events. Otherwise, typing
+// will yield wrong characters.
+type Layout interface {
+	// Strokes returns the key strokes that produce the given character, or false if the character cannot be typed
+	// using this layout.
+	Strokes(r rune) ([]KeyStroke, bool)
+}
+
+// A KeyMap is a layout that maps every character to the key strokes that produce it, which allows to define custom
+// layouts (or to extend the predefined ones, see CombineLayouts). Characters that need more than one stroke are
+// typed using dead keys: e.g. on German keyboards, 'é' is typed by pressing the acute dead key followed by 'e'.
+type KeyMap map[rune][]KeyStroke
+
+func (m KeyMap) Strokes(r rune) ([]KeyStroke, bool) {
+	strokes, ok := m[r]
+	return strokes, ok
+}
+
+// addKeys adds the characters that are produced by the given keys while the given modifiers are held down. A space
+// means that the key does not produce a character. Characters that have been added already are kept, which is why
+// the levels of a layout are expected to be added in order (plain, shifted, AltGr).
+func (m KeyMap) addKeys(keys []int, runes string, modifiers ...int) {
+	for i, r := range []rune(runes) {
+		if _, ok := m[r]; ok || r == ' ' {
+			continue
+		}
+		m[r] = []KeyStroke{{Key: keys[i], Modifiers: modifiers}}
+	}
+}
+
+// addDeadKey adds the characters that are composed by the dead key followed by the respective base characters. The
+// base characters need to be available already.
+func (m KeyMap) addDeadKey(dead KeyStroke, composed string, bases string) {
+	baseRunes := []rune(bases)
+	for i, r := range []rune(composed) {
+		if _, ok := m[r]; ok {
+			continue
+		}
+		m[r] = append([]KeyStroke{dead}, m[baseRunes[i]]...)
+	}
+}
+
+// CombineLayouts returns a layout that looks up every character in the given layouts in order, which allows to
+// override or extend a layout with custom mappings.
+func CombineLayouts(layouts ...Layout) Layout {
+	return combinedLayout(layouts)
+}
+
+type combinedLayout []Layout
+
+func (l combinedLayout) Strokes(r rune) ([]KeyStroke, bool) {
+	for _, layout := range l {
+		if strokes, ok := layout.Strokes(r); ok {
+			return strokes, true
+		}
+	}
+	return nil, false
+}
+
+// newKeymapLayout builds a layout from the characters that are produced by the given keys without and with shift
+// being held down. A space in either string means that the key does not produce a character in that state.
+func newKeymapLayout(keys []int, plain string, shifted string) KeyMap {
+	layout := KeyMap{
+		' ':  {{Key: KeySpace}},
+		'\n': {{Key: KeyEnter}},
+		'\t': {{Key: KeyTab}},
+	}
+	layout.addKeys(keys, plain)
+	layout.addKeys(keys, shifted, KeyLeftshift)
+	return layout
+}
+
+// usKeys are the keys that produce characters on a US keyboard, ordered by rows
+var usKeys = []int{
+	KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual,
+	KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace, KeyBackslash,
+	KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe,
+	KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash,
+}
+
+// LayoutUS is the US (QWERTY) keyboard layout. It is used for typing, unless another layout is selected using
+// WithLayout.
+var LayoutUS Layout = newKeymapLayout(usKeys,
+	"`1234567890-="+"qwertyuiop[]\\"+"asdfghjkl;'"+"zxcvbnm,./",
+	"~!@#$%^&*()_+"+"QWERTYUIOP{}|"+"ASDFGHJKL:\""+"ZXCVBNM<>?")
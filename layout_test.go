@@ -0,0 +1,59 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestUSLayoutStrokes(t *testing.T) {
+	tests := []struct {
+		r        rune
+		expected KeyStroke
+	}{
+		{'a', KeyStroke{Key: KeyA}},
+		{'A', KeyStroke{Key: KeyA, Modifiers: []int{KeyLeftshift}}},
+		{'1', KeyStroke{Key: Key1}},
+		{'!', KeyStroke{Key: Key1, Modifiers: []int{KeyLeftshift}}},
+		{'?', KeyStroke{Key: KeySlash, Modifiers: []int{KeyLeftshift}}},
+		{'"', KeyStroke{Key: KeyApostrophe, Modifiers: []int{KeyLeftshift}}},
+		{' ', KeyStroke{Key: KeySpace}},
+		{'\n', KeyStroke{Key: KeyEnter}},
+	}
+	for _, test := range tests {
+		strokes, ok := LayoutUS.Strokes(test.r)
+		if !ok || !reflect.DeepEqual(strokes, []KeyStroke{test.expected}) {
+			t.Fatalf("%q: Expected: %+v\nActual: %+v (found: %v)", test.r, test.expected, strokes, ok)
+		}
+	}
+
+	if _, ok := LayoutUS.Strokes('ä'); ok {
+		t.Fatalf("Expected 'ä' not to be available in the US layout")
+	}
+}
+
+func TestTypeStrokeReleasesModifiers(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-layout-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err = typeStroke(newUinputDevice(tempFile{file}, newDeviceConfig(nil)), KeyStroke{Key: KeyA, Modifiers: []int{KeyLeftshift}})
+	if err != nil {
+		t.Fatalf("Failed to type stroke: %v", err)
+	}
+
+	keys := readEvents(t, file.Name(), evKey)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, keys)
+	}
+}
@@ -0,0 +1,70 @@
+package uinput
+
+// isoKeys are the keys that produce characters on ISO keyboards, ordered like usKeys. ISO keyboards have an additional
+// key next to the left shift key (KEY_102ND), while the key that is KEY_BACKSLASH on US keyboards sits next to enter.
+var isoKeys = append(usKeys[:len(usKeys):len(usKeys)], Key102Nd)
+
+// the characters composed by dead keys (following the default compose table of X11 and libxkbcommon) along with
+// the base characters they are composed from
+const (
+	circumflexComposed = "âêîôûÂÊÎÔÛ"
+	circumflexBases    = "aeiouAEIOU"
+	acuteComposed      = "áéíóúýÁÉÍÓÚÝ"
+	acuteBases         = "aeiouyAEIOUY"
+	graveComposed      = "àèìòùÀÈÌÒÙ"
+	graveBases         = "aeiouAEIOU"
+	diaeresisComposed  = "äëïöüÿÄËÏÖÜ"
+	diaeresisBases     = "aeiouyAEIOU"
+)
+
+// LayoutDE is the German (QWERTZ) keyboard layout, including its dead keys for circumflex, acute and grave accents.
+var LayoutDE Layout = newDELayout()
+
+// LayoutFR is the French (AZERTY) keyboard layout, including its dead keys for circumflex and diaeresis.
+var LayoutFR Layout = newFRLayout()
+
+// LayoutUK is the British (QWERTY) keyboard layout.
+var LayoutUK Layout = newUKLayout()
+
+func newDELayout() KeyMap {
+	layout := newKeymapLayout(isoKeys,
+		" 1234567890ß "+"qwertzuiopü+#"+"asdfghjklöä"+"yxcvbnm,.-"+"<",
+		"°!\"§$%&/()=? "+"QWERTZUIOPÜ*'"+"ASDFGHJKLÖÄ"+"YXCVBNM;:_"+">")
+	layout.addKeys(isoKeys,
+		"  ²³   {[]}\\ "+"@ €        ~ "+"           "+"      µ   "+"|", KeyRightalt)
+
+	circumflex := KeyStroke{Key: KeyGrave}
+	acute := KeyStroke{Key: KeyEqual}
+	grave := KeyStroke{Key: KeyEqual, Modifiers: []int{KeyLeftshift}}
+	layout['^'] = []KeyStroke{circumflex, {Key: KeySpace}}
+	layout['´'] = []KeyStroke{acute, acute}
+	layout['`'] = []KeyStroke{grave, {Key: KeySpace}}
+	layout.addDeadKey(circumflex, circumflexComposed, circumflexBases)
+	layout.addDeadKey(acute, acuteComposed, acuteBases)
+	layout.addDeadKey(grave, graveComposed, graveBases)
+	return layout
+}
+
+func newFRLayout() KeyMap {
+	layout := newKeymapLayout(isoKeys,
+		"²&é\"'(-è_çà)="+"azertyuiop $*"+"qsdfghjklmù"+"wxcvbn,;:!"+"<",
+		"~1234567890°+"+"AZERTYUIOP £µ"+"QSDFGHJKLM%"+"WXCVBN?./§"+">")
+	layout.addKeys(isoKeys,
+		"  ~#{[|`\\^@]}"+"  €          "+"           "+"          "+" ", KeyRightalt)
+
+	circumflex := KeyStroke{Key: KeyLeftbrace}
+	diaeresis := KeyStroke{Key: KeyLeftbrace, Modifiers: []int{KeyLeftshift}}
+	layout['¨'] = []KeyStroke{diaeresis, diaeresis}
+	layout.addDeadKey(circumflex, circumflexComposed, circumflexBases)
+	layout.addDeadKey(diaeresis, diaeresisComposed, diaeresisBases)
+	return layout
+}
+
+func newUKLayout() KeyMap {
+	layout := newKeymapLayout(isoKeys,
+		"`1234567890-="+"qwertyuiop[]#"+"asdfghjkl;'"+"zxcvbnm,./"+"\\",
+		"¬!\"£$%^&*()_+"+"QWERTYUIOP{}~"+"ASDFGHJKL:@"+"ZXCVBNM<>?"+"|")
+	layout.addKeys(isoKeys,
+		"    €        "+"             "+"           "+"          "+" ", KeyRightalt)
+	return layout
+}
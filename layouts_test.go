@@ -0,0 +1,75 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayoutStrokes(t *testing.T) {
+	shift := []int{KeyLeftshift}
+	altGr := []int{KeyRightalt}
+	tests := []struct {
+		name     string
+		layout   Layout
+		r        rune
+		expected []KeyStroke
+	}{
+		{"DE", LayoutDE, 'z', []KeyStroke{{Key: KeyY}}},
+		{"DE", LayoutDE, 'ß', []KeyStroke{{Key: KeyMinus}}},
+		{"DE", LayoutDE, '#', []KeyStroke{{Key: KeyBackslash}}},
+		{"DE", LayoutDE, '>', []KeyStroke{{Key: Key102Nd, Modifiers: shift}}},
+		{"DE", LayoutDE, '@', []KeyStroke{{Key: KeyQ, Modifiers: altGr}}},
+		{"DE", LayoutDE, '\\', []KeyStroke{{Key: KeyMinus, Modifiers: altGr}}},
+		{"DE", LayoutDE, '~', []KeyStroke{{Key: KeyRightbrace, Modifiers: altGr}}},
+		{"DE", LayoutDE, 'µ', []KeyStroke{{Key: KeyM, Modifiers: altGr}}},
+		{"DE", LayoutDE, '|', []KeyStroke{{Key: Key102Nd, Modifiers: altGr}}},
+		{"DE", LayoutDE, 'é', []KeyStroke{{Key: KeyEqual}, {Key: KeyE}}},
+		{"DE", LayoutDE, 'Ê', []KeyStroke{{Key: KeyGrave}, {Key: KeyE, Modifiers: shift}}},
+		{"DE", LayoutDE, 'ò', []KeyStroke{{Key: KeyEqual, Modifiers: shift}, {Key: KeyO}}},
+		{"DE", LayoutDE, '^', []KeyStroke{{Key: KeyGrave}, {Key: KeySpace}}},
+		{"FR", LayoutFR, 'a', []KeyStroke{{Key: KeyQ}}},
+		{"FR", LayoutFR, 'm', []KeyStroke{{Key: KeySemicolon}}},
+		{"FR", LayoutFR, '1', []KeyStroke{{Key: Key1, Modifiers: shift}}},
+		{"FR", LayoutFR, '!', []KeyStroke{{Key: KeySlash}}},
+		{"FR", LayoutFR, '@', []KeyStroke{{Key: Key0, Modifiers: altGr}}},
+		{"FR", LayoutFR, '}', []KeyStroke{{Key: KeyEqual, Modifiers: altGr}}},
+		{"FR", LayoutFR, '€', []KeyStroke{{Key: KeyE, Modifiers: altGr}}},
+		{"FR", LayoutFR, '^', []KeyStroke{{Key: Key9, Modifiers: altGr}}},
+		{"FR", LayoutFR, 'ê', []KeyStroke{{Key: KeyLeftbrace}, {Key: KeyE}}},
+		{"FR", LayoutFR, 'Ï', []KeyStroke{{Key: KeyLeftbrace, Modifiers: shift}, {Key: KeyI, Modifiers: shift}}},
+		{"UK", LayoutUK, '£', []KeyStroke{{Key: Key3, Modifiers: shift}}},
+		{"UK", LayoutUK, '@', []KeyStroke{{Key: KeyApostrophe, Modifiers: shift}}},
+		{"UK", LayoutUK, '#', []KeyStroke{{Key: KeyBackslash}}},
+		{"UK", LayoutUK, '\\', []KeyStroke{{Key: Key102Nd}}},
+		{"UK", LayoutUK, '€', []KeyStroke{{Key: Key4, Modifiers: altGr}}},
+	}
+	for _, test := range tests {
+		strokes, ok := test.layout.Strokes(test.r)
+		if !ok || !reflect.DeepEqual(strokes, test.expected) {
+			t.Fatalf("%s %q: Expected: %+v\nActual: %+v (found: %v)", test.name, test.r, test.expected, strokes, ok)
+		}
+	}
+}
+
+func TestCombineLayoutsPrefersFirstLayout(t *testing.T) {
+	custom := KeyMap{'ü': {{Key: KeyU, Modifiers: []int{KeyRightalt}}}, 'a': {{Key: KeyB}}}
+	layout := CombineLayouts(custom, LayoutUS)
+
+	tests := []struct {
+		r        rune
+		expected []KeyStroke
+	}{
+		{'ü', []KeyStroke{{Key: KeyU, Modifiers: []int{KeyRightalt}}}},
+		{'a', []KeyStroke{{Key: KeyB}}},
+		{'z', []KeyStroke{{Key: KeyZ}}},
+	}
+	for _, test := range tests {
+		strokes, ok := layout.Strokes(test.r)
+		if !ok || !reflect.DeepEqual(strokes, test.expected) {
+			t.Fatalf("%q: Expected: %+v\nActual: %+v (found: %v)", test.r, test.expected, strokes, ok)
+		}
+	}
+	if _, ok := layout.Strokes('ä'); ok {
+		t.Fatalf("Expected 'ä' not to be available in any of the layouts")
+	}
+}
@@ -0,0 +1,92 @@
+package uinput
+
+import (
+	"sync"
+)
+
+// ledBufferSize is the number of LED events that are buffered for consumers of the LED channel. If the buffer is
+// full, further events will be dropped, as the state remains available via LedState.
+const ledBufferSize = 16
+
+// the LEDs that are registered on keyboards
+var keyboardLeds = []uint16{LedNuml, LedCapsl, LedScrolll, LedCompose, LedKana}
+
+// An LedEvent reports that an LED of a keyboard has been switched on or off. LEDs are switched by the consumers of the
+// keyboard (like the display server), for example when caps lock is pressed.
+type LedEvent struct {
+	Led uint16
+	On  bool
+}
+
+// LedState is a snapshot of the lock LEDs of a keyboard.
+type LedState struct {
+	NumLock    bool
+	CapsLock   bool
+	ScrollLock bool
+	Compose    bool
+	Kana       bool
+}
+
+// ledTracker keeps track of the LED state of a device and forwards all changes to its channel.
+type ledTracker struct {
+	mu     sync.Mutex
+	state  LedState
+	events chan LedEvent
+}
+
+func newLedTracker() *ledTracker {
+	return &ledTracker{events: make(chan LedEvent, ledBufferSize)}
+}
+
+func (t *ledTracker) update(led uint16, on bool) {
+	t.mu.Lock()
+	switch led {
+	case LedNuml:
+		t.state.NumLock = on
+	case LedCapsl:
+		t.state.CapsLock = on
+	case LedScrolll:
+		t.state.ScrollLock = on
+	case LedCompose:
+		t.state.Compose = on
+	case LedKana:
+		t.state.Kana = on
+	}
+	t.mu.Unlock()
+
+	select {
+	case t.events <- LedEvent{Led: led, On: on}:
+	default:
+	}
+}
+
+func (t *ledTracker) snapshot() LedState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// registerLeds registers the given LEDs on the device, so that consumers may switch them.
+func registerLeds(deviceFile DeviceFile, leds []uint16) error {
+	err := ioctl(deviceFile, uiSetEvBit, uintptr(EvLed))
+	if err != nil {
+		return err
+	}
+	for _, led := range leds {
+		err = ioctl(deviceFile, uiSetLedBit, uintptr(led))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleEvent updates the tracker with the LED events that are sent to the device.
+func (t *ledTracker) handleEvent(_ DeviceFile, iev inputEvent) {
+	t.update(iev.Code, iev.Value != 0)
+}
+
+// close closes the channel of the tracker once the device has been closed.
+func (t *ledTracker) close() {
+	close(t.events)
+}
@@ -0,0 +1,50 @@
+package uinput
+
+import (
+	"os"
+	"testing"
+	"unsafe"
+)
+
+func TestLedEventsAreTracked(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer reader.Close()
+
+	tracker := newLedTracker()
+	cfg := deviceConfig{}.withHandler(EvLed, tracker.handleEvent).withCloser(tracker.close)
+	go dispatchEvents(tempFile{reader}, cfg.handlers)
+
+	for _, iev := range []inputEvent{
+		{Type: EvLed, Code: LedCapsl, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: EvLed, Code: LedNuml, Value: 1},
+		{Type: EvLed, Code: LedNuml, Value: 0},
+	} {
+		_, err = writer.Write((*[inputEventSize]byte)(unsafe.Pointer(&iev))[:])
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+	writer.Close()
+
+	var events []LedEvent
+	for event := range tracker.events {
+		events = append(events, event)
+	}
+	expected := []LedEvent{{Led: LedCapsl, On: true}, {Led: LedNuml, On: true}, {Led: LedNuml, On: false}}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+		}
+	}
+
+	if state := tracker.snapshot(); state != (LedState{CapsLock: true}) {
+		t.Fatalf("Expected: %+v\nActual: %+v", LedState{CapsLock: true}, state)
+	}
+}
@@ -0,0 +1,150 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// A Manager keeps track of a group of devices, which may be looked up by name and are closed all at once. This is
+// useful for test harnesses that create many devices: a single deferred call to Close removes all of them, even if
+// the test panics. Devices are removed by the kernel when the process exits anyway, but CloseOnSignal makes sure that
+// they are also removed cleanly when the process is interrupted.
+type Manager struct {
+	mu      sync.Mutex
+	devices []Device
+	closed  bool
+	signals chan os.Signal
+}
+
+// NewManager will create a new manager without any devices.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add will add the given device to the manager, which takes over the responsibility of closing it. The name of the
+// device needs to be unique within the manager. If the device cannot be added, it is closed right away, which allows
+// to add devices straight from their constructors:
+//
+//	keyboard, err := uinput.CreateKeyboard("/dev/uinput", []byte("keyboard"))
+//	if err == nil {
+//		err = manager.Add(keyboard)
+//	}
+func (m *Manager) Add(device Device) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		device.Close()
+		return errorf(ErrDeviceClosed, "failed to add device %q. The manager has been closed", device.Name())
+	}
+	for _, d := range m.devices {
+		if d.Name() == device.Name() {
+			device.Close()
+			return errorf(ErrInvalidArgument, "failed to add device %q. A device with the same name already exists", device.Name())
+		}
+	}
+	m.devices = append(m.devices, device)
+	return nil
+}
+
+// Get will return the device with the given name.
+func (m *Manager) Get(name string) (Device, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.devices {
+		if d.Name() == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Devices will return all devices of the manager in the order they have been added.
+func (m *Manager) Devices() []Device {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Device(nil), m.devices...)
+}
+
+// Remove will close the device with the given name and remove it from the manager.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	var device Device
+	for i, d := range m.devices {
+		if d.Name() == name {
+			device = d
+			m.devices = append(m.devices[:i], m.devices[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	if device == nil {
+		return errorf(ErrInvalidArgument, "failed to remove device %q. The device does not exist", name)
+	}
+	return closeManaged(device)
+}
+
+// Close will close all devices of the manager in reverse order. All devices are closed even if closing one of them
+// fails, in which case the first error is returned. Devices that have already been closed directly are skipped. Once
+// closed, no more devices may be added to the manager.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	devices := m.devices
+	m.devices = nil
+	m.closed = true
+	if m.signals != nil {
+		signal.Stop(m.signals)
+		close(m.signals)
+		m.signals = nil
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for i := len(devices) - 1; i >= 0; i-- {
+		err := closeManaged(devices[i])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// CloseOnSignal will close all devices once the process receives one of the given signals (SIGINT and SIGTERM if
+// none are given). Afterwards, the signal is raised again with its default behavior restored, so that the process
+// terminates just as it would without the manager.
+func (m *Manager) CloseOnSignal(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || m.signals != nil {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	m.signals = ch
+	go func() {
+		sig, ok := <-ch
+		if !ok {
+			return
+		}
+		m.Close()
+		signal.Reset(sig)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(sig)
+		}
+	}()
+}
+
+func closeManaged(device Device) error {
+	err := device.Close()
+	if err != nil && !errors.Is(err, ErrDeviceClosed) {
+		return fmt.Errorf("failed to close device %q: %w", device.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeDevice is a device that records whether it has been closed
+type fakeDevice struct {
+	name     string
+	closed   *[]string
+	closeErr error
+}
+
+func (d fakeDevice) Name() string                                            { return d.name }
+func (d fakeDevice) Path() string                                            { return "" }
+func (d fakeDevice) Fd() uintptr                                             { return 0 }
+func (d fakeDevice) EmitEvent(evType uint16, code uint16, value int32) error { return nil }
+func (d fakeDevice) EmitEvents(events []InputEvent) error                    { return nil }
+func (d fakeDevice) Sync() error                                             { return nil }
+func (d fakeDevice) SysPath() (string, error)                                { return "", nil }
+func (d fakeDevice) EventPath() (string, error)                              { return "", nil }
+func (d fakeDevice) WaitReady(ctx context.Context) error                     { return nil }
+func (d fakeDevice) Stats() Stats                                            { return Stats{} }
+func (d fakeDevice) State() State                                            { return State{} }
+func (d fakeDevice) ResetAll() error                                         { return nil }
+func (d fakeDevice) Disconnect() error                                       { return nil }
+func (d fakeDevice) Reconnect() error                                        { return nil }
+
+func (d fakeDevice) Close() error {
+	*d.closed = append(*d.closed, d.name)
+	return d.closeErr
+}
+
+func TestManagerClosesAllDevicesInReverseOrder(t *testing.T) {
+	var closed []string
+	m := NewManager()
+	for _, name := range []string{"first", "second", "third"} {
+		err := m.Add(fakeDevice{name: name, closed: &closed})
+		if err != nil {
+			t.Fatalf("Failed to add device %q: %v", name, err)
+		}
+	}
+
+	if device, ok := m.Get("second"); !ok || device.Name() != "second" {
+		t.Fatalf("Expected to find the device named second, got %v", device)
+	}
+	if _, ok := m.Get("fourth"); ok {
+		t.Fatalf("Expected not to find an unknown device")
+	}
+
+	err := m.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the manager: %v", err)
+	}
+	expected := []string{"third", "second", "first"}
+	if !reflect.DeepEqual(closed, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, closed)
+	}
+	if len(m.Devices()) != 0 {
+		t.Fatalf("Expected no devices after closing the manager, got %v", m.Devices())
+	}
+}
+
+func TestManagerRejectsDuplicateNames(t *testing.T) {
+	var closed []string
+	m := NewManager()
+	defer m.Close()
+
+	err := m.Add(fakeDevice{name: "device", closed: &closed})
+	if err != nil {
+		t.Fatalf("Failed to add device: %v", err)
+	}
+	err = m.Add(fakeDevice{name: "device", closed: &closed})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a duplicate name, got: %v", err)
+	}
+	if !reflect.DeepEqual(closed, []string{"device"}) {
+		t.Fatalf("Expected the rejected device to be closed, got %v", closed)
+	}
+}
+
+func TestManagerRemoveClosesDevice(t *testing.T) {
+	var closed []string
+	m := NewManager()
+	defer m.Close()
+
+	_ = m.Add(fakeDevice{name: "device", closed: &closed})
+	err := m.Remove("device")
+	if err != nil {
+		t.Fatalf("Failed to remove device: %v", err)
+	}
+	if !reflect.DeepEqual(closed, []string{"device"}) {
+		t.Fatalf("Expected the removed device to be closed, got %v", closed)
+	}
+	err = m.Remove("device")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unknown device, got: %v", err)
+	}
+}
+
+func TestManagerCloseContinuesOnError(t *testing.T) {
+	var closed []string
+	failure := errors.New("failure")
+	m := NewManager()
+	_ = m.Add(fakeDevice{name: "first", closed: &closed})
+	_ = m.Add(fakeDevice{name: "second", closed: &closed, closeErr: failure})
+	_ = m.Add(fakeDevice{name: "third", closed: &closed, closeErr: ErrDeviceClosed})
+
+	err := m.Close()
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected: %v\nActual: %v", failure, err)
+	}
+	if len(closed) != 3 {
+		t.Fatalf("Expected all devices to be closed, got %v", closed)
+	}
+
+	err = m.Add(fakeDevice{name: "fourth", closed: &closed})
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrDeviceClosed, err)
+	}
+}
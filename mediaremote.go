@@ -0,0 +1,190 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the keys of media remotes
+var mediaKeys = []int{
+	KeyPlaypause, KeyPlaycd, KeyPausecd, KeyStopcd, KeyNextsong, KeyPrevioussong, KeyFastforward, KeyRewind,
+	KeyRecord, KeyEjectcd, KeyVolumeup, KeyVolumedown, KeyMute,
+}
+
+// A MediaRemote is a device with media keys, like a remote control or the media keys of a multimedia keyboard.
+// Desktops pass these keys to media players (e.g. via MPRIS) and use the volume keys to control the volume.
+type MediaRemote interface {
+	// PlayPause will press the play/pause key.
+	PlayPause() error
+
+	// Stop will press the stop key.
+	Stop() error
+
+	// Next will press the key that skips to the next track.
+	Next() error
+
+	// Previous will press the key that skips to the previous track.
+	Previous() error
+
+	// VolumeUp will press the volume up key the given number of times.
+	VolumeUp(steps int) error
+
+	// VolumeDown will press the volume down key the given number of times.
+	VolumeDown(steps int) error
+
+	// Mute will press the mute key.
+	Mute() error
+
+	// KeyPress will press and release any of the media keys of the remote (see the list of keys in mediaremote.go),
+	// like KeyFastforward or KeyEjectcd.
+	KeyPress(key int) error
+
+	Device
+}
+
+type vMediaRemote struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreateMediaRemote will create a new media remote device.
+func CreateMediaRemote(path string, name []byte, opts ...Option) (MediaRemote, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateMediaRemote", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createKeyDevice(path, name, mediaKeys, 0x081d, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vMediaRemote{name: name, deviceFile: fd}, nil
+}
+
+// PlayPause will press the play/pause key.
+func (vm vMediaRemote) PlayPause() error {
+	return pressKey(vm.deviceFile, KeyPlaypause)
+}
+
+// Stop will press the stop key.
+func (vm vMediaRemote) Stop() error {
+	return pressKey(vm.deviceFile, KeyStopcd)
+}
+
+// Next will press the next track key.
+func (vm vMediaRemote) Next() error {
+	return pressKey(vm.deviceFile, KeyNextsong)
+}
+
+// Previous will press the previous track key.
+func (vm vMediaRemote) Previous() error {
+	return pressKey(vm.deviceFile, KeyPrevioussong)
+}
+
+// VolumeUp will press the volume up key the given number of times.
+func (vm vMediaRemote) VolumeUp(steps int) error {
+	return vm.pressRepeatedly("VolumeUp", KeyVolumeup, steps)
+}
+
+// VolumeDown will press the volume down key the given number of times.
+func (vm vMediaRemote) VolumeDown(steps int) error {
+	return vm.pressRepeatedly("VolumeDown", KeyVolumedown, steps)
+}
+
+// Mute will press the mute key.
+func (vm vMediaRemote) Mute() error {
+	return pressKey(vm.deviceFile, KeyMute)
+}
+
+// KeyPress will press and release the given media key.
+func (vm vMediaRemote) KeyPress(key int) error {
+	if !codeSupported(mediaKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyPress. Code %d is not a media key", key)
+	}
+	return pressKey(vm.deviceFile, key)
+}
+
+func (vm vMediaRemote) pressRepeatedly(action string, key int, steps int) error {
+	if steps < 0 {
+		return errorf(ErrInvalidArgument, "failed to perform %s. %d is out of range. Expected a positive or zero number of steps", action, steps)
+	}
+	for i := 0; i < steps; i++ {
+		err := pressKey(vm.deviceFile, key)
+		if err != nil {
+			return fmt.Errorf("failed to perform %s: %w", action, err)
+		}
+	}
+	return nil
+}
+
+func (vm vMediaRemote) Name() string {
+	return string(vm.name)
+}
+
+func (vm vMediaRemote) Path() string {
+	return devicePath(vm.deviceFile)
+}
+
+func (vm vMediaRemote) Fd() uintptr {
+	return deviceFd(vm.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vm vMediaRemote) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vm.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vm vMediaRemote) EmitEvents(events []InputEvent) error {
+	return emitEvents(vm.deviceFile, events)
+}
+
+func (vm vMediaRemote) Sync() error {
+	return writeSyncEvent(vm.deviceFile)
+}
+
+func (vm vMediaRemote) SysPath() (string, error) {
+	return sysPath(vm.deviceFile)
+}
+
+func (vm vMediaRemote) EventPath() (string, error) {
+	return eventPath(vm.deviceFile)
+}
+
+func (vm vMediaRemote) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vm.deviceFile)
+}
+
+func (vm vMediaRemote) Stats() Stats {
+	return deviceStats(vm.deviceFile)
+}
+
+func (vm vMediaRemote) State() State {
+	return deviceState(vm.deviceFile)
+}
+
+func (vm vMediaRemote) ResetAll() error {
+	return resetDevice(vm.deviceFile)
+}
+
+func (vm vMediaRemote) Disconnect() error {
+	return disconnectDevice(vm.deviceFile)
+}
+
+func (vm vMediaRemote) Reconnect() error {
+	return reconnectDevice(vm.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vm vMediaRemote) Close() error {
+	return closeDevice(vm.deviceFile)
+}
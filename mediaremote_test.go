@@ -0,0 +1,71 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicMediaRemote(t *testing.T) {
+	remote, err := CreateMediaRemote("/dev/uinput", []byte("Test Media Remote"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual media remote. Last error was: %s\n", err)
+	}
+
+	err = remote.PlayPause()
+	if err != nil {
+		t.Fatalf("Failed to press play/pause. Last error was: %s\n", err)
+	}
+
+	err = remote.VolumeDown(2)
+	if err != nil {
+		t.Fatalf("Failed to lower the volume. Last error was: %s\n", err)
+	}
+
+	err = remote.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestMediaRemoteCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateMediaRemote("", []byte("MediaRemote"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestMediaRemoteVolumeSteps(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-mediaremote-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	remote := vMediaRemote{name: []byte("Test Media Remote"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	err = remote.VolumeUp(2)
+	if err != nil {
+		t.Fatalf("Failed to raise the volume: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyVolumeup, Value: btnStatePressed},
+		{Type: evKey, Code: KeyVolumeup, Value: btnStateReleased},
+		{Type: evKey, Code: KeyVolumeup, Value: btnStatePressed},
+		{Type: evKey, Code: KeyVolumeup, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestMediaRemoteRejectsInvalidArguments(t *testing.T) {
+	remote := vMediaRemote{name: []byte("Test Media Remote")}
+	if err := remote.VolumeUp(-1); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for negative steps, got %v", err)
+	}
+	if err := remote.KeyPress(KeyA); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a key that is not a media key, got %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"os"
+	"math"
+	"sync"
 	"syscall"
+	"time"
 )
 
 // A Mouse is a device that will trigger an absolute change event.
@@ -27,6 +29,15 @@ type Mouse interface {
 	// values will cause a move towards the upper left corner.
 	Move(x, y int32) error
 
+	// MoveSmooth will move the mouse pointer by x and y just like Move, but splits the movement into the given number
+	// of steps that are spread evenly across the duration. This way, the pointer glides along the path, so that hover
+	// effects are triggered on the way. If steps is zero, a step is performed every 8ms (the rate of a typical mouse).
+	MoveSmooth(x, y int32, duration time.Duration, steps int) error
+
+	// MoveSmoothContext will move the mouse pointer just like MoveSmooth, but stops once the context is done. The
+	// pointer then remains where it has been moved so far.
+	MoveSmoothContext(ctx context.Context, x, y int32, duration time.Duration, steps int) error
+
 	// LeftClick will issue a single left click.
 	LeftClick() error
 
@@ -47,21 +58,59 @@ type Mouse interface {
 	// RightRelease will simulate the release of the right mouse button.
 	RightRelease() error
 
+	// MiddleClick will issue a middle click.
+	MiddleClick() error
+
+	// MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
+	// MiddleRelease is invoked.
+	MiddlePress() error
+
+	// MiddleRelease will simulate the release of the middle mouse button.
+	MiddleRelease() error
+
+	// ButtonPressFor will press the given button (BtnLeft, BtnRight or BtnMiddle), hold it for the duration and
+	// release it again (see PressFor).
+	ButtonPressFor(button int, d time.Duration) error
+
+	// ButtonPressForContext will press the button just like ButtonPressFor, but releases it early once the context is
+	// done.
+	ButtonPressForContext(ctx context.Context, button int, d time.Duration) error
+
 	// Wheel will simulate a wheel movement.
 	Wheel(horizontal bool, delta int32) error
 
-	io.Closer
+	// Scroll will scroll by the given number of wheel notches, where positive values scroll up (vertical) and right
+	// (horizontal). Fractions of a notch are reported using the high-resolution wheel axes, which allows consumers
+	// like libinput to scroll smoothly. Fractions add up, so that a notch is reported once a whole notch has been
+	// scrolled in total.
+	Scroll(vertical, horizontal float64) error
+
+	Device
 }
 
 type vMouse struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *uinputDevice
+	wheel      *wheelState
+	human      *humanizer
+}
+
+// the number of high-resolution wheel units that make up a single notch of the wheel, as defined by the kernel
+const hiResNotch = 120
+
+// the largest distance that may be scrolled at once, as the high-resolution axes report it in 32 bit
+const maxScrollNotches = math.MaxInt32 / hiResNotch
+
+// wheelState keeps track of the high-resolution scroll distance that has not been reported as a whole notch yet.
+type wheelState struct {
+	mu        sync.Mutex
+	remainder [2]int32 // vertical, horizontal
 }
 
 // CreateMouse will create a new mouse input device. A mouse is a device that allows relative input.
 // Relative input means that all changes to the x and y coordinates of the mouse pointer will be
-func CreateMouse(path string, name []byte) (Mouse, error) {
-	err := validateDevicePath(path)
+func CreateMouse(path string, name []byte, opts ...Option) (Mouse, error) {
+	err := validateDevicePath(path, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,12 +119,16 @@ func CreateMouse(path string, name []byte) (Mouse, error) {
 		return nil, err
 	}
 
-	fd, err := createMouse(path, name)
+	cfg, err := applyOptions("CreateMouse", opts, optHumanizedMovement)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createMouse(path, name, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return vMouse{name: name, deviceFile: fd}, nil
+	return vMouse{name: name, deviceFile: fd, wheel: &wheelState{}, human: newHumanizer(cfg)}, nil
 }
 
 // MoveLeft will move the cursor left by the number of pixel specified.
@@ -113,21 +166,39 @@ func (vRel vMouse) MoveDown(pixel int32) error {
 // Move will perform a move of the mouse pointer along the x and y axes relative to the current position as requested.
 // Note that the upper left corner is (0, 0), so positive x and y means moving right (x) and down (y), whereas negative
 // values will cause a move towards the upper left corner.
+// Both axes are updated within a single frame, so that diagonal movements are not reported as two separate steps.
 func (vRel vMouse) Move(x, y int32) error {
-	if err := sendRelEvent(vRel.deviceFile, relX, x); err != nil {
-		return fmt.Errorf("Failed to move pointer along x axis: %v", err)
-	}
-	if err := sendRelEvent(vRel.deviceFile, relY, y); err != nil {
-		return fmt.Errorf("Failed to move pointer along y axis: %v", err)
+	err := sendEvents(vRel.deviceFile, []inputEvent{
+		{Type: evRel, Code: relX, Value: x},
+		{Type: evRel, Code: relY, Value: y},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to move pointer: %w", err)
 	}
 	return nil
 }
 
+// MoveSmooth will move the mouse pointer in timed steps. The call blocks until the movement is complete.
+func (vRel vMouse) MoveSmooth(x, y int32, duration time.Duration, steps int) error {
+	return vRel.MoveSmoothContext(context.Background(), x, y, duration, steps)
+}
+
+// MoveSmoothContext will move the mouse pointer in timed steps until the movement is complete or the context is done.
+func (vRel vMouse) MoveSmoothContext(ctx context.Context, x, y int32, duration time.Duration, steps int) error {
+	if steps < 0 {
+		return errorf(ErrInvalidArgument, "failed to perform MoveSmooth. %d is out of range. Expected a positive or zero number of steps", steps)
+	}
+	deltas := vRel.human.interpolate(x, y, moveSteps(duration, steps))
+	return moveTimed(ctx, duration, len(deltas), func(i int) error {
+		return vRel.Move(deltas[i][0], deltas[i][1])
+	})
+}
+
 // LeftClick will issue a LeftClick.
 func (vRel vMouse) LeftClick() error {
 	err := sendBtnEvent(vRel.deviceFile, []int{evBtnLeft}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("Failed to issue the LeftClick event: %w", err)
 	}
 
 	return sendBtnEvent(vRel.deviceFile, []int{evBtnLeft}, btnStateReleased)
@@ -137,7 +208,7 @@ func (vRel vMouse) LeftClick() error {
 func (vRel vMouse) RightClick() error {
 	err := sendBtnEvent(vRel.deviceFile, []int{evBtnRight}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("Failed to issue the RightClick event: %w", err)
 	}
 
 	return sendBtnEvent(vRel.deviceFile, []int{evBtnRight}, btnStateReleased)
@@ -165,13 +236,129 @@ func (vRel vMouse) RightRelease() error {
 	return sendBtnEvent(vRel.deviceFile, []int{evBtnRight}, btnStateReleased)
 }
 
-// Wheel will simulate a wheel movement.
+// MiddleClick will issue a MiddleClick.
+func (vRel vMouse) MiddleClick() error {
+	err := sendBtnEvent(vRel.deviceFile, []int{evBtnMiddle}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("Failed to issue the MiddleClick event: %w", err)
+	}
+
+	return sendBtnEvent(vRel.deviceFile, []int{evBtnMiddle}, btnStateReleased)
+}
+
+// MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
+// MiddleRelease is invoked.
+func (vRel vMouse) MiddlePress() error {
+	return sendBtnEvent(vRel.deviceFile, []int{evBtnMiddle}, btnStatePressed)
+}
+
+// MiddleRelease will simulate the release of the middle mouse button.
+func (vRel vMouse) MiddleRelease() error {
+	return sendBtnEvent(vRel.deviceFile, []int{evBtnMiddle}, btnStateReleased)
+}
+
+// ButtonPressFor will press the given button, hold it for the duration and release it again.
+func (vRel vMouse) ButtonPressFor(button int, d time.Duration) error {
+	return vRel.ButtonPressForContext(context.Background(), button, d)
+}
+
+// ButtonPressForContext will press the given button just like ButtonPressFor, but releases it early once the context
+// is done.
+func (vRel vMouse) ButtonPressForContext(ctx context.Context, button int, d time.Duration) error {
+	if button != evBtnLeft && button != evBtnRight && button != evBtnMiddle {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonPressFor. Code %d is not a mouse button", button)
+	}
+	return PressForContext(ctx, vRel, uint16(button), d)
+}
+
+// Wheel will simulate a wheel movement. The movement is reported on the high-resolution wheel axes as well, as
+// consumers that support them ignore the regular wheel axes.
 func (vRel vMouse) Wheel(horizontal bool, delta int32) error {
-	w := relWheel
+	if delta > maxScrollNotches || delta < -maxScrollNotches {
+		return errorf(ErrInvalidArgument, "failed to perform Wheel. %d is out of range. Expected a number of notches up to %d", delta, maxScrollNotches)
+	}
+	w, hiRes := uint16(relWheel), uint16(RelWheelHiRes)
 	if horizontal {
-		w = relHWheel
+		w, hiRes = relHWheel, RelHWheelHiRes
+	}
+	return sendEvents(vRel.deviceFile, []inputEvent{
+		{Type: evRel, Code: w, Value: delta},
+		{Type: evRel, Code: hiRes, Value: delta * hiResNotch},
+	})
+}
+
+// Scroll will scroll by the given number of wheel notches. Both axes are updated within a single frame.
+func (vRel vMouse) Scroll(vertical, horizontal float64) error {
+	events, err := vRel.wheel.scroll(vertical, horizontal)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %w", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	err = sendEvents(vRel.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %w", err)
 	}
-	return sendRelEvent(vRel.deviceFile, uint16(w), delta)
+	return nil
+}
+
+func (vRel vMouse) Name() string {
+	return string(vRel.name)
+}
+
+func (vRel vMouse) Path() string {
+	return devicePath(vRel.deviceFile)
+}
+
+func (vRel vMouse) Fd() uintptr {
+	return deviceFd(vRel.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vRel vMouse) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vRel.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vRel vMouse) EmitEvents(events []InputEvent) error {
+	return emitEvents(vRel.deviceFile, events)
+}
+
+func (vRel vMouse) Sync() error {
+	return writeSyncEvent(vRel.deviceFile)
+}
+
+func (vRel vMouse) SysPath() (string, error) {
+	return sysPath(vRel.deviceFile)
+}
+
+func (vRel vMouse) EventPath() (string, error) {
+	return eventPath(vRel.deviceFile)
+}
+
+func (vRel vMouse) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vRel.deviceFile)
+}
+
+func (vRel vMouse) Stats() Stats {
+	return deviceStats(vRel.deviceFile)
+}
+
+func (vRel vMouse) State() State {
+	return deviceState(vRel.deviceFile)
+}
+
+func (vRel vMouse) ResetAll() error {
+	return resetDevice(vRel.deviceFile)
+}
+
+func (vRel vMouse) Disconnect() error {
+	return disconnectDevice(vRel.deviceFile)
+}
+
+func (vRel vMouse) Reconnect() error {
+	return reconnectDevice(vRel.deviceFile)
 }
 
 // Close closes the device and releases the device.
@@ -179,75 +366,108 @@ func (vRel vMouse) Close() error {
 	return closeDevice(vRel.deviceFile)
 }
 
-func createMouse(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createMouse(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not create relative axis input device: %v", err)
+		return nil, fmt.Errorf("could not create relative axis input device: %w", err)
 	}
 
-	err = registerDevice(deviceFile, uintptr(evKey))
+	err = registerMouse(deviceFile)
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		return nil, err
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0816,
+				Version: 1}},
+		cfg)
+}
+
+// registerMouse registers the buttons, axes and wheels of a mouse on the device file.
+func registerMouse(deviceFile DeviceFile) error {
+	err := registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		return fmt.Errorf("failed to register key device: %w", err)
 	}
 
-	// register button events (in order to enable left and right click)
-	for _, event := range []int{evBtnLeft, evBtnRight} {
+	// register button events (in order to enable left, right and middle click)
+	for _, event := range []int{evBtnLeft, evBtnRight, evBtnMiddle} {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
-			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register click event %v: %v", event, err)
+			return fmt.Errorf("failed to register click event %v: %w", event, err)
 		}
 	}
 
 	err = registerDevice(deviceFile, uintptr(evRel))
 	if err != nil {
-		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register relative axis input device: %v", err)
+		return fmt.Errorf("failed to register relative axis input device: %w", err)
 	}
 
 	// register relative events
-	for _, event := range []int{relX, relY, relWheel, relHWheel} {
+	for _, event := range []int{relX, relY, relWheel, relHWheel, RelWheelHiRes, RelHWheelHiRes} {
 		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
 		if err != nil {
-			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register relative event %v: %v", event, err)
+			return fmt.Errorf("failed to register relative event %v: %w", event, err)
 		}
 	}
-
-	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  0x4711,
-				Product: 0x0816,
-				Version: 1}})
+	return nil
 }
 
-func sendRelEvent(deviceFile *os.File, eventCode uint16, pixel int32) error {
+func sendRelEvent(deviceFile *uinputDevice, eventCode uint16, pixel int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
 		Code:  eventCode,
 		Value: pixel}
 
-	buf, err := inputEventToBuffer(iev)
-	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
-	}
+	return sendEvents(deviceFile, []inputEvent{iev})
+}
 
-	_, err = deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
+// scroll returns the events that report the given scroll distance (in notches). The high-resolution axes report the
+// distance as is, while the regular axes only report the whole notches that have been scrolled in total. Distances
+// that are not finite (or too large to be reported) are rejected, as they would corrupt the fractions that are kept.
+func (w *wheelState) scroll(vertical, horizontal float64) ([]inputEvent, error) {
+	for _, notches := range []float64{vertical, horizontal} {
+		if math.IsNaN(notches) || math.IsInf(notches, 0) || math.Abs(notches) > maxScrollNotches {
+			return nil, errorf(ErrInvalidArgument, "%v is out of range. Expected a finite number of notches up to %d", notches, maxScrollNotches)
+		}
 	}
-
-	return syncEvents(deviceFile)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []inputEvent
+	for i, axis := range []struct {
+		notches float64
+		code    uint16
+		hiRes   uint16
+	}{
+		{vertical, relWheel, RelWheelHiRes},
+		{horizontal, relHWheel, RelHWheelHiRes},
+	} {
+		hiRes := int32(math.Round(axis.notches * hiResNotch))
+		if hiRes == 0 {
+			continue
+		}
+		w.remainder[i] += hiRes
+		notches := w.remainder[i] / hiResNotch
+		w.remainder[i] -= notches * hiResNotch
+		if notches != 0 {
+			events = append(events, inputEvent{Type: evRel, Code: axis.code, Value: notches})
+		}
+		events = append(events, inputEvent{Type: evRel, Code: axis.hiRes, Value: hiRes})
+	}
+	return events, nil
 }
 
 func assertNotNegative(val int32) error {
 	if val < 0 {
-		return fmt.Errorf("%v is out of range. Expected a positive or zero value", val)
+		return errorf(ErrInvalidArgument, "%v is out of range. Expected a positive or zero value", val)
 	}
 	return nil
 }
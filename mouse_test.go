@@ -1,10 +1,14 @@
 package uinput
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // This test confirms that all basic mouse events are working as expected.
@@ -74,6 +78,21 @@ func TestBasicMouseMoves(t *testing.T) {
 		t.Fatalf("Failed to perform right key release. Last error was: %s\n", err)
 	}
 
+	err = relDev.MiddleClick()
+	if err != nil {
+		t.Fatalf("Failed to perform middle click. Last error was: %s\n", err)
+	}
+
+	err = relDev.MiddlePress()
+	if err != nil {
+		t.Fatalf("Failed to perform middle key press. Last error was: %s\n", err)
+	}
+
+	err = relDev.MiddleRelease()
+	if err != nil {
+		t.Fatalf("Failed to perform middle key release. Last error was: %s\n", err)
+	}
+
 	err = relDev.Wheel(false, 1)
 	if err != nil {
 		t.Fatalf("Failed to perform wheel movement. Last error was: %s\n", err)
@@ -84,18 +103,20 @@ func TestBasicMouseMoves(t *testing.T) {
 		t.Fatalf("Failed to perform horizontal wheel movement. Last error was: %s\n", err)
 	}
 
+	err = relDev.Scroll(0.5, -0.5)
+	if err != nil {
+		t.Fatalf("Failed to perform smooth scroll. Last error was: %s\n", err)
+	}
+
 	err = relDev.Close()
 	if err != nil {
 		t.Fatalf("Failed to close device. Last error was: %s\n", err)
 	}
 }
 
-func TestMouseCreationFailsOnEmptyPath(t *testing.T) {
-	expected := "device path must not be empty"
-	_, err := CreateMouse("", []byte("MouseDevice"))
-	if err.Error() != expected {
-		t.Fatalf("Expected: %s\nActual: %s", expected, err)
-	}
+func TestMouseCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateMouse("", []byte("MouseDevice"))
+	assertPathDiscovered(t, device, err)
 }
 
 func TestMouseCreationFailsOnNonExistentPathName(t *testing.T) {
@@ -207,6 +228,45 @@ func TestVMouse_RightReleaseFailsIfDeviceIsClosed(t *testing.T) {
 	}
 }
 
+func TestMouseMiddleClickFailsIfDeviceIsClosed(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Basic Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	relDev.Close()
+
+	err = relDev.MiddleClick()
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestMouseMiddlePressFailsIfDeviceIsClosed(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Basic Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	relDev.Close()
+
+	err = relDev.MiddlePress()
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestMouseMiddleReleaseFailsIfDeviceIsClosed(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Basic Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	relDev.Close()
+
+	err = relDev.MiddleRelease()
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
 func TestMouseMoveUpFailsIfDeviceIsClosed(t *testing.T) {
 	relDev, err := CreateMouse("/dev/uinput", []byte("Test Basic Mouse"))
 	if err != nil {
@@ -328,3 +388,168 @@ func TestMouseWheelFailsIfDeviceIsClosed(t *testing.T) {
 		t.Fatalf("Expected error due to closed device, but no error was returned.")
 	}
 }
+
+func TestMouseWithManualSync(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Basic Mouse"), WithManualSync())
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	err = relDev.MoveRight(10)
+	if err != nil {
+		t.Fatalf("Failed to move mouse right. Last error was: %s\n", err)
+	}
+	err = relDev.LeftPress()
+	if err != nil {
+		t.Fatalf("Failed to perform left key press. Last error was: %s\n", err)
+	}
+	err = relDev.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync events. Last error was: %s\n", err)
+	}
+}
+
+func TestScrollReportsFractionsOnHighResolutionAxes(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-scroll-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	relDev := vMouse{name: []byte("Test Scroll Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), wheel: &wheelState{}}
+	for _, scroll := range [][2]float64{{0.5, 0}, {0.5, -1.25}, {2, 0.25}} {
+		err = relDev.Scroll(scroll[0], scroll[1])
+		if err != nil {
+			t.Fatalf("Failed to scroll: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: RelWheelHiRes, Value: 60},
+		{Type: evRel, Code: relWheel, Value: 1},
+		{Type: evRel, Code: RelWheelHiRes, Value: 60},
+		{Type: evRel, Code: relHWheel, Value: -1},
+		{Type: evRel, Code: RelHWheelHiRes, Value: -150},
+		{Type: evRel, Code: relWheel, Value: 2},
+		{Type: evRel, Code: RelWheelHiRes, Value: 240},
+		{Type: evRel, Code: RelHWheelHiRes, Value: 30},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestWheelRejectsDistancesOutOfRange(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-wheel-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	relDev := vMouse{name: []byte("Test Wheel Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), wheel: &wheelState{}}
+	for _, delta := range []int32{math.MaxInt32/hiResNotch + 1, -(math.MaxInt32/hiResNotch + 1), math.MinInt32} {
+		err = relDev.Wheel(false, delta)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected a wheel movement by %d to fail with ErrInvalidArgument, got %v", delta, err)
+		}
+	}
+	err = relDev.Wheel(true, -maxScrollNotches)
+	if err != nil {
+		t.Fatalf("Failed to move the wheel: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: relHWheel, Value: -maxScrollNotches},
+		{Type: evRel, Code: RelHWheelHiRes, Value: -maxScrollNotches * hiResNotch},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestScrollRejectsNonFiniteDistances(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-scroll-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	relDev := vMouse{name: []byte("Test Scroll Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), wheel: &wheelState{}}
+	for _, scroll := range [][2]float64{{math.NaN(), 0}, {0, math.Inf(1)}, {math.Inf(-1), 0}, {1e12, 0}} {
+		err = relDev.Scroll(scroll[0], scroll[1])
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected scrolling by %v to fail with ErrInvalidArgument, got %v", scroll, err)
+		}
+	}
+	// the rejected distances must not affect later scrolling
+	for i := 0; i < 2; i++ {
+		err = relDev.Scroll(0.5, 0)
+		if err != nil {
+			t.Fatalf("Failed to scroll: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: RelWheelHiRes, Value: 60},
+		{Type: evRel, Code: relWheel, Value: 1},
+		{Type: evRel, Code: RelWheelHiRes, Value: 60},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestWheelReportsHighResolutionAxes(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-wheel-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	relDev := vMouse{name: []byte("Test Wheel Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), wheel: &wheelState{}}
+	err = relDev.Wheel(true, -2)
+	if err != nil {
+		t.Fatalf("Failed to perform wheel movement: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: relHWheel, Value: -2},
+		{Type: evRel, Code: RelHWheelHiRes, Value: -240},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestMoveSmoothReachesTarget(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-smooth-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	relDev := vMouse{name: []byte("Test Smooth Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), wheel: &wheelState{}}
+	err = relDev.MoveSmooth(50, -20, 10*time.Millisecond, 5)
+	if err != nil {
+		t.Fatalf("Failed to move smoothly: %v", err)
+	}
+
+	var x, y int32
+	events := readEvents(t, file.Name(), evRel)
+	for _, iev := range events {
+		if iev.Code == relX {
+			x += iev.Value
+		} else {
+			y += iev.Value
+		}
+	}
+	if len(events) != 10 || x != 50 || y != -20 {
+		t.Fatalf("Expected 5 steps moving by (50, -20), got %d events moving by (%d, %d)", len(events), x, y)
+	}
+}
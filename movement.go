@@ -0,0 +1,77 @@
+package uinput
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultStepInterval is the interval of the steps of smooth movements, unless the number of steps is given
+// explicitly. It matches the polling rate of a typical USB mouse (125 Hz).
+const defaultStepInterval = 8 * time.Millisecond
+
+// moveSteps returns the number of steps of a smooth movement that takes the given duration. If steps is positive, it
+// is used as is.
+func moveSteps(duration time.Duration, steps int) int {
+	if steps > 0 {
+		return steps
+	}
+	steps = int(duration / defaultStepInterval)
+	if steps < 1 {
+		return 1
+	}
+	return steps
+}
+
+// interpolate splits the movement by dx and dy into the given number of steps and returns the relative movement of
+// each step. Rounding errors are carried over to the next step, so that the steps add up to the whole movement.
+func interpolate(dx, dy int32, steps int) [][2]int32 {
+	deltas := make([][2]int32, steps)
+	var prevX, prevY int32
+	for i := range deltas {
+		x := int32(int64(dx) * int64(i+1) / int64(steps))
+		y := int32(int64(dy) * int64(i+1) / int64(steps))
+		deltas[i] = [2]int32{x - prevX, y - prevY}
+		prevX, prevY = x, y
+	}
+	return deltas
+}
+
+// moveTimed performs the given number of steps evenly spread across the duration, starting with the first step after
+// a single interval. The deadlines of the steps are computed upfront, so that slow writes do not add up. Once the
+// context is done, the remaining steps are skipped and the error of the context is returned.
+func moveTimed(ctx context.Context, duration time.Duration, steps int, step func(i int) error) error {
+	start := time.Now()
+	interval := duration / time.Duration(steps)
+	for i := 0; i < steps; i++ {
+		err := sleepUntil(ctx, start.Add(interval*time.Duration(i+1)))
+		if err != nil {
+			return err
+		}
+		err = step(i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pointerPosition keeps track of the last position of an absolute pointer, so that smooth movements know where to
+// start from.
+type pointerPosition struct {
+	mu    sync.Mutex
+	x, y  int32
+	known bool
+}
+
+func (p *pointerPosition) set(x, y int32) {
+	p.mu.Lock()
+	p.x, p.y, p.known = x, y, true
+	p.mu.Unlock()
+}
+
+func (p *pointerPosition) get() (x, y int32, known bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.x, p.y, p.known
+}
@@ -0,0 +1,78 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInterpolatedStepsAddUpToMovement(t *testing.T) {
+	for _, tc := range []struct {
+		dx, dy int32
+		steps  int
+	}{
+		{100, 0, 3},
+		{-7, 13, 4},
+		{1, -1, 10},
+		{0, 0, 1},
+	} {
+		deltas := interpolate(tc.dx, tc.dy, tc.steps)
+		if len(deltas) != tc.steps {
+			t.Fatalf("Expected %d steps, got %d", tc.steps, len(deltas))
+		}
+		var x, y int32
+		for _, d := range deltas {
+			x, y = x+d[0], y+d[1]
+		}
+		if x != tc.dx || y != tc.dy {
+			t.Fatalf("Expected the steps to add up to (%d, %d), got (%d, %d)", tc.dx, tc.dy, x, y)
+		}
+	}
+}
+
+func TestMoveStepsDefaultsToPollingRate(t *testing.T) {
+	if steps := moveSteps(80*time.Millisecond, 0); steps != 10 {
+		t.Fatalf("Expected 10 steps, got %d", steps)
+	}
+	if steps := moveSteps(0, 0); steps != 1 {
+		t.Fatalf("Expected a single step for an instant movement, got %d", steps)
+	}
+	if steps := moveSteps(time.Second, 3); steps != 3 {
+		t.Fatalf("Expected the given number of steps to be used, got %d", steps)
+	}
+}
+
+func TestMoveTimedSpreadsStepsAcrossDuration(t *testing.T) {
+	var steps []int
+	start := time.Now()
+	err := moveTimed(context.Background(), 40*time.Millisecond, 4, func(i int) error {
+		steps = append(steps, i)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("Expected the movement to take at least 40ms, took %v", elapsed)
+	}
+	if len(steps) != 4 || steps[3] != 3 {
+		t.Fatalf("Expected 4 steps in order, got %v", steps)
+	}
+}
+
+func TestMoveTimedStopsOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var steps []int
+	err := moveTimed(ctx, 40*time.Millisecond, 4, func(i int) error {
+		steps = append(steps, i)
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected: %v\nActual: %v", context.Canceled, err)
+	}
+	if len(steps) != 1 {
+		t.Fatalf("Expected a single step before the cancellation, got %v", steps)
+	}
+}
@@ -0,0 +1,174 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the keys of numeric keypads. Num lock is provided as well, since consumers only interpret the keypad keys as digits
+// while num lock is active.
+var numpadKeys = []int{
+	KeyKp0, KeyKp1, KeyKp2, KeyKp3, KeyKp4, KeyKp5, KeyKp6, KeyKp7, KeyKp8, KeyKp9, KeyKpdot, KeyKpenter, KeyKpplus,
+	KeyKpminus, KeyKpasterisk, KeyKpslash, KeyNumlock,
+}
+
+// numpadRunes are the characters that may be typed on numeric keypads
+var numpadRunes = map[rune]int{
+	'0': KeyKp0, '1': KeyKp1, '2': KeyKp2, '3': KeyKp3, '4': KeyKp4, '5': KeyKp5, '6': KeyKp6, '7': KeyKp7,
+	'8': KeyKp8, '9': KeyKp9, '.': KeyKpdot, '\n': KeyKpenter, '+': KeyKpplus, '-': KeyKpminus, '*': KeyKpasterisk,
+	'/': KeyKpslash,
+}
+
+// A Numpad is a standalone numeric keypad, which provides the keypad keys only. This allows to test software that
+// distinguishes keypad input from the digits of the main row, like point-of-sale or kiosk software.
+type Numpad interface {
+	// Type will type the given text using the keypad keys. The text may consist of digits, '.', '+', '-', '*', '/'
+	// and '\n' (which is typed using the enter key of the keypad).
+	Type(text string) error
+
+	// KeyPress will press and release the given keypad key (see the list of keys in numpad.go), like KeyKp5.
+	KeyPress(key int) error
+
+	// KeyDown will press the given keypad key. Note that the key remains pressed until KeyUp is called.
+	KeyDown(key int) error
+
+	// KeyUp will release the given keypad key.
+	KeyUp(key int) error
+
+	Device
+}
+
+type vNumpad struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreateNumpad will create a new numeric keypad device.
+func CreateNumpad(path string, name []byte, opts ...Option) (Numpad, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateNumpad", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createKeyDevice(path, name, numpadKeys, 0x081f, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vNumpad{name: name, deviceFile: fd}, nil
+}
+
+// Type will type the given text using the keypad keys. The text is validated upfront, so that nothing is typed if
+// it contains characters that are not available on the keypad.
+func (vn vNumpad) Type(text string) error {
+	keys := make([]int, 0, len(text))
+	for _, r := range text {
+		key, ok := numpadRunes[r]
+		if !ok {
+			return errorf(ErrInvalidArgument, "failed to perform Type. %q is not available on a numeric keypad", r)
+		}
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		err := pressKey(vn.deviceFile, key)
+		if err != nil {
+			return fmt.Errorf("failed to perform Type: %w", err)
+		}
+	}
+	return nil
+}
+
+// KeyPress will press and release the given keypad key.
+func (vn vNumpad) KeyPress(key int) error {
+	if !codeSupported(numpadKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyPress. Code %d is not a keypad key", key)
+	}
+	return pressKey(vn.deviceFile, key)
+}
+
+// KeyDown will press the given keypad key.
+func (vn vNumpad) KeyDown(key int) error {
+	if !codeSupported(numpadKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyDown. Code %d is not a keypad key", key)
+	}
+	return sendBtnEvent(vn.deviceFile, []int{key}, btnStatePressed)
+}
+
+// KeyUp will release the given keypad key.
+func (vn vNumpad) KeyUp(key int) error {
+	if !codeSupported(numpadKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyUp. Code %d is not a keypad key", key)
+	}
+	return sendBtnEvent(vn.deviceFile, []int{key}, btnStateReleased)
+}
+
+func (vn vNumpad) Name() string {
+	return string(vn.name)
+}
+
+func (vn vNumpad) Path() string {
+	return devicePath(vn.deviceFile)
+}
+
+func (vn vNumpad) Fd() uintptr {
+	return deviceFd(vn.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vn vNumpad) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vn.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vn vNumpad) EmitEvents(events []InputEvent) error {
+	return emitEvents(vn.deviceFile, events)
+}
+
+func (vn vNumpad) Sync() error {
+	return writeSyncEvent(vn.deviceFile)
+}
+
+func (vn vNumpad) SysPath() (string, error) {
+	return sysPath(vn.deviceFile)
+}
+
+func (vn vNumpad) EventPath() (string, error) {
+	return eventPath(vn.deviceFile)
+}
+
+func (vn vNumpad) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vn.deviceFile)
+}
+
+func (vn vNumpad) Stats() Stats {
+	return deviceStats(vn.deviceFile)
+}
+
+func (vn vNumpad) State() State {
+	return deviceState(vn.deviceFile)
+}
+
+func (vn vNumpad) ResetAll() error {
+	return resetDevice(vn.deviceFile)
+}
+
+func (vn vNumpad) Disconnect() error {
+	return disconnectDevice(vn.deviceFile)
+}
+
+func (vn vNumpad) Reconnect() error {
+	return reconnectDevice(vn.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vn vNumpad) Close() error {
+	return closeDevice(vn.deviceFile)
+}
@@ -0,0 +1,69 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicNumpad(t *testing.T) {
+	numpad, err := CreateNumpad("/dev/uinput", []byte("Test Numpad"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual numpad. Last error was: %s\n", err)
+	}
+
+	err = numpad.Type("12.5\n")
+	if err != nil {
+		t.Fatalf("Failed to type on the numpad. Last error was: %s\n", err)
+	}
+
+	err = numpad.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestNumpadCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateNumpad("", []byte("Numpad"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestNumpadTypesKeypadKeys(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-numpad-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	numpad := vNumpad{name: []byte("Test Numpad"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	err = numpad.Type("7*\n")
+	if err != nil {
+		t.Fatalf("Failed to type on the numpad: %v", err)
+	}
+	err = numpad.Type("1a")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a character that is not on the keypad, got: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyKp7, Value: btnStatePressed},
+		{Type: evKey, Code: KeyKp7, Value: btnStateReleased},
+		{Type: evKey, Code: KeyKpasterisk, Value: btnStatePressed},
+		{Type: evKey, Code: KeyKpasterisk, Value: btnStateReleased},
+		{Type: evKey, Code: KeyKpenter, Value: btnStatePressed},
+		{Type: evKey, Code: KeyKpenter, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestNumpadRejectsMainRowKeys(t *testing.T) {
+	numpad := vNumpad{name: []byte("Test Numpad")}
+	if err := numpad.KeyPress(Key1); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a key of the main row, got %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package uinput
+
+import (
+	"time"
+)
+
+// the bus types that may be reported by a device, as defined in input.h
+const (
+	BusUSB       = busUsb
+	BusBluetooth = 0x05
+	BusVirtual   = 0x06
+)
+
+// the device properties that may be set using WithProperties, as defined in input-event-codes.h. Properties are used
+// by consumers like libinput to classify devices.
+const (
+	PropPointer       = inputPropPointer       // device needs a pointer (e.g. touchpads)
+	PropDirect        = inputPropDirect        // direct input devices (e.g. touch screens)
+	PropButtonpad     = inputPropButtonpad     // the touchpad has its button(s) under the surface
+	PropSemiMT        = inputPropSemiMT        // the touch rectangle only (no real multitouch)
+	PropTopButtonpad  = inputPropTopButtonpad  // software buttons at the top of the pad
+	PropPointingStick = inputPropPointingStick // pointing stick (e.g. trackpoint)
+	PropAccelerometer = inputPropAccelerometer // the device is an accelerometer
+)
+
+// An Option configures optional properties of a device upon creation. Options may be passed to all Create* functions,
+// except for the options that only apply to some devices (like WithLayout for keyboards), which are rejected with an
+// error wrapping ErrInvalidArgument by the other Create* functions. Properties that are not configured explicitly keep
+// the defaults of the respective device.
+type Option func(*deviceConfig)
+
+// the names of the options that only apply to some devices
+const (
+	optName                = "WithName"
+	optKeys                = "WithKeys"
+	optAxis                = "WithAxis"
+	optFF                  = "WithFF"
+	optLayout              = "WithLayout"
+	optKeyRepeat           = "WithKeyRepeat"
+	optStickyKeys          = "WithStickyKeys"
+	optDpadButtons         = "WithDpadButtons"
+	optMultitouchProtocolA = "WithMultitouchProtocolA"
+	optHumanizedMovement   = "WithHumanizedMovement"
+)
+
+type deviceConfig struct {
+	bustype *uint16
+	vendor  *uint16
+	product *uint16
+	version *uint16
+	phys    string
+	uniq    string
+	tunings map[uint16]AxisTuning
+	props   []uint16
+	relAxes []uint16
+
+	manualSync   bool
+	rangePolicy  AxisRangePolicy
+	clock        func() time.Time
+	rateInterval time.Duration
+	retry        *writeRetry
+	hooks        Hooks
+	recreate     bool
+	dwell        *dwellClick
+	layout       Layout
+	keyRepeat    *keyRepeat
+	stickyKeys   bool
+	mtProtocolA  bool
+	dpadButtons  bool
+	humanize     bool
+	dedup        bool
+
+	// the capabilities of devices created using CreateDevice
+	name    []byte
+	keys    []uint16
+	absAxes []absAxis
+	ff      *ForceFeedback
+
+	handlers  eventHandlers
+	readCodes map[uint16][]uint16
+	recorder  *Recorder
+	backend   Backend
+
+	specific []string // the options that only apply to some devices, which have been given (see applyOptions)
+}
+
+// An AxisRangePolicy determines how values of absolute axes that exceed the range of the axis are treated (see
+// WithAxisRangePolicy). Consumers are likely to misbehave when they receive such values.
+type AxisRangePolicy int
+
+// the policies for values of absolute axes that exceed the range of the axis
+const (
+	// AxisRangeIgnore passes all values on as is, which is the default.
+	AxisRangeIgnore AxisRangePolicy = iota
+	// AxisRangeClamp clamps values to the range of the axis.
+	AxisRangeClamp
+	// AxisRangeReject rejects frames that contain values outside the range with an error wrapping ErrInvalidArgument.
+	// None of the events of the frame are written in that case.
+	AxisRangeReject
+)
+
+// the retries of writes that fail with a transient error, unless configured otherwise using WithWriteRetry
+const (
+	defaultWriteRetries    = 3
+	defaultWriteRetryDelay = time.Millisecond
+)
+
+type writeRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+// Hooks are called by a device for all of its writes, which allows applications to log, count or react to events
+// and failures in a central place (see WithHooks). Hooks are called while the device is locked for writing, so they
+// must not call any methods of the device and should return quickly. Hooks that are nil are skipped.
+type Hooks struct {
+	// OnEvent is called for every event that has been written to the device, including the sync events that
+	// terminate frames. The event carries a timestamp if the device has stamped it (see WithClock).
+	OnEvent func(event InputEvent)
+	// OnWriteError is called for every write that failed, after transient errors have been retried (see
+	// WithWriteRetry). The error is returned to the caller of the device method as well.
+	OnWriteError func(err error)
+	// OnRecreate is called once the device has been recreated after its device file failed with the given error (see
+	// WithAutoRecreate).
+	OnRecreate func(cause error)
+}
+
+type keyRepeat struct {
+	delay  time.Duration
+	period time.Duration
+}
+
+// AxisTuning describes how consumers should treat an absolute axis. Values within Fuzz of the previous value are
+// treated as noise and values within Flat of the center are treated as the dead zone of the axis. Joystick consumers,
+// for example, usually apply their dead zones based on Flat. Resolution is given in units per millimeter (units per
+// g or per degree per second for motion sensors). Note that the resolution can only be reported on kernels that
+// support UI_ABS_SETUP (4.5 and later), it is ignored on older kernels.
+type AxisTuning struct {
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// WithBusType sets the bus type (e.g. BusUSB or BusBluetooth) reported by the device.
+func WithBusType(bustype uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.bustype = &bustype
+	}
+}
+
+// WithVendor sets the vendor id reported by the device. Many applications (like SDL based games) identify devices by
+// their vendor and product ids.
+func WithVendor(vendor uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.vendor = &vendor
+	}
+}
+
+// WithProduct sets the product id reported by the device.
+func WithProduct(product uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.product = &product
+	}
+}
+
+// WithVersion sets the version reported by the device.
+func WithVersion(version uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.version = &version
+	}
+}
+
+// WithPhys sets the physical path of the device (e.g. "usb-0000:00:14.0-1/input0"), which is usually used to
+// describe the physical location of the device.
+func WithPhys(phys string) Option {
+	return func(cfg *deviceConfig) {
+		cfg.phys = phys
+	}
+}
+
+// WithUniq sets the unique identifier of the device (usually a serial number). Applications like Steam use it to
+// remember per-device settings, which is why it is a good idea to use a distinct value per virtual device.
+func WithUniq(uniq string) Option {
+	return func(cfg *deviceConfig) {
+		cfg.uniq = uniq
+	}
+}
+
+// WithAxisTuning sets the fuzz, flat and resolution values of the absolute axis with the given code. The defaults of the device
+// (e.g. those of the Xbox 360 sticks for the gamepad) are overridden for this axis.
+func WithAxisTuning(code uint16, tuning AxisTuning) Option {
+	return func(cfg *deviceConfig) {
+		if cfg.tunings == nil {
+			cfg.tunings = make(map[uint16]AxisTuning)
+		}
+		cfg.tunings[code] = tuning
+	}
+}
+
+// WithProperties sets the given properties (see the Prop constants) on the device, in addition to the properties the
+// device sets by default.
+func WithProperties(props ...uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.props = append(cfg.props, props...)
+	}
+}
+
+// WithRelativeAxes registers the given relative axes (see the Rel constants) in addition to the axes the device
+// provides by default. This allows to mix absolute and relative axes, like on spinners or throttles with encoders.
+// On gamepads, these axes may be moved using MoveAxis.
+func WithRelativeAxes(codes ...uint16) Option {
+	return func(cfg *deviceConfig) {
+		cfg.relAxes = append(cfg.relAxes, codes...)
+	}
+}
+
+// WithManualSync disables the sync event that is otherwise issued after every call to a device method. Events are
+// then only reported to consumers once Sync is called, which allows to compose a frame of several axis and button
+// changes. Note that presses (like KeyPress or LeftClick) end up in a single frame as well, unless Sync is called
+// in between pushing down and releasing the button.
+func WithManualSync() Option {
+	return func(cfg *deviceConfig) {
+		cfg.manualSync = true
+	}
+}
+
+// WithAxisRangePolicy sets the policy for values of absolute axes that exceed the range the axis has been created with
+// (see AxisRangePolicy). The policy applies to all events of the device, including raw events. It does not apply to
+// axes without a range, like the multitouch axes of touch pads, which are not checked.
+func WithAxisRangePolicy(policy AxisRangePolicy) Option {
+	return func(cfg *deviceConfig) {
+		cfg.rangePolicy = policy
+	}
+}
+
+// WithClock stamps all events of the device with the time returned by clock, which is called once per frame. By
+// default, events carry no timestamp, which causes the kernel to stamp them upon receipt. Consumers that compute
+// velocities from timestamps (like libinput) see the timing of the caller instead, which is useful for replaying or
+// generating events ahead of time. Explicit timestamps of raw events (see InputEvent) take precedence. Note that only
+// recent kernels (6.3 and later) honor the timestamps of uinput events, which are expected to be CLOCK_MONOTONIC
+// times. Older kernels stamp events upon receipt.
+func WithClock(clock func() time.Time) Option {
+	return func(cfg *deviceConfig) {
+		cfg.clock = clock
+	}
+}
+
+// WithMonotonicClock stamps all events of the device with the current CLOCK_MONOTONIC time (see MonotonicClock and
+// WithClock). This is the clock the kernel bases event timestamps on, which are then converted to the clock consumers
+// have selected using EVIOCSCLOCKID. Velocities that consumers like libinput compute for gestures are based on the
+// time the events have been generated rather than received then.
+func WithMonotonicClock() Option {
+	return WithClock(MonotonicClock)
+}
+
+// WithRateLimit limits the rate of the frames a device writes, so that high-frequency producers (like telemetry
+// streams) do not flood slow consumers. Consecutive frames are at least the given interval apart, e.g. time.Second/60
+// for at most 60 frames per second. Frames that only move axes and arrive too early are coalesced into a single frame,
+// which is written once the interval has passed: absolute axes report their latest value, while movements of relative
+// axes add up. Frames with other events (like presses) block the caller until the interval has passed, but not other
+// users of the device (like Close). Errors of coalesced frames are returned by the next frame. The limit does not apply
+// to devices created using WithManualSync.
+func WithRateLimit(interval time.Duration) Option {
+	return func(cfg *deviceConfig) {
+		cfg.rateInterval = interval
+	}
+}
+
+// WithWriteRetry sets how often writes that fail with a transient error (EINTR or EAGAIN) are retried before the error
+// is returned to the caller. The delay before the first retry is doubled for every further retry. By default, writes
+// are retried up to 3 times, starting with a delay of 1ms. Zero attempts disable retries.
+func WithWriteRetry(attempts int, delay time.Duration) Option {
+	return func(cfg *deviceConfig) {
+		cfg.retry = &writeRetry{attempts: attempts, delay: delay}
+	}
+}
+
+// WithHooks sets the hooks that are called for all writes of the device (see Hooks).
+func WithHooks(hooks Hooks) Option {
+	return func(cfg *deviceConfig) {
+		cfg.hooks = hooks
+	}
+}
+
+// WithLayout sets the layout that is used by keyboards for typing (see Keyboard.Type). The layout needs to match the
+// layout that is configured in the session that consumes the events. LayoutUS is used by default, while LayoutDE,
+// LayoutFR and LayoutUK are predefined as well. Custom layouts may be defined using KeyMap.
+func WithLayout(layout Layout) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optLayout)
+		cfg.layout = layout
+	}
+}
+
+// WithKeyRepeat enables the autorepeat of held down keys on keyboards, just like on a real keyboard. Keys start to
+// repeat after the given delay and then repeat in the given period. The rate may be adjusted using SetRepeatRate.
+func WithKeyRepeat(delay time.Duration, period time.Duration) Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optKeyRepeat)
+		cfg.keyRepeat = &keyRepeat{delay: delay, period: period}
+	}
+}
+
+// WithEventHandler registers a handler for events of the given type that consumers send to the device, like LED
+// changes (EvLed) or bell and click sounds (EvSnd). The given codes are registered on the device, so that consumers
+// know they may be sent. Handlers are called from a background goroutine that reads the device until it is closed,
+// which is why they should return quickly. Only EvLed and EvSnd are supported, creating the device fails otherwise.
+func WithEventHandler(evType uint16, handler func(event InputEvent), codes ...uint16) Option {
+	return func(cfg *deviceConfig) {
+		readCodes := make(map[uint16][]uint16, len(cfg.readCodes)+1)
+		for t, c := range cfg.readCodes {
+			readCodes[t] = c
+		}
+		readCodes[evType] = append(readCodes[evType][:len(readCodes[evType]):len(readCodes[evType])], codes...)
+		cfg.readCodes = readCodes
+		*cfg = cfg.withHandler(evType, func(_ DeviceFile, iev inputEvent) {
+			handler(InputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+		})
+	}
+}
+
+func newDeviceConfig(opts []Option) deviceConfig {
+	var cfg deviceConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// applyOptions returns the config of a device that is created by the given function, which fails if an option is
+// given that only applies to other devices, as it would be ignored otherwise.
+func applyOptions(function string, opts []Option, supported ...string) (deviceConfig, error) {
+	cfg := newDeviceConfig(opts)
+options:
+	for _, name := range cfg.specific {
+		for _, s := range supported {
+			if name == s {
+				continue options
+			}
+		}
+		return deviceConfig{}, errorf(ErrInvalidArgument, "failed to perform %s. Option %s is not supported by the device", function, name)
+	}
+	return cfg, nil
+}
+
+// mark records that the given option that only applies to some devices has been given.
+func (cfg *deviceConfig) mark(option string) {
+	for _, name := range cfg.specific {
+		if name == option {
+			return
+		}
+	}
+	cfg.specific = append(cfg.specific, option)
+}
+
+// withDefaultTuning returns a copy of the config that uses the given tuning for the axis, unless the axis has been
+// tuned explicitly.
+func (cfg deviceConfig) withDefaultTuning(code uint16, tuning AxisTuning) deviceConfig {
+	tunings := make(map[uint16]AxisTuning, len(cfg.tunings)+1)
+	for c, t := range cfg.tunings {
+		tunings[c] = t
+	}
+	if _, ok := tunings[code]; !ok {
+		tunings[code] = tuning
+	}
+	cfg.tunings = tunings
+	return cfg
+}
+
+// applyTunings overrides the default fuzz and flat values of the device with the explicitly configured values and
+// returns the resolution of all axes. Only axes that are available on the device (meaning that they have a range)
+// may be tuned.
+func (cfg deviceConfig) applyTunings(dev *uinputUserDev) (resolutions [absSize]int32, err error) {
+	for code, tuning := range cfg.tunings {
+		if code >= absSize {
+			return resolutions, errorf(ErrInvalidArgument, "failed to tune axis %d. Expected an axis code below %d", code, absSize)
+		}
+		if dev.Absmin[code] == 0 && dev.Absmax[code] == 0 {
+			return resolutions, errorf(ErrInvalidArgument, "failed to tune axis %d. The axis is not available on this device", code)
+		}
+		dev.Absfuzz[code] = tuning.Fuzz
+		dev.Absflat[code] = tuning.Flat
+		resolutions[code] = tuning.Resolution
+	}
+	return resolutions, nil
+}
+
+// writeRetry returns the configured retries of writes, or the defaults.
+func (cfg deviceConfig) writeRetry() writeRetry {
+	if cfg.retry == nil {
+		return writeRetry{attempts: defaultWriteRetries, delay: defaultWriteRetryDelay}
+	}
+	return *cfg.retry
+}
+
+// applyID overrides the default id of the device with the explicitly configured values.
+func (cfg deviceConfig) applyID(id *inputID) {
+	if cfg.bustype != nil {
+		id.Bustype = *cfg.bustype
+	}
+	if cfg.vendor != nil {
+		id.Vendor = *cfg.vendor
+	}
+	if cfg.product != nil {
+		id.Product = *cfg.product
+	}
+	if cfg.version != nil {
+		id.Version = *cfg.version
+	}
+}
@@ -0,0 +1,230 @@
+package uinput
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestOptionsOverrideDefaultID(t *testing.T) {
+	id := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0815, Version: 1}
+	cfg := newDeviceConfig([]Option{WithVendor(0x045e), WithProduct(0x028e), WithBusType(BusBluetooth)})
+	cfg.applyID(&id)
+
+	expected := inputID{Bustype: BusBluetooth, Vendor: 0x045e, Product: 0x028e, Version: 1}
+	if id != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, id)
+	}
+}
+
+func TestNoOptionsKeepDefaultID(t *testing.T) {
+	id := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0815, Version: 1}
+	expected := id
+	newDeviceConfig(nil).applyID(&id)
+
+	if id != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, id)
+	}
+}
+
+func TestStringOptionsAreStored(t *testing.T) {
+	cfg := newDeviceConfig([]Option{WithPhys("usb-0000:00:14.0-1/input0"), WithUniq("00:11:22:33:44:55")})
+	if cfg.phys != "usb-0000:00:14.0-1/input0" {
+		t.Fatalf("Expected: %s\nActual: %s", "usb-0000:00:14.0-1/input0", cfg.phys)
+	}
+	if cfg.uniq != "00:11:22:33:44:55" {
+		t.Fatalf("Expected: %s\nActual: %s", "00:11:22:33:44:55", cfg.uniq)
+	}
+}
+
+func TestAxisTuningOverridesDefaults(t *testing.T) {
+	var dev uinputUserDev
+	dev.Absmin[absX] = stickMin
+	dev.Absmax[absX] = stickMax
+	dev.Absfuzz[absX] = stickFuzz
+	dev.Absflat[absX] = stickFlat
+	cfg := newDeviceConfig([]Option{WithAxisTuning(AxisLeftX, AxisTuning{Fuzz: 0, Flat: 4096, Resolution: 12})})
+
+	resolutions, err := cfg.applyTunings(&dev)
+	if err != nil {
+		t.Fatalf("Failed to apply axis tuning: %v", err)
+	}
+	if dev.Absfuzz[absX] != 0 || dev.Absflat[absX] != 4096 || resolutions[absX] != 12 {
+		t.Fatalf("Expected: fuzz 0, flat 4096, resolution 12\nActual: fuzz %d, flat %d, resolution %d",
+			dev.Absfuzz[absX], dev.Absflat[absX], resolutions[absX])
+	}
+}
+
+func TestAxisTuningFailsOnInvalidAxis(t *testing.T) {
+	var dev uinputUserDev
+	cfg := newDeviceConfig([]Option{WithAxisTuning(absSize, AxisTuning{Flat: 1})})
+
+	expected := "failed to tune axis 64. Expected an axis code below 64"
+	_, err := cfg.applyTunings(&dev)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
+
+func TestAxisTuningFailsOnUnavailableAxis(t *testing.T) {
+	var dev uinputUserDev
+	cfg := newDeviceConfig([]Option{WithAxisTuning(absZ, AxisTuning{Flat: 1})})
+
+	expected := "failed to tune axis 2. The axis is not available on this device"
+	_, err := cfg.applyTunings(&dev)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %v", expected, err)
+	}
+}
+
+func TestDefaultTuningDoesNotOverrideExplicitTuning(t *testing.T) {
+	cfg := newDeviceConfig([]Option{WithAxisTuning(absX, AxisTuning{Resolution: 1})})
+	cfg = cfg.withDefaultTuning(absX, AxisTuning{Resolution: 2}).withDefaultTuning(absY, AxisTuning{Resolution: 3})
+
+	if cfg.tunings[absX].Resolution != 1 || cfg.tunings[absY].Resolution != 3 {
+		t.Fatalf("Expected: resolutions 1 and 3\nActual: %+v", cfg.tunings)
+	}
+}
+
+func TestPropertiesAreAccumulated(t *testing.T) {
+	cfg := newDeviceConfig([]Option{WithProperties(PropPointer), WithProperties(PropButtonpad, PropTopButtonpad)})
+
+	expected := []uint16{PropPointer, PropButtonpad, PropTopButtonpad}
+	if len(cfg.props) != len(expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, cfg.props)
+	}
+	for i := range expected {
+		if cfg.props[i] != expected[i] {
+			t.Fatalf("Expected: %v\nActual: %v", expected, cfg.props)
+		}
+	}
+}
+
+func TestAxisRangePolicyClampsValues(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Pad"), WithBackend(backend), WithAxisRangePolicy(AxisRangeClamp))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = gamepad.SetAxes(map[uint16]int32{AxisLeftTrigger: 1000})
+	if err != nil {
+		t.Fatalf("Failed to set axes: %v", err)
+	}
+	err = gamepad.EmitEvent(EvAbs, AxisHatX, -5)
+	if err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	written := backend.file.writes.Bytes()
+	backend.file.mu.Unlock()
+	if len(written) != 4*inputEventSize {
+		t.Fatalf("Expected 4 events to be written, got %d bytes", len(written))
+	}
+	trigger := (*inputEvent)(unsafe.Pointer(&written[0]))
+	if trigger.Code != AxisLeftTrigger || trigger.Value != triggerMax {
+		t.Fatalf("Expected the trigger to be clamped to %d, got %+v", triggerMax, *trigger)
+	}
+	hat := (*inputEvent)(unsafe.Pointer(&written[2*inputEventSize]))
+	if hat.Code != AxisHatX || hat.Value != -1 {
+		t.Fatalf("Expected the hat to be clamped to -1, got %+v", *hat)
+	}
+}
+
+func TestAxisRangePolicyRejectsFrames(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Pad"), WithBackend(backend), WithAxisRangePolicy(AxisRangeReject))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = gamepad.EmitEvents([]InputEvent{{Type: EvKey, Code: ButtonSouth, Value: 1}, {Type: EvAbs, Code: AxisLeftX, Value: 40000}})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+	err = gamepad.EmitEvent(EvAbs, AxisLeftX, stickMin)
+	if err != nil {
+		t.Fatalf("Expected values within the range to pass, got %v", err)
+	}
+
+	backend.file.mu.Lock()
+	written := backend.file.writes.Len()
+	backend.file.mu.Unlock()
+	if written != 2*inputEventSize {
+		t.Fatalf("Expected only the valid frame to be written, got %d bytes", written)
+	}
+}
+
+func TestClockStampsAllEvents(t *testing.T) {
+	backend := &mockBackend{}
+	now := time.Unix(1234, 567000)
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	explicit := time.Unix(99, 1000)
+	err = kbd.EmitEvents([]InputEvent{{Type: EvKey, Code: KeyA, Value: 1, Time: explicit}, {Type: EvKey, Code: KeyB, Value: 1}})
+	if err != nil {
+		t.Fatalf("Failed to emit events: %v", err)
+	}
+	err = kbd.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	written := append([]byte(nil), backend.file.writes.Bytes()...)
+	backend.file.mu.Unlock()
+	if len(written) != 4*inputEventSize {
+		t.Fatalf("Expected 4 events to be written, got %d bytes", len(written))
+	}
+	for i, expected := range []time.Time{explicit, now, now, now} {
+		iev := (*inputEvent)(unsafe.Pointer(&written[i*inputEventSize]))
+		if int64(iev.Time.Sec) != expected.Unix() || int64(iev.Time.Usec) != int64(expected.Nanosecond()/1000) {
+			t.Fatalf("Expected event %d to be stamped with %v, got %+v", i, expected, iev.Time)
+		}
+	}
+}
+
+func TestHooksObserveEventsAndErrors(t *testing.T) {
+	backend := &mockBackend{}
+	var events []InputEvent
+	var writeErrors []error
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithHooks(Hooks{
+		OnEvent:      func(event InputEvent) { events = append(events, event) },
+		OnWriteError: func(err error) { writeErrors = append(writeErrors, err) },
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+
+	err = kbd.KeyDown(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+	expected := []InputEvent{{Type: EvKey, Code: KeyA, Value: 1}, {Type: EvSyn}}
+	if len(events) != 2 || events[0] != expected[0] || events[1] != expected[1] {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+
+	backend.file.Close()
+	err = kbd.KeyUp(KeyA)
+	if len(writeErrors) != 1 || !errors.Is(writeErrors[0], ErrDeviceClosed) || !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected the write error to be reported, got %v (%v)", writeErrors, err)
+	}
+}
@@ -0,0 +1,412 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// the ranges of the pressure and tilt axes of pens. Tilt is given in degrees, which is why the resolution of the
+// tilt axes (in units per radian) is 180/pi.
+const (
+	penPressureMax    = 4095
+	penTiltMax        = 90
+	penTiltResolution = 57
+)
+
+// A Pen is a graphics tablet with a pressure and tilt sensitive stylus, like the tablets that are used with drawing
+// applications (e.g. Krita or GIMP). The pen is in proximity of the tablet (hovering) as soon as it is moved, and
+// touches the tablet between PenDown and PenUp. Just like a real stylus, the pen has an eraser end, which may be
+// selected using SetTool.
+type Pen interface {
+	// PenDown will touch the tablet at the given position with the given pressure (between 0 and 1). The pen
+	// remains down until PenUp is called.
+	PenDown(x int32, y int32, pressure float64) error
+
+	// PenMove will move the pen to the given position. While the pen is down, the pressure (between 0 and 1) is
+	// updated as well, otherwise the pen hovers above the tablet and the pressure is ignored.
+	PenMove(x int32, y int32, pressure float64) error
+
+	// PenUp will lift the pen off the tablet. The pen keeps hovering above the tablet until Leave is called.
+	PenUp() error
+
+	// Leave will move the pen out of the proximity of the tablet, lifting it first if necessary.
+	Leave() error
+
+	// Tilt will tilt the pen by the given angles (between -90 and 90 degrees) along the x and y axes.
+	Tilt(x int32, y int32) error
+
+	// SetTool will switch the active tool of the pen to either the tip (BtnToolPen) or the eraser end
+	// (BtnToolRubber). If the pen is in proximity, it leaves the tablet with the previous tool and enters with the new
+	// one at the same position, just like when the pen is flipped over. The pen hovers afterwards.
+	SetTool(tool int) error
+
+	// ButtonDown will press the given button of the pen (BtnStylus or BtnStylus2).
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button of the pen.
+	ButtonUp(button int) error
+
+	Device
+}
+
+// the buttons of the pen
+var penButtons = []int{BtnStylus, BtnStylus2}
+
+// the tools of the pen
+var penTools = []int{BtnToolPen, BtnToolRubber}
+
+type vPen struct {
+	name       []byte
+	deviceFile *uinputDevice
+
+	// mu guards the state of the pen, which needs to be updated along with the events that are sent
+	mu          sync.Mutex
+	inProximity bool
+	down        bool
+	eraser      bool
+	x, y        int32
+}
+
+// CreatePen will create a new graphics tablet with a pen. Just like with the touch pad, the x and y axis boundaries
+// of the tablet need to be defined upon creation.
+func CreatePen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...Option) (Pen, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreatePen", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createPen(path, name, minX, maxX, minY, maxY, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vPen{name: name, deviceFile: fd}, nil
+}
+
+// PenDown will touch the tablet at the given position.
+func (vp *vPen) PenDown(x int32, y int32, pressure float64) error {
+	value, err := penPressure("PenDown", pressure)
+	if err != nil {
+		return err
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	if vp.down {
+		return errorf(ErrInvalidArgument, "failed to perform PenDown. The pen is already down")
+	}
+
+	events := append(vp.proximityEvents(x, y),
+		inputEvent{Type: evAbs, Code: AbsPressure, Value: value},
+		inputEvent{Type: evKey, Code: BtnTouch, Value: btnStatePressed})
+	err = sendEvents(vp.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to issue the PenDown event: %w", err)
+	}
+	vp.inProximity, vp.down = true, true
+	vp.x, vp.y = x, y
+	return nil
+}
+
+// PenMove will move the pen to the given position.
+func (vp *vPen) PenMove(x int32, y int32, pressure float64) error {
+	value, err := penPressure("PenMove", pressure)
+	if err != nil {
+		return err
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+
+	events := vp.proximityEvents(x, y)
+	if vp.down {
+		events = append(events, inputEvent{Type: evAbs, Code: AbsPressure, Value: value})
+	}
+	err = sendEvents(vp.deviceFile, events)
+	if err != nil {
+		return err
+	}
+	vp.inProximity = true
+	vp.x, vp.y = x, y
+	return nil
+}
+
+// PenUp will lift the pen off the tablet.
+func (vp *vPen) PenUp() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	if !vp.down {
+		return errorf(ErrInvalidArgument, "failed to perform PenUp. The pen is not down")
+	}
+
+	err := sendEvents(vp.deviceFile, vp.liftEvents())
+	if err != nil {
+		return fmt.Errorf("failed to issue the PenUp event: %w", err)
+	}
+	vp.down = false
+	return nil
+}
+
+// Leave will move the pen out of the proximity of the tablet.
+func (vp *vPen) Leave() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	if !vp.inProximity {
+		return nil
+	}
+
+	var events []inputEvent
+	if vp.down {
+		events = vp.liftEvents()
+	}
+	events = append(events, inputEvent{Type: evKey, Code: vp.tool(), Value: btnStateReleased})
+	err := sendEvents(vp.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to issue the Leave event: %w", err)
+	}
+	vp.inProximity, vp.down = false, false
+	return nil
+}
+
+// SetTool will switch the active tool of the pen.
+func (vp *vPen) SetTool(tool int) error {
+	if !codeSupported(penTools, tool) {
+		return errorf(ErrInvalidArgument, "failed to perform SetTool. Code %d is not a pen tool", tool)
+	}
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	eraser := tool == BtnToolRubber
+	if eraser == vp.eraser {
+		return nil
+	}
+	if !vp.inProximity {
+		vp.eraser = eraser
+		return nil
+	}
+
+	var events []inputEvent
+	if vp.down {
+		events = vp.liftEvents()
+	}
+	events = append(events, inputEvent{Type: evKey, Code: vp.tool(), Value: btnStateReleased})
+	err := sendEvents(vp.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to leave the tablet with the previous tool: %w", err)
+	}
+	vp.inProximity, vp.down, vp.eraser = false, false, eraser
+
+	err = sendEvents(vp.deviceFile, vp.proximityEvents(vp.x, vp.y))
+	if err != nil {
+		return fmt.Errorf("failed to enter the tablet with the new tool: %w", err)
+	}
+	vp.inProximity = true
+	return nil
+}
+
+// Tilt will tilt the pen by the given angles.
+func (vp *vPen) Tilt(x int32, y int32) error {
+	if x < -penTiltMax || x > penTiltMax || y < -penTiltMax || y > penTiltMax {
+		return errorf(ErrInvalidArgument, "failed to perform Tilt. Angles (%d, %d) are out of range. Expected values between %d and %d", x, y, -penTiltMax, penTiltMax)
+	}
+	return sendEvents(vp.deviceFile, []inputEvent{
+		{Type: evAbs, Code: AbsTiltX, Value: x},
+		{Type: evAbs, Code: AbsTiltY, Value: y},
+	})
+}
+
+// ButtonDown will press the given button of the pen.
+func (vp *vPen) ButtonDown(button int) error {
+	if !codeSupported(penButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a pen button", button)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button of the pen.
+func (vp *vPen) ButtonUp(button int) error {
+	if !codeSupported(penButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a pen button", button)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{button}, btnStateReleased)
+}
+
+func (vp *vPen) Name() string {
+	return string(vp.name)
+}
+
+func (vp *vPen) Path() string {
+	return devicePath(vp.deviceFile)
+}
+
+func (vp *vPen) Fd() uintptr {
+	return deviceFd(vp.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vp *vPen) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vp.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vp *vPen) EmitEvents(events []InputEvent) error {
+	return emitEvents(vp.deviceFile, events)
+}
+
+func (vp *vPen) Sync() error {
+	return writeSyncEvent(vp.deviceFile)
+}
+
+func (vp *vPen) SysPath() (string, error) {
+	return sysPath(vp.deviceFile)
+}
+
+func (vp *vPen) EventPath() (string, error) {
+	return eventPath(vp.deviceFile)
+}
+
+func (vp *vPen) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vp.deviceFile)
+}
+
+func (vp *vPen) Stats() Stats {
+	return deviceStats(vp.deviceFile)
+}
+
+func (vp *vPen) State() State {
+	return deviceState(vp.deviceFile)
+}
+
+func (vp *vPen) ResetAll() error {
+	return resetDevice(vp.deviceFile)
+}
+
+func (vp *vPen) Disconnect() error {
+	return disconnectDevice(vp.deviceFile)
+}
+
+func (vp *vPen) Reconnect() error {
+	return reconnectDevice(vp.deviceFile)
+}
+
+// Close will close the device and free resources.
+func (vp *vPen) Close() error {
+	return closeDevice(vp.deviceFile)
+}
+
+// proximityEvents returns the events that move the pen to the given position, bringing it into proximity of the
+// tablet first if necessary. The caller needs to hold mu.
+func (vp *vPen) proximityEvents(x int32, y int32) []inputEvent {
+	var events []inputEvent
+	if !vp.inProximity {
+		events = append(events, inputEvent{Type: evKey, Code: vp.tool(), Value: btnStatePressed})
+	}
+	return append(events,
+		inputEvent{Type: evAbs, Code: absX, Value: x},
+		inputEvent{Type: evAbs, Code: absY, Value: y})
+}
+
+// tool returns the code of the active tool.
+func (vp *vPen) tool() uint16 {
+	if vp.eraser {
+		return BtnToolRubber
+	}
+	return BtnToolPen
+}
+
+// liftEvents returns the events that lift the pen off the tablet.
+func (vp *vPen) liftEvents() []inputEvent {
+	return []inputEvent{
+		{Type: evAbs, Code: AbsPressure, Value: 0},
+		{Type: evKey, Code: BtnTouch, Value: btnStateReleased},
+	}
+}
+
+// penPressure converts the given pressure (between 0 and 1) to the range of the pressure axis.
+func penPressure(action string, pressure float64) (int32, error) {
+	if pressure < 0 || pressure > 1 || math.IsNaN(pressure) {
+		return 0, errorf(ErrInvalidArgument, "failed to perform %s. Pressure %v is out of range. Expected a value between 0 and 1", action, pressure)
+	}
+	return int32(math.Round(pressure * penPressureMax)), nil
+}
+
+func createPen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create pen input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	for _, event := range append(append([]int{BtnTouch}, penTools...), penButtons...) {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+	for _, event := range []int{absX, absY, AbsPressure, AbsTiltX, AbsTiltY} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	// graphics tablets that are not built into a screen move the pointer, just like touch pads
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropPointer))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register pointer property: %w", err)
+	}
+
+	var absMin [absSize]int32
+	absMin[absX] = minX
+	absMin[absY] = minY
+	absMin[AbsTiltX] = -penTiltMax
+	absMin[AbsTiltY] = -penTiltMax
+
+	var absMax [absSize]int32
+	absMax[absX] = maxX
+	absMax[absY] = maxY
+	absMax[AbsPressure] = penPressureMax
+	absMax[AbsTiltX] = penTiltMax
+	absMax[AbsTiltY] = penTiltMax
+
+	for _, axis := range []uint16{AbsTiltX, AbsTiltY} {
+		cfg = cfg.withDefaultTuning(axis, AxisTuning{Resolution: penTiltResolution})
+	}
+
+	fd, err = createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x081a,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	fd.state.setPositions(absX, absY)
+	return fd, nil
+}
@@ -0,0 +1,160 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicPenStroke(t *testing.T) {
+	pen, err := CreatePen("/dev/uinput", []byte("Test Pen"), 0, 4096, 0, 4096)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual pen. Last error was: %s\n", err)
+	}
+
+	err = pen.PenMove(100, 100, 0)
+	if err != nil {
+		t.Fatalf("Failed to hover. Last error was: %s\n", err)
+	}
+
+	err = pen.PenDown(100, 100, 0.3)
+	if err != nil {
+		t.Fatalf("Failed to put the pen down. Last error was: %s\n", err)
+	}
+
+	err = pen.Tilt(20, -10)
+	if err != nil {
+		t.Fatalf("Failed to tilt the pen. Last error was: %s\n", err)
+	}
+
+	err = pen.PenMove(400, 300, 0.8)
+	if err != nil {
+		t.Fatalf("Failed to draw. Last error was: %s\n", err)
+	}
+
+	err = pen.PenUp()
+	if err != nil {
+		t.Fatalf("Failed to lift the pen. Last error was: %s\n", err)
+	}
+
+	err = pen.SetTool(BtnToolRubber)
+	if err != nil {
+		t.Fatalf("Failed to switch to the eraser. Last error was: %s\n", err)
+	}
+
+	err = pen.ButtonDown(BtnStylus2)
+	if err != nil {
+		t.Fatalf("Failed to press the second stylus button. Last error was: %s\n", err)
+	}
+
+	err = pen.ButtonUp(BtnStylus2)
+	if err != nil {
+		t.Fatalf("Failed to release the second stylus button. Last error was: %s\n", err)
+	}
+
+	err = pen.Leave()
+	if err != nil {
+		t.Fatalf("Failed to leave proximity. Last error was: %s\n", err)
+	}
+
+	err = pen.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestPenCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreatePen("", []byte("Pen"), 0, 4096, 0, 4096)
+	assertPathDiscovered(t, device, err)
+}
+
+func TestPenReportsProximityAndPressure(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-pen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	pen := &vPen{name: []byte("Test Pen"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for _, step := range []func() error{
+		func() error { return pen.PenDown(10, 20, 0.5) },
+		func() error { return pen.PenMove(30, 40, 1) },
+		func() error { return pen.Leave() },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to use pen: %v", err)
+		}
+	}
+
+	expectedKeys := []inputEvent{
+		{Type: evKey, Code: BtnToolPen, Value: btnStatePressed},
+		{Type: evKey, Code: BtnTouch, Value: btnStatePressed},
+		{Type: evKey, Code: BtnTouch, Value: btnStateReleased},
+		{Type: evKey, Code: BtnToolPen, Value: btnStateReleased},
+	}
+	if keys := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(keys, expectedKeys) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expectedKeys, keys)
+	}
+	expectedAxes := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 10},
+		{Type: evAbs, Code: absY, Value: 20},
+		{Type: evAbs, Code: AbsPressure, Value: 2048},
+		{Type: evAbs, Code: absX, Value: 30},
+		{Type: evAbs, Code: absY, Value: 40},
+		{Type: evAbs, Code: AbsPressure, Value: penPressureMax},
+		{Type: evAbs, Code: AbsPressure, Value: 0},
+	}
+	if axes := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(axes, expectedAxes) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expectedAxes, axes)
+	}
+}
+
+func TestPenRejectsInvalidArguments(t *testing.T) {
+	pen := &vPen{name: []byte("Test Pen")}
+	for name, err := range map[string]error{
+		"pressure": pen.PenDown(0, 0, 1.5),
+		"tilt":     pen.Tilt(91, 0),
+		"button":   pen.ButtonDown(BtnLeft),
+		"not down": pen.PenUp(),
+		"negative": pen.PenMove(0, 0, -0.1),
+	} {
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %s, got %v", name, err)
+		}
+	}
+}
+
+func TestSwitchingToolsReentersTablet(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-pen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	pen := &vPen{name: []byte("Test Pen"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for _, step := range []func() error{
+		func() error { return pen.PenDown(10, 20, 0.5) },
+		func() error { return pen.SetTool(BtnToolRubber) },
+		func() error { return pen.PenDown(10, 20, 0.5) },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to use pen: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: BtnToolPen, Value: btnStatePressed},
+		{Type: evKey, Code: BtnTouch, Value: btnStatePressed},
+		{Type: evKey, Code: BtnTouch, Value: btnStateReleased},
+		{Type: evKey, Code: BtnToolPen, Value: btnStateReleased},
+		{Type: evKey, Code: BtnToolRubber, Value: btnStatePressed},
+		{Type: evKey, Code: BtnTouch, Value: btnStatePressed},
+	}
+	if keys := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, keys)
+	}
+}
@@ -0,0 +1,151 @@
+package uinput
+
+import "context"
+
+// the keys of power key devices
+var powerKeys = []int{KeyPower, KeySleep, KeyWakeup}
+
+// PowerKeys is a device with the power, sleep and wakeup keys, like the power button of a computer. Consumers like
+// systemd-logind handle these keys (e.g. by suspending the system), which allows to test power management without
+// physical hardware.
+type PowerKeys interface {
+	// PressPower will press and release the power key.
+	PressPower() error
+
+	// PressSleep will press and release the sleep key.
+	PressSleep() error
+
+	// PressWakeup will press and release the wakeup key.
+	PressWakeup() error
+
+	// KeyDown will press the given key (KeyPower, KeySleep or KeyWakeup), which allows to simulate long presses.
+	// Note that the key remains pressed until KeyUp is called.
+	KeyDown(key int) error
+
+	// KeyUp will release the given key.
+	KeyUp(key int) error
+
+	Device
+}
+
+type vPowerKeys struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreatePowerKeys will create a new power key device.
+func CreatePowerKeys(path string, name []byte, opts ...Option) (PowerKeys, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreatePowerKeys", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createKeyDevice(path, name, powerKeys, 0x081c, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vPowerKeys{name: name, deviceFile: fd}, nil
+}
+
+// PressPower will press and release the power key.
+func (vp vPowerKeys) PressPower() error {
+	return pressKey(vp.deviceFile, KeyPower)
+}
+
+// PressSleep will press and release the sleep key.
+func (vp vPowerKeys) PressSleep() error {
+	return pressKey(vp.deviceFile, KeySleep)
+}
+
+// PressWakeup will press and release the wakeup key.
+func (vp vPowerKeys) PressWakeup() error {
+	return pressKey(vp.deviceFile, KeyWakeup)
+}
+
+// KeyDown will press the given key.
+func (vp vPowerKeys) KeyDown(key int) error {
+	if !codeSupported(powerKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyDown. Code %d is not a power key", key)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{key}, btnStatePressed)
+}
+
+// KeyUp will release the given key.
+func (vp vPowerKeys) KeyUp(key int) error {
+	if !codeSupported(powerKeys, key) {
+		return errorf(ErrInvalidArgument, "failed to perform KeyUp. Code %d is not a power key", key)
+	}
+	return sendBtnEvent(vp.deviceFile, []int{key}, btnStateReleased)
+}
+
+func (vp vPowerKeys) Name() string {
+	return string(vp.name)
+}
+
+func (vp vPowerKeys) Path() string {
+	return devicePath(vp.deviceFile)
+}
+
+func (vp vPowerKeys) Fd() uintptr {
+	return deviceFd(vp.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vp vPowerKeys) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vp.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vp vPowerKeys) EmitEvents(events []InputEvent) error {
+	return emitEvents(vp.deviceFile, events)
+}
+
+func (vp vPowerKeys) Sync() error {
+	return writeSyncEvent(vp.deviceFile)
+}
+
+func (vp vPowerKeys) SysPath() (string, error) {
+	return sysPath(vp.deviceFile)
+}
+
+func (vp vPowerKeys) EventPath() (string, error) {
+	return eventPath(vp.deviceFile)
+}
+
+func (vp vPowerKeys) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vp.deviceFile)
+}
+
+func (vp vPowerKeys) Stats() Stats {
+	return deviceStats(vp.deviceFile)
+}
+
+func (vp vPowerKeys) State() State {
+	return deviceState(vp.deviceFile)
+}
+
+func (vp vPowerKeys) ResetAll() error {
+	return resetDevice(vp.deviceFile)
+}
+
+func (vp vPowerKeys) Disconnect() error {
+	return disconnectDevice(vp.deviceFile)
+}
+
+func (vp vPowerKeys) Reconnect() error {
+	return reconnectDevice(vp.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vp vPowerKeys) Close() error {
+	return closeDevice(vp.deviceFile)
+}
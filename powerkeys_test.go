@@ -0,0 +1,58 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicPowerKeys(t *testing.T) {
+	dev, err := CreatePowerKeys("/dev/uinput", []byte("Test Power Keys"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual power key device. Last error was: %s\n", err)
+	}
+
+	err = dev.PressWakeup()
+	if err != nil {
+		t.Fatalf("Failed to press the wakeup key. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestPowerKeysCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreatePowerKeys("", []byte("PowerKeys"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestPowerKeyPresses(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-powerkeys-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dev := vPowerKeys{name: []byte("Test Power Keys"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	err = dev.PressPower()
+	if err != nil {
+		t.Fatalf("Failed to press the power key: %v", err)
+	}
+	err = dev.KeyDown(KeyA)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a key that is not a power key, got %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyPower, Value: btnStatePressed},
+		{Type: evKey, Code: KeyPower, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
@@ -0,0 +1,46 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PressFor will press the key or button with the given code, hold it for the duration and release it again. The
+// press and the release are emitted as frames of their own. The key is released even if emitting the press fails, so
+// that no key is left pressed behind.
+func PressFor(device EventEmitter, code uint16, d time.Duration) error {
+	return PressForContext(context.Background(), device, code, d)
+}
+
+// PressForContext will press the key or button just like PressFor, but releases it early once the context is done,
+// in which case the error of the context is returned. The key is released in any case.
+func PressForContext(ctx context.Context, device EventEmitter, code uint16, d time.Duration) (err error) {
+	if CodeName(evKey, code) == "" {
+		return errorf(ErrInvalidArgument, "failed to perform PressFor. %d is not a known key or button", code)
+	}
+	if d < 0 {
+		return errorf(ErrInvalidArgument, "failed to perform PressFor. Duration %v is out of range. Expected a positive or zero value", d)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to press key %s: %w", CodeName(evKey, code), err)
+	}
+
+	defer func() {
+		// the release does not depend on the context, as it must not be skipped once the key has been pressed
+		releaseErr := device.EmitEvents([]InputEvent{{Type: evKey, Code: code, Value: btnStateReleased}})
+		if err == nil && releaseErr != nil {
+			err = fmt.Errorf("failed to release key %s: %w", CodeName(evKey, code), releaseErr)
+		}
+	}()
+
+	err = device.EmitEvents([]InputEvent{{Type: evKey, Code: code, Value: btnStatePressed}})
+	if err != nil {
+		return fmt.Errorf("failed to press key %s: %w", CodeName(evKey, code), err)
+	}
+	err = sleepUntil(ctx, time.Now().Add(d))
+	if err != nil {
+		return fmt.Errorf("failed to hold key %s: %w", CodeName(evKey, code), err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// failingEmitter records all frames, but fails to emit the first ones
+type failingEmitter struct {
+	recordingEmitter
+	failures int
+}
+
+func (f *failingEmitter) EmitEvents(events []InputEvent) error {
+	_ = f.recordingEmitter.EmitEvents(events)
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("device is unavailable")
+	}
+	return nil
+}
+
+func TestPressForHoldsKey(t *testing.T) {
+	recorder := &timedRecorder{start: time.Now()}
+	err := PressFor(recorder, BtnA, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+
+	expected := [][]InputEvent{
+		{{Type: EvKey, Code: BtnA, Value: 1}},
+		{{Type: EvKey, Code: BtnA, Value: 0}},
+	}
+	if !reflect.DeepEqual(recorder.frames, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, recorder.frames)
+	}
+	if held := recorder.times[1] - recorder.times[0]; held < 20*time.Millisecond {
+		t.Fatalf("Expected the key to be held for 20ms, but it was released after %v", held)
+	}
+}
+
+func TestPressForContextReleasesKeyOnCancel(t *testing.T) {
+	emitter := &recordingEmitter{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := PressForContext(ctx, emitter, KeyA, time.Hour)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got: %v", err)
+	}
+	if len(emitter.frames) != 2 || emitter.frames[1][0].Value != 0 {
+		t.Fatalf("Expected the key to be released, got %+v", emitter.frames)
+	}
+}
+
+func TestPressForReleasesKeyIfPressFails(t *testing.T) {
+	emitter := &failingEmitter{failures: 1}
+	err := PressFor(emitter, KeyA, 0)
+	if err == nil {
+		t.Fatalf("Expected the failed press to be reported")
+	}
+	if len(emitter.frames) != 2 || emitter.frames[1][0].Value != 0 {
+		t.Fatalf("Expected the key to be released, got %+v", emitter.frames)
+	}
+}
+
+func TestPressForRejectsInvalidInput(t *testing.T) {
+	emitter := &recordingEmitter{}
+	if err := PressFor(emitter, 0x2ff, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unknown code, got: %v", err)
+	}
+	if err := PressFor(emitter, KeyA, -time.Second); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a negative duration, got: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := PressForContext(ctx, emitter, KeyA, time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation to be reported, got: %v", err)
+	}
+	if len(emitter.frames) != 0 {
+		t.Fatalf("Expected nothing to be emitted, got %+v", emitter.frames)
+	}
+}
+
+func TestDevicesPressFor(t *testing.T) {
+	keyboardBackend, mouseBackend, gamepadBackend := &mockBackend{}, &mockBackend{}, &mockBackend{}
+	keyboard, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(keyboardBackend))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer keyboard.Close()
+	mouse, err := CreateMouse("mock", []byte("Test Mouse"), WithBackend(mouseBackend))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer mouse.Close()
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(gamepadBackend))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer gamepad.Close()
+
+	tests := []struct {
+		name    string
+		backend *mockBackend
+		code    int
+		press   func(code int, d time.Duration) error
+	}{
+		{"keyboard", keyboardBackend, KeyA, keyboard.KeyPressFor},
+		{"mouse", mouseBackend, BtnRight, mouse.ButtonPressFor},
+		{"gamepad", gamepadBackend, ButtonSouth, gamepad.ButtonPressFor},
+	}
+	for _, test := range tests {
+		takeWrites(test.backend)
+		if err := test.press(test.code, time.Millisecond); err != nil {
+			t.Fatalf("Failed to press the %s button: %v", test.name, err)
+		}
+		expected := []inputEvent{
+			{Type: evKey, Code: uint16(test.code), Value: btnStatePressed},
+			{Type: evSyn, Code: synReport},
+			{Type: evKey, Code: uint16(test.code), Value: btnStateReleased},
+			{Type: evSyn, Code: synReport},
+		}
+		if events := takeWrites(test.backend); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("Expected the %s to emit %+v, got %+v", test.name, expected, events)
+		}
+		if err := test.press(BtnTouch, 0); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected the %s to reject an unsupported code, got: %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package uinput
+
+// the code of SYN_MT_REPORT events, which separate the contacts of multitouch protocol A frames
+const synMtReport = 2
+
+// WithMultitouchProtocolA makes touch screens follow the multitouch protocol A instead of the slot based protocol B
+// (see https://www.kernel.org/doc/Documentation/input/multi-touch-protocol.txt). Protocol A devices have neither slots
+// nor tracking ids: every frame reports all contacts that rest on the screen, each followed by SYN_MT_REPORT, while a
+// frame with a single SYN_MT_REPORT reports that all contacts have been lifted. This allows to test legacy drivers and
+// embedded stacks that still consume protocol A. Slots are still used to address the contacts, but they are not
+// reported. The option applies to touch screens only.
+func WithMultitouchProtocolA() Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optMultitouchProtocolA)
+		cfg.mtProtocolA = true
+	}
+}
+
+// protocolAEvents converts the events of a protocol B frame into a protocol A frame, which reports the state of all
+// active contacts in the order of their slots. Single touch emulation and BTN_TOUCH are kept as is.
+func (vTouch *vTouchScreen) protocolAEvents(events []inputEvent) []inputEvent {
+	var frame []inputEvent
+	for slot, trackingID := range vTouch.trackingIDs {
+		if trackingID == -1 {
+			continue
+		}
+		shape, position := vTouch.shapes[slot], vTouch.positions[slot]
+		frame = append(frame,
+			inputEvent{Type: evAbs, Code: AbsMtToolType, Value: shape.Tool},
+			inputEvent{Type: evAbs, Code: AbsMtTouchMajor, Value: shape.Major},
+			inputEvent{Type: evAbs, Code: AbsMtTouchMinor, Value: shape.Minor},
+			inputEvent{Type: evAbs, Code: absMtPositionX, Value: position.X},
+			inputEvent{Type: evAbs, Code: absMtPositionY, Value: position.Y},
+			inputEvent{Type: evSyn, Code: synMtReport})
+	}
+	if len(frame) == 0 {
+		frame = append(frame, inputEvent{Type: evSyn, Code: synMtReport})
+	}
+	for _, iev := range events {
+		if iev.Type == evKey || iev.Type == evAbs && (iev.Code == absX || iev.Code == absY) {
+			frame = append(frame, iev)
+		}
+	}
+	return frame
+}
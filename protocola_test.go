@@ -0,0 +1,56 @@
+package uinput
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestProtocolAReportsAllContactsPerFrame(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 2)
+	defer os.Remove(file.Name())
+	defer file.Close()
+	vTouch.protocolA = true
+
+	steps := []func() error{
+		func() error { return vTouch.TouchDown(0, 100, 100) },
+		func() error { return vTouch.TouchDown(1, 200, 200) },
+		func() error { return vTouch.TouchMove(0, 150, 100) },
+		func() error { return vTouch.TouchUp(0) },
+		func() error { return vTouch.TouchUp(1) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to perform step: %v", err)
+		}
+	}
+
+	var syncs []uint16
+	for _, iev := range readEvents(t, file.Name(), evSyn) {
+		syncs = append(syncs, iev.Code)
+	}
+	expected := []uint16{
+		synMtReport, synReport,
+		synMtReport, synMtReport, synReport,
+		synMtReport, synMtReport, synReport,
+		synMtReport, synReport,
+		// the single SYN_MT_REPORT reports that all contacts have been lifted
+		synMtReport, synReport,
+	}
+	if !reflect.DeepEqual(syncs, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, syncs)
+	}
+
+	var xs []int32
+	for _, iev := range readEvents(t, file.Name(), evAbs) {
+		switch iev.Code {
+		case absMtSlot, absMtTrackingID:
+			t.Fatalf("Expected protocol A frames to lack slots and tracking ids, got %+v", iev)
+		case absMtPositionX:
+			xs = append(xs, iev.Value)
+		}
+	}
+	if expectedXs := []int32{100, 100, 200, 150, 200, 200}; !reflect.DeepEqual(xs, expectedXs) {
+		t.Fatalf("Expected: %v\nActual: %v", expectedXs, xs)
+	}
+}
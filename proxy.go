@@ -0,0 +1,188 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// the code of SYN_DROPPED events, which report that the kernel dropped events because they were not read in time
+const synDropped = 3
+
+// A FrameFilter transforms a frame of events that has been read from a physical device before it is forwarded by a
+// Proxy. The returned events are emitted as a single frame, while returning no events drops the frame. The frame may
+// be modified in place.
+type FrameFilter func(frame []InputEvent) []InputEvent
+
+// ChainFilters returns a filter that applies the given filters in order. Once a filter drops a frame, the remaining
+// filters are skipped.
+func ChainFilters(filters ...FrameFilter) FrameFilter {
+	return func(frame []InputEvent) []InputEvent {
+		for _, filter := range filters {
+			frame = filter(frame)
+			if len(frame) == 0 {
+				return nil
+			}
+		}
+		return frame
+	}
+}
+
+// A Proxy grabs a physical input device and forwards its events to a virtual clone, passing every frame through a
+// filter in between. This is the core loop of remappers: the filter may drop, change or add events, while no other
+// consumer sees the events of the physical device. LED changes (like caps lock) are passed back to the physical
+// device. Force feedback is not forwarded.
+type Proxy struct {
+	source DeviceFile
+	clone  Device
+
+	closeOnce sync.Once
+	done      chan struct{}
+	err       error
+}
+
+// CreateProxy will grab the evdev device at sourcePath (e.g. /dev/input/event3) and create a virtual clone of it on
+// the uinput device at path, which reports the same capabilities, ids and name (unless name is given). The events of
+// the physical device are forwarded until Close is called. If filter is nil, all events are forwarded as is.
+func CreateProxy(path string, sourcePath string, name []byte, filter FrameFilter, opts ...Option) (*Proxy, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if name != nil {
+		err = validateUinputName(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := applyOptions("CreateProxy", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := openEvdev(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open source device: %w", err)
+	}
+	caps, err := queryCaps(source)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	if name == nil {
+		name = caps.name
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+
+	if len(caps.codes[EvLed]) > 0 {
+		cfg = cfg.withHandler(EvLed, func(_ DeviceFile, iev inputEvent) {
+			// the LED state of the physical device follows the clone, errors are of no concern to the consumer
+			_, _ = source.Write(appendInputEvent(appendInputEvent(nil, iev), syncEvent()))
+		})
+	}
+	fd, err := createClone(path, name, caps, cfg)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	clone := vConfiguredDevice{name: name, deviceFile: fd}
+
+	// the grab is taken last, so that the physical device keeps working if anything goes wrong before
+	err = ioctl(source, eviocgRab, 1)
+	if err != nil {
+		clone.Close()
+		source.Close()
+		return nil, fmt.Errorf("failed to grab source device: %w", err)
+	}
+
+	p := &Proxy{source: source, clone: clone, done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		p.err = forwardFrames(source, clone, filter)
+	}()
+	return p, nil
+}
+
+// Device returns the virtual clone, which may be used to emit additional events.
+func (p *Proxy) Device() Device {
+	return p.clone
+}
+
+// Done returns a channel that is closed once the proxy has stopped forwarding events, either because it has been
+// closed or because the physical device is gone (see Err).
+func (p *Proxy) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error that stopped the proxy, if any. It is nil while the proxy is running and after Close.
+func (p *Proxy) Err() error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+		return nil
+	}
+}
+
+// Close releases the physical device and closes the clone.
+func (p *Proxy) Close() error {
+	err := errorf(ErrDeviceClosed, "failed to close proxy")
+	p.closeOnce.Do(func() {
+		_ = ioctl(p.source, eviocgRab, 0)
+		err = p.source.Close()
+		<-p.done
+		if cloneErr := p.clone.Close(); err == nil {
+			err = cloneErr
+		}
+	})
+	return err
+}
+
+// forwardFrames reads events from the source until it fails and emits every complete frame that passes the filter.
+// Incomplete frames are discarded if the kernel reports that events have been dropped.
+func forwardFrames(source io.Reader, target EventEmitter, filter FrameFilter) error {
+	buf := make([]byte, 64*inputEventSize)
+	var frame []InputEvent
+	dropped := false
+	for {
+		n, err := source.Read(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) || err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read from source device: %w", err)
+		}
+		for i := 0; i+inputEventSize <= n; i += inputEventSize {
+			iev := *(*inputEvent)(unsafe.Pointer(&buf[i]))
+			switch {
+			case iev.Type == evSyn && iev.Code == synDropped:
+				frame, dropped = frame[:0], true
+			case iev.Type == evSyn && iev.Code == synReport:
+				if !dropped && len(frame) > 0 {
+					out := frame
+					if filter != nil {
+						out = filter(frame)
+					}
+					if len(out) > 0 {
+						err = target.EmitEvents(out)
+						if err != nil {
+							return fmt.Errorf("failed to forward events: %w", err)
+						}
+					}
+				}
+				frame, dropped = frame[:0], false
+			default:
+				// other sync events (like SYN_MT_REPORT) are part of the frame
+				frame = append(frame, InputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package uinput
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestForwardFramesAppliesFilter(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer reader.Close()
+
+	// swap A and B, drop C
+	filter := func(frame []InputEvent) []InputEvent {
+		out := frame[:0]
+		for _, ev := range frame {
+			switch ev.Code {
+			case KeyA:
+				ev.Code = KeyB
+			case KeyC:
+				continue
+			}
+			out = append(out, ev)
+		}
+		return out
+	}
+	var buf []byte
+	for _, iev := range []inputEvent{
+		{Type: evKey, Code: KeyA, Value: 1}, syncEvent(),
+		{Type: evKey, Code: KeyC, Value: 1}, syncEvent(),
+		{Type: evKey, Code: KeyD, Value: 1}, {Type: evSyn, Code: synDropped}, {Type: evKey, Code: KeyE, Value: 1}, syncEvent(),
+		{Type: evKey, Code: KeyA, Value: 0}, {Type: evKey, Code: KeyF, Value: 1}, syncEvent(),
+	} {
+		buf = appendInputEvent(buf, iev)
+	}
+	_, err = writer.Write(buf)
+	if err != nil {
+		t.Fatalf("Failed to write events: %v", err)
+	}
+	writer.Close()
+
+	target := &frameRecorder{}
+	err = forwardFrames(reader, target, filter)
+	if err != nil {
+		t.Fatalf("Failed to forward frames: %v", err)
+	}
+	expected := [][]InputEvent{
+		{{Type: EvKey, Code: KeyB, Value: 1}},
+		{{Type: EvKey, Code: KeyB, Value: 0}, {Type: EvKey, Code: KeyF, Value: 1}},
+	}
+	if !reflect.DeepEqual(target.frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, target.frames)
+	}
+}
+
+func TestChainFiltersStopsOnceDropped(t *testing.T) {
+	calls := 0
+	drop := func([]InputEvent) []InputEvent { calls++; return nil }
+	keep := func(frame []InputEvent) []InputEvent { calls++; return frame }
+
+	frame := []InputEvent{{Type: EvKey, Code: KeyA, Value: 1}}
+	if out := ChainFilters(keep, keep)(frame); !reflect.DeepEqual(out, frame) {
+		t.Fatalf("Expected the frame to pass, got %v", out)
+	}
+	calls = 0
+	if out := ChainFilters(drop, keep)(frame); len(out) != 0 || calls != 1 {
+		t.Fatalf("Expected the frame to be dropped by the first filter, got %v after %d calls", out, calls)
+	}
+}
+
+func TestCreateCloneRegistersCapabilities(t *testing.T) {
+	backend := &mockBackend{}
+	caps := evdevCaps{
+		id:    inputID{Bustype: busUsb, Vendor: 0x046d, Product: 0xc52b, Version: 1},
+		types: []uint16{evKey, evRel, EvRep},
+		codes: map[uint16][]uint16{evKey: {evBtnLeft, evBtnRight}, evRel: {relX, relY}},
+		abs:   map[uint16]inputAbsinfo{},
+	}
+	fd, err := createClone("mock", []byte("Test Clone"), caps, newDeviceConfig([]Option{WithBackend(backend)}))
+	if err != nil {
+		t.Fatalf("Failed to create the clone: %v", err)
+	}
+	defer closeDevice(fd)
+
+	for _, cmd := range []uintptr{uiSetEvBit, uiSetKeyBit, uiSetRelBit, uiDevCreate} {
+		if !backend.file.issued(cmd) {
+			t.Fatalf("Expected request %#x to be issued", cmd)
+		}
+	}
+	if backend.file.issued(uiSetAbsBit) {
+		t.Fatalf("Expected no absolute axes to be registered")
+	}
+}
+
+func TestCreateCloneAcceptsAxesWithoutRange(t *testing.T) {
+	backend := &mockBackend{}
+	caps := evdevCaps{
+		id:    inputID{Bustype: busUsb, Vendor: 0x045e, Product: 0x028e, Version: 1},
+		types: []uint16{evKey, evAbs},
+		codes: map[uint16][]uint16{evKey: {BtnSouth}, evAbs: {absX, AbsMisc}},
+		abs: map[uint16]inputAbsinfo{
+			absX:    {Minimum: -32768, Maximum: 32767, Fuzz: 16, Flat: 128},
+			AbsMisc: {Resolution: 1},
+		},
+	}
+	fd, err := createClone("mock", []byte("Test Clone"), caps, newDeviceConfig([]Option{WithBackend(backend)}))
+	if err != nil {
+		t.Fatalf("Failed to create the clone of a device with an unused axis: %v", err)
+	}
+	defer closeDevice(fd)
+	if !backend.file.issued(uiSetAbsBit) {
+		t.Fatalf("Expected the absolute axes to be registered")
+	}
+}
@@ -0,0 +1,329 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// the ids of the Logitech G29, which racing sims and SDL recognize as a wheel
+const (
+	g29Vendor  = 0x046d
+	g29Product = 0xc24f
+)
+
+// the axis ranges of racing wheels. The steering axis is centered in the middle of its range, while the pedals are
+// released at 0.
+const (
+	steeringMin = -32768
+	steeringMax = 32767
+	pedalMax    = 255
+)
+
+// the range of rotation of racing wheels (lock to lock, in degrees)
+const (
+	defaultWheelRotation = 900
+	maxWheelRotation     = 3600
+)
+
+// the axis codes of racing wheels
+const (
+	WheelAxisSteering = absX
+	WheelAxisClutch   = absY
+	WheelAxisThrottle = absZ
+	WheelAxisBrake    = absRZ
+)
+
+// the buttons of racing wheels. The paddle shifters are mapped to the gear buttons, while the gears of the H-pattern
+// shifter (including reverse) are mapped to separate buttons, just like the shifters of real wheels.
+const (
+	WheelShiftUp      = BtnGearUp
+	WheelShiftDown    = BtnGearDown
+	WheelGear1        = BtnTriggerHappy1
+	WheelGear2        = BtnTriggerHappy2
+	WheelGear3        = BtnTriggerHappy3
+	WheelGear4        = BtnTriggerHappy4
+	WheelGear5        = BtnTriggerHappy5
+	WheelGear6        = BtnTriggerHappy6
+	WheelGearReverse  = BtnTriggerHappy7
+	WheelButtonSelect = ButtonSelect
+	WheelButtonStart  = ButtonStart
+)
+
+var wheelGears = []int{WheelGear1, WheelGear2, WheelGear3, WheelGear4, WheelGear5, WheelGear6}
+
+var wheelButtons = append([]int{WheelShiftUp, WheelShiftDown, WheelGearReverse, WheelButtonSelect, WheelButtonStart},
+	wheelGears...)
+
+var wheelAxes = []int{WheelAxisSteering, WheelAxisClutch, WheelAxisThrottle, WheelAxisBrake}
+
+// wheelEffects are the force feedback effects that racing wheels announce unless specified otherwise
+var wheelEffects = []uint16{FFConstant, FFSpring, FFDamper, FFFriction, FFPeriodic}
+
+// A RacingWheel is a steering wheel with pedals and shifters. It uses the identity of a Logitech G29 and announces
+// force feedback capabilities, so that racing sims detect it as a wheel rather than a generic joystick.
+type RacingWheel interface {
+	// SetSteering will turn the wheel to the given angle (in degrees, positive values turn right). The angle is
+	// expected to be within the range of rotation, e.g. -450 to 450 for a 900° wheel.
+	SetSteering(degrees float64) error
+
+	// SetThrottle will set the throttle pedal to the given position, ranging from 0 (released) to 1 (fully pressed).
+	SetThrottle(value float64) error
+
+	// SetBrake will set the brake pedal to the given position, ranging from 0 (released) to 1 (fully pressed).
+	SetBrake(value float64) error
+
+	// SetClutch will set the clutch pedal to the given position, ranging from 0 (released) to 1 (fully pressed).
+	SetClutch(value float64) error
+
+	// SetGear will engage the given gear of the H-pattern shifter (1 to 6, or -1 for reverse), while 0 returns the
+	// shifter to neutral. The previous gear is released within the same frame.
+	SetGear(gear int) error
+
+	// ShiftUp will pull the right paddle shifter and immediately release it.
+	ShiftUp() error
+
+	// ShiftDown will pull the left paddle shifter and immediately release it.
+	ShiftDown() error
+
+	// ButtonDown will press the given button (see the Wheel constants). Note that the button remains pressed until
+	// ButtonUp is called.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button.
+	ButtonUp(button int) error
+
+	Device
+}
+
+type vRacingWheel struct {
+	name       []byte
+	deviceFile *uinputDevice
+	rotation   int
+
+	// mu guards the engaged gear, which needs to be updated along with the events that are sent
+	mu   sync.Mutex
+	gear int
+}
+
+// CreateRacingWheel will create a new racing wheel with the given range of rotation (lock to lock, in degrees). A
+// rotation of 0 defaults to 900°. Effects uploaded by applications are passed to the callbacks defined in ff. If ff
+// does not list any effects, constant, spring, damper, friction and periodic effects are announced.
+func CreateRacingWheel(path string, name []byte, rotation int, ff ForceFeedback, opts ...Option) (RacingWheel, error) {
+	if rotation == 0 {
+		rotation = defaultWheelRotation
+	}
+	if rotation < 0 || rotation > maxWheelRotation {
+		return nil, errorf(ErrInvalidArgument, "rotation of %d° is out of range. Expected a value between 1 and %d", rotation, maxWheelRotation)
+	}
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(ff.Effects) == 0 {
+		ff.Effects = wheelEffects
+	}
+	err = validateForceFeedback(ff)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateRacingWheel", opts)
+	if err != nil {
+		return nil, err
+	}
+	cfg = cfg.withForceFeedback(&ff)
+	fd, err := createRacingWheel(path, name, &ff, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vRacingWheel{name: name, deviceFile: fd, rotation: rotation}, nil
+}
+
+// SetSteering will turn the wheel to the given angle.
+func (vw *vRacingWheel) SetSteering(degrees float64) error {
+	lock := float64(vw.rotation) / 2
+	if math.IsNaN(degrees) || degrees < -lock || degrees > lock {
+		return errorf(ErrInvalidArgument, "failed to perform SetSteering. %v° is out of range. Expected a value between %v and %v", degrees, -lock, lock)
+	}
+	value := int32(math.Round(degrees / lock * steeringMax))
+	return sendEvents(vw.deviceFile, []inputEvent{{Type: evAbs, Code: WheelAxisSteering, Value: value}})
+}
+
+// SetThrottle will set the throttle pedal to the given position.
+func (vw *vRacingWheel) SetThrottle(value float64) error {
+	return sendPedalEvent(vw.deviceFile, WheelAxisThrottle, value)
+}
+
+// SetBrake will set the brake pedal to the given position.
+func (vw *vRacingWheel) SetBrake(value float64) error {
+	return sendPedalEvent(vw.deviceFile, WheelAxisBrake, value)
+}
+
+// SetClutch will set the clutch pedal to the given position.
+func (vw *vRacingWheel) SetClutch(value float64) error {
+	return sendPedalEvent(vw.deviceFile, WheelAxisClutch, value)
+}
+
+// SetGear will engage the given gear of the H-pattern shifter.
+func (vw *vRacingWheel) SetGear(gear int) error {
+	next, err := gearButton(gear)
+	if err != nil {
+		return err
+	}
+	vw.mu.Lock()
+	defer vw.mu.Unlock()
+	prev, _ := gearButton(vw.gear)
+
+	var events []inputEvent
+	if prev != 0 && prev != next {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(prev), Value: btnStateReleased})
+	}
+	if next != 0 && prev != next {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(next), Value: btnStatePressed})
+	}
+	if len(events) > 0 {
+		err = sendEvents(vw.deviceFile, events)
+		if err != nil {
+			return fmt.Errorf("failed to shift gears: %w", err)
+		}
+	}
+	vw.gear = gear
+	return nil
+}
+
+// ShiftUp will pull the right paddle shifter and immediately release it.
+func (vw *vRacingWheel) ShiftUp() error {
+	return pressKey(vw.deviceFile, WheelShiftUp)
+}
+
+// ShiftDown will pull the left paddle shifter and immediately release it.
+func (vw *vRacingWheel) ShiftDown() error {
+	return pressKey(vw.deviceFile, WheelShiftDown)
+}
+
+// ButtonDown will press the given button.
+func (vw *vRacingWheel) ButtonDown(button int) error {
+	if !codeSupported(wheelButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a racing wheel button", button)
+	}
+	return sendBtnEvent(vw.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vw *vRacingWheel) ButtonUp(button int) error {
+	if !codeSupported(wheelButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a racing wheel button", button)
+	}
+	return sendBtnEvent(vw.deviceFile, []int{button}, btnStateReleased)
+}
+
+func (vw *vRacingWheel) Name() string {
+	return string(vw.name)
+}
+
+func (vw *vRacingWheel) Path() string {
+	return devicePath(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) Fd() uintptr {
+	return deviceFd(vw.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vw *vRacingWheel) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vw.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vw *vRacingWheel) EmitEvents(events []InputEvent) error {
+	return emitEvents(vw.deviceFile, events)
+}
+
+func (vw *vRacingWheel) Sync() error {
+	return writeSyncEvent(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) SysPath() (string, error) {
+	return sysPath(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) EventPath() (string, error) {
+	return eventPath(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vw.deviceFile)
+}
+
+func (vw *vRacingWheel) Stats() Stats {
+	return deviceStats(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) State() State {
+	return deviceState(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) ResetAll() error {
+	return resetDevice(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) Disconnect() error {
+	return disconnectDevice(vw.deviceFile)
+}
+
+func (vw *vRacingWheel) Reconnect() error {
+	return reconnectDevice(vw.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vw *vRacingWheel) Close() error {
+	return closeDevice(vw.deviceFile)
+}
+
+// gearButton returns the button of the given gear, or 0 for neutral.
+func gearButton(gear int) (int, error) {
+	switch {
+	case gear == 0:
+		return 0, nil
+	case gear == -1:
+		return WheelGearReverse, nil
+	case gear >= 1 && gear <= len(wheelGears):
+		return wheelGears[gear-1], nil
+	}
+	return 0, errorf(ErrInvalidArgument, "failed to perform SetGear. Gear %d is out of range. Expected a value between -1 and %d", gear, len(wheelGears))
+}
+
+func sendPedalEvent(deviceFile *uinputDevice, code uint16, value float64) error {
+	if math.IsNaN(value) || value < 0 || value > 1 {
+		return errorf(ErrInvalidArgument, "%v is out of range. Expected a value between 0 and 1", value)
+	}
+	return sendEvents(deviceFile, []inputEvent{{Type: evAbs, Code: code, Value: int32(math.Round(value * pedalMax))}})
+}
+
+func createRacingWheel(path string, name []byte, ff *ForceFeedback, cfg deviceConfig) (fd *uinputDevice, err error) {
+	var absMin, absMax [absSize]int32
+	absMin[WheelAxisSteering] = steeringMin
+	absMax[WheelAxisSteering] = steeringMax
+	for _, pedal := range []int{WheelAxisClutch, WheelAxisThrottle, WheelAxisBrake} {
+		absMax[pedal] = pedalMax
+	}
+
+	return createAxisDevice(path, wheelButtons, wheelAxes, ff,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  g29Vendor,
+				Product: g29Product,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+}
@@ -0,0 +1,136 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestBasicRacingWheel(t *testing.T) {
+	wheel, err := CreateRacingWheel("/dev/uinput", []byte("Test Racing Wheel"), 900, ForceFeedback{})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual racing wheel. Last error was: %s\n", err)
+	}
+
+	err = wheel.SetSteering(-90)
+	if err != nil {
+		t.Fatalf("Failed to steer. Last error was: %s\n", err)
+	}
+
+	err = wheel.SetThrottle(0.75)
+	if err != nil {
+		t.Fatalf("Failed to set the throttle. Last error was: %s\n", err)
+	}
+
+	err = wheel.SetGear(1)
+	if err != nil {
+		t.Fatalf("Failed to shift gears. Last error was: %s\n", err)
+	}
+
+	err = wheel.ShiftUp()
+	if err != nil {
+		t.Fatalf("Failed to shift up. Last error was: %s\n", err)
+	}
+
+	err = wheel.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestRacingWheelCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateRacingWheel("", []byte("RacingWheel"), 0, ForceFeedback{})
+	assertPathDiscovered(t, device, err)
+}
+
+func TestRacingWheelCreationFailsOnInvalidRotation(t *testing.T) {
+	_, err := CreateRacingWheel("/dev/uinput", []byte("RacingWheel"), -1, ForceFeedback{})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a negative rotation, got: %v", err)
+	}
+}
+
+func newTestRacingWheel(t *testing.T, rotation int) (*vRacingWheel, *os.File) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-racingwheel-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	return &vRacingWheel{name: []byte("Test Racing Wheel"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), rotation: rotation}, file
+}
+
+func TestSteeringIsScaledToRotationRange(t *testing.T) {
+	wheel, file := newTestRacingWheel(t, 900)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	for _, degrees := range []float64{450, -225, 0} {
+		err := wheel.SetSteering(degrees)
+		if err != nil {
+			t.Fatalf("Failed to steer: %v", err)
+		}
+	}
+	err := wheel.SetSteering(451)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an angle beyond the lock, got: %v", err)
+	}
+	err = wheel.SetBrake(1)
+	if err != nil {
+		t.Fatalf("Failed to brake: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: WheelAxisSteering, Value: steeringMax},
+		{Type: evAbs, Code: WheelAxisSteering, Value: -16384},
+		{Type: evAbs, Code: WheelAxisSteering, Value: 0},
+		{Type: evAbs, Code: WheelAxisBrake, Value: pedalMax},
+	}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestSetGearReleasesPreviousGear(t *testing.T) {
+	wheel, file := newTestRacingWheel(t, 900)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	for _, gear := range []int{1, 2, 2, -1, 0} {
+		err := wheel.SetGear(gear)
+		if err != nil {
+			t.Fatalf("Failed to shift gears: %v", err)
+		}
+	}
+	err := wheel.SetGear(7)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unknown gear, got: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: WheelGear1, Value: btnStatePressed},
+		{Type: evKey, Code: WheelGear1, Value: btnStateReleased},
+		{Type: evKey, Code: WheelGear2, Value: btnStatePressed},
+		{Type: evKey, Code: WheelGear2, Value: btnStateReleased},
+		{Type: evKey, Code: WheelGearReverse, Value: btnStatePressed},
+		{Type: evKey, Code: WheelGearReverse, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestToFFEffectConvertsConstantForce(t *testing.T) {
+	raw := ffEffect{Type: ffConstant, ID: 1, Direction: 0x4000, Replay: ffReplay{Length: 1000}}
+	constant := (*ffConstantEffect)(unsafe.Pointer(&raw.U))
+	constant.Level = -0x2000
+	constant.Envelope.FadeLength = 100
+
+	effect := toFFEffect(raw)
+	expected := FFEffect{Type: FFConstant, ID: 1, Direction: 0x4000, Length: 1000,
+		Constant: FFConstantEffect{Level: -0x2000, Envelope: FFEnvelope{FadeLength: 100}}}
+	if effect != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, effect)
+	}
+}
@@ -0,0 +1,125 @@
+package uinput
+
+import (
+	"time"
+	"unsafe"
+)
+
+// A rateLimiter spaces the frames of a device by a minimum interval. Frames that only move axes and arrive too early
+// are coalesced into a pending frame, which is written once the interval has passed: absolute axes keep their latest
+// value, while the values of relative axes add up. All other frames are delayed until the interval has passed, since
+// presses and releases cannot be coalesced without losing them. All methods expect the lock of the device to be held,
+// which is released while a frame is delayed.
+type rateLimiter struct {
+	interval time.Duration
+	last     time.Time
+	pending  []inputEvent
+	timer    *time.Timer
+	waiting  int   // the number of delayed frames, which write the pending frame along with them
+	err      error // the error of the last deferred frame, which is returned by the next submitted frame
+}
+
+// newRateLimiter returns the rate limiter of a device with the given config, if any. Devices created using
+// WithManualSync are not limited, as they decide on their frames themselves.
+func newRateLimiter(cfg deviceConfig) *rateLimiter {
+	if cfg.rateInterval <= 0 || cfg.manualSync {
+		return nil
+	}
+	return &rateLimiter{interval: cfg.rateInterval}
+}
+
+// submit writes the encoded frame (which lacks the sync event) as soon as the interval allows.
+func (l *rateLimiter) submit(deviceFile *uinputDevice, buf []byte) error {
+	if err := l.err; err != nil {
+		l.err = nil
+		return err
+	}
+	now := time.Now()
+	next := l.last.Add(l.interval)
+	if now.Before(next) && axesOnly(buf) {
+		l.coalesce(buf)
+		if l.timer == nil && l.waiting == 0 {
+			var timer *time.Timer
+			timer = time.AfterFunc(next.Sub(now), func() {
+				deviceFile.mu.Lock()
+				defer deviceFile.mu.Unlock()
+				if l.timer != timer {
+					// the pending frame has been written in the meantime
+					return
+				}
+				l.err = l.flush(deviceFile, nil)
+			})
+			l.timer = timer
+		}
+		return nil
+	}
+	if now.Before(next) {
+		// the lock is released while waiting, so that other callers (like Close) are not blocked by the delay. The
+		// buffer of the device may be reused in the meantime, and other frames may have been written once the lock is
+		// taken again. The pending frame is written along with the delayed frame, rather than by its timer.
+		buf = append([]byte(nil), buf...)
+		if l.timer != nil {
+			l.timer.Stop()
+			l.timer = nil
+		}
+		l.waiting++
+		for now.Before(next) {
+			deviceFile.mu.Unlock()
+			<-time.After(next.Sub(now))
+			deviceFile.mu.Lock()
+			now, next = time.Now(), l.last.Add(l.interval)
+		}
+		l.waiting--
+	}
+	return l.flush(deviceFile, buf)
+}
+
+// flush writes the pending frame along with the given encoded events as a single frame.
+func (l *rateLimiter) flush(deviceFile *uinputDevice, buf []byte) error {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 && len(buf) == 0 {
+		return nil
+	}
+	frame := make([]byte, 0, (len(l.pending)+1)*inputEventSize+len(buf))
+	for _, iev := range l.pending {
+		frame = appendInputEvent(frame, iev)
+	}
+	frame = append(frame, buf...)
+	l.pending = l.pending[:0]
+	l.last = time.Now()
+	return writeEncoded(deviceFile, frame)
+}
+
+// coalesce merges the axis events of the encoded frame into the pending frame.
+func (l *rateLimiter) coalesce(buf []byte) {
+events:
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[i]))
+		for j := range l.pending {
+			if l.pending[j].Type == iev.Type && l.pending[j].Code == iev.Code {
+				if iev.Type == evRel {
+					l.pending[j].Value += iev.Value
+				} else {
+					l.pending[j] = iev
+				}
+				continue events
+			}
+		}
+		l.pending = append(l.pending, iev)
+	}
+}
+
+// axesOnly reports whether the encoded frame only contains events of absolute and relative axes. Multitouch axes are
+// not coalesced, as their meaning depends on the slot that has been selected before.
+func axesOnly(buf []byte) bool {
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		if (iev.Type != evAbs || iev.Code >= absMtSlot) && iev.Type != evRel {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// writtenFrames decodes the frames that have been written to the mock file since the last reset, leaving out the
+// sync events.
+func writtenFrames(f *mockFile) [][]inputEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	buf := f.writes.Bytes()
+	var frames [][]inputEvent
+	var frame []inputEvent
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[i]))
+		if iev.Type == evSyn {
+			frames, frame = append(frames, frame), nil
+			continue
+		}
+		frame = append(frame, iev)
+	}
+	return frames
+}
+
+func createLimitedMouse(t *testing.T, interval time.Duration) (Mouse, *mockBackend) {
+	backend := &mockBackend{}
+	mouse, err := CreateMouse("mock", []byte("Test Mouse"), WithBackend(backend), WithRateLimit(interval))
+	if err != nil {
+		t.Fatalf("Failed to create the mouse: %v", err)
+	}
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	return mouse, backend
+}
+
+func TestRateLimitCoalescesAxisMovements(t *testing.T) {
+	mouse, backend := createLimitedMouse(t, 50*time.Millisecond)
+	defer mouse.Close()
+
+	for i := 0; i < 3; i++ {
+		err := mouse.Move(2, -1)
+		if err != nil {
+			t.Fatalf("Failed to move: %v", err)
+		}
+	}
+	if frames := writtenFrames(backend.file); len(frames) != 1 {
+		t.Fatalf("Expected only the first frame to be written right away, got %v", frames)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	frames := writtenFrames(backend.file)
+	if len(frames) != 2 {
+		t.Fatalf("Expected the coalesced frame to be written after the interval, got %v", frames)
+	}
+	if len(frames[1]) != 2 || frames[1][0].Value != 4 || frames[1][1].Value != -2 {
+		t.Fatalf("Expected the movements to add up, got %v", frames[1])
+	}
+}
+
+func TestRateLimitDelaysPresses(t *testing.T) {
+	mouse, backend := createLimitedMouse(t, 30*time.Millisecond)
+
+	start := time.Now()
+	err := mouse.Move(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	err = mouse.Move(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	err = mouse.LeftClick()
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("Expected the press and release to wait for the interval, took %v", elapsed)
+	}
+
+	frames := writtenFrames(backend.file)
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %v", frames)
+	}
+	if len(frames[1]) != 3 || frames[1][0].Type != evRel || frames[1][2].Type != evKey || frames[1][2].Value != 1 {
+		t.Fatalf("Expected the pending movement to be written along with the press, got %v", frames[1])
+	}
+
+	err = mouse.Move(5, 0)
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	err = mouse.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the mouse: %v", err)
+	}
+	if frames := writtenFrames(backend.file); len(frames) != 4 {
+		t.Fatalf("Expected the pending movement to be written upon closing, got %v", frames)
+	}
+}
+
+func TestRateLimitDoesNotBlockWhileDelaying(t *testing.T) {
+	mouse, backend := createLimitedMouse(t, 200*time.Millisecond)
+	defer mouse.Close()
+
+	err := mouse.LeftPress()
+	if err != nil {
+		t.Fatalf("Failed to press: %v", err)
+	}
+	released := make(chan error, 1)
+	go func() { released <- mouse.RightPress() }()
+	time.Sleep(20 * time.Millisecond)
+
+	// the delayed press must not hold the lock of the device, which is needed by the reset
+	start := time.Now()
+	err = mouse.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("Expected the reset not to wait for the delayed frame, took %v", elapsed)
+	}
+	err = <-released
+	if err != nil {
+		t.Fatalf("Failed to press: %v", err)
+	}
+	if frames := writtenFrames(backend.file); len(frames) != 3 || frames[2][0].Code != evBtnRight {
+		t.Fatalf("Expected the delayed press to be written after the reset, got %v", frames)
+	}
+}
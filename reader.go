@@ -0,0 +1,97 @@
+package uinput
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// an eventHandler handles an event that the kernel sent to a device (like an LED being switched or a force feedback
+// effect being uploaded by an application). The device file is passed along, as some requests need to be
+// acknowledged via ioctls.
+type eventHandler func(deviceFile DeviceFile, iev inputEvent)
+
+// eventHandlers are the handlers of a device by event type, along with the functions that are called once the device
+// has been closed and no more events will be handled.
+type eventHandlers struct {
+	byType  map[uint16][]eventHandler
+	closers []func()
+}
+
+func (h eventHandlers) empty() bool {
+	return len(h.byType) == 0 && len(h.closers) == 0
+}
+
+// withHandler returns a copy of the config that additionally passes events of the given type to the handler.
+func (cfg deviceConfig) withHandler(evType uint16, handler eventHandler) deviceConfig {
+	byType := make(map[uint16][]eventHandler, len(cfg.handlers.byType)+1)
+	for t, handlers := range cfg.handlers.byType {
+		byType[t] = handlers
+	}
+	byType[evType] = append(byType[evType][:len(byType[evType]):len(byType[evType])], handler)
+	cfg.handlers.byType = byType
+	return cfg
+}
+
+// withCloser returns a copy of the config that additionally calls the given function once the device has been closed.
+func (cfg deviceConfig) withCloser(closer func()) deviceConfig {
+	closers := cfg.handlers.closers
+	cfg.handlers.closers = append(closers[:len(closers):len(closers)], closer)
+	return cfg
+}
+
+// dispatchEvents reads the events that the kernel sends to the device from the device file and passes them to the
+// registered handlers, until the device is closed. Handlers are called sequentially from a single goroutine, which
+// is why they should not block. Otherwise, further events (and the applications sending them) are delayed.
+func dispatchEvents(deviceFile DeviceFile, handlers eventHandlers) {
+	defer func() {
+		for _, closer := range handlers.closers {
+			closer()
+		}
+	}()
+	buf := make([]byte, inputEventSize)
+	for {
+		n, err := deviceFile.Read(buf)
+		if err != nil {
+			return
+		}
+		if n < inputEventSize {
+			continue
+		}
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[0]))
+		for _, handler := range handlers.byType[iev.Type] {
+			handler(deviceFile, iev)
+		}
+	}
+}
+
+// registerReadCodes registers the LED and sound codes that handlers have been added for (see WithEventHandler), so
+// that consumers of the device may send them.
+func registerReadCodes(deviceFile DeviceFile, codes map[uint16][]uint16) error {
+	for evType, evCodes := range codes {
+		var bit uintptr
+		var max uint16
+		switch evType {
+		case EvLed:
+			bit, max = uiSetLedBit, ledMax
+		case EvSnd:
+			bit, max = uiSetSndBit, sndMax
+		default:
+			return errorf(ErrInvalidArgument, "failed to register event handler. Events of type %d cannot be sent to a device", evType)
+		}
+
+		err := ioctl(deviceFile, uiSetEvBit, uintptr(evType))
+		if err != nil {
+			return fmt.Errorf("failed to register event type %d: %w", evType, err)
+		}
+		for _, code := range evCodes {
+			if code > max {
+				return errorf(ErrInvalidArgument, "failed to register code %d of event type %d. Expected a code up to %d", code, evType, max)
+			}
+			err = ioctl(deviceFile, bit, uintptr(code))
+			if err != nil {
+				return fmt.Errorf("failed to register code %d of event type %d: %w", code, evType, err)
+			}
+		}
+	}
+	return nil
+}
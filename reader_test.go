@@ -0,0 +1,76 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func writeTestEvents(t *testing.T, writer *os.File, events []inputEvent) {
+	for _, iev := range events {
+		_, err := writer.Write((*[inputEventSize]byte)(unsafe.Pointer(&iev))[:])
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+}
+
+func TestEventsAreDispatchedByType(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer reader.Close()
+
+	var leds, sounds []InputEvent
+	done := make(chan struct{})
+	cfg := newDeviceConfig([]Option{
+		WithEventHandler(EvLed, func(event InputEvent) { leds = append(leds, event) }, LedCapsl),
+		WithEventHandler(EvSnd, func(event InputEvent) { sounds = append(sounds, event) }, SndBell),
+	}).withCloser(func() { close(done) })
+	go dispatchEvents(tempFile{reader}, cfg.handlers)
+
+	writeTestEvents(t, writer, []inputEvent{
+		{Type: EvLed, Code: LedCapsl, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: EvSnd, Code: SndBell, Value: 1},
+		{Type: EvLed, Code: LedCapsl, Value: 0},
+	})
+	writer.Close()
+	<-done
+
+	expectedLeds := []InputEvent{{Type: EvLed, Code: LedCapsl, Value: 1}, {Type: EvLed, Code: LedCapsl, Value: 0}}
+	if !reflect.DeepEqual(leds, expectedLeds) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expectedLeds, leds)
+	}
+	expectedSounds := []InputEvent{{Type: EvSnd, Code: SndBell, Value: 1}}
+	if !reflect.DeepEqual(sounds, expectedSounds) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expectedSounds, sounds)
+	}
+	if !reflect.DeepEqual(cfg.readCodes, map[uint16][]uint16{EvLed: {LedCapsl}, EvSnd: {SndBell}}) {
+		t.Fatalf("Expected the codes of the handlers to be registered, got %v", cfg.readCodes)
+	}
+}
+
+func TestHandlersAreNotSharedBetweenConfigs(t *testing.T) {
+	base := deviceConfig{}.withHandler(EvLed, func(DeviceFile, inputEvent) {})
+	first := base.withHandler(EvLed, func(DeviceFile, inputEvent) {})
+	second := base.withCloser(func() {})
+
+	if len(base.handlers.byType[EvLed]) != 1 || len(base.handlers.closers) != 0 {
+		t.Fatalf("Expected the base config to remain unchanged, got %+v", base.handlers)
+	}
+	if len(first.handlers.byType[EvLed]) != 2 || len(second.handlers.closers) != 1 {
+		t.Fatalf("Expected the handlers to be added to the copies")
+	}
+}
+
+func TestEventHandlerForUnsupportedTypeFails(t *testing.T) {
+	cfg := newDeviceConfig([]Option{WithEventHandler(EvKey, func(InputEvent) {})})
+	err := registerReadCodes(nil, cfg.readCodes)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package uinput
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// A Recorder writes all events emitted by the devices it has been attached to (see WithRecorder) to a writer, along
+// with the time they have been emitted at. The events are written in the event format of evemu-record (one event per
+// line, e.g. "E: 0.016000 0002 0000 5"), so that recordings may be inspected, diffed and edited easily. Timestamps
+// are relative to the first recorded event. A recorder may be shared between several devices, which makes the
+// timestamps of all devices comparable.
+type Recorder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	err   error
+}
+
+// NewRecorder will create a new recorder that writes to w. Note that writes are not buffered.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// WithRecorder records all events emitted by the device using the given recorder (including sync events).
+func WithRecorder(r *Recorder) Option {
+	return func(cfg *deviceConfig) {
+		cfg.recorder = r
+	}
+}
+
+// Err will return the first error that occurred while writing the recording. Once an error occurred, no more events
+// are recorded. Recording errors do not affect the devices.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// record records the encoded events of a single write to a device.
+func (r *Recorder) record(b []byte) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if r.start.IsZero() {
+		r.start = now
+	}
+	offset := now.Sub(r.start)
+	for i := 0; i+inputEventSize <= len(b); i += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&b[i]))
+		_, err := fmt.Fprintf(r.w, "E: %d.%06d %04x %04x %d\n", offset/time.Second, offset%time.Second/time.Microsecond,
+			iev.Type, iev.Code, iev.Value)
+		if err != nil {
+			r.err = fmt.Errorf("failed to record event: %w", err)
+			return
+		}
+	}
+}
+
+// A RecordedEvent is an event of a recording, along with the time it has been emitted at (relative to the start of the
+// recording).
+type RecordedEvent struct {
+	Time time.Duration
+	InputEvent
+}
+
+// ReadRecording will read all events from a recording (see Recorder). Lines that do not describe an event (like
+// comments or the device description of evemu recordings) are skipped.
+func ReadRecording(r io.Reader) ([]RecordedEvent, error) {
+	var events []RecordedEvent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "E:") {
+			continue
+		}
+		var sec, usec int64
+		var ev RecordedEvent
+		_, err := fmt.Sscanf(text, "E: %d.%d %x %x %d", &sec, &usec, &ev.Type, &ev.Code, &ev.Value)
+		if err != nil {
+			return nil, errorf(ErrInvalidArgument, "failed to read recording. Line %d is not a valid event: %w", line, err)
+		}
+		ev.Time = time.Duration(sec)*time.Second + time.Duration(usec)*time.Microsecond
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return events, nil
+}
+
+// Replay will read the recording from r and emit its events through the given device, which may be any device that
+// provides the recorded event codes. Events are emitted frame by frame (as terminated by the recorded sync events),
+// keeping the recorded timing (see Timeline). The speed scales the pace of the replay, e.g. 2 replays twice as fast,
+// while math.Inf(1) replays without any delay.
+func Replay(r io.Reader, device EventEmitter, speed float64) error {
+	return ReplayContext(context.Background(), r, device, speed)
+}
+
+// ReplayContext will replay the recording just like Replay, but stops once the context is done.
+func ReplayContext(ctx context.Context, r io.Reader, device EventEmitter, speed float64) error {
+	if !(speed > 0) {
+		return errorf(ErrInvalidArgument, "failed to replay. A speed of %v is out of range. Expected a positive speed", speed)
+	}
+	events, err := ReadRecording(r)
+	if err != nil {
+		return err
+	}
+
+	timeline := NewTimeline()
+	var frame []InputEvent
+	var offset time.Duration
+	for _, ev := range events {
+		if ev.Type != evSyn || ev.Code != synReport {
+			frame = append(frame, ev.InputEvent)
+			continue
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		if !math.IsInf(speed, 1) {
+			offset = time.Duration(float64(ev.Time) / speed)
+		}
+		timeline.At(offset, frame...)
+		frame = nil
+	}
+	if len(frame) > 0 {
+		// events after the last sync event are emitted right after the last frame
+		timeline.At(offset, frame...)
+	}
+
+	err = timeline.PlayContext(ctx, device)
+	if err != nil {
+		return fmt.Errorf("failed to replay: %w", err)
+	}
+	return nil
+}
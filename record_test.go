@@ -0,0 +1,117 @@
+package uinput
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// frameRecorder is an event emitter that records the emitted frames
+type frameRecorder struct {
+	frames [][]InputEvent
+}
+
+func (f *frameRecorder) EmitEvents(events []InputEvent) error {
+	f.frames = append(f.frames, append([]InputEvent(nil), events...))
+	return nil
+}
+
+func TestRecorderRecordsEmittedEvents(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-record-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	var out bytes.Buffer
+	recorder := NewRecorder(&out)
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig([]Option{WithRecorder(recorder)}))
+	err = sendRelEvent(dev, relX, 5)
+	if err != nil {
+		t.Fatalf("Failed to send event: %v", err)
+	}
+	err = sendBtnEvent(dev, []int{evBtnLeft}, btnStatePressed)
+	if err != nil {
+		t.Fatalf("Failed to send event: %v", err)
+	}
+	if recorder.Err() != nil {
+		t.Fatalf("Failed to record events: %v", recorder.Err())
+	}
+
+	events, err := ReadRecording(&out)
+	if err != nil {
+		t.Fatalf("Failed to read the recording: %v", err)
+	}
+	if events[0].Time != 0 {
+		t.Fatalf("Expected the recording to start at 0, got %v", events[0].Time)
+	}
+	var recorded []InputEvent
+	for _, ev := range events {
+		recorded = append(recorded, ev.InputEvent)
+	}
+	expected := []InputEvent{
+		{Type: evRel, Code: relX, Value: 5},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evBtnLeft, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+	}
+	if !reflect.DeepEqual(recorded, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, recorded)
+	}
+}
+
+func TestReplayEmitsRecordedFrames(t *testing.T) {
+	recording := `# EVEMU 1.3
+N: Test Device
+E: 0.000000 0002 0000 5
+E: 0.000000 0002 0001 -3
+E: 0.000000 0000 0000 0
+E: 0.008000 0001 0110 1
+E: 0.008000 0000 0000 0
+`
+	var emitter frameRecorder
+	err := Replay(strings.NewReader(recording), &emitter, math.Inf(1))
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+
+	expected := [][]InputEvent{
+		{{Type: evRel, Code: relX, Value: 5}, {Type: evRel, Code: relY, Value: -3}},
+		{{Type: evKey, Code: evBtnLeft, Value: 1}},
+	}
+	if !reflect.DeepEqual(emitter.frames, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, emitter.frames)
+	}
+}
+
+func TestReplayKeepsRecordedTimingAtGivenSpeed(t *testing.T) {
+	recording := "E: 0.000000 0002 0000 1\nE: 0.000000 0000 0000 0\nE: 0.100000 0002 0000 1\nE: 0.100000 0000 0000 0\n"
+	var emitter frameRecorder
+	start := time.Now()
+	err := Replay(strings.NewReader(recording), &emitter, 4)
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond || elapsed > 90*time.Millisecond {
+		t.Fatalf("Expected the replay to take about 25ms, took %v", elapsed)
+	}
+}
+
+func TestReplayRejectsInvalidInput(t *testing.T) {
+	var emitter frameRecorder
+	err := Replay(strings.NewReader(""), &emitter, 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a speed of 0, got: %v", err)
+	}
+	err = Replay(strings.NewReader("E: now 0002 0000 1\n"), &emitter, 1)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a malformed event, got: %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// the direction bits of ioctl numbers (see _IOC in asm-generic/ioctl.h), which tell requests that pass data to the
+// kernel apart from queries
+const (
+	iocDirShift  = 30
+	iocSizeShift = 16
+	iocSizeMask  = 0x3fff
+	iocWrite     = 1
+)
+
+// WithAutoRecreate makes the device recreate itself once writes fail because its device file is gone (ENODEV or
+// EBADF), which happens if the uinput module is reloaded, for instance. The device is created again with the same
+// configuration on a newly opened device file, and the failed write is repeated on it. Consumers see the recreated
+// device as a new device, which starts out with all keys released. Set Hooks.OnRecreate in order to be notified.
+func WithAutoRecreate() Option {
+	return func(cfg *deviceConfig) {
+		cfg.recreate = true
+	}
+}
+
+// deviceGone reports whether a failed write indicates that the device file is no longer usable.
+func deviceGone(err error) bool {
+	return errors.Is(err, syscall.ENODEV) || errors.Is(err, syscall.EBADF)
+}
+
+// a setupStep is a request that has been issued on the device file in order to create the device
+type setupStep struct {
+	cmd   uintptr
+	arg   uintptr
+	data  []byte // the data of pointer requests and writes
+	ptr   bool
+	write bool
+}
+
+// A recreatableFile records the requests that create the device, so that they may be replayed on a new device file
+// once the current one is gone (see WithAutoRecreate) or the device is reconnected (see Device.Reconnect). Reads of
+// event handlers are resumed on the new device file, which is why handlers keep working after the device has been
+// recreated.
+type recreatableFile struct {
+	path    string
+	backend Backend
+
+	mu           sync.Mutex
+	gone         *sync.Cond // signaled once the file has been replaced or closed
+	file         DeviceFile
+	setup        []setupStep
+	created      bool // whether the device has been created, after which no more requests are recorded
+	disconnected bool // whether the device has been destroyed by disconnect, in which case the file is closed
+	closed       bool
+}
+
+func newRecreatableFile(file DeviceFile, path string, backend Backend) *recreatableFile {
+	r := &recreatableFile{path: path, backend: backend, file: file}
+	r.gone = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *recreatableFile) current() DeviceFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file
+}
+
+// Read reads from the current device file. If the device file is gone or the device has been disconnected, Read waits
+// until the device file has been replaced and resumes reading from the new one.
+func (r *recreatableFile) Read(b []byte) (int, error) {
+	for {
+		file := r.current()
+		n, err := file.Read(b)
+		if err == nil {
+			return n, err
+		}
+		r.mu.Lock()
+		for r.file == file && !r.closed && (r.disconnected || deviceGone(err)) {
+			r.gone.Wait()
+		}
+		replaced := r.file != file && !r.closed
+		r.mu.Unlock()
+		if !replaced {
+			return n, err
+		}
+	}
+}
+
+func (r *recreatableFile) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disconnected {
+		return 0, errorf(ErrDisconnected, "failed to write to device")
+	}
+	n, err := r.file.Write(b)
+	if err == nil && !r.created {
+		r.setup = append(r.setup, setupStep{data: append([]byte(nil), b[:n]...), write: true})
+	}
+	return n, err
+}
+
+// Close closes the current device file, unless the device has been disconnected, which closed it already.
+func (r *recreatableFile) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.gone.Broadcast()
+	file, disconnected := r.file, r.disconnected
+	r.mu.Unlock()
+	if disconnected {
+		return nil
+	}
+	return file.Close()
+}
+
+func (r *recreatableFile) Name() string {
+	return r.current().Name()
+}
+
+func (r *recreatableFile) Fd() uintptr {
+	return r.current().Fd()
+}
+
+func (r *recreatableFile) Ioctl(cmd uintptr, arg uintptr) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disconnected {
+		if cmd == uiDevDestroy {
+			// the device has been destroyed upon disconnect already
+			return nil
+		}
+		return errorf(ErrDisconnected, "failed to issue request %#x", cmd)
+	}
+	err := r.file.Ioctl(cmd, arg)
+	if err == nil && !r.created {
+		r.setup = append(r.setup, setupStep{cmd: cmd, arg: arg})
+		r.created = cmd == uiDevCreate
+	}
+	return err
+}
+
+func (r *recreatableFile) IoctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disconnected {
+		return errorf(ErrDisconnected, "failed to issue request %#x", cmd)
+	}
+	err := r.file.IoctlPtr(cmd, arg)
+	// queries (like UI_GET_VERSION) need not be replayed, as their results have been used already
+	if err == nil && !r.created && cmd>>iocDirShift == iocWrite {
+		r.setup = append(r.setup, setupStep{cmd: cmd, data: requestData(cmd, arg), ptr: true})
+	}
+	return err
+}
+
+// requestData copies the data the request passes to the kernel. The strings of UI_SET_PHYS and UI_SET_UNIQ are
+// passed as pointers, while all other requests pass a struct of the size that is encoded in the request.
+func requestData(cmd uintptr, arg unsafe.Pointer) []byte {
+	if cmd == uiSetPhys || cmd == uiSetUniq {
+		str := (*[1 << 16]byte)(arg)
+		for i := range str {
+			if str[i] == 0 {
+				return append([]byte(nil), str[:i+1]...)
+			}
+		}
+	}
+	size := int(cmd >> iocSizeShift & iocSizeMask)
+	return append([]byte(nil), (*[iocSizeMask]byte)(arg)[:size:size]...)
+}
+
+// disconnect destroys the device and closes its device file, while keeping the requests that created it.
+func (r *recreatableFile) disconnect() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errorf(ErrDeviceClosed, "failed to disconnect device")
+	}
+	if r.disconnected {
+		return errorf(ErrDisconnected, "failed to disconnect device. The device is disconnected already")
+	}
+	r.disconnected = true
+	err := releaseDevice(r.file)
+	if err != nil {
+		r.disconnected = false
+		return fmt.Errorf("failed to disconnect device: %w", err)
+	}
+	return r.file.Close()
+}
+
+// recreate opens a new device file and replays the requests that created the device on it. The old device file is
+// closed once it has been replaced, unless the device has been disconnected.
+func (r *recreatableFile) recreate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return errorf(ErrDeviceClosed, "failed to recreate device")
+	}
+	file, err := r.backend.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("could not open device file: %w", err)
+	}
+	for _, step := range r.setup {
+		switch {
+		case step.write:
+			_, err = file.Write(step.data)
+		case step.ptr:
+			err = file.IoctlPtr(step.cmd, unsafe.Pointer(&step.data[0]))
+		default:
+			err = file.Ioctl(step.cmd, step.arg)
+		}
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to replay the setup of the device: %w", err)
+		}
+	}
+	time.Sleep(time.Millisecond * 200)
+
+	if !r.disconnected {
+		_ = r.file.Close()
+	}
+	r.file, r.disconnected = file, false
+	r.gone.Broadcast()
+	return nil
+}
+
+// recreateAndWrite recreates the device after the write of b failed with the given error, and repeats the write on
+// the recreated device. The original error is returned if the device cannot be recreated.
+func (d *uinputDevice) recreateAndWrite(b []byte, cause error) (int, error) {
+	r, ok := d.file.(*recreatableFile)
+	if !ok || !d.autoRecreate {
+		return 0, cause
+	}
+	err := r.recreate()
+	if err != nil {
+		return 0, fmt.Errorf("%w (failed to recreate the device: %v)", cause, err)
+	}
+	if d.hooks.OnRecreate != nil {
+		d.hooks.OnRecreate(cause)
+	}
+	return r.Write(b)
+}
@@ -0,0 +1,165 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// vanishingBackend opens files that fail all writes with ENODEV once they have vanished, just like the files of a
+// reloaded uinput module.
+type vanishingBackend struct {
+	mockBackend
+	opened []*vanishingFile
+}
+
+func (b *vanishingBackend) Open(path string) (DeviceFile, error) {
+	file, _ := b.mockBackend.Open(path)
+	vf := &vanishingFile{mockFile: file.(*mockFile)}
+	b.opened = append(b.opened, vf)
+	return vf, nil
+}
+
+type vanishingFile struct {
+	*mockFile
+	vanished bool
+}
+
+func (f *vanishingFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	vanished := f.vanished
+	f.mu.Unlock()
+	if vanished {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.ENODEV}
+	}
+	return f.mockFile.Write(b)
+}
+
+func TestAutoRecreateReplaysSetup(t *testing.T) {
+	backend := &vanishingBackend{}
+	var causes []error
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithAutoRecreate(),
+		WithHooks(Hooks{OnRecreate: func(cause error) { causes = append(causes, cause) }}))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	first := backend.opened[0]
+	first.mu.Lock()
+	setup := first.writes.Len()
+	ioctls := len(first.ioctls)
+	first.vanished = true
+	first.mu.Unlock()
+
+	err = kbd.KeyDown(KeyA)
+	if err != nil {
+		t.Fatalf("Expected the device to be recreated, got %v", err)
+	}
+	if len(causes) != 1 || !deviceGone(causes[0]) {
+		t.Fatalf("Expected a single notification about ENODEV, got %v", causes)
+	}
+	if len(backend.opened) != 2 {
+		t.Fatalf("Expected a new device file to be opened, got %d files", len(backend.opened))
+	}
+	second := backend.opened[1]
+	second.mu.Lock()
+	defer second.mu.Unlock()
+	if !first.closed {
+		t.Fatalf("Expected the vanished device file to be closed")
+	}
+	// UI_GET_VERSION is not replayed, as it is a query
+	if len(second.ioctls) != ioctls-1 {
+		t.Fatalf("Expected %d requests to be replayed, got %d", ioctls-1, len(second.ioctls))
+	}
+	buf := second.writes.Bytes()
+	if len(buf) != setup+2*inputEventSize || string(buf[:setup]) != string(first.writes.Bytes()[:setup]) {
+		t.Fatalf("Expected the setup to be replayed, followed by the failed frame")
+	}
+	iev := (*inputEvent)(unsafe.Pointer(&buf[setup]))
+	if iev.Type != evKey || iev.Code != KeyA || iev.Value != 1 {
+		t.Fatalf("Expected the key press to be written to the recreated device, got %+v", *iev)
+	}
+}
+
+func TestDeviceGoneWithoutAutoRecreate(t *testing.T) {
+	backend := &vanishingBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+
+	backend.opened[0].mu.Lock()
+	backend.opened[0].vanished = true
+	backend.opened[0].mu.Unlock()
+	err = kbd.KeyDown(KeyA)
+	if !deviceGone(err) || len(backend.opened) != 1 {
+		t.Fatalf("Expected ENODEV to be returned, got %v", err)
+	}
+}
+
+func TestRequestDataCopiesStringsAndStructs(t *testing.T) {
+	phys := []byte("usb-test/input0\x00")
+	if data := requestData(uiSetPhys, unsafe.Pointer(&phys[0])); string(data) != string(phys) {
+		t.Fatalf("Expected the string including its terminator, got %q", data)
+	}
+	setup := uinputAbsSetup{Code: absX}
+	setup.Absinfo.Maximum = 255
+	data := requestData(uiAbsSetup, unsafe.Pointer(&setup))
+	if len(data) != int(unsafe.Sizeof(setup)) || *(*uinputAbsSetup)(unsafe.Pointer(&data[0])) != setup {
+		t.Fatalf("Expected a copy of the struct, got %v", data)
+	}
+}
+
+func TestDisconnectAndReconnect(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	first := backend.file
+
+	err = kbd.Disconnect()
+	if err != nil {
+		t.Fatalf("Failed to disconnect the keyboard: %v", err)
+	}
+	if !first.issued(uiDevDestroy) || !first.closed {
+		t.Fatalf("Expected the device to be destroyed upon disconnect")
+	}
+	err = kbd.KeyDown(KeyA)
+	if !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Expected ErrDisconnected while disconnected, got %v", err)
+	}
+	err = kbd.Disconnect()
+	if !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Expected ErrDisconnected when disconnecting twice, got %v", err)
+	}
+
+	err = kbd.Reconnect()
+	if err != nil {
+		t.Fatalf("Failed to reconnect the keyboard: %v", err)
+	}
+	if backend.file == first || !backend.file.issued(uiDevCreate) {
+		t.Fatalf("Expected the device to be created again on a new device file")
+	}
+	err = kbd.KeyDown(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to press a key after reconnecting: %v", err)
+	}
+	err = kbd.Reconnect()
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument when reconnecting a connected device, got %v", err)
+	}
+
+	err = kbd.Disconnect()
+	if err != nil {
+		t.Fatalf("Failed to disconnect the keyboard again: %v", err)
+	}
+	err = kbd.Close()
+	if err != nil {
+		t.Fatalf("Expected a disconnected device to be closed without errors, got %v", err)
+	}
+}
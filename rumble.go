@@ -0,0 +1,66 @@
+package uinput
+
+import "time"
+
+// rumbleBufferSize is the number of rumble effects that are buffered for consumers of the rumble channel.
+// If the buffer is full, further effects will be dropped, as blocking would also block the application
+// that requested the effect.
+const rumbleBufferSize = 16
+
+// A RumbleEffect is a request to vibrate the rumble motors of a device. Both magnitudes being zero means that the
+// motors should be stopped. A Duration of zero means that the effect should be played until it is stopped.
+type RumbleEffect struct {
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+	Duration        time.Duration
+}
+
+// withRumbleChannel returns a copy of ff that additionally keeps track of uploaded rumble effects and forwards them to
+// the returned channel whenever they are played or stopped. The channel is closed once the device has been closed.
+func withRumbleChannel(ff ForceFeedback) (ForceFeedback, <-chan RumbleEffect, func()) {
+	effects := make(map[int16]FFEffect)
+	rumble := make(chan RumbleEffect, rumbleBufferSize)
+
+	wrapped := ff
+	wrapped.OnUpload = func(effect FFEffect) error {
+		if ff.OnUpload != nil {
+			if err := ff.OnUpload(effect); err != nil {
+				return err
+			}
+		}
+		effects[effect.ID] = effect
+		return nil
+	}
+	wrapped.OnErase = func(id int16) error {
+		if ff.OnErase != nil {
+			if err := ff.OnErase(id); err != nil {
+				return err
+			}
+		}
+		delete(effects, id)
+		return nil
+	}
+	wrapped.OnPlay = func(id int16, count int32) {
+		if ff.OnPlay != nil {
+			ff.OnPlay(id, count)
+		}
+		effect, ok := effects[id]
+		if !ok || effect.Type != FFRumble {
+			return
+		}
+		r := RumbleEffect{}
+		if count > 0 {
+			r = RumbleEffect{
+				StrongMagnitude: effect.Rumble.StrongMagnitude,
+				WeakMagnitude:   effect.Rumble.WeakMagnitude,
+				Duration:        time.Duration(effect.Length) * time.Millisecond,
+			}
+		}
+		select {
+		case rumble <- r:
+		default:
+		}
+	}
+
+	return wrapped, rumble, func() { close(rumble) }
+}
@@ -0,0 +1,46 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRumbleChannelReceivesPlayedEffects(t *testing.T) {
+	ff, rumble, done := withRumbleChannel(ForceFeedback{Effects: []uint16{FFRumble}})
+
+	err := ff.OnUpload(FFEffect{Type: FFRumble, ID: 1, Length: 250,
+		Rumble: FFRumbleEffect{StrongMagnitude: 0xffff, WeakMagnitude: 0x1000}})
+	if err != nil {
+		t.Fatalf("Failed to upload effect: %v", err)
+	}
+
+	ff.OnPlay(1, 1)
+	expected := RumbleEffect{StrongMagnitude: 0xffff, WeakMagnitude: 0x1000, Duration: 250 * time.Millisecond}
+	if actual := <-rumble; actual != expected {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, actual)
+	}
+
+	ff.OnPlay(1, 0)
+	if actual := <-rumble; actual != (RumbleEffect{}) {
+		t.Fatalf("Expected: stop effect\nActual: %+v", actual)
+	}
+
+	done()
+	if _, ok := <-rumble; ok {
+		t.Fatalf("Expected rumble channel to be closed")
+	}
+}
+
+func TestRumbleChannelIgnoresErasedEffects(t *testing.T) {
+	ff, rumble, _ := withRumbleChannel(ForceFeedback{Effects: []uint16{FFRumble}})
+
+	_ = ff.OnUpload(FFEffect{Type: FFRumble, ID: 1})
+	_ = ff.OnErase(1)
+	ff.OnPlay(1, 1)
+
+	select {
+	case r := <-rumble:
+		t.Fatalf("Expected no effect to be played\nActual: %+v", r)
+	default:
+	}
+}
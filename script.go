@@ -0,0 +1,159 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// a scriptStep is a single command of a script, which either emits a frame of events or waits
+type scriptStep struct {
+	events []InputEvent
+	wait   time.Duration
+}
+
+// RunScript will run the given macro script on the device. A script consists of commands separated by semicolons or
+// newlines, while everything after a '#' is a comment. The following commands are available, where codes are given
+// by their kernel names (see CodeFromName):
+//
+//	press CODE        presses the key or button (e.g. press BTN_A)
+//	release CODE      releases the key or button
+//	tap CODE          presses the key or button and releases it right away
+//	axis CODE VALUE   moves the absolute axis to the value (e.g. axis ABS_X 32767)
+//	move CODE DELTA   moves the relative axis by delta (e.g. move REL_X -10)
+//	wait DURATION     waits for the duration (e.g. wait 50ms, see time.ParseDuration)
+//
+// Every command (but wait) is emitted as a frame of its own. The whole script is parsed before running it, so that
+// nothing is emitted if the script contains errors.
+func RunScript(device EventEmitter, script string) error {
+	return RunScriptContext(context.Background(), device, script)
+}
+
+// RunScriptContext will run the given script just like RunScript, but stops once the context is done. Keys and buttons
+// that have been pressed by the script, but not released yet, are released if the script is stopped or a step fails.
+func RunScriptContext(ctx context.Context, device EventEmitter, script string) (err error) {
+	steps, err := parseScript(script)
+	if err != nil {
+		return err
+	}
+	held := map[uint16]bool{}
+	defer func() {
+		if err != nil && len(held) > 0 {
+			// the release does not depend on the context, as it must not be skipped once the keys have been pressed
+			_ = device.EmitEvents(heldReleaseEvents(held))
+		}
+	}()
+	for i, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to run step %d of the script: %w", i+1, err)
+		}
+		if len(step.events) == 0 {
+			err = sleepUntil(ctx, time.Now().Add(step.wait))
+			if err != nil {
+				return fmt.Errorf("failed to run step %d of the script: %w", i+1, err)
+			}
+			continue
+		}
+		err = device.EmitEvents(step.events)
+		if err != nil {
+			return fmt.Errorf("failed to run step %d of the script: %w", i+1, err)
+		}
+		for _, ev := range step.events {
+			if ev.Type == EvKey {
+				held[ev.Code] = ev.Value == btnStatePressed
+			}
+		}
+	}
+	return nil
+}
+
+// heldReleaseEvents returns the events that release the keys that are held, ordered by code.
+func heldReleaseEvents(held map[uint16]bool) []InputEvent {
+	var events []InputEvent
+	for code, pressed := range held {
+		if pressed {
+			events = append(events, InputEvent{Type: EvKey, Code: code, Value: btnStateReleased})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Code < events[j].Code })
+	return events
+}
+
+func parseScript(script string) ([]scriptStep, error) {
+	var steps []scriptStep
+	for lineNo, line := range strings.Split(script, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		for _, command := range strings.Split(line, ";") {
+			fields := strings.Fields(command)
+			if len(fields) == 0 {
+				continue
+			}
+			parsed, err := parseScriptCommand(fields)
+			if err != nil {
+				return nil, errorf(ErrInvalidArgument, "failed to parse line %d of the script: %w", lineNo+1, err)
+			}
+			steps = append(steps, parsed...)
+		}
+	}
+	return steps, nil
+}
+
+// parseScriptCommand parses a single command, which results in one step (or two steps for tap, as the release needs
+// to be a frame of its own, otherwise consumers may miss the press).
+func parseScriptCommand(fields []string) ([]scriptStep, error) {
+	command, args := fields[0], fields[1:]
+	expectArgs := map[string]int{"press": 1, "release": 1, "tap": 1, "axis": 2, "move": 2, "wait": 1}
+	count, ok := expectArgs[command]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", command)
+	}
+	if len(args) != count {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", command, count, len(args))
+	}
+
+	if command == "wait" {
+		wait, err := time.ParseDuration(args[0])
+		if err != nil || wait < 0 {
+			return nil, fmt.Errorf("%q is not a valid duration", args[0])
+		}
+		return []scriptStep{{wait: wait}}, nil
+	}
+
+	evType := map[string]uint16{"press": EvKey, "release": EvKey, "tap": EvKey, "axis": EvAbs, "move": EvRel}[command]
+	code, err := scriptCode(evType, args[0])
+	if err != nil {
+		return nil, err
+	}
+	press := scriptStep{events: []InputEvent{{Type: EvKey, Code: code, Value: btnStatePressed}}}
+	release := scriptStep{events: []InputEvent{{Type: EvKey, Code: code, Value: btnStateReleased}}}
+	switch command {
+	case "press":
+		return []scriptStep{press}, nil
+	case "release":
+		return []scriptStep{release}, nil
+	case "tap":
+		return []scriptStep{press, release}, nil
+	}
+	value, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid value", args[1])
+	}
+	return []scriptStep{{events: []InputEvent{{Type: evType, Code: code, Value: int32(value)}}}}, nil
+}
+
+// scriptCode resolves the code name, which is expected to belong to the given event type.
+func scriptCode(evType uint16, name string) (uint16, error) {
+	prefixes := map[uint16][]string{EvKey: {"KEY_", "BTN_"}, EvAbs: {"ABS_"}, EvRel: {"REL_"}}[evType]
+	code, ok := CodeFromName(name)
+	for _, prefix := range prefixes {
+		if ok && strings.HasPrefix(name, prefix) {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a known %s code", name, strings.Join(prefixes, " or "))
+}
@@ -0,0 +1,93 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunScriptEmitsFramesInOrder(t *testing.T) {
+	var emitter frameRecorder
+	script := `press BTN_A; wait 10ms; axis ABS_X 32767
+# comments and empty lines are skipped
+
+move REL_WHEEL -1 # trailing comment
+release BTN_A; tap KEY_ENTER`
+	start := time.Now()
+	err := RunScript(&emitter, script)
+	if err != nil {
+		t.Fatalf("Failed to run the script: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("Expected the script to wait for 10ms, took %v", elapsed)
+	}
+
+	expected := [][]InputEvent{
+		{{Type: EvKey, Code: BtnA, Value: btnStatePressed}},
+		{{Type: EvAbs, Code: AbsX, Value: 32767}},
+		{{Type: EvRel, Code: RelWheel, Value: -1}},
+		{{Type: EvKey, Code: BtnA, Value: btnStateReleased}},
+		{{Type: EvKey, Code: KeyEnter, Value: btnStatePressed}},
+		{{Type: EvKey, Code: KeyEnter, Value: btnStateReleased}},
+	}
+	if !reflect.DeepEqual(emitter.frames, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, emitter.frames)
+	}
+}
+
+func TestRunScriptRejectsInvalidScriptsUpfront(t *testing.T) {
+	for _, script := range []string{
+		"press BTN_A; jump",
+		"press",
+		"press ABS_X",
+		"axis BTN_A 1",
+		"axis ABS_X high",
+		"move REL_X 1 2",
+		"wait soon",
+		"wait -5ms",
+	} {
+		var emitter frameRecorder
+		err := RunScript(&emitter, script)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for script %q, got: %v", script, err)
+		}
+		if len(emitter.frames) != 0 {
+			t.Fatalf("Expected nothing to be emitted for script %q, got %v", script, emitter.frames)
+		}
+	}
+}
+
+func TestRunScriptContextStopsOnCancellation(t *testing.T) {
+	var emitter frameRecorder
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RunScriptContext(ctx, &emitter, "press BTN_A; release BTN_A")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected: %v\nActual: %v", context.Canceled, err)
+	}
+	if len(emitter.frames) != 0 {
+		t.Fatalf("Expected nothing to be emitted, got %v", emitter.frames)
+	}
+}
+
+func TestRunScriptContextReleasesHeldKeysWhenStopped(t *testing.T) {
+	var emitter frameRecorder
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := RunScriptContext(ctx, &emitter, "press KEY_LEFTSHIFT; press BTN_A; tap KEY_A; release BTN_A; press KEY_B; wait 1s")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected: %v\nActual: %v", context.DeadlineExceeded, err)
+	}
+	// only the keys that are still held are released
+	expected := []InputEvent{
+		{Type: EvKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: EvKey, Code: KeyB, Value: btnStateReleased},
+	}
+	if last := emitter.frames[len(emitter.frames)-1]; !reflect.DeepEqual(last, expected) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, last)
+	}
+}
@@ -0,0 +1,138 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// the first button code that SDL enumerates on Linux, buttons below follow after all others (BTN_JOYSTICK)
+const sdlFirstButton = 0x120
+
+// An SDLMapping describes a gamepad in the format of SDL_GAMECONTROLLERCONFIG (and gamecontrollerdb.txt), which
+// allows SDL based games to use a controller they do not recognize on their own. String returns the complete mapping,
+// which may be passed to games using the SDL_GAMECONTROLLERCONFIG environment variable.
+type SDLMapping struct {
+	// GUID is the joystick GUID that SDL computes for the device on Linux. The CRC of the name is left out, which SDL
+	// accepts for any device name.
+	GUID string
+	Name string
+	// Bindings map the controller elements of SDL to the buttons (bN), axes (aN) and hats (hN.M) of the device, e.g.
+	// a:b0,b:b1,leftx:a0.
+	Bindings string
+}
+
+func (m SDLMapping) String() string {
+	return m.GUID + "," + m.Name + "," + m.Bindings + ",platform:Linux,"
+}
+
+// an sdlBinding binds an SDL controller element to a button code (or an axis code, if axis is set)
+type sdlBinding struct {
+	element string
+	code    int
+	axis    bool
+}
+
+var gamepadSDLBindings = []sdlBinding{
+	{"a", ButtonSouth, false}, {"b", ButtonEast, false}, {"x", ButtonNorth, false}, {"y", ButtonWest, false},
+	{"leftshoulder", ButtonBumperLeft, false}, {"rightshoulder", ButtonBumperRight, false},
+	{"back", ButtonSelect, false}, {"start", ButtonStart, false}, {"guide", ButtonMode, false},
+	{"leftstick", ButtonThumbLeft, false}, {"rightstick", ButtonThumbRight, false},
+	{"leftx", AxisLeftX, true}, {"lefty", AxisLeftY, true}, {"rightx", AxisRightX, true}, {"righty", AxisRightY, true},
+	{"lefttrigger", AxisLeftTrigger, true}, {"righttrigger", AxisRightTrigger, true},
+}
+
+// the d-pad of gamepads created using WithDpadButtons
+var dpadSDLBindings = []sdlBinding{
+	{"dpup", BtnDpadUp, false}, {"dpdown", BtnDpadDown, false}, {"dpleft", BtnDpadLeft, false},
+	{"dpright", BtnDpadRight, false},
+}
+
+// the hid-sony driver reports triangle as BTN_NORTH and square as BTN_WEST, which is the other way round than xpad
+var ds4SDLBindings = []sdlBinding{
+	{"a", ButtonSouth, false}, {"b", ButtonEast, false}, {"x", ButtonWest, false}, {"y", ButtonNorth, false},
+	{"leftshoulder", ButtonBumperLeft, false}, {"rightshoulder", ButtonBumperRight, false},
+	{"back", ButtonSelect, false}, {"start", ButtonStart, false}, {"guide", ButtonMode, false},
+	{"leftstick", ButtonThumbLeft, false}, {"rightstick", ButtonThumbRight, false},
+	{"leftx", AxisLeftX, true}, {"lefty", AxisLeftY, true}, {"rightx", AxisRightX, true}, {"righty", AxisRightY, true},
+	{"lefttrigger", AxisLeftTrigger, true}, {"righttrigger", AxisRightTrigger, true},
+}
+
+// GamepadSDLMapping returns the SDL mapping of a gamepad created by CreateGamepad with the given name and options.
+// Options that override the id of the device (like WithVendor) are reflected in the GUID, while all other options are
+// of no concern.
+func GamepadSDLMapping(name []byte, opts ...Option) SDLMapping {
+	id := inputID{Bustype: busUsb, Vendor: xbox360Vendor, Product: xbox360Product, Version: xbox360Version}
+	cfg := newDeviceConfig(opts)
+	buttons, _ := gamepadLayout(cfg)
+	bindings := gamepadSDLBindings
+	if cfg.dpadButtons {
+		bindings = append(bindings[:len(bindings):len(bindings)], dpadSDLBindings...)
+	}
+	return sdlMapping(name, id, cfg, buttons, bindings)
+}
+
+// DualShock4SDLMapping returns the SDL mapping of a controller created by CreateDualShock4 with the given name and
+// options, just like GamepadSDLMapping does for gamepads.
+func DualShock4SDLMapping(name []byte, opts ...Option) SDLMapping {
+	id := inputID{Bustype: busUsb, Vendor: ds4Vendor, Product: ds4Product, Version: ds4Version}
+	return sdlMapping(name, id, newDeviceConfig(opts), ds4Buttons, ds4SDLBindings)
+}
+
+// sdlMapping computes the mapping of a device with the given buttons, the default axes of gamepads (see
+// gamepadAxes) and a single hat, unless the d-pad is reported as buttons. The indices are assigned the way SDL enumerates them on Linux: buttons are numbered
+// in the order of their codes (starting at BTN_JOYSTICK), axes in the order of their codes (skipping the hat axes).
+func sdlMapping(name []byte, id inputID, cfg deviceConfig, buttons []int, bindings []sdlBinding) SDLMapping {
+	cfg.applyID(&id)
+
+	codes := append([]int(nil), buttons...)
+	sort.Slice(codes, func(i, j int) bool {
+		if (codes[i] >= sdlFirstButton) != (codes[j] >= sdlFirstButton) {
+			return codes[i] >= sdlFirstButton
+		}
+		return codes[i] < codes[j]
+	})
+	buttonIndex := make(map[int]int, len(codes))
+	for i, code := range codes {
+		buttonIndex[code] = i
+	}
+	axisIndex := map[int]int{}
+	for _, code := range gamepadAxes {
+		if code != absHat0X && code != absHat0Y {
+			axisIndex[code] = len(axisIndex)
+		}
+	}
+
+	var elements []string
+	if !codeSupported(buttons, BtnDpadUp) {
+		elements = []string{"dpup:h0.1", "dpright:h0.2", "dpdown:h0.4", "dpleft:h0.8"}
+	}
+	for _, b := range bindings {
+		if b.axis {
+			elements = append(elements, b.element+":a"+strconv.Itoa(axisIndex[b.code]))
+		} else {
+			elements = append(elements, b.element+":b"+strconv.Itoa(buttonIndex[b.code]))
+		}
+	}
+	sort.Strings(elements)
+
+	return SDLMapping{GUID: sdlGUID(id), Name: sdlName(name), Bindings: strings.Join(elements, ",")}
+}
+
+// sdlGUID returns the GUID of the device in the layout SDL uses on Linux: the bus type and CRC (zero here), followed by
+// the vendor, product and version, which are padded by two zero bytes each. All values are 16 bit little endian.
+func sdlGUID(id inputID) string {
+	var guid [16]byte
+	binary.LittleEndian.PutUint16(guid[0:], id.Bustype)
+	binary.LittleEndian.PutUint16(guid[4:], id.Vendor)
+	binary.LittleEndian.PutUint16(guid[8:], id.Product)
+	binary.LittleEndian.PutUint16(guid[12:], id.Version)
+	return hex.EncodeToString(guid[:])
+}
+
+// sdlName returns the name of the device as it may appear in a mapping, which must not contain any commas.
+func sdlName(name []byte) string {
+	return strings.Replace(string(name), ",", " ", -1)
+}
@@ -0,0 +1,44 @@
+package uinput
+
+import "testing"
+
+func TestGamepadSDLMappingMatchesXbox360(t *testing.T) {
+	mapping := GamepadSDLMapping([]byte("Test, Pad"))
+	expected := "030000005e0400008e02000010010000,Test  Pad,a:b0,b:b1,back:b6,dpdown:h0.4,dpleft:h0.8,dpright:h0.2," +
+		"dpup:h0.1,guide:b8,leftshoulder:b4,leftstick:b9,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5," +
+		"rightstick:b10,righttrigger:a5,rightx:a3,righty:a4,start:b7,x:b2,y:b3,platform:Linux,"
+	if mapping.String() != expected {
+		t.Fatalf("Expected mapping %q, got %q", expected, mapping.String())
+	}
+}
+
+func TestDualShock4SDLMappingMatchesHidSony(t *testing.T) {
+	mapping := DualShock4SDLMapping([]byte("ds4"))
+	if mapping.GUID != "030000004c050000cc09000011810000" {
+		t.Fatalf("Unexpected GUID %s", mapping.GUID)
+	}
+	expected := "a:b0,b:b1,back:b8,dpdown:h0.4,dpleft:h0.8,dpright:h0.2,dpup:h0.1,guide:b10,leftshoulder:b4," +
+		"leftstick:b11,lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b12,righttrigger:a5,rightx:a3," +
+		"righty:a4,start:b9,x:b3,y:b2"
+	if mapping.Bindings != expected {
+		t.Fatalf("Expected bindings %q, got %q", expected, mapping.Bindings)
+	}
+}
+
+func TestSDLMappingReflectsOverriddenID(t *testing.T) {
+	mapping := GamepadSDLMapping([]byte("pad"), WithBusType(BusBluetooth), WithVendor(0x4711), WithProduct(0x0822),
+		WithVersion(2))
+	if mapping.GUID != "05000000114700002208000002000000" {
+		t.Fatalf("Unexpected GUID %s", mapping.GUID)
+	}
+}
+
+func TestGamepadSDLMappingBindsDpadButtons(t *testing.T) {
+	mapping := GamepadSDLMapping([]byte("pad"), WithDpadButtons())
+	expected := "a:b0,b:b1,back:b6,dpdown:b12,dpleft:b13,dpright:b14,dpup:b11,guide:b8,leftshoulder:b4,leftstick:b9," +
+		"lefttrigger:a2,leftx:a0,lefty:a1,rightshoulder:b5,rightstick:b10,righttrigger:a5,rightx:a3,righty:a4,start:b7," +
+		"x:b2,y:b3"
+	if mapping.Bindings != expected {
+		t.Fatalf("Expected bindings %q, got %q", expected, mapping.Bindings)
+	}
+}
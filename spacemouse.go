@@ -0,0 +1,195 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// the ids of the 3Dconnexion SpaceNavigator, which spacenavd (and thereby libspnav) uses to recognize space mice
+const (
+	spaceNavigatorVendor  = 0x046d
+	spaceNavigatorProduct = 0xc626
+)
+
+// spaceMouseMax is the deflection of the axes of space mice. 3Dconnexion devices report up to ±350 on each axis.
+const spaceMouseMax = 350
+
+// the buttons of space mice
+const (
+	SpaceMouseButtonLeft  = Btn0
+	SpaceMouseButtonRight = Btn1
+)
+
+var spaceMouseButtons = []int{SpaceMouseButtonLeft, SpaceMouseButtonRight}
+
+var spaceMouseAxes = []int{absX, absY, absZ, absRX, absRY, absRZ}
+
+// A SpaceMouse is a 6-DOF input device like the 3Dconnexion SpaceNavigator, which is used to navigate 3D scenes in CAD
+// and modelling software. Its cap may be translated and rotated along all three axes at once, so the pose is always
+// set as a whole.
+type SpaceMouse interface {
+	// SetPose will set the translation (tx, ty, tz) and rotation (rx, ry, rz) of the cap within a single frame. All
+	// values are expected to be in the range of -1 to 1, where 0 is the neutral position.
+	SetPose(tx, ty, tz, rx, ry, rz float64) error
+
+	// ButtonPress will press the given button (SpaceMouseButtonLeft or SpaceMouseButtonRight) and immediately
+	// release it.
+	ButtonPress(button int) error
+
+	// ButtonDown will press the given button. Note that the button remains pressed until ButtonUp is called.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button.
+	ButtonUp(button int) error
+
+	Device
+}
+
+type vSpaceMouse struct {
+	name       []byte
+	deviceFile *uinputDevice
+}
+
+// CreateSpaceMouse will create a new space mouse that uses the ids of the 3Dconnexion SpaceNavigator.
+func CreateSpaceMouse(path string, name []byte, opts ...Option) (SpaceMouse, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateSpaceMouse", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createSpaceMouse(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vSpaceMouse{name: name, deviceFile: fd}, nil
+}
+
+// SetPose will set the translation and rotation of the cap.
+func (vs vSpaceMouse) SetPose(tx, ty, tz, rx, ry, rz float64) error {
+	var events []inputEvent
+	for i, value := range []float64{tx, ty, tz, rx, ry, rz} {
+		scaled, err := scaleAxis(value, spaceMouseMax)
+		if err != nil {
+			return fmt.Errorf("failed to perform SetPose: %w", err)
+		}
+		events = append(events, inputEvent{Type: evAbs, Code: uint16(spaceMouseAxes[i]), Value: scaled})
+	}
+	return sendEvents(vs.deviceFile, events)
+}
+
+// ButtonPress will press the given button and immediately release it.
+func (vs vSpaceMouse) ButtonPress(button int) error {
+	err := vs.ButtonDown(button)
+	if err != nil {
+		return err
+	}
+	return vs.ButtonUp(button)
+}
+
+// ButtonDown will press the given button.
+func (vs vSpaceMouse) ButtonDown(button int) error {
+	if !codeSupported(spaceMouseButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a space mouse button", button)
+	}
+	return sendBtnEvent(vs.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vs vSpaceMouse) ButtonUp(button int) error {
+	if !codeSupported(spaceMouseButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a space mouse button", button)
+	}
+	return sendBtnEvent(vs.deviceFile, []int{button}, btnStateReleased)
+}
+
+func (vs vSpaceMouse) Name() string {
+	return string(vs.name)
+}
+
+func (vs vSpaceMouse) Path() string {
+	return devicePath(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) Fd() uintptr {
+	return deviceFd(vs.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vs vSpaceMouse) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vs.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vs vSpaceMouse) EmitEvents(events []InputEvent) error {
+	return emitEvents(vs.deviceFile, events)
+}
+
+func (vs vSpaceMouse) Sync() error {
+	return writeSyncEvent(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) SysPath() (string, error) {
+	return sysPath(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) EventPath() (string, error) {
+	return eventPath(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vs.deviceFile)
+}
+
+func (vs vSpaceMouse) Stats() Stats {
+	return deviceStats(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) State() State {
+	return deviceState(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) ResetAll() error {
+	return resetDevice(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) Disconnect() error {
+	return disconnectDevice(vs.deviceFile)
+}
+
+func (vs vSpaceMouse) Reconnect() error {
+	return reconnectDevice(vs.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vs vSpaceMouse) Close() error {
+	return closeDevice(vs.deviceFile)
+}
+
+func createSpaceMouse(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	var absMin, absMax [absSize]int32
+	for _, axis := range spaceMouseAxes {
+		absMin[axis] = -spaceMouseMax
+		absMax[axis] = spaceMouseMax
+	}
+
+	return createAxisDevice(path, spaceMouseButtons, spaceMouseAxes, nil,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  spaceNavigatorVendor,
+				Product: spaceNavigatorProduct,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+}
@@ -0,0 +1,75 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicSpaceMouse(t *testing.T) {
+	mouse, err := CreateSpaceMouse("/dev/uinput", []byte("Test Space Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual space mouse. Last error was: %s\n", err)
+	}
+
+	err = mouse.SetPose(0.5, 0, -0.5, 0, 0.25, 0)
+	if err != nil {
+		t.Fatalf("Failed to set the pose. Last error was: %s\n", err)
+	}
+
+	err = mouse.ButtonPress(SpaceMouseButtonLeft)
+	if err != nil {
+		t.Fatalf("Failed to press the button. Last error was: %s\n", err)
+	}
+
+	err = mouse.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestSpaceMouseCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateSpaceMouse("", []byte("SpaceMouse"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestSpaceMousePoseIsSentWithinSingleFrame(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-spacemouse-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	mouse := vSpaceMouse{name: []byte("Test Space Mouse"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	err = mouse.SetPose(1, -1, 0.5, 0, 0, -0.5)
+	if err != nil {
+		t.Fatalf("Failed to set the pose: %v", err)
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 350},
+		{Type: evAbs, Code: absY, Value: -350},
+		{Type: evAbs, Code: absZ, Value: 175},
+		{Type: evAbs, Code: absRX, Value: 0},
+		{Type: evAbs, Code: absRY, Value: 0},
+		{Type: evAbs, Code: absRZ, Value: -175},
+	}
+	if events := readEvents(t, file.Name(), evAbs); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestSpaceMouseRejectsInvalidInput(t *testing.T) {
+	mouse := vSpaceMouse{name: []byte("Test Space Mouse")}
+	err := mouse.SetPose(0, 0, 1.5, 0, 0, 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an out of range pose, got: %v", err)
+	}
+	err = mouse.ButtonDown(Btn2)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unsupported button, got: %v", err)
+	}
+}
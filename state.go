@@ -0,0 +1,211 @@
+package uinput
+
+import (
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// State is a snapshot of the controls of a device (see Device.State), as they have been written to the device. This
+// allows bridges to compare the state of a source with what has been emitted already.
+type State struct {
+	// Keys are the last values of the keys and buttons that have been written to, which is 0 for released keys, 1
+	// for pressed keys and 2 for keys that are repeating.
+	Keys map[uint16]int32
+	// Axes are the last values of the absolute axes that have been written to. Multitouch axes are left out, as their
+	// values depend on the slot that has been selected.
+	Axes map[uint16]int32
+	// Contacts are the tracking ids of the multitouch slots that have a contact.
+	Contacts map[int32]int32
+}
+
+// stateTracker keeps the state of the controls of a device. It has a lock of its own, so that the state may be
+// retrieved while a write is in progress.
+type stateTracker struct {
+	mu       sync.Mutex
+	keys     map[uint16]int32
+	axes     map[uint16]int32
+	slot     int32
+	contacts map[int32]int32
+
+	// the rest positions of the axes, which are set once the device has been created
+	ranges    axisRanges
+	rest      map[uint16]int32
+	positions map[uint16]bool
+}
+
+func newStateTracker(ranges axisRanges) *stateTracker {
+	return &stateTracker{keys: map[uint16]int32{}, axes: map[uint16]int32{}, contacts: map[int32]int32{},
+		ranges: ranges, rest: map[uint16]int32{}, positions: map[uint16]bool{}}
+}
+
+// setRest overrides the rest position of the given axes, which is 0 (or the end of the range next to it) by default.
+func (s *stateTracker) setRest(value int32, codes ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, code := range codes {
+		s.rest[code] = value
+	}
+}
+
+// setPositions marks the given axes as positions (like the pointer of absolute mice), which have no rest position and
+// are left as they are when the device is reset.
+func (s *stateTracker) setPositions(codes ...uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, code := range codes {
+		s.positions[code] = true
+	}
+}
+
+// track records the values of the given encoded events that have been written.
+func (s *stateTracker) track(written []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i+inputEventSize <= len(written); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&written[i]))
+		switch {
+		case iev.Type == evKey:
+			s.keys[iev.Code] = iev.Value
+		case iev.Type == evAbs && iev.Code == absMtSlot:
+			s.slot = iev.Value
+		case iev.Type == evAbs && iev.Code == absMtTrackingID:
+			if iev.Value < 0 {
+				delete(s.contacts, s.slot)
+			} else {
+				s.contacts[s.slot] = iev.Value
+			}
+		case iev.Type == evAbs && iev.Code < absMtSlot:
+			s.axes[iev.Code] = iev.Value
+		}
+	}
+}
+
+// value returns the last value of the key or absolute axis that has been written, if any.
+func (s *stateTracker) value(evType uint16, code uint16) (value int32, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if evType == evKey {
+		value, ok = s.keys[code]
+	} else {
+		value, ok = s.axes[code]
+	}
+	return value, ok
+}
+
+// snapshot returns a copy of the state.
+func (s *stateTracker) snapshot() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := State{Keys: make(map[uint16]int32, len(s.keys)), Axes: make(map[uint16]int32, len(s.axes)),
+		Contacts: make(map[int32]int32, len(s.contacts))}
+	for code, value := range s.keys {
+		state.Keys[code] = value
+	}
+	for code, value := range s.axes {
+		state.Axes[code] = value
+	}
+	for slot, id := range s.contacts {
+		state.Contacts[slot] = id
+	}
+	return state
+}
+
+// restValue returns the rest position of the axis.
+func (s *stateTracker) restValue(code uint16) int32 {
+	if value, ok := s.rest[code]; ok {
+		return value
+	}
+	min, max := s.ranges.min[code], s.ranges.max[code]
+	switch {
+	case min == max:
+		return 0
+	case min > 0:
+		return min
+	case max < 0:
+		return max
+	}
+	return 0
+}
+
+// resetEvents returns the events that neutralize the state: all contacts are lifted, all keys are released and all
+// axes are moved to their rest position. The events are ordered by code, so that resets are reproducible.
+func (s *stateTracker) resetEvents() []inputEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []inputEvent
+	slots := make([]int32, 0, len(s.contacts))
+	for slot := range s.contacts {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	for _, slot := range slots {
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtSlot, Value: slot},
+			inputEvent{Type: evAbs, Code: absMtTrackingID, Value: -1})
+	}
+	for _, code := range sortedCodes(s.keys) {
+		if s.keys[code] != 0 {
+			events = append(events, inputEvent{Type: evKey, Code: code, Value: 0})
+		}
+	}
+	for _, code := range sortedCodes(s.axes) {
+		if rest := s.restValue(code); !s.positions[code] && s.axes[code] != rest {
+			events = append(events, inputEvent{Type: evAbs, Code: code, Value: rest})
+		}
+	}
+	return events
+}
+
+func sortedCodes(values map[uint16]int32) []uint16 {
+	codes := make([]uint16, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// deviceState returns a snapshot of the state of the device.
+func deviceState(deviceFile *uinputDevice) State {
+	return deviceFile.state.snapshot()
+}
+
+// resetDevice neutralizes every control of the device that is not at rest as a single frame. Nothing is written if
+// all controls are at rest already.
+func resetDevice(deviceFile *uinputDevice) error {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	if deviceFile.limiter != nil {
+		// pending axis movements are written first, so that they do not undo the reset
+		err := deviceFile.limiter.flush(deviceFile, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return writeReset(deviceFile)
+}
+
+// writeReset writes the frame that neutralizes the controls of the device, if any, and calls the reset hook of the
+// device once the frame has been written. The caller must hold the lock of the device.
+func writeReset(deviceFile *uinputDevice) error {
+	events := deviceFile.state.resetEvents()
+	if len(events) > 0 {
+		buf := deviceFile.buf[:0]
+		for _, iev := range events {
+			buf = appendInputEvent(buf, iev)
+		}
+		if deviceFile.manualSync {
+			// the reset is a frame of its own, even if the device is synced manually
+			buf = appendInputEvent(buf, syncEvent())
+		}
+		err := writeEncoded(deviceFile, buf)
+		if err != nil {
+			return err
+		}
+	}
+	if deviceFile.onReset != nil {
+		deviceFile.onReset()
+	}
+	return nil
+}
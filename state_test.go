@@ -0,0 +1,250 @@
+package uinput
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+func TestStateTracksWrittenControls(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	if state := gamepad.State(); len(state.Keys) != 0 || len(state.Axes) != 0 {
+		t.Fatalf("Expected no state after creation, got %+v", state)
+	}
+	err = gamepad.ButtonDown(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to press the button: %v", err)
+	}
+	err = gamepad.LeftStickMove(1, 0)
+	if err != nil {
+		t.Fatalf("Failed to move the stick: %v", err)
+	}
+	err = gamepad.SetLeftTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move the trigger: %v", err)
+	}
+
+	state := gamepad.State()
+	if state.Keys[ButtonSouth] != 1 || state.Axes[AbsX] != stickMax || state.Axes[AbsZ] != triggerMax {
+		t.Fatalf("Expected the pressed button and the moved axes, got %+v", state)
+	}
+	state.Keys[ButtonSouth] = 0
+	if gamepad.State().Keys[ButtonSouth] != 1 {
+		t.Fatalf("Expected the snapshot to be a copy")
+	}
+
+	err = gamepad.ButtonUp(ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to release the button: %v", err)
+	}
+	if value, ok := gamepad.State().Keys[ButtonSouth]; !ok || value != 0 {
+		t.Fatalf("Expected the button to be reported as released, got %d", value)
+	}
+}
+
+func TestResetAllNeutralizesControls(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer gamepad.Close()
+
+	err = gamepad.ButtonDown(ButtonStart)
+	if err != nil {
+		t.Fatalf("Failed to press the button: %v", err)
+	}
+	err = gamepad.RightStickMove(0, -1)
+	if err != nil {
+		t.Fatalf("Failed to move the stick: %v", err)
+	}
+	err = gamepad.SetRightTrigger(0.5)
+	if err != nil {
+		t.Fatalf("Failed to move the trigger: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = gamepad.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset the gamepad: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	buf := backend.file.writes.Bytes()
+	var events []inputEvent
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		events = append(events, inputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+	}
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	// the untouched axis (ABS_RX) is at rest already
+	expected := []inputEvent{
+		{Type: evKey, Code: ButtonStart, Value: 0},
+		{Type: evAbs, Code: AbsRY, Value: 0},
+		{Type: evAbs, Code: AbsRZ, Value: 0},
+		syncEvent(),
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected the events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected the events %v, got %v", expected, events)
+		}
+	}
+
+	err = gamepad.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset the gamepad again: %v", err)
+	}
+	backend.file.mu.Lock()
+	written := backend.file.writes.Len()
+	backend.file.mu.Unlock()
+	if written != 0 {
+		t.Fatalf("Expected nothing to be written once everything is at rest, got %d bytes", written)
+	}
+}
+
+func TestResetAllCentersDualShock4Sticks(t *testing.T) {
+	ds4, err := CreateDualShock4("mock", []byte("Test DS4"), WithBackend(&mockBackend{}))
+	if err != nil {
+		t.Fatalf("Failed to create the controller: %v", err)
+	}
+	defer ds4.Close()
+
+	err = ds4.LeftStickMove(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to move the stick: %v", err)
+	}
+	err = ds4.SetLeftTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move the trigger: %v", err)
+	}
+	err = ds4.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset the controller: %v", err)
+	}
+	state := ds4.State()
+	if state.Axes[AbsX] != ds4StickMax/2 || state.Axes[AbsY] != ds4StickMax/2 || state.Axes[AbsZ] != 0 {
+		t.Fatalf("Expected the stick to be centered and the trigger to be released, got %+v", state.Axes)
+	}
+	if state.Keys[ButtonTriggerLeft] != 0 {
+		t.Fatalf("Expected the trigger button to be released, got %+v", state.Keys)
+	}
+}
+
+func TestResetAllLiftsContactsAndKeepsPositions(t *testing.T) {
+	screen, err := CreateTouchScreen("mock", []byte("Test Touch Screen"), 0, 1023, 0, 767, 2, WithBackend(&mockBackend{}))
+	if err != nil {
+		t.Fatalf("Failed to create the touch screen: %v", err)
+	}
+	defer screen.Close()
+
+	err = screen.TouchDown(1, 100, 200)
+	if err != nil {
+		t.Fatalf("Failed to touch down: %v", err)
+	}
+	state := screen.State()
+	if len(state.Contacts) != 1 || state.Keys[BtnTouch] != 1 {
+		t.Fatalf("Expected a single contact, got %+v", state)
+	}
+	if _, ok := state.Contacts[1]; !ok {
+		t.Fatalf("Expected a contact in slot 1, got %+v", state.Contacts)
+	}
+
+	err = screen.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset the touch screen: %v", err)
+	}
+	state = screen.State()
+	if len(state.Contacts) != 0 || state.Keys[BtnTouch] != 0 {
+		t.Fatalf("Expected all contacts to be lifted, got %+v", state)
+	}
+	if state.Axes[AbsX] != 100 || state.Axes[AbsY] != 200 {
+		t.Fatalf("Expected the position to be kept, got %+v", state.Axes)
+	}
+}
+
+func TestResetAllForgetsContacts(t *testing.T) {
+	screen, err := CreateTouchScreen("mock", []byte("Test Touch Screen"), 0, 1023, 0, 767, 2, WithBackend(&mockBackend{}))
+	if err != nil {
+		t.Fatalf("Failed to create the touch screen: %v", err)
+	}
+	defer screen.Close()
+
+	contact, err := screen.Touch(100, 200)
+	if err != nil {
+		t.Fatalf("Failed to touch: %v", err)
+	}
+	err = screen.TouchDown(1, 300, 400)
+	if err != nil {
+		t.Fatalf("Failed to touch down: %v", err)
+	}
+	err = screen.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset the touch screen: %v", err)
+	}
+
+	err = contact.Move(150, 250)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected the handle of the lifted contact to be rejected, got %v", err)
+	}
+	err = screen.TouchDown(1, 300, 400)
+	if err != nil {
+		t.Fatalf("Expected the slot to be free after the reset: %v", err)
+	}
+	// the first contact after the reset has to report BTN_TOUCH again
+	if state := screen.State(); len(state.Contacts) != 1 || state.Keys[BtnTouch] != 1 {
+		t.Fatalf("Expected a single contact, got %+v", state)
+	}
+}
+
+func TestCloseReleasesHeldControls(t *testing.T) {
+	backend := &mockBackend{}
+	gamepad, err := CreateGamepad("mock", []byte("Test Gamepad"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	err = gamepad.ButtonDown(ButtonWest)
+	if err != nil {
+		t.Fatalf("Failed to press the button: %v", err)
+	}
+	err = gamepad.LeftStickMove(-1, 0)
+	if err != nil {
+		t.Fatalf("Failed to move the stick: %v", err)
+	}
+
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+	err = gamepad.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the gamepad: %v", err)
+	}
+	events := takeWrites(backend)
+	expected := []inputEvent{
+		{Type: evKey, Code: ButtonWest, Value: 0},
+		{Type: evAbs, Code: AbsX, Value: 0},
+		syncEvent(),
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected the events %v before the device is destroyed, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected the events %v before the device is destroyed, got %v", expected, events)
+		}
+	}
+	if !backend.file.issued(uiDevDestroy) {
+		t.Fatalf("Expected the device to be destroyed")
+	}
+}
@@ -0,0 +1,56 @@
+package uinput
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Stats are the counters of a device since its creation (see Device.Stats), which allow operators of long-running
+// applications to monitor the health of their virtual devices.
+type Stats struct {
+	// Events is the number of events that have been written, including sync events.
+	Events uint64
+	// Bytes is the number of bytes that have been written.
+	Bytes uint64
+	// Frames is the number of sync events that have been written, which is the number of frames consumers have seen.
+	Frames uint64
+	// WriteErrors is the number of writes that failed (after retrying transient errors).
+	WriteErrors uint64
+	// LastWriteLatency is the duration of the last write, including its retries.
+	LastWriteLatency time.Duration
+}
+
+// statCounters collect the counters of a device. They have a lock of their own, so that the counters may be retrieved
+// while a write is in progress.
+type statCounters struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// count adds the given write of the encoded events to the counters.
+func (s *statCounters) count(written []byte, err error, latency time.Duration) {
+	frames := uint64(0)
+	for i := 0; i+inputEventSize <= len(written); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&written[i]))
+		if iev.Type == evSyn && iev.Code == synReport {
+			frames++
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Events += uint64(len(written) / inputEventSize)
+	s.stats.Bytes += uint64(len(written))
+	s.stats.Frames += frames
+	if err != nil {
+		s.stats.WriteErrors++
+	}
+	s.stats.LastWriteLatency = latency
+}
+
+// deviceStats returns the current counters of the device.
+func deviceStats(deviceFile *uinputDevice) Stats {
+	deviceFile.stats.mu.Lock()
+	defer deviceFile.stats.mu.Unlock()
+	return deviceFile.stats.stats
+}
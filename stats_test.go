@@ -0,0 +1,41 @@
+package uinput
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatsCountWrites(t *testing.T) {
+	backend := &mockBackend{}
+	mouse, err := CreateMouse("mock", []byte("Test Mouse"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the mouse: %v", err)
+	}
+	if stats := mouse.Stats(); stats != (Stats{}) {
+		t.Fatalf("Expected no counters after creation, got %+v", stats)
+	}
+
+	err = mouse.Move(10, 5)
+	if err != nil {
+		t.Fatalf("Failed to move the mouse: %v", err)
+	}
+	err = mouse.LeftClick()
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+	stats := mouse.Stats()
+	// a move (REL_X, REL_Y and a sync event), followed by a press and a release (a button and a sync event each)
+	if stats.Events != 7 || stats.Frames != 3 || stats.Bytes != uint64(7*inputEventSize) || stats.WriteErrors != 0 {
+		t.Fatalf("Expected 7 events in 3 frames, got %+v", stats)
+	}
+
+	backend.file.Close()
+	err = mouse.Move(1, 1)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected the write to fail, got %v", err)
+	}
+	stats = mouse.Stats()
+	if stats.WriteErrors != 1 || stats.Events != 7 {
+		t.Fatalf("Expected a single write error and no more events, got %+v", stats)
+	}
+}
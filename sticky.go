@@ -0,0 +1,63 @@
+package uinput
+
+import (
+	"fmt"
+	"sync"
+)
+
+// the modifier keys, which latch on keyboards created using WithStickyKeys
+var modifierKeys = map[int]bool{
+	KeyLeftctrl: true, KeyRightctrl: true, KeyLeftshift: true, KeyRightshift: true,
+	KeyLeftalt: true, KeyRightalt: true, KeyLeftmeta: true, KeyRightmeta: true,
+}
+
+// WithStickyKeys makes keyboards latch modifiers, just like the sticky keys of desktop accessibility settings: once a
+// modifier (like KeyLeftctrl) is pressed using KeyPress, it is held down until the next key that is not a modifier has
+// been pressed, after which all latched modifiers are released. Pressing a latched modifier again releases it. This
+// applies to KeyPress only, while KeyDown, KeyUp, Combo and Type work as usual.
+func WithStickyKeys() Option {
+	return func(cfg *deviceConfig) {
+		cfg.mark(optStickyKeys)
+		cfg.stickyKeys = true
+	}
+}
+
+// stickyKeys are the modifiers that are latched by a keyboard
+type stickyKeys struct {
+	mu      sync.Mutex
+	latched []int
+}
+
+// press latches the given modifier, or presses the given key and releases all latched modifiers afterwards.
+func (s *stickyKeys) press(deviceFile *uinputDevice, key int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if modifierKeys[key] {
+		for i, latched := range s.latched {
+			if latched == key {
+				s.latched = append(s.latched[:i], s.latched[i+1:]...)
+				return sendBtnEvent(deviceFile, []int{key}, btnStateReleased)
+			}
+		}
+		err := sendBtnEvent(deviceFile, []int{key}, btnStatePressed)
+		if err != nil {
+			return fmt.Errorf("failed to latch modifier: %w", err)
+		}
+		s.latched = append(s.latched, key)
+		return nil
+	}
+
+	err := sendBtnEvent(deviceFile, []int{key}, btnStatePressed)
+	if err == nil {
+		err = sendBtnEvent(deviceFile, []int{key}, btnStateReleased)
+	}
+	if len(s.latched) > 0 {
+		// the modifiers are released even if the key failed, so that they do not remain stuck
+		releaseErr := sendBtnEvent(deviceFile, s.latched, btnStateReleased)
+		if err == nil {
+			err = releaseErr
+		}
+		s.latched = nil
+	}
+	return err
+}
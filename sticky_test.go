@@ -0,0 +1,52 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestStickyKeysLatchModifiers(t *testing.T) {
+	backend := &mockBackend{}
+	kbd, err := CreateKeyboard("mock", []byte("Test Keyboard"), WithBackend(backend), WithStickyKeys())
+	if err != nil {
+		t.Fatalf("Failed to create the keyboard: %v", err)
+	}
+	defer kbd.Close()
+	backend.file.mu.Lock()
+	backend.file.writes.Reset()
+	backend.file.mu.Unlock()
+
+	for _, key := range []int{KeyLeftctrl, KeyLeftshift, KeyT, KeyLeftalt, KeyLeftalt, KeyA} {
+		err = kbd.KeyPress(key)
+		if err != nil {
+			t.Fatalf("Failed to press key %d: %v", key, err)
+		}
+	}
+
+	backend.file.mu.Lock()
+	buf := backend.file.writes.Bytes()
+	var keys []inputEvent
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[i]))
+		if iev.Type == evKey {
+			keys = append(keys, iev)
+		}
+	}
+	backend.file.mu.Unlock()
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyT, Value: btnStatePressed},
+		{Type: evKey, Code: KeyT, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftalt, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftalt, Value: btnStateReleased},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, keys)
+	}
+}
@@ -0,0 +1,273 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// the ids of the Microsoft Surface Dial, which are used by the hardware databases of udev and the desktops to
+// recognize the device
+const (
+	surfaceDialVendor  = 0x045e
+	surfaceDialProduct = 0x091b
+)
+
+// the resolution of the rotation of surface dials. The dial reports rotations in tenths of a degree and has a detent
+// every 10 degrees (36 per revolution).
+const (
+	dialUnitsPerDegree = 10
+	dialUnitsPerDetent = 100
+)
+
+// the rotation modes of surface dials
+const (
+	// DialDetented reports rotations in whole detents only, like a dial with haptic feedback.
+	DialDetented = iota
+	// DialSmooth reports rotations in tenths of a degree, like a dial without haptic feedback.
+	DialSmooth
+)
+
+// A SurfaceDial is a rotary puck like the Microsoft Surface Dial, which can be rotated and pressed. Rotations are
+// reported on the dial axis as well as the scroll wheel (clockwise rotations scroll down), so that applications
+// without dial support may be scrolled.
+type SurfaceDial interface {
+	// Rotate will rotate the dial by the given angle (clockwise for positive degrees). In detented mode, rotations
+	// add up until a detent is reached.
+	Rotate(degrees float64) error
+
+	// SetRotationMode will switch between detented (DialDetented) and smooth (DialSmooth) rotation.
+	SetRotationMode(mode int) error
+
+	// Click will press the dial and then immediately release it.
+	Click() error
+
+	// Press will press the dial. Note that the dial remains pressed until Release is called.
+	Press() error
+
+	// Release will release the dial.
+	Release() error
+
+	Device
+}
+
+type vSurfaceDial struct {
+	name       []byte
+	deviceFile *uinputDevice
+
+	// mu guards the rotation state, which needs to be updated along with the events that are sent
+	mu sync.Mutex
+	// position is the total rotation in tenths of a degree
+	position int64
+	smooth   bool
+}
+
+// CreateSurfaceDial will create a new surface dial. The dial starts in detented mode.
+func CreateSurfaceDial(path string, name []byte, opts ...Option) (SurfaceDial, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateSurfaceDial", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createSurfaceDial(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vSurfaceDial{name: name, deviceFile: fd}, nil
+}
+
+// Rotate will rotate the dial by the given angle.
+func (vd *vSurfaceDial) Rotate(degrees float64) error {
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		return errorf(ErrInvalidArgument, "failed to perform Rotate. %v is not a valid angle", degrees)
+	}
+	vd.mu.Lock()
+	defer vd.mu.Unlock()
+
+	prev := vd.position
+	units := int64(math.Round(degrees * dialUnitsPerDegree))
+	next := prev + units
+	detents := int32(floorDiv(next, dialUnitsPerDetent) - floorDiv(prev, dialUnitsPerDetent))
+
+	var events []inputEvent
+	if vd.smooth {
+		events = append(events, inputEvent{Type: evRel, Code: relDial, Value: int32(units)})
+		if detents != 0 {
+			events = append(events, inputEvent{Type: evRel, Code: relWheel, Value: -detents})
+		}
+		// the high-resolution wheel follows the dial as is, with a notch per detent
+		hiRes := int32(floorDiv(next*hiResNotch, dialUnitsPerDetent) - floorDiv(prev*hiResNotch, dialUnitsPerDetent))
+		if hiRes != 0 {
+			events = append(events, inputEvent{Type: evRel, Code: RelWheelHiRes, Value: -hiRes})
+		}
+	} else if detents != 0 {
+		events = append(events,
+			inputEvent{Type: evRel, Code: relDial, Value: detents * dialUnitsPerDetent},
+			inputEvent{Type: evRel, Code: relWheel, Value: -detents},
+			inputEvent{Type: evRel, Code: RelWheelHiRes, Value: -detents * hiResNotch})
+	}
+
+	if len(events) > 0 {
+		err := sendEvents(vd.deviceFile, events)
+		if err != nil {
+			return fmt.Errorf("failed to rotate the dial: %w", err)
+		}
+	}
+	vd.position = next
+	return nil
+}
+
+// SetRotationMode will switch between detented and smooth rotation.
+func (vd *vSurfaceDial) SetRotationMode(mode int) error {
+	if mode != DialDetented && mode != DialSmooth {
+		return errorf(ErrInvalidArgument, "failed to perform SetRotationMode. %d is not a valid rotation mode", mode)
+	}
+	vd.mu.Lock()
+	defer vd.mu.Unlock()
+	vd.smooth = mode == DialSmooth
+	return nil
+}
+
+// Click will press the dial and then immediately release it.
+func (vd *vSurfaceDial) Click() error {
+	err := sendBtnEvent(vd.deviceFile, []int{Btn0}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the Press event: %w", err)
+	}
+
+	return sendBtnEvent(vd.deviceFile, []int{Btn0}, btnStateReleased)
+}
+
+// Press will press the dial.
+func (vd *vSurfaceDial) Press() error {
+	return sendBtnEvent(vd.deviceFile, []int{Btn0}, btnStatePressed)
+}
+
+// Release will release the dial.
+func (vd *vSurfaceDial) Release() error {
+	return sendBtnEvent(vd.deviceFile, []int{Btn0}, btnStateReleased)
+}
+
+func (vd *vSurfaceDial) Name() string {
+	return string(vd.name)
+}
+
+func (vd *vSurfaceDial) Path() string {
+	return devicePath(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) Fd() uintptr {
+	return deviceFd(vd.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vd *vSurfaceDial) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vd.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vd *vSurfaceDial) EmitEvents(events []InputEvent) error {
+	return emitEvents(vd.deviceFile, events)
+}
+
+func (vd *vSurfaceDial) Sync() error {
+	return writeSyncEvent(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) SysPath() (string, error) {
+	return sysPath(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) EventPath() (string, error) {
+	return eventPath(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) Stats() Stats {
+	return deviceStats(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) State() State {
+	return deviceState(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) ResetAll() error {
+	return resetDevice(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) Disconnect() error {
+	return disconnectDevice(vd.deviceFile)
+}
+
+func (vd *vSurfaceDial) Reconnect() error {
+	return reconnectDevice(vd.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vd *vSurfaceDial) Close() error {
+	return closeDevice(vd.deviceFile)
+}
+
+// floorDiv divides a by b (b > 0), rounding towards negative infinity. Unlike truncating division, this yields
+// consistent detents for rotations in both directions.
+func floorDiv(a int64, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+func createSurfaceDial(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create surface dial input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	err = ioctl(deviceFile, uiSetKeyBit, uintptr(Btn0))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register button event %v: %w", Btn0, err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evRel))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register relative axis input device: %w", err)
+	}
+	for _, event := range []int{relDial, relWheel, RelWheelHiRes} {
+		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register relative event %v: %w", event, err)
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: BusBluetooth,
+				Vendor:  surfaceDialVendor,
+				Product: surfaceDialProduct,
+				Version: 1}},
+		cfg)
+}
@@ -0,0 +1,81 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicSurfaceDial(t *testing.T) {
+	dial, err := CreateSurfaceDial("/dev/uinput", []byte("Test Surface Dial"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual surface dial. Last error was: %s\n", err)
+	}
+
+	err = dial.Rotate(25)
+	if err != nil {
+		t.Fatalf("Failed to rotate the dial. Last error was: %s\n", err)
+	}
+
+	err = dial.Click()
+	if err != nil {
+		t.Fatalf("Failed to click the dial. Last error was: %s\n", err)
+	}
+
+	err = dial.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestSurfaceDialCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateSurfaceDial("", []byte("SurfaceDial"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestSurfaceDialRotationModes(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-surfacedial-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dial := &vSurfaceDial{name: []byte("Test Surface Dial"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	for _, step := range []func() error{
+		func() error { return dial.Rotate(6) },  // no detent reached yet
+		func() error { return dial.Rotate(6) },  // first detent at 10 degrees
+		func() error { return dial.Rotate(-3) }, // back to 9 degrees, crossing the detent again
+		func() error { return dial.SetRotationMode(DialSmooth) },
+		func() error { return dial.Rotate(2.5) }, // smooth rotation to 11.5 degrees, crossing the detent
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to use dial: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: relDial, Value: 100},
+		{Type: evRel, Code: relWheel, Value: -1},
+		{Type: evRel, Code: RelWheelHiRes, Value: -120},
+		{Type: evRel, Code: relDial, Value: -100},
+		{Type: evRel, Code: relWheel, Value: 1},
+		{Type: evRel, Code: RelWheelHiRes, Value: 120},
+		{Type: evRel, Code: relDial, Value: 25},
+		{Type: evRel, Code: relWheel, Value: -1},
+		{Type: evRel, Code: RelWheelHiRes, Value: -30},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestSurfaceDialRejectsInvalidMode(t *testing.T) {
+	dial := &vSurfaceDial{name: []byte("Test Surface Dial")}
+	err := dial.SetRotationMode(2)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// A SwitchDevice is a device with switches that are either on or off, like the lid switch of a laptop (SwLid), the
+// tablet mode switch of a convertible (SwTabletMode) or the jack of headphones (SwHeadphoneInsert). Consumers like
+// systemd-logind or the desktop react to these switches, for example by suspending when the lid is closed.
+type SwitchDevice interface {
+	// SetSwitch will switch the given switch on or off. Note that all switches are off when the device is created.
+	SetSwitch(code uint16, on bool) error
+
+	Device
+}
+
+type vSwitchDevice struct {
+	name       []byte
+	deviceFile *uinputDevice
+	switches   []uint16
+}
+
+// CreateSwitchDevice will create a new device that provides the given switches (see the Sw constants).
+func CreateSwitchDevice(path string, name []byte, switches []uint16, opts ...Option) (SwitchDevice, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(switches) == 0 {
+		return nil, errorf(ErrInvalidArgument, "at least one switch is required")
+	}
+	for _, code := range switches {
+		if code > swMax {
+			return nil, errorf(ErrInvalidArgument, "switch %d is out of range. Expected a switch code up to %d", code, swMax)
+		}
+	}
+
+	cfg, err := applyOptions("CreateSwitchDevice", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createSwitchDevice(path, name, switches, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return vSwitchDevice{name: name, deviceFile: fd, switches: switches}, nil
+}
+
+// SetSwitch will switch the given switch on or off.
+func (vs vSwitchDevice) SetSwitch(code uint16, on bool) error {
+	if !switchSupported(vs.switches, code) {
+		return errorf(ErrInvalidArgument, "failed to perform SetSwitch. Switch %d is not available on this device", code)
+	}
+
+	var value int32
+	if on {
+		value = 1
+	}
+	return sendEvents(vs.deviceFile, []inputEvent{{Type: EvSw, Code: code, Value: value}})
+}
+
+func (vs vSwitchDevice) Name() string {
+	return string(vs.name)
+}
+
+func (vs vSwitchDevice) Path() string {
+	return devicePath(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) Fd() uintptr {
+	return deviceFd(vs.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vs vSwitchDevice) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vs.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vs vSwitchDevice) EmitEvents(events []InputEvent) error {
+	return emitEvents(vs.deviceFile, events)
+}
+
+func (vs vSwitchDevice) Sync() error {
+	return writeSyncEvent(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) SysPath() (string, error) {
+	return sysPath(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) EventPath() (string, error) {
+	return eventPath(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vs.deviceFile)
+}
+
+func (vs vSwitchDevice) Stats() Stats {
+	return deviceStats(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) State() State {
+	return deviceState(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) ResetAll() error {
+	return resetDevice(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) Disconnect() error {
+	return disconnectDevice(vs.deviceFile)
+}
+
+func (vs vSwitchDevice) Reconnect() error {
+	return reconnectDevice(vs.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vs vSwitchDevice) Close() error {
+	return closeDevice(vs.deviceFile)
+}
+
+func switchSupported(switches []uint16, code uint16) bool {
+	for _, c := range switches {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func createSwitchDevice(path string, name []byte, switches []uint16, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create switch input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(EvSw))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register switch device: %w", err)
+	}
+	for _, code := range switches {
+		err = ioctl(deviceFile, uiSetSwBit, uintptr(code))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register switch %v: %w", code, err)
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: BusVirtual,
+				Vendor:  0x4711,
+				Product: 0x081b,
+				Version: 1}},
+		cfg)
+}
@@ -0,0 +1,76 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicSwitchDevice(t *testing.T) {
+	dev, err := CreateSwitchDevice("/dev/uinput", []byte("Test Switches"), []uint16{SwLid, SwTabletMode})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual switch device. Last error was: %s\n", err)
+	}
+
+	err = dev.SetSwitch(SwLid, true)
+	if err != nil {
+		t.Fatalf("Failed to close the lid. Last error was: %s\n", err)
+	}
+
+	err = dev.SetSwitch(SwLid, false)
+	if err != nil {
+		t.Fatalf("Failed to open the lid. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestSwitchDeviceCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateSwitchDevice("", []byte("Switches"), []uint16{SwLid})
+	assertPathDiscovered(t, device, err)
+}
+
+func TestSwitchDeviceCreationFailsOnInvalidSwitches(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-switch-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	for _, switches := range [][]uint16{nil, {SwLid, swMax + 1}} {
+		_, err = CreateSwitchDevice(file.Name(), []byte("Switches"), switches)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %v, got %v", switches, err)
+		}
+	}
+}
+
+func TestSetSwitch(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-switch-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dev := vSwitchDevice{name: []byte("Test Switches"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)), switches: []uint16{SwTabletMode}}
+	err = dev.SetSwitch(SwTabletMode, true)
+	if err != nil {
+		t.Fatalf("Failed to set switch: %v", err)
+	}
+	err = dev.SetSwitch(SwLid, true)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unavailable switch, got %v", err)
+	}
+
+	expected := []inputEvent{{Type: EvSw, Code: SwTabletMode, Value: 1}}
+	if events := readEvents(t, file.Name(), EvSw); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
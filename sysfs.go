@@ -0,0 +1,86 @@
+package uinput
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// sysfsInputPath is the sysfs directory that contains all virtual input devices, including those created via uinput.
+const sysfsInputPath = "/sys/devices/virtual/input"
+
+// devInputPath is the directory that contains the event nodes of all input devices.
+const devInputPath = "/dev/input"
+
+// sysPath returns the sysfs directory of the device (e.g. /sys/devices/virtual/input/input23). UI_GET_SYSNAME is
+// supported on kernels 3.15 and later.
+func sysPath(deviceFile *uinputDevice) (string, error) {
+	var name [sysnameSize]byte
+	err := ioctlPtr(deviceFile.file, uiGetSysname, unsafe.Pointer(&name[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to get sysfs name of device: %w", err)
+	}
+	n := bytes.IndexByte(name[:], 0)
+	if n < 0 {
+		n = len(name)
+	}
+	return filepath.Join(sysfsInputPath, string(name[:n])), nil
+}
+
+// eventPath returns the event node of the device (e.g. /dev/input/event7), which may be opened by consumers in order
+// to read the events emitted by the device. The node is looked up in the sysfs directory of the device.
+func eventPath(deviceFile *uinputDevice) (string, error) {
+	dir, err := sysPath(deviceFile)
+	if err != nil {
+		return "", err
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read sysfs directory of device: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "event") {
+			return filepath.Join(devInputPath, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("failed to find event node of device in %s", dir)
+}
+
+// readyPollInterval is the interval in which waitReady checks whether the event node of a device is available.
+const readyPollInterval = 10 * time.Millisecond
+
+// waitReady blocks until the event node of the device exists and may be opened, or until the context is done. Event
+// nodes are created asynchronously (and their permissions are adjusted by udev), which is why consumers may miss
+// events that are sent right after the device has been created.
+func waitReady(ctx context.Context, deviceFile *uinputDevice) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		path, err := eventPath(deviceFile)
+		if errors.Is(err, ErrDeviceClosed) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EINVAL) {
+			// the device is gone or the kernel does not support UI_GET_SYSNAME, so waiting won't help
+			return err
+		}
+		if err == nil {
+			var node *os.File
+			node, err = os.OpenFile(path, os.O_RDONLY, 0)
+			if err == nil {
+				return node.Close()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("device did not become ready: %w (last error: %v)", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
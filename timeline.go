@@ -0,0 +1,124 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// spinThreshold is the time before a deadline at which playback stops sleeping and starts spinning instead, since
+// the wake-up of a sleeping goroutine may be delayed by the scheduler and the timer resolution of the system.
+const spinThreshold = 500 * time.Microsecond
+
+// A Timeline is a sequence of event frames, each of which is emitted at a given offset from the start of the
+// playback. Deadlines are computed from the start of the playback using the monotonic clock, so that delays (like
+// slow writes) do not add up over time. This allows for accurate timing, as required for rhythm games or latency
+// tests, for example. Timelines may be played several times, but must not be modified while being played.
+type Timeline struct {
+	entries []timelineEntry
+}
+
+type timelineEntry struct {
+	offset time.Duration
+	events []InputEvent
+}
+
+// NewTimeline will create a new, empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// At will add a frame of the given events, which is emitted once the given offset from the start of the playback has
+// passed. Frames with the same offset are emitted in the order they have been added.
+func (t *Timeline) At(offset time.Duration, events ...InputEvent) *Timeline {
+	t.entries = append(t.entries, timelineEntry{offset: offset, events: events})
+	return t
+}
+
+// After will add a frame of the given events, which is emitted once the given delay after the frame that has been
+// added last has passed.
+func (t *Timeline) After(delay time.Duration, events ...InputEvent) *Timeline {
+	var last time.Duration
+	if len(t.entries) > 0 {
+		last = t.entries[len(t.entries)-1].offset
+	}
+	return t.At(last+delay, events...)
+}
+
+// Duration will return the offset of the last frame of the timeline.
+func (t *Timeline) Duration() time.Duration {
+	var duration time.Duration
+	for _, entry := range t.entries {
+		if entry.offset > duration {
+			duration = entry.offset
+		}
+	}
+	return duration
+}
+
+// Play will emit all frames of the timeline through the device at their offsets. Playing blocks until the last frame
+// has been emitted.
+func (t *Timeline) Play(device EventEmitter) error {
+	return t.PlayContext(context.Background(), device)
+}
+
+// PlayContext will play the timeline just like Play, but stops once the context is done. Frames that have not been
+// emitted by then are skipped and the error of the context is returned.
+func (t *Timeline) PlayContext(ctx context.Context, device EventEmitter) error {
+	entries := t.sorted()
+	for _, entry := range entries {
+		if len(entry.events) == 0 {
+			return errorf(ErrInvalidArgument, "failed to play timeline. The frame at %v is empty", entry.offset)
+		}
+	}
+
+	start := time.Now()
+	for _, entry := range entries {
+		err := sleepUntil(ctx, start.Add(entry.offset))
+		if err != nil {
+			return fmt.Errorf("failed to play the frame at %v: %w", entry.offset, err)
+		}
+		err = device.EmitEvents(entry.events)
+		if err != nil {
+			return fmt.Errorf("failed to play the frame at %v: %w", entry.offset, err)
+		}
+	}
+	return nil
+}
+
+// sorted returns the entries ordered by their offsets, keeping the order of entries with the same offset.
+func (t *Timeline) sorted() []timelineEntry {
+	entries := append([]timelineEntry(nil), t.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].offset < entries[j].offset
+	})
+	return entries
+}
+
+// sleepUntil blocks until the deadline has passed or the context is done, in which case the error of the context is
+// returned. Once the deadline has passed, nil is returned even if the context is done by then, as the wait has been
+// completed. It sleeps until shortly before the deadline and spins for the remaining time, which is far more accurate
+// than sleeping only.
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	if remaining := time.Until(deadline) - spinThreshold; remaining > 0 {
+		timer := time.NewTimer(remaining)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			if !time.Now().Before(deadline) {
+				return nil
+			}
+			return ctx.Err()
+		}
+	}
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		runtime.Gosched()
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// timedRecorder is an event emitter that records when each frame has been emitted
+type timedRecorder struct {
+	start  time.Time
+	times  []time.Duration
+	frames [][]InputEvent
+}
+
+func (r *timedRecorder) EmitEvents(events []InputEvent) error {
+	r.times = append(r.times, time.Since(r.start))
+	r.frames = append(r.frames, events)
+	return nil
+}
+
+func TestTimelinePlaysFramesInOrderOfOffsets(t *testing.T) {
+	press := InputEvent{Type: EvKey, Code: BtnA, Value: 1}
+	release := InputEvent{Type: EvKey, Code: BtnA, Value: 0}
+	axis := InputEvent{Type: EvAbs, Code: AbsX, Value: 100}
+
+	timeline := NewTimeline().
+		At(20*time.Millisecond, release).
+		At(0, press).
+		After(5*time.Millisecond, axis)
+	if timeline.Duration() != 20*time.Millisecond {
+		t.Fatalf("Expected: %v\nActual: %v", 20*time.Millisecond, timeline.Duration())
+	}
+
+	recorder := &timedRecorder{start: time.Now()}
+	err := timeline.Play(recorder)
+	if err != nil {
+		t.Fatalf("Failed to play the timeline: %v", err)
+	}
+
+	expected := [][]InputEvent{{press}, {axis}, {release}}
+	if !reflect.DeepEqual(recorder.frames, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, recorder.frames)
+	}
+	for i, offset := range []time.Duration{0, 5 * time.Millisecond, 20 * time.Millisecond} {
+		if recorder.times[i] < offset || recorder.times[i] > offset+10*time.Millisecond {
+			t.Fatalf("Expected frame %d to be emitted at %v, was emitted at %v", i, offset, recorder.times[i])
+		}
+	}
+}
+
+func TestTimelineRejectsEmptyFrames(t *testing.T) {
+	recorder := &timedRecorder{start: time.Now()}
+	err := NewTimeline().At(0, InputEvent{Type: EvKey, Code: BtnA, Value: 1}).At(time.Second).Play(recorder)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an empty frame, got: %v", err)
+	}
+	if len(recorder.frames) != 0 {
+		t.Fatalf("Expected nothing to be emitted, got %v", recorder.frames)
+	}
+}
+
+func TestSleepUntilIsAccurate(t *testing.T) {
+	deadline := time.Now().Add(3 * time.Millisecond)
+	if err := sleepUntil(context.Background(), deadline); err != nil {
+		t.Fatalf("Failed to sleep: %v", err)
+	}
+	if late := time.Since(deadline); late < 0 || late > time.Millisecond {
+		t.Fatalf("Expected to wake up within 1ms after the deadline, woke up %v late", late)
+	}
+}
+
+func TestSleepUntilSucceedsIfContextEndsAtDeadline(t *testing.T) {
+	deadline := time.Now().Add(3 * time.Millisecond)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := sleepUntil(ctx, deadline); err != nil {
+		t.Fatalf("Expected the completed wait to succeed, got: %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
-	"io"
-	"os"
+	"time"
 )
 
 // A TouchPad is an input device that uses absolute axis events, meaning that you can specify
@@ -13,6 +13,11 @@ type TouchPad interface {
 	// MoveTo will move the cursor to the specified position on the screen
 	MoveTo(x int32, y int32) error
 
+	// MoveToSmooth will move the cursor to the specified position in steps that are spread evenly across the
+	// duration, starting from the position of the last move. This way, the cursor glides along the path rather than
+	// jumping to the target. If the cursor has not been moved before, it is moved to the position directly.
+	MoveToSmooth(x int32, y int32, duration time.Duration) error
+
 	// LeftClick will issue a single left click.
 	LeftClick() error
 
@@ -39,18 +44,23 @@ type TouchPad interface {
 	// TouchUp will end or ,more precisely, unset the touch event issued by TouchDown
 	TouchUp() error
 
-	io.Closer
+	Device
 }
 
+// the buttons of touch pads, which includes the fingers that rest on the pad
+var touchPadButtons = []int{evBtnLeft, evBtnRight, evBtnTouch, evBtnToolFinger}
+
 type vTouchPad struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *uinputDevice
+	position   *pointerPosition
+	human      *humanizer
 }
 
 // CreateTouchPad will create a new touch pad device. note that you will need to define the x and y axis boundaries
 // (min and max) within which the cursor maybe moved around.
-func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (TouchPad, error) {
-	err := validateDevicePath(path)
+func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...Option) (TouchPad, error) {
+	err := validateDevicePath(path, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,22 +69,43 @@ func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		return nil, err
 	}
 
-	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY)
+	cfg, err := applyOptions("CreateTouchPad", opts, optHumanizedMovement)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createTouchPad(path, name, touchPadButtons, 0x0817, minX, maxX, minY, maxY, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return vTouchPad{name: name, deviceFile: fd}, nil
+	return vTouchPad{name: name, deviceFile: fd, position: &pointerPosition{}, human: newHumanizer(cfg)}, nil
 }
 
 func (vTouch vTouchPad) MoveTo(x int32, y int32) error {
-	return sendAbsEvent(vTouch.deviceFile, x, y)
+	err := sendAbsEvent(vTouch.deviceFile, x, y)
+	if err != nil {
+		return err
+	}
+	vTouch.position.set(x, y)
+	return nil
+}
+
+func (vTouch vTouchPad) MoveToSmooth(x int32, y int32, duration time.Duration) error {
+	fromX, fromY, known := vTouch.position.get()
+	if !known {
+		return vTouch.MoveTo(x, y)
+	}
+	deltas := vTouch.human.interpolate(x-fromX, y-fromY, moveSteps(duration, 0))
+	return moveTimed(context.Background(), duration, len(deltas), func(i int) error {
+		fromX, fromY = fromX+deltas[i][0], fromY+deltas[i][1]
+		return vTouch.MoveTo(fromX, fromY)
+	})
 }
 
 func (vTouch vTouchPad) LeftClick() error {
 	err := sendBtnEvent(vTouch.deviceFile, []int{evBtnLeft}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("Failed to issue the LeftClick event: %w", err)
 	}
 
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnLeft}, btnStateReleased)
@@ -83,7 +114,7 @@ func (vTouch vTouchPad) LeftClick() error {
 func (vTouch vTouchPad) RightClick() error {
 	err := sendBtnEvent(vTouch.deviceFile, []int{evBtnRight}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("Failed to issue the RightClick event: %w", err)
 	}
 
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnRight}, btnStateReleased)
@@ -119,34 +150,92 @@ func (vTouch vTouchPad) TouchUp() error {
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch, evBtnToolFinger}, btnStateReleased)
 }
 
+func (vTouch vTouchPad) Name() string {
+	return string(vTouch.name)
+}
+
+func (vTouch vTouchPad) Path() string {
+	return devicePath(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) Fd() uintptr {
+	return deviceFd(vTouch.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vTouch vTouchPad) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vTouch.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vTouch vTouchPad) EmitEvents(events []InputEvent) error {
+	return emitEvents(vTouch.deviceFile, events)
+}
+
+func (vTouch vTouchPad) Sync() error {
+	return writeSyncEvent(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) SysPath() (string, error) {
+	return sysPath(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) EventPath() (string, error) {
+	return eventPath(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) Stats() Stats {
+	return deviceStats(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) State() State {
+	return deviceState(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) ResetAll() error {
+	return resetDevice(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) Disconnect() error {
+	return disconnectDevice(vTouch.deviceFile)
+}
+
+func (vTouch vTouchPad) Reconnect() error {
+	return reconnectDevice(vTouch.deviceFile)
+}
+
 func (vTouch vTouchPad) Close() error {
 	return closeDevice(vTouch.deviceFile)
 }
 
-func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+func createTouchPad(path string, name []byte, buttons []int, product uint16, minX int32, maxX int32, minY int32, maxY int32, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
+		return nil, fmt.Errorf("could not create absolute axis input device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
-	// register button events (in order to enable left and right click)
-	for _, event := range []int{evBtnLeft, evBtnRight, evBtnTouch, evBtnToolFinger} {
+	// register button events (in order to enable clicks and touches)
+	for _, event := range buttons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register button event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
 		}
 	}
 
 	err = registerDevice(deviceFile, uintptr(evAbs))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute axis input device: %v", err)
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
 	}
 
 	// register x and y axis events
@@ -154,7 +243,7 @@ func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute axis event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
 		}
 	}
 
@@ -166,19 +255,25 @@ func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 	absMax[absX] = maxX
 	absMax[absY] = maxY
 
-	return createUsbDevice(deviceFile,
+	fd, err = createUsbDevice(deviceFile,
 		uinputUserDev{
 			Name: toUinputName(name),
 			ID: inputID{
 				Bustype: busUsb,
 				Vendor:  0x4711,
-				Product: 0x0817,
+				Product: product,
 				Version: 1},
 			Absmin: absMin,
-			Absmax: absMax})
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	fd.state.setPositions(absX, absY)
+	return fd, nil
 }
 
-func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error {
+func sendAbsEvent(deviceFile *uinputDevice, xPos int32, yPos int32) error {
 	var ev [2]inputEvent
 	ev[0].Type = evAbs
 	ev[0].Code = absX
@@ -196,17 +291,5 @@ func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error {
 	ev[1].Code = absY
 	ev[1].Value = yPos
 
-	for _, iev := range ev {
-		buf, err := inputEventToBuffer(iev)
-		if err != nil {
-			return fmt.Errorf("writing abs event failed: %v", err)
-		}
-
-		_, err = deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs event to device file: %v", err)
-		}
-	}
-
-	return syncEvents(deviceFile)
+	return sendEvents(deviceFile, ev[:])
 }
@@ -58,12 +58,9 @@ func TestBasicTouchPadMoves(t *testing.T) {
 	}
 }
 
-func TestTouchPadCreationFailsOnEmptyPath(t *testing.T) {
-	expected := "device path must not be empty"
-	_, err := CreateTouchPad("", []byte("TouchDevice"), 0, 1024, 0, 768)
-	if err.Error() != expected {
-		t.Fatalf("Expected: %s\nActual: %s", expected, err)
-	}
+func TestTouchPadCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateTouchPad("", []byte("TouchDevice"), 0, 1024, 0, 768)
+	assertPathDiscovered(t, device, err)
 }
 
 func TestTouchPadCreationFailsOnNonExistentPathName(t *testing.T) {
@@ -234,3 +231,15 @@ func TestSingleTouchEvent(t *testing.T) {
 	}
 
 }
+
+func TestTouchPadWithProperties(t *testing.T) {
+	dev, err := CreateTouchPad("/dev/uinput", []byte("touchpad"), 0, 200, 0, 100, WithProperties(PropPointer, PropButtonpad))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
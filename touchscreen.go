@@ -0,0 +1,548 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A TouchScreen is a multitouch input device that follows the slot based multitouch protocol (type B), as
+// described in https://www.kernel.org/doc/Documentation/input/multi-touch-protocol.txt. Each contact is bound
+// to a slot, which is either selected by the caller or picked by Touch. Slot selection and tracking ids are handled
+// by the device.
+type TouchScreen interface {
+	// Touch will place a new contact at the given position using the lowest free slot. The returned handle is used to
+	// move and lift the contact, so that slots and tracking ids are of no concern to the caller.
+	Touch(x int32, y int32) (*Contact, error)
+
+	// NewFrame returns a frame that batches changes to contacts, which are addressed by ids of the caller's choice.
+	// All changes are reported as a single frame once the frame is committed.
+	NewFrame() *TouchFrame
+
+	// TouchDown will place a new contact at the given position using the given slot. The slot must not be in use
+	// already.
+	TouchDown(slot int, x int32, y int32) error
+
+	// TouchMove will move the contact of the given slot to the given position.
+	TouchMove(slot int, x int32, y int32) error
+
+	// TouchUp will lift the contact of the given slot, freeing the slot for new contacts.
+	TouchUp(slot int) error
+
+	// Swipe will swipe from one position to another with the given number of fingers, which are placed side by side.
+	// The call blocks until the gesture is complete.
+	Swipe(from Point, to Point, fingers int, duration time.Duration) error
+
+	// Pinch will place two fingers around the center and move them apart (scale > 1) or together (scale < 1) until
+	// their distance has changed by the given factor. The call blocks until the gesture is complete.
+	Pinch(center Point, scale float64, duration time.Duration) error
+
+	// Rotate will place two fingers around the center and rotate them by the given angle (clockwise for positive
+	// degrees). The call blocks until the gesture is complete.
+	Rotate(center Point, degrees float64, duration time.Duration) error
+
+	// PalmDown will place a palm at the given position using the given slot. The contact is reported as MT_TOOL_PALM
+	// with a large touch area, which palm rejection is expected to ignore. Use TouchUp to lift it.
+	PalmDown(slot int, x int32, y int32) error
+
+	// SetContactShape will change the tool type and touch area of the contact of the given slot, e.g. in order to
+	// turn a finger into a palm while it rests on the screen.
+	SetContactShape(slot int, shape ContactShape) error
+
+	Device
+}
+
+// the tool types of multitouch contacts (ABS_MT_TOOL_TYPE)
+const (
+	MtToolFinger = 0x00
+	MtToolPen    = 0x01
+	MtToolPalm   = 0x02
+)
+
+// A ContactShape describes the tool and the touch area of a contact. The touch area is an ellipse, whose major and
+// minor axes (ABS_MT_TOUCH_MAJOR and ABS_MT_TOUCH_MINOR) are given in the units of the position axes. The zero value
+// is a finger without any touch area, which is what TouchDown reports.
+type ContactShape struct {
+	Tool  int32 // one of MtToolFinger, MtToolPen or MtToolPalm
+	Major int32
+	Minor int32
+}
+
+type vTouchScreen struct {
+	name       []byte
+	deviceFile *uinputDevice
+	minX, maxX int32
+	minY, maxY int32
+
+	// mu guards the contact state, which needs to be updated along with the events that are sent
+	mu             sync.Mutex
+	trackingIDs    []int32
+	shapes         []ContactShape   // the last shape that has been reported for each slot
+	positions      []Point          // the last position that has been reported for each slot
+	contacts       map[int]*Contact // the contacts that have been placed by frames, by id
+	nextTrackingID int32
+	activeContacts int
+	protocolA      bool
+}
+
+// CreateTouchScreen will create a new multitouch touch screen device. Just like with the touch pad, the x and y axis
+// boundaries need to be defined upon creation. The number of slots determines the maximum number of simultaneous
+// contacts.
+func CreateTouchScreen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...Option) (TouchScreen, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+	if slots < 1 {
+		return nil, errorf(ErrInvalidArgument, "%d is not a valid number of slots. At least one slot is required", slots)
+	}
+
+	cfg, err := applyOptions("CreateTouchScreen", opts, optMultitouchProtocolA)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createTouchScreen(path, name, minX, maxX, minY, maxY, slots, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	trackingIDs := make([]int32, slots)
+	for i := range trackingIDs {
+		trackingIDs[i] = -1
+	}
+
+	vTouch := &vTouchScreen{name: name, deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, trackingIDs: trackingIDs,
+		shapes: make([]ContactShape, slots), positions: make([]Point, slots), protocolA: cfg.mtProtocolA}
+	fd.onReset = vTouch.liftContactsLocked
+	return vTouch, nil
+}
+
+// TouchDown will place a new contact at the given position using the given slot.
+func (vTouch *vTouchScreen) TouchDown(slot int, x int32, y int32) error {
+	err := vTouch.sendContacts([]contactChange{{kind: contactDown, slot: slot, x: x, y: y}})
+	if err != nil && !errors.Is(err, ErrInvalidArgument) {
+		return fmt.Errorf("failed to issue the TouchDown event: %w", err)
+	}
+	return err
+}
+
+// TouchMove will move the contact of the given slot to the given position.
+func (vTouch *vTouchScreen) TouchMove(slot int, x int32, y int32) error {
+	return vTouch.sendContacts([]contactChange{{kind: contactMove, slot: slot, x: x, y: y}})
+}
+
+// TouchUp will lift the contact of the given slot.
+func (vTouch *vTouchScreen) TouchUp(slot int) error {
+	err := vTouch.sendContacts([]contactChange{{kind: contactUp, slot: slot}})
+	if err != nil && !errors.Is(err, ErrInvalidArgument) {
+		return fmt.Errorf("failed to issue the TouchUp event: %w", err)
+	}
+	return err
+}
+
+// PalmDown will place a palm at the given position using the given slot.
+func (vTouch *vTouchScreen) PalmDown(slot int, x int32, y int32) error {
+	// palms cover about a fifth of the smaller side of the screen, which is well above the size of any finger
+	major := int32(vTouch.gestureDistance(0.2))
+	shape := ContactShape{Tool: MtToolPalm, Major: major, Minor: major * 3 / 4}
+	err := vTouch.sendContacts([]contactChange{{kind: contactDown, slot: slot, x: x, y: y, shape: shape}})
+	if err != nil && !errors.Is(err, ErrInvalidArgument) {
+		return fmt.Errorf("failed to issue the PalmDown event: %w", err)
+	}
+	return err
+}
+
+// SetContactShape will change the tool type and touch area of the contact of the given slot.
+func (vTouch *vTouchScreen) SetContactShape(slot int, shape ContactShape) error {
+	return vTouch.sendContacts([]contactChange{{kind: contactReshape, slot: slot, shape: shape}})
+}
+
+// the kinds of changes to the contacts of a touch screen
+const (
+	contactDown = iota
+	contactMove
+	contactUp
+	contactReshape
+)
+
+// a contactChange places, moves, reshapes or lifts the contact of a slot
+type contactChange struct {
+	kind  int
+	slot  int
+	x, y  int32
+	shape ContactShape // the shape of placed and reshaped contacts
+}
+
+// sendContacts applies all given changes within a single frame, so that contacts move simultaneously.
+func (vTouch *vTouchScreen) sendContacts(changes []contactChange) error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return vTouch.sendContactsLocked(changes)
+}
+
+// sendContactsLocked applies the changes to the contact state while building the events of the frame. The state is
+// restored if a change is invalid or the frame cannot be sent. The caller needs to hold mu.
+func (vTouch *vTouchScreen) sendContactsLocked(changes []contactChange) error {
+	trackingIDs := append([]int32(nil), vTouch.trackingIDs...)
+	shapes := append([]ContactShape(nil), vTouch.shapes...)
+	positions := append([]Point(nil), vTouch.positions...)
+	nextTrackingID, activeContacts := vTouch.nextTrackingID, vTouch.activeContacts
+	restore := func() {
+		copy(vTouch.trackingIDs, trackingIDs)
+		copy(vTouch.shapes, shapes)
+		copy(vTouch.positions, positions)
+		vTouch.nextTrackingID, vTouch.activeContacts = nextTrackingID, activeContacts
+	}
+
+	var events []inputEvent
+	for _, change := range changes {
+		var err error
+		events, err = vTouch.appendContactEvents(events, change)
+		if err != nil {
+			restore()
+			return err
+		}
+	}
+
+	if vTouch.protocolA {
+		events = vTouch.protocolAEvents(events)
+	}
+	err := sendEvents(vTouch.deviceFile, events)
+	if err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// appendContactEvents applies the change to the contact state and appends the respective events.
+func (vTouch *vTouchScreen) appendContactEvents(events []inputEvent, change contactChange) ([]inputEvent, error) {
+	slot := change.slot
+	switch change.kind {
+	case contactDown:
+		if err := vTouch.assertSlotInRange(slot); err != nil {
+			return nil, err
+		}
+		if vTouch.trackingIDs[slot] != -1 {
+			return nil, errorf(ErrInvalidArgument, "failed to perform TouchDown. Slot %d is already in use", slot)
+		}
+		if err := vTouch.assertShapeValid(change.shape); err != nil {
+			return nil, err
+		}
+
+		trackingID := vTouch.allocateTrackingID()
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtSlot, Value: int32(slot)},
+			inputEvent{Type: evAbs, Code: absMtTrackingID, Value: trackingID})
+		events = vTouch.appendShapeEvents(events, slot, change.shape)
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtPositionX, Value: change.x},
+			inputEvent{Type: evAbs, Code: absMtPositionY, Value: change.y})
+		if vTouch.activeContacts == 0 {
+			events = append(events,
+				inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+				inputEvent{Type: evAbs, Code: absX, Value: change.x},
+				inputEvent{Type: evAbs, Code: absY, Value: change.y})
+		}
+
+		vTouch.trackingIDs[slot] = trackingID
+		vTouch.positions[slot] = Point{X: change.x, Y: change.y}
+		vTouch.activeContacts++
+	case contactMove:
+		if err := vTouch.assertSlotInRange(slot); err != nil {
+			return nil, err
+		}
+		if vTouch.trackingIDs[slot] == -1 {
+			return nil, errorf(ErrInvalidArgument, "failed to perform TouchMove. Slot %d has no active contact", slot)
+		}
+
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtSlot, Value: int32(slot)},
+			inputEvent{Type: evAbs, Code: absMtPositionX, Value: change.x},
+			inputEvent{Type: evAbs, Code: absMtPositionY, Value: change.y})
+		vTouch.positions[slot] = Point{X: change.x, Y: change.y}
+		// single touch emulation follows the contact in the lowest active slot
+		if vTouch.lowestActiveSlot() == slot {
+			events = append(events,
+				inputEvent{Type: evAbs, Code: absX, Value: change.x},
+				inputEvent{Type: evAbs, Code: absY, Value: change.y})
+		}
+	case contactUp:
+		if err := vTouch.assertSlotInRange(slot); err != nil {
+			return nil, err
+		}
+		if vTouch.trackingIDs[slot] == -1 {
+			return nil, errorf(ErrInvalidArgument, "failed to perform TouchUp. Slot %d has no active contact", slot)
+		}
+
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absMtSlot, Value: int32(slot)},
+			inputEvent{Type: evAbs, Code: absMtTrackingID, Value: -1})
+		if vTouch.activeContacts == 1 {
+			events = append(events, inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStateReleased})
+		}
+
+		pointer := vTouch.lowestActiveSlot() == slot
+		vTouch.trackingIDs[slot] = -1
+		vTouch.activeContacts--
+		// single touch emulation moves on to the contact in the lowest remaining slot, just like the pointer
+		// emulation of the kernel does
+		if next := vTouch.lowestActiveSlot(); pointer && next != -1 {
+			events = append(events,
+				inputEvent{Type: evAbs, Code: absX, Value: vTouch.positions[next].X},
+				inputEvent{Type: evAbs, Code: absY, Value: vTouch.positions[next].Y})
+		}
+	case contactReshape:
+		if err := vTouch.assertSlotInRange(slot); err != nil {
+			return nil, err
+		}
+		if vTouch.trackingIDs[slot] == -1 {
+			return nil, errorf(ErrInvalidArgument, "failed to perform SetContactShape. Slot %d has no active contact", slot)
+		}
+		if err := vTouch.assertShapeValid(change.shape); err != nil {
+			return nil, err
+		}
+
+		events = append(events, inputEvent{Type: evAbs, Code: absMtSlot, Value: int32(slot)})
+		events = vTouch.appendShapeEvents(events, slot, change.shape)
+	}
+	return events, nil
+}
+
+// appendShapeEvents appends the axes of the shape that differ from the last shape reported for the slot. Slots keep
+// their values across contacts, so the shape of a new contact only needs to be reported where it differs.
+func (vTouch *vTouchScreen) appendShapeEvents(events []inputEvent, slot int, shape ContactShape) []inputEvent {
+	last := vTouch.shapes[slot]
+	if shape.Tool != last.Tool {
+		events = append(events, inputEvent{Type: evAbs, Code: AbsMtToolType, Value: shape.Tool})
+	}
+	if shape.Major != last.Major {
+		events = append(events, inputEvent{Type: evAbs, Code: AbsMtTouchMajor, Value: shape.Major})
+	}
+	if shape.Minor != last.Minor {
+		events = append(events, inputEvent{Type: evAbs, Code: AbsMtTouchMinor, Value: shape.Minor})
+	}
+	vTouch.shapes[slot] = shape
+	return events
+}
+
+func (vTouch *vTouchScreen) Name() string {
+	return string(vTouch.name)
+}
+
+func (vTouch *vTouchScreen) Path() string {
+	return devicePath(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) Fd() uintptr {
+	return deviceFd(vTouch.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vTouch *vTouchScreen) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vTouch.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vTouch *vTouchScreen) EmitEvents(events []InputEvent) error {
+	return emitEvents(vTouch.deviceFile, events)
+}
+
+func (vTouch *vTouchScreen) Sync() error {
+	return writeSyncEvent(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) SysPath() (string, error) {
+	return sysPath(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) EventPath() (string, error) {
+	return eventPath(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) Stats() Stats {
+	return deviceStats(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) State() State {
+	return deviceState(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) ResetAll() error {
+	// the contacts are locked first, as the reset hook updates them (see liftContactsLocked)
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return resetDevice(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) Disconnect() error {
+	return disconnectDevice(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) Reconnect() error {
+	return reconnectDevice(vTouch.deviceFile)
+}
+
+// Close will close the device and free resources.
+func (vTouch *vTouchScreen) Close() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return closeDevice(vTouch.deviceFile)
+}
+
+func (vTouch *vTouchScreen) assertSlotInRange(slot int) error {
+	if slot < 0 || slot >= len(vTouch.trackingIDs) {
+		return errorf(ErrInvalidArgument, "slot %d is out of range. Expected a value between 0 and %d", slot, len(vTouch.trackingIDs)-1)
+	}
+	return nil
+}
+
+func (vTouch *vTouchScreen) assertShapeValid(shape ContactShape) error {
+	if shape.Tool < MtToolFinger || shape.Tool > MtToolPalm {
+		return errorf(ErrInvalidArgument, "tool type %d is not supported. Expected MtToolFinger, MtToolPen or MtToolPalm", shape.Tool)
+	}
+	if touchMax := touchAxisMax(vTouch.minX, vTouch.maxX, vTouch.minY, vTouch.maxY); shape.Major < 0 || shape.Major > touchMax {
+		return errorf(ErrInvalidArgument, "touch major %d is out of range. Expected a value between 0 and %d", shape.Major, touchMax)
+	}
+	if shape.Minor < 0 || shape.Minor > shape.Major {
+		return errorf(ErrInvalidArgument, "touch minor %d is out of range. Expected a value between 0 and the touch major (%d)", shape.Minor, shape.Major)
+	}
+	return nil
+}
+
+// touchAxisMax returns the maximum of the touch area axes, which is the larger side of the touch screen.
+func touchAxisMax(minX int32, maxX int32, minY int32, maxY int32) int32 {
+	side := maxX - minX
+	if height := maxY - minY; height > side {
+		side = height
+	}
+	return side
+}
+
+// allocateTrackingID returns the next tracking id, skipping ids that are still in use by contacts that have been
+// placed before the ids wrapped around.
+func (vTouch *vTouchScreen) allocateTrackingID() int32 {
+	for {
+		trackingID := vTouch.nextTrackingID
+		vTouch.nextTrackingID = (trackingID + 1) % (trackingIDMax + 1)
+		inUse := false
+		for _, id := range vTouch.trackingIDs {
+			inUse = inUse || id == trackingID
+		}
+		if !inUse {
+			return trackingID
+		}
+	}
+}
+
+// liftContactsLocked is the reset hook of the touch screen, which forgets about all contacts once they have been lifted
+// by a reset. Handles to the contacts become invalid, while positions and shapes are kept. The caller needs to hold mu.
+func (vTouch *vTouchScreen) liftContactsLocked() {
+	for slot := range vTouch.trackingIDs {
+		vTouch.trackingIDs[slot] = -1
+	}
+	for _, c := range vTouch.contacts {
+		c.trackingID = -1
+	}
+	vTouch.contacts = nil
+	vTouch.activeContacts = 0
+}
+
+func (vTouch *vTouchScreen) lowestActiveSlot() int {
+	for slot, trackingID := range vTouch.trackingIDs {
+		if trackingID != -1 {
+			return slot
+		}
+	}
+	return -1
+}
+
+func createTouchScreen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create touch screen input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+
+	err = ioctl(deviceFile, uiSetKeyBit, uintptr(evBtnTouch))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register button event %v: %w", evBtnTouch, err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+
+	// register single touch emulation and multitouch axis events, protocol A has neither slots nor tracking ids
+	events := []int{absX, absY, absMtPositionX, absMtPositionY, AbsMtToolType, AbsMtTouchMajor, AbsMtTouchMinor}
+	if !cfg.mtProtocolA {
+		events = append(events, absMtSlot, absMtTrackingID)
+	}
+	for _, event := range events {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropDirect))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register direct input property: %w", err)
+	}
+
+	var absMin [absSize]int32
+	absMin[absX] = minX
+	absMin[absY] = minY
+	absMin[absMtPositionX] = minX
+	absMin[absMtPositionY] = minY
+
+	var absMax [absSize]int32
+	absMax[absX] = maxX
+	absMax[absY] = maxY
+	absMax[absMtPositionX] = maxX
+	absMax[absMtPositionY] = maxY
+	if !cfg.mtProtocolA {
+		absMax[absMtSlot] = int32(slots - 1)
+		absMax[absMtTrackingID] = trackingIDMax
+	}
+	absMax[AbsMtToolType] = MtToolPalm
+	absMax[AbsMtTouchMajor] = touchAxisMax(minX, maxX, minY, maxY)
+	absMax[AbsMtTouchMinor] = touchAxisMax(minX, maxX, minY, maxY)
+
+	fd, err = createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0818,
+				Version: 1},
+			Absmin: absMin,
+			Absmax: absMax},
+		cfg)
+	if err != nil {
+		return nil, err
+	}
+	fd.state.setPositions(absX, absY)
+	return fd, nil
+}
@@ -0,0 +1,258 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicTouchScreenGesture(t *testing.T) {
+	dev, err := CreateTouchScreen("/dev/uinput", []byte("Test TouchScreen"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch screen. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchDown(0, 100, 100)
+	if err != nil {
+		t.Fatalf("Failed to place first contact. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchDown(1, 200, 200)
+	if err != nil {
+		t.Fatalf("Failed to place second contact. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchMove(0, 50, 50)
+	if err != nil {
+		t.Fatalf("Failed to move first contact. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchMove(1, 250, 250)
+	if err != nil {
+		t.Fatalf("Failed to move second contact. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchUp(0)
+	if err != nil {
+		t.Fatalf("Failed to lift first contact. Last error was: %s\n", err)
+	}
+
+	err = dev.TouchUp(1)
+	if err != nil {
+		t.Fatalf("Failed to lift second contact. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestTouchScreenCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateTouchScreen("", []byte("TouchScreenDevice"), 0, 1024, 0, 768, 2)
+	assertPathDiscovered(t, device, err)
+}
+
+func TestTouchScreenCreationFailsOnNonExistentPathName(t *testing.T) {
+	path := "/some/bogus/path"
+	_, err := CreateTouchScreen(path, []byte("TouchScreenDevice"), 0, 1024, 0, 768, 2)
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected: os.IsNotExist error\nActual: %s", err)
+	}
+}
+
+func TestTouchScreenCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-touchscreen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateTouchScreen(file.Name(), []byte("TouchScreenDevice"), 0, 1024, 0, 768, 2)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchScreenCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateTouchScreen("/dev/uinput", []byte(name), 0, 1024, 0, 768, 2)
+	if err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchScreenCreationFailsWithoutSlots(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-touchscreen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "0 is not a valid number of slots. At least one slot is required"
+	_, err = CreateTouchScreen(file.Name(), []byte("TouchScreenDevice"), 0, 1024, 0, 768, 0)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchScreenSlotOutOfRangeFails(t *testing.T) {
+	dev, err := CreateTouchScreen("/dev/uinput", []byte("Test TouchScreen"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch screen. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.TouchDown(2, 1, 1); err == nil {
+		t.Fatalf("Expected TouchDown to fail due to invalid slot, but got no error.")
+	}
+
+	if err = dev.TouchDown(-1, 1, 1); err == nil {
+		t.Fatalf("Expected TouchDown to fail due to invalid slot, but got no error.")
+	}
+}
+
+func TestTouchScreenInactiveSlotFails(t *testing.T) {
+	dev, err := CreateTouchScreen("/dev/uinput", []byte("Test TouchScreen"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch screen. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	if err = dev.TouchMove(0, 1, 1); err == nil {
+		t.Fatalf("Expected TouchMove to fail due to inactive slot, but got no error.")
+	}
+
+	if err = dev.TouchUp(0); err == nil {
+		t.Fatalf("Expected TouchUp to fail due to inactive slot, but got no error.")
+	}
+}
+
+func TestTouchScreenTouchDownFailsOnClosedDevice(t *testing.T) {
+	dev, err := CreateTouchScreen("/dev/uinput", []byte("Test TouchScreen"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch screen. Last error was: %s\n", err)
+	}
+	_ = dev.Close()
+	err = dev.TouchDown(0, 1, 1)
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestTouchScreenReportsPalmsAndResetsShape(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 1)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	steps := []func() error{
+		func() error { return vTouch.TouchDown(0, 100, 100) },
+		func() error { return vTouch.SetContactShape(0, ContactShape{Tool: MtToolPalm, Major: 200, Minor: 150}) },
+		func() error { return vTouch.TouchUp(0) },
+		// the next finger in the slot needs to reset the shape of the palm
+		func() error { return vTouch.TouchDown(0, 100, 100) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to perform step: %v", err)
+		}
+	}
+
+	var shapes []inputEvent
+	for _, iev := range readEvents(t, file.Name(), evAbs) {
+		if iev.Code == AbsMtToolType || iev.Code == AbsMtTouchMajor || iev.Code == AbsMtTouchMinor {
+			shapes = append(shapes, iev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evAbs, Code: AbsMtToolType, Value: MtToolPalm},
+		{Type: evAbs, Code: AbsMtTouchMajor, Value: 200},
+		{Type: evAbs, Code: AbsMtTouchMinor, Value: 150},
+		{Type: evAbs, Code: AbsMtToolType, Value: MtToolFinger},
+		{Type: evAbs, Code: AbsMtTouchMajor, Value: 0},
+		{Type: evAbs, Code: AbsMtTouchMinor, Value: 0},
+	}
+	if !reflect.DeepEqual(shapes, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, shapes)
+	}
+}
+
+func TestTouchScreenPointerEmulationFollowsRemainingContact(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 3)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	steps := []func() error{
+		func() error { return vTouch.TouchDown(0, 100, 100) },
+		func() error { return vTouch.TouchDown(2, 300, 300) },
+		func() error { return vTouch.TouchDown(1, 200, 200) },
+		// the pointer moves on to slot 1, while lifting slot 2 afterwards leaves it there
+		func() error { return vTouch.TouchUp(0) },
+		func() error { return vTouch.TouchUp(2) },
+		func() error { return vTouch.TouchUp(1) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to perform step: %v", err)
+		}
+	}
+
+	var pointer []inputEvent
+	for _, iev := range readEvents(t, file.Name(), evAbs) {
+		if iev.Code == absX || iev.Code == absY {
+			pointer = append(pointer, iev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 100},
+		{Type: evAbs, Code: absY, Value: 100},
+		{Type: evAbs, Code: absX, Value: 200},
+		{Type: evAbs, Code: absY, Value: 200},
+	}
+	if !reflect.DeepEqual(pointer, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, pointer)
+	}
+}
+
+func TestTouchScreenPalmDownUsesPalmTool(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 1)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err := vTouch.PalmDown(0, 500, 500)
+	if err != nil {
+		t.Fatalf("Failed to place palm: %v", err)
+	}
+	if shape := vTouch.shapes[0]; shape.Tool != MtToolPalm || shape.Major != 200 || shape.Minor != 150 {
+		t.Fatalf("Expected a palm covering a fifth of the screen, got %+v", shape)
+	}
+}
+
+func TestTouchScreenRejectsInvalidShapes(t *testing.T) {
+	vTouch, file := newTestTouchScreen(t, 1)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	err := vTouch.SetContactShape(0, ContactShape{Tool: MtToolPalm})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a slot without contact, got: %v", err)
+	}
+	err = vTouch.TouchDown(0, 10, 10)
+	if err != nil {
+		t.Fatalf("Failed to place contact: %v", err)
+	}
+	for _, shape := range []ContactShape{{Tool: 3}, {Major: 1001}, {Major: 10, Minor: 20}, {Major: -1}} {
+		err = vTouch.SetContactShape(0, shape)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("Expected an invalid argument error for %+v, got: %v", shape, err)
+		}
+	}
+	if vTouch.shapes[0] != (ContactShape{}) {
+		t.Fatalf("Expected the shape to remain unchanged, got %+v", vTouch.shapes[0])
+	}
+}
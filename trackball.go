@@ -0,0 +1,242 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// trackballCountsPerDegree is the number of counts a trackball reports per degree of rotation of its ball. A 34mm
+// ball read at 400 CPI reports about 1600 counts per revolution.
+const trackballCountsPerDegree = 1600.0 / 360
+
+var trackballButtons = []int{BtnLeft, BtnRight, BtnMiddle}
+
+// A Trackball is a pointer device whose ball is rotated instead of moving the device itself. Unlike mice, trackballs
+// have no wheel and report INPUT_PROP_POINTER. libinput treats devices that carry the ID_INPUT_TRACKBALL udev
+// property as trackballs (which enables button scrolling, for instance), see the README for a matching udev rule.
+type Trackball interface {
+	// Move will move the pointer by the given number of counts along the x and y axes.
+	Move(x, y int32) error
+
+	// Spin will rotate the ball by the given angles (in degrees), where positive values move the pointer right (dx)
+	// and down (dy). Fractions of a count add up, so that slow spins move the pointer eventually.
+	Spin(dx, dy float64) error
+
+	// ButtonPress will press the given button (BtnLeft, BtnRight or BtnMiddle) and immediately release it.
+	ButtonPress(button int) error
+
+	// ButtonDown will press the given button. Note that the button remains pressed until ButtonUp is called.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button.
+	ButtonUp(button int) error
+
+	Device
+}
+
+type vTrackball struct {
+	name       []byte
+	deviceFile *uinputDevice
+
+	mu        sync.Mutex
+	remainder [2]float64 // the fractions of counts that have not been reported yet (x, y)
+}
+
+// CreateTrackball will create a new trackball with a left, right and middle button.
+func CreateTrackball(path string, name []byte, opts ...Option) (Trackball, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateTrackball", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createTrackball(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vTrackball{name: name, deviceFile: fd}, nil
+}
+
+// Move will move the pointer by the given number of counts.
+func (vt *vTrackball) Move(x, y int32) error {
+	return sendEvents(vt.deviceFile, []inputEvent{
+		{Type: evRel, Code: relX, Value: x},
+		{Type: evRel, Code: relY, Value: y},
+	})
+}
+
+// Spin will rotate the ball by the given angles.
+func (vt *vTrackball) Spin(dx, dy float64) error {
+	if math.IsNaN(dx) || math.IsInf(dx, 0) || math.IsNaN(dy) || math.IsInf(dy, 0) {
+		return errorf(ErrInvalidArgument, "failed to perform Spin. The angles (%v, %v) are not finite", dx, dy)
+	}
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	var counts [2]int32
+	for i, angle := range []float64{dx, dy} {
+		total := vt.remainder[i] + angle*trackballCountsPerDegree
+		whole := math.Trunc(total)
+		if whole > math.MaxInt32 || whole < math.MinInt32 {
+			return errorf(ErrInvalidArgument, "failed to perform Spin. The angle %v is too large", angle)
+		}
+		counts[i] = int32(whole)
+		vt.remainder[i] = total - whole
+	}
+	if counts[0] == 0 && counts[1] == 0 {
+		return nil
+	}
+	return vt.Move(counts[0], counts[1])
+}
+
+// ButtonPress will press the given button and immediately release it.
+func (vt *vTrackball) ButtonPress(button int) error {
+	err := vt.ButtonDown(button)
+	if err != nil {
+		return err
+	}
+	return vt.ButtonUp(button)
+}
+
+// ButtonDown will press the given button.
+func (vt *vTrackball) ButtonDown(button int) error {
+	if !codeSupported(trackballButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a trackball button", button)
+	}
+	return sendBtnEvent(vt.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vt *vTrackball) ButtonUp(button int) error {
+	if !codeSupported(trackballButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a trackball button", button)
+	}
+	return sendBtnEvent(vt.deviceFile, []int{button}, btnStateReleased)
+}
+
+func (vt *vTrackball) Name() string {
+	return string(vt.name)
+}
+
+func (vt *vTrackball) Path() string {
+	return devicePath(vt.deviceFile)
+}
+
+func (vt *vTrackball) Fd() uintptr {
+	return deviceFd(vt.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vt *vTrackball) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vt.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vt *vTrackball) EmitEvents(events []InputEvent) error {
+	return emitEvents(vt.deviceFile, events)
+}
+
+func (vt *vTrackball) Sync() error {
+	return writeSyncEvent(vt.deviceFile)
+}
+
+func (vt *vTrackball) SysPath() (string, error) {
+	return sysPath(vt.deviceFile)
+}
+
+func (vt *vTrackball) EventPath() (string, error) {
+	return eventPath(vt.deviceFile)
+}
+
+func (vt *vTrackball) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vt.deviceFile)
+}
+
+func (vt *vTrackball) Stats() Stats {
+	return deviceStats(vt.deviceFile)
+}
+
+func (vt *vTrackball) State() State {
+	return deviceState(vt.deviceFile)
+}
+
+func (vt *vTrackball) ResetAll() error {
+	return resetDevice(vt.deviceFile)
+}
+
+func (vt *vTrackball) Disconnect() error {
+	return disconnectDevice(vt.deviceFile)
+}
+
+func (vt *vTrackball) Reconnect() error {
+	return reconnectDevice(vt.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vt *vTrackball) Close() error {
+	return closeDevice(vt.deviceFile)
+}
+
+func createTrackball(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create trackball input device: %w", err)
+	}
+
+	err = registerPointer(deviceFile, trackballButtons, inputPropPointer)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0822,
+				Version: 1}},
+		cfg)
+}
+
+// registerPointer registers the given buttons and the x and y axes of a pointer device without a wheel, along with
+// the given property.
+func registerPointer(deviceFile DeviceFile, buttons []int, prop uintptr) error {
+	err := registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		return fmt.Errorf("failed to register key device: %w", err)
+	}
+	for _, button := range buttons {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(button))
+		if err != nil {
+			return fmt.Errorf("failed to register button %v: %w", button, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evRel))
+	if err != nil {
+		return fmt.Errorf("failed to register relative axis input device: %w", err)
+	}
+	for _, axis := range []int{relX, relY} {
+		err = ioctl(deviceFile, uiSetRelBit, uintptr(axis))
+		if err != nil {
+			return fmt.Errorf("failed to register relative event %v: %w", axis, err)
+		}
+	}
+
+	err = ioctl(deviceFile, uiSetPropBit, prop)
+	if err != nil {
+		return fmt.Errorf("failed to register input property %v: %w", prop, err)
+	}
+	return nil
+}
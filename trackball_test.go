@@ -0,0 +1,90 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicTrackball(t *testing.T) {
+	trackball, err := CreateTrackball("/dev/uinput", []byte("Test Trackball"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual trackball. Last error was: %s\n", err)
+	}
+
+	err = trackball.Spin(10, -5)
+	if err != nil {
+		t.Fatalf("Failed to spin the ball. Last error was: %s\n", err)
+	}
+
+	err = trackball.ButtonPress(BtnLeft)
+	if err != nil {
+		t.Fatalf("Failed to press the button. Last error was: %s\n", err)
+	}
+
+	err = trackball.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestTrackballCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateTrackball("", []byte("Trackball"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestTrackballRegistersPointerWithoutWheel(t *testing.T) {
+	backend := &mockBackend{}
+	trackball, err := CreateTrackball("mock", []byte("Test Trackball"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the trackball: %v", err)
+	}
+	defer trackball.Close()
+
+	if !backend.file.issued(uiSetPropBit) || !backend.file.issued(uiSetRelBit) {
+		t.Fatalf("Expected the relative axes and INPUT_PROP_POINTER to be registered")
+	}
+}
+
+func TestTrackballSpinAddsUpFractions(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-trackball-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	trackball := &vTrackball{name: []byte("Test Trackball"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil))}
+	// 0.15° is two thirds of a count, so the second spin reports a count along with the remainder of the first
+	for i := 0; i < 2; i++ {
+		err = trackball.Spin(0.15, -90)
+		if err != nil {
+			t.Fatalf("Failed to spin the ball: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: relX, Value: 0},
+		{Type: evRel, Code: relY, Value: -400},
+		{Type: evRel, Code: relX, Value: 1},
+		{Type: evRel, Code: relY, Value: -400},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestTrackballRejectsInvalidInput(t *testing.T) {
+	trackball := &vTrackball{name: []byte("Test Trackball")}
+	err := trackball.Spin(math.NaN(), 0)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for a NaN angle, got: %v", err)
+	}
+	err = trackball.ButtonDown(BtnSide)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unsupported button, got: %v", err)
+	}
+}
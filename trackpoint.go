@@ -0,0 +1,246 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// the number of counts of stick motion that scroll by a single notch while the scroll button is held
+const trackpointCountsPerNotch = 10
+
+var trackpointButtons = []int{BtnLeft, BtnRight, BtnMiddle}
+
+// A Trackpoint is a pointing stick, like the TrackPoint of ThinkPad keyboards. It reports INPUT_PROP_POINTING_STICK,
+// which makes consumers like libinput apply the acceleration of pointing sticks. Like on ThinkPads, holding the scroll
+// button (BtnMiddle by default) turns motion into scroll events, while pressing and releasing it without any motion
+// is reported as a click. This allows to test the scroll behavior of toolkits without the actual hardware.
+type Trackpoint interface {
+	// Move will move the pointer by the given number of counts along the x and y axes. While the scroll button is
+	// held, the motion scrolls instead: moving up (negative y) scrolls up and moving right scrolls right.
+	Move(x, y int32) error
+
+	// ButtonPress will press the given button (BtnLeft, BtnRight or BtnMiddle) and immediately release it.
+	ButtonPress(button int) error
+
+	// ButtonDown will press the given button. Note that the button remains pressed until ButtonUp is called. The
+	// press of the scroll button is reported once it is released without any motion in between.
+	ButtonDown(button int) error
+
+	// ButtonUp will release the given button.
+	ButtonUp(button int) error
+
+	// SetScrollButton will set the button that turns motion into scroll events while it is held. Zero disables
+	// scrolling, which reports the button and all motion as is.
+	SetScrollButton(button int) error
+
+	Device
+}
+
+type vTrackpoint struct {
+	name       []byte
+	deviceFile *uinputDevice
+	wheel      *wheelState
+
+	mu           sync.Mutex
+	scrollButton int
+	scrolling    bool // whether the scroll button is held
+	scrolled     bool // whether the stick has been moved since the scroll button has been pressed
+}
+
+// CreateTrackpoint will create a new pointing stick with a left, right and middle button.
+func CreateTrackpoint(path string, name []byte, opts ...Option) (Trackpoint, error) {
+	err := validateDevicePath(path, opts...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateUinputName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := applyOptions("CreateTrackpoint", opts)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := createTrackpoint(path, name, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vTrackpoint{name: name, deviceFile: fd, wheel: &wheelState{}, scrollButton: BtnMiddle}, nil
+}
+
+// Move will move the pointer, or scroll while the scroll button is held.
+func (vt *vTrackpoint) Move(x, y int32) error {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	if !vt.scrolling {
+		return sendEvents(vt.deviceFile, []inputEvent{
+			{Type: evRel, Code: relX, Value: x},
+			{Type: evRel, Code: relY, Value: y},
+		})
+	}
+	vt.scrolled = vt.scrolled || x != 0 || y != 0
+	events, err := vt.wheel.scroll(float64(-y)/trackpointCountsPerNotch, float64(x)/trackpointCountsPerNotch)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %w", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	err = sendEvents(vt.deviceFile, events)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %w", err)
+	}
+	return nil
+}
+
+// ButtonPress will press the given button and immediately release it.
+func (vt *vTrackpoint) ButtonPress(button int) error {
+	err := vt.ButtonDown(button)
+	if err != nil {
+		return err
+	}
+	return vt.ButtonUp(button)
+}
+
+// ButtonDown will press the given button.
+func (vt *vTrackpoint) ButtonDown(button int) error {
+	if !codeSupported(trackpointButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonDown. Code %d is not a trackpoint button", button)
+	}
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	if button == vt.scrollButton {
+		vt.scrolling, vt.scrolled = true, false
+		return nil
+	}
+	return sendBtnEvent(vt.deviceFile, []int{button}, btnStatePressed)
+}
+
+// ButtonUp will release the given button.
+func (vt *vTrackpoint) ButtonUp(button int) error {
+	if !codeSupported(trackpointButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform ButtonUp. Code %d is not a trackpoint button", button)
+	}
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	if button == vt.scrollButton && vt.scrolling {
+		vt.scrolling = false
+		if vt.scrolled {
+			return nil
+		}
+		err := sendBtnEvent(vt.deviceFile, []int{button}, btnStatePressed)
+		if err != nil {
+			return err
+		}
+	}
+	return sendBtnEvent(vt.deviceFile, []int{button}, btnStateReleased)
+}
+
+// SetScrollButton will set the button that scrolls while it is held.
+func (vt *vTrackpoint) SetScrollButton(button int) error {
+	if button != 0 && !codeSupported(trackpointButtons, button) {
+		return errorf(ErrInvalidArgument, "failed to perform SetScrollButton. Code %d is not a trackpoint button", button)
+	}
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	if vt.scrolling {
+		return errorf(ErrInvalidArgument, "failed to perform SetScrollButton. The scroll button is held")
+	}
+	vt.scrollButton = button
+	return nil
+}
+
+func (vt *vTrackpoint) Name() string {
+	return string(vt.name)
+}
+
+func (vt *vTrackpoint) Path() string {
+	return devicePath(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) Fd() uintptr {
+	return deviceFd(vt.deviceFile)
+}
+
+// EmitEvent will emit a raw event, followed by a sync event.
+func (vt *vTrackpoint) EmitEvent(evType uint16, code uint16, value int32) error {
+	return emitEvents(vt.deviceFile, []InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will emit all given raw events, followed by a single sync event.
+func (vt *vTrackpoint) EmitEvents(events []InputEvent) error {
+	return emitEvents(vt.deviceFile, events)
+}
+
+func (vt *vTrackpoint) Sync() error {
+	return writeSyncEvent(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) SysPath() (string, error) {
+	return sysPath(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) EventPath() (string, error) {
+	return eventPath(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, vt.deviceFile)
+}
+
+func (vt *vTrackpoint) Stats() Stats {
+	return deviceStats(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) State() State {
+	return deviceState(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) ResetAll() error {
+	return resetDevice(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) Disconnect() error {
+	return disconnectDevice(vt.deviceFile)
+}
+
+func (vt *vTrackpoint) Reconnect() error {
+	return reconnectDevice(vt.deviceFile)
+}
+
+// Close closes the device and releases the device.
+func (vt *vTrackpoint) Close() error {
+	return closeDevice(vt.deviceFile)
+}
+
+func createTrackpoint(path string, name []byte, cfg deviceConfig) (fd *uinputDevice, err error) {
+	deviceFile, err := createDeviceFile(path, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create trackpoint input device: %w", err)
+	}
+
+	err = registerPointer(deviceFile, trackpointButtons, inputPropPointingStick)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+	// the wheel axes report the motion while the scroll button is held
+	err = registerRelAxes(deviceFile, []uint16{relWheel, relHWheel, RelWheelHiRes, RelHWheelHiRes})
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID: inputID{
+				Bustype: busUsb,
+				Vendor:  0x4711,
+				Product: 0x0823,
+				Version: 1}},
+		cfg)
+}
@@ -0,0 +1,116 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBasicTrackpoint(t *testing.T) {
+	trackpoint, err := CreateTrackpoint("/dev/uinput", []byte("Test Trackpoint"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual trackpoint. Last error was: %s\n", err)
+	}
+
+	err = trackpoint.Move(10, -5)
+	if err != nil {
+		t.Fatalf("Failed to move the pointer. Last error was: %s\n", err)
+	}
+
+	err = trackpoint.ButtonDown(BtnMiddle)
+	if err != nil {
+		t.Fatalf("Failed to hold the scroll button. Last error was: %s\n", err)
+	}
+	err = trackpoint.Move(0, -20)
+	if err != nil {
+		t.Fatalf("Failed to scroll. Last error was: %s\n", err)
+	}
+	err = trackpoint.ButtonUp(BtnMiddle)
+	if err != nil {
+		t.Fatalf("Failed to release the scroll button. Last error was: %s\n", err)
+	}
+
+	err = trackpoint.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestTrackpointCreationDiscoversEmptyPath(t *testing.T) {
+	device, err := CreateTrackpoint("", []byte("Trackpoint"))
+	assertPathDiscovered(t, device, err)
+}
+
+func TestTrackpointRegistersPointingStick(t *testing.T) {
+	backend := &mockBackend{}
+	trackpoint, err := CreateTrackpoint("mock", []byte("Test Trackpoint"), WithBackend(backend))
+	if err != nil {
+		t.Fatalf("Failed to create the trackpoint: %v", err)
+	}
+	defer trackpoint.Close()
+
+	if !backend.file.issued(uiSetPropBit) || !backend.file.issued(uiSetRelBit) {
+		t.Fatalf("Expected the relative axes and INPUT_PROP_POINTING_STICK to be registered")
+	}
+}
+
+func TestTrackpointScrollsWhileScrollButtonIsHeld(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-trackpoint-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	trackpoint := &vTrackpoint{name: []byte("Test Trackpoint"), deviceFile: newUinputDevice(tempFile{file}, newDeviceConfig(nil)),
+		wheel: &wheelState{}, scrollButton: BtnMiddle}
+	steps := []func() error{
+		func() error { return trackpoint.ButtonDown(BtnMiddle) },
+		func() error { return trackpoint.Move(5, -10) },
+		func() error { return trackpoint.ButtonUp(BtnMiddle) },
+		func() error { return trackpoint.Move(1, 2) },
+		// without any motion in between, holding the scroll button clicks it
+		func() error { return trackpoint.ButtonPress(BtnMiddle) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("Failed to perform step: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evRel, Code: relWheel, Value: 1},
+		{Type: evRel, Code: RelWheelHiRes, Value: 120},
+		{Type: evRel, Code: RelHWheelHiRes, Value: 60},
+		{Type: evRel, Code: relX, Value: 1},
+		{Type: evRel, Code: relY, Value: 2},
+	}
+	if events := readEvents(t, file.Name(), evRel); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+	expected = []inputEvent{
+		{Type: evKey, Code: BtnMiddle, Value: btnStatePressed},
+		{Type: evKey, Code: BtnMiddle, Value: btnStateReleased},
+	}
+	if events := readEvents(t, file.Name(), evKey); !reflect.DeepEqual(events, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v", expected, events)
+	}
+}
+
+func TestTrackpointRejectsInvalidInput(t *testing.T) {
+	trackpoint := &vTrackpoint{name: []byte("Test Trackpoint"), wheel: &wheelState{}, scrollButton: BtnMiddle}
+	err := trackpoint.ButtonDown(BtnSide)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unsupported button, got: %v", err)
+	}
+	err = trackpoint.SetScrollButton(BtnSide)
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected an invalid argument error for an unsupported scroll button, got: %v", err)
+	}
+	err = trackpoint.SetScrollButton(0)
+	if err != nil {
+		t.Fatalf("Failed to disable scrolling: %v", err)
+	}
+}
@@ -82,24 +82,40 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"syscall"
 	"time"
+	"unsafe"
 )
 
-func validateDevicePath(path string) error {
+func validateDevicePath(path string, opts ...Option) error {
+	custom := newDeviceConfig(opts).backend != nil
+	if path == "" && custom {
+		return errorf(ErrInvalidPath, "device path must not be empty")
+	}
+	if custom {
+		// the path is meaningful to the backend only, which need not be backed by the file system
+		return nil
+	}
+	err := validatePlatform()
+	if err != nil {
+		return err
+	}
 	if path == "" {
-		return errors.New("device path must not be empty")
+		// the path is discovered upon creation
+		_, err = DefaultPath()
+		return err
 	}
-	_, err := os.Stat(path)
+	_, err = os.Stat(path)
 	return err
 }
 
 func validateUinputName(name []byte) error {
 	if name == nil || len(name) == 0 {
-		return errors.New("device name may not be empty")
+		return errorf(ErrInvalidName, "device name may not be empty")
 	}
 	if len(name) > uinputMaxNameSize {
-		return fmt.Errorf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+		return errorf(ErrInvalidName, "device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
 	}
 	return nil
 }
@@ -110,111 +126,456 @@ func toUinputName(name []byte) (uinputName [uinputMaxNameSize]byte) {
 	return fixedSizeName
 }
 
-func createDeviceFile(path string) (fd *os.File, err error) {
-	deviceFile, err := os.OpenFile(path, syscall.O_WRONLY|syscall.O_NONBLOCK, 0660)
+func createDeviceFile(path string, cfg deviceConfig) (fd DeviceFile, err error) {
+	backend := cfg.backend
+	if backend == nil {
+		backend = osBackend{}
+		if path == "" {
+			path, err = DefaultPath()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	deviceFile, err := backend.Open(path)
 	if err != nil {
-		return nil, errors.New("could not open device file")
+		if os.IsPermission(err) {
+			return nil, errorf(ErrPermissionDenied, "could not open device file: %w", err)
+		}
+		return nil, fmt.Errorf("could not open device file: %w", err)
 	}
-	return deviceFile, err
+	return newRecreatableFile(deviceFile, path, backend), nil
 }
 
-func registerDevice(deviceFile *os.File, evType uintptr) error {
+func registerDevice(deviceFile DeviceFile, evType uintptr) error {
 	err := ioctl(deviceFile, uiSetEvBit, evType)
 	if err != nil {
 		err = releaseDevice(deviceFile)
 		if err != nil {
 			deviceFile.Close()
-			return fmt.Errorf("failed to close device: %v", err)
+			return fmt.Errorf("failed to close device: %w", err)
 		}
 		deviceFile.Close()
-		return fmt.Errorf("invalid file handle returned from ioctl: %v", err)
+		return fmt.Errorf("invalid file handle returned from ioctl: %w", err)
 	}
 	return nil
 }
 
-func createUsbDevice(deviceFile *os.File, dev uinputUserDev) (fd *os.File, err error) {
-	buf := new(bytes.Buffer)
-	err = binary.Write(buf, binary.LittleEndian, dev)
+func createUsbDevice(deviceFile DeviceFile, dev uinputUserDev, cfg deviceConfig) (fd *uinputDevice, err error) {
+	cfg.applyID(&dev.ID)
+	resolutions, err := cfg.applyTunings(&dev)
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to write user device buffer: %v", err)
+		return nil, err
 	}
-	_, err = deviceFile.Write(buf.Bytes())
+
+	err = setDeviceStrings(deviceFile, cfg)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	err = setProperties(deviceFile, cfg.props)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	err = registerRelAxes(deviceFile, cfg.relAxes)
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to write uidev struct to device file: %v", err)
+		return nil, err
+	}
+
+	err = registerReadCodes(deviceFile, cfg.readCodes)
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
+	}
+
+	// Kernels >= 4.5 support setting up the device via ioctls, which is required in order to report the resolution
+	// of axes. Older kernels only support writing the uinput_user_dev struct to the device file.
+	if uinputVersion(deviceFile) >= uinputVersionDevSetup {
+		err = setupDevice(deviceFile, dev, resolutions)
+	} else {
+		err = writeUserDev(deviceFile, dev)
+	}
+	if err != nil {
+		deviceFile.Close()
+		return nil, err
 	}
 
 	err = ioctl(deviceFile, uiDevCreate, uintptr(0))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to create device: %v", err)
+		return nil, fmt.Errorf("failed to create device: %w", err)
 	}
 
 	time.Sleep(time.Millisecond * 200)
 
-	return deviceFile, err
+	if !cfg.handlers.empty() {
+		go dispatchEvents(deviceFile, cfg.handlers)
+	}
+
+	fd = newUinputDevice(deviceFile, cfg)
+	fd.state.ranges = axisRanges{min: dev.Absmin, max: dev.Absmax}
+	if fd.rangePolicy != AxisRangeIgnore {
+		fd.ranges = &axisRanges{min: dev.Absmin, max: dev.Absmax}
+	}
+	return fd, err
+}
+
+// uinputDevice wraps the file of a created device. All events are written through it, which allows settings that
+// apply to the device as a whole (like manual syncing) to be respected by every device method. Frames are written
+// while holding mu, so that concurrent callers cannot interleave their events.
+type uinputDevice struct {
+	file         DeviceFile
+	manualSync   bool
+	recorder     *Recorder
+	rangePolicy  AxisRangePolicy
+	ranges       *axisRanges // the ranges of the absolute axes, if the range policy needs them
+	clock        func() time.Time
+	limiter      *rateLimiter
+	retry        writeRetry
+	hooks        Hooks
+	stats        statCounters
+	autoRecreate bool
+	dwell        *dwellEngine
+	state        *stateTracker
+	dedup        bool
+	onReset      func() // resets the state that is kept by the device itself, once its controls have been reset
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func newUinputDevice(file DeviceFile, cfg deviceConfig) *uinputDevice {
+	return &uinputDevice{file: file, manualSync: cfg.manualSync, recorder: cfg.recorder, rangePolicy: cfg.rangePolicy,
+		clock: cfg.clock, limiter: newRateLimiter(cfg), retry: cfg.writeRetry(),
+		hooks: cfg.hooks, autoRecreate: cfg.recreate, dwell: newDwellEngine(cfg), state: newStateTracker(axisRanges{}),
+		dedup: cfg.dedup}
+}
+
+// transientError reports whether a failed write may succeed if it is retried.
+func transientError(err error) bool {
+	return errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN)
+}
+
+// axisRanges are the ranges the absolute axes of a device have been created with
+type axisRanges struct {
+	min, max [absSize]int32
+}
+
+// applyRangePolicy returns the value of the event after applying the range policy of the device. Only absolute axes
+// that have a range are checked.
+func (d *uinputDevice) applyRangePolicy(iev inputEvent) (int32, error) {
+	if d.ranges == nil || iev.Type != evAbs || iev.Code >= absSize {
+		return iev.Value, nil
+	}
+	min, max := d.ranges.min[iev.Code], d.ranges.max[iev.Code]
+	if min == max || (iev.Value >= min && iev.Value <= max) {
+		return iev.Value, nil
+	}
+	if d.rangePolicy == AxisRangeReject {
+		return 0, errorf(ErrInvalidArgument, "value %d of axis %d is out of range. Expected a value between %d and %d", iev.Value, iev.Code, min, max)
+	}
+	if iev.Value < min {
+		return min, nil
+	}
+	return max, nil
+}
+
+func (d *uinputDevice) Write(b []byte) (n int, err error) {
+	start := time.Now()
+	defer func() {
+		d.stats.count(b[:n], err, time.Since(start))
+	}()
+	n, err = d.file.Write(b)
+	for attempt := 0; err != nil && attempt < d.retry.attempts && transientError(err); attempt++ {
+		time.Sleep(d.retry.delay << uint(attempt))
+		var m int
+		m, err = d.file.Write(b[n:])
+		n += m
+	}
+	if err != nil && deviceGone(err) {
+		n, err = d.recreateAndWrite(b, err)
+	}
+	if errors.Is(err, os.ErrClosed) {
+		err = errorf(ErrDeviceClosed, "%w", err)
+	}
+	if err != nil {
+		if d.hooks.OnWriteError != nil {
+			d.hooks.OnWriteError(err)
+		}
+		return n, err
+	}
+	d.state.track(b[:n])
+	if d.recorder != nil {
+		d.recorder.record(b[:n])
+	}
+	if d.dwell != nil {
+		d.dwell.observe(d, b[:n])
+	}
+	if d.hooks.OnEvent != nil {
+		for i := 0; i+inputEventSize <= n; i += inputEventSize {
+			iev := (*inputEvent)(unsafe.Pointer(&b[i]))
+			event := InputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value}
+			if iev.Time.Sec != 0 || iev.Time.Usec != 0 {
+				event.Time = time.Unix(0, iev.Time.Nano())
+			}
+			d.hooks.OnEvent(event)
+		}
+	}
+	return n, nil
+}
+
+// uinputVersion returns the version of the uinput module. Zero is returned if the version cannot be determined,
+// which is the case for old kernels that do not support UI_GET_VERSION.
+func uinputVersion(deviceFile DeviceFile) uint32 {
+	var version uint32
+	err := ioctlPtr(deviceFile, uiGetVersion, unsafe.Pointer(&version))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func writeUserDev(deviceFile DeviceFile, dev uinputUserDev) error {
+	buf := new(bytes.Buffer)
+	err := binary.Write(buf, binary.LittleEndian, dev)
+	if err != nil {
+		return fmt.Errorf("failed to write user device buffer: %w", err)
+	}
+	_, err = deviceFile.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write uidev struct to device file: %w", err)
+	}
+	return nil
+}
+
+// setupDevice is the equivalent of writeUserDev for kernels that support UI_DEV_SETUP and UI_ABS_SETUP. Only axes that
+// have a range or any other non-default value are set up, as UI_ABS_SETUP implicitly registers the axis.
+func setupDevice(deviceFile DeviceFile, dev uinputUserDev, resolutions [absSize]int32) error {
+	for code := uint16(0); code < absSize; code++ {
+		absSetup := uinputAbsSetup{
+			Code: code,
+			Absinfo: inputAbsinfo{
+				Minimum:    dev.Absmin[code],
+				Maximum:    dev.Absmax[code],
+				Fuzz:       dev.Absfuzz[code],
+				Flat:       dev.Absflat[code],
+				Resolution: resolutions[code],
+			},
+		}
+		if absSetup.Absinfo == (inputAbsinfo{}) {
+			continue
+		}
+		err := ioctlPtr(deviceFile, uiAbsSetup, unsafe.Pointer(&absSetup))
+		if err != nil {
+			return fmt.Errorf("failed to set up absolute axis %d: %w", code, err)
+		}
+	}
+
+	setup := uinputSetup{ID: dev.ID, Name: dev.Name, FFEffectsMax: dev.EffectsMax}
+	err := ioctlPtr(deviceFile, uiDevSetup, unsafe.Pointer(&setup))
+	if err != nil {
+		return fmt.Errorf("failed to set up device: %w", err)
+	}
+	return nil
 }
 
-func closeDevice(deviceFile *os.File) (err error) {
-	err = releaseDevice(deviceFile)
+// setDeviceStrings sets the optional phys and uniq strings of the device. This needs to happen before the
+// device is created.
+func setDeviceStrings(deviceFile DeviceFile, cfg deviceConfig) error {
+	if cfg.phys != "" {
+		phys := append([]byte(cfg.phys), 0)
+		err := ioctlPtr(deviceFile, uiSetPhys, unsafe.Pointer(&phys[0]))
+		if err != nil {
+			return fmt.Errorf("failed to set phys %q: %w", cfg.phys, err)
+		}
+	}
+	if cfg.uniq != "" {
+		uniq := append([]byte(cfg.uniq), 0)
+		err := ioctlPtr(deviceFile, uiSetUniq, unsafe.Pointer(&uniq[0]))
+		if err != nil {
+			return fmt.Errorf("failed to set uniq %q: %w", cfg.uniq, err)
+		}
+	}
+	return nil
+}
+
+func setProperties(deviceFile DeviceFile, props []uint16) error {
+	for _, prop := range props {
+		if prop > inputPropMax {
+			return errorf(ErrInvalidArgument, "failed to set property %d. Expected a property below %d", prop, inputPropMax+1)
+		}
+		err := ioctl(deviceFile, uiSetPropBit, uintptr(prop))
+		if err != nil {
+			return fmt.Errorf("failed to set property %d: %w", prop, err)
+		}
+	}
+	return nil
+}
+
+func registerRelAxes(deviceFile DeviceFile, codes []uint16) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	err := ioctl(deviceFile, uiSetEvBit, uintptr(evRel))
 	if err != nil {
-		return fmt.Errorf("failed to close device: %v", err)
+		return fmt.Errorf("failed to register relative axis input device: %w", err)
+	}
+	for _, code := range codes {
+		if code > relMax {
+			return errorf(ErrInvalidArgument, "failed to register relative axis %d. Expected an axis below %d", code, relMax+1)
+		}
+		err = ioctl(deviceFile, uiSetRelBit, uintptr(code))
+		if err != nil {
+			return fmt.Errorf("failed to register relative event %v: %w", code, err)
+		}
 	}
-	return deviceFile.Close()
+	return nil
 }
 
-func releaseDevice(deviceFile *os.File) (err error) {
+func closeDevice(deviceFile *uinputDevice) (err error) {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	if deviceFile.limiter != nil {
+		// pending axis movements are written before the device is gone
+		_ = deviceFile.limiter.flush(deviceFile, nil)
+	}
+	if deviceFile.dwell != nil {
+		deviceFile.dwell.stop()
+	}
+	// consumers that keep the state of devices (like held buttons) would otherwise be stuck with the last state. The
+	// device is destroyed regardless of whether the reset could be written, and it is not recreated to write it.
+	deviceFile.autoRecreate = false
+	_ = writeReset(deviceFile)
+	err = releaseDevice(deviceFile.file)
+	if err != nil {
+		return fmt.Errorf("failed to close device: %w", err)
+	}
+	return deviceFile.file.Close()
+}
+
+func releaseDevice(deviceFile DeviceFile) (err error) {
 	return ioctl(deviceFile, uiDevDestroy, uintptr(0))
 }
 
 // Note that mice and touch pads do have buttons as well. Therefore, this function is used
 // by all currently available devices and resides in the main source file.
-func sendBtnEvent(deviceFile *os.File, keys []int, btnState int) (err error) {
+func sendBtnEvent(deviceFile *uinputDevice, keys []int, btnState int) (err error) {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	buf := deviceFile.buf[:0]
 	for _, key := range keys {
-		buf, err := inputEventToBuffer(inputEvent{
+		buf = appendInputEvent(buf, inputEvent{
 			Time:  syscall.Timeval{Sec: 0, Usec: 0},
 			Type:  evKey,
 			Code:  uint16(key),
 			Value: int32(btnState)})
+	}
+	return writeFrame(deviceFile, buf)
+}
+
+// sendEvents writes all given events to the device file and issues a single sync event afterwards, so that all
+// events are reported to consumers as one frame.
+func sendEvents(deviceFile *uinputDevice, events []inputEvent) (err error) {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	buf := deviceFile.buf[:0]
+	for _, iev := range events {
+		iev.Value, err = deviceFile.applyRangePolicy(iev)
 		if err != nil {
-			return fmt.Errorf("key event could not be set: %v", err)
+			return err
 		}
-		_, err = deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("writing btnEvent structure to the device file failed: %v", err)
+		buf = appendInputEvent(buf, iev)
+	}
+	return writeFrame(deviceFile, buf)
+}
+
+// writeFrame terminates the given encoded events with a sync event (unless the device has been created using
+// WithManualSync) and writes the whole frame using a single write call, as the kernel accepts any number of events
+// per write. The buffer is kept by the device for subsequent frames in order to avoid allocations. The caller must
+// hold the lock of the device.
+func writeFrame(deviceFile *uinputDevice, buf []byte) error {
+	if deviceFile.dedup {
+		buf = deviceFile.dropUnchanged(buf)
+		if len(buf) == 0 {
+			return nil
 		}
 	}
-	return syncEvents(deviceFile)
+	if deviceFile.limiter != nil {
+		return deviceFile.limiter.submit(deviceFile, buf)
+	}
+	return writeEncoded(deviceFile, buf)
 }
 
-func syncEvents(deviceFile *os.File) (err error) {
-	buf, err := inputEventToBuffer(inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evSyn,
-		Code:  uint16(synReport),
-		Value: 0})
+// writeEncoded writes the given encoded events as a frame right away (see writeFrame).
+func writeEncoded(deviceFile *uinputDevice, buf []byte) error {
+	if !deviceFile.manualSync {
+		buf = appendInputEvent(buf, syncEvent())
+	}
+	stampFrame(deviceFile, buf)
+	deviceFile.buf = buf
+	_, err := deviceFile.Write(buf)
 	if err != nil {
-		return fmt.Errorf("writing sync event failed: %v", err)
+		return fmt.Errorf("failed to write event to device file: %w", err)
 	}
+	return nil
+}
+
+// writeSyncEvent issues the sync event that terminates an event frame. It is sent by sendEvents implicitly, unless
+// the device has been created using WithManualSync.
+func writeSyncEvent(deviceFile *uinputDevice) (err error) {
+	deviceFile.mu.Lock()
+	defer deviceFile.mu.Unlock()
+	buf := appendInputEvent(deviceFile.buf[:0], syncEvent())
+	stampFrame(deviceFile, buf)
 	_, err = deviceFile.Write(buf)
 	return err
 }
 
-func inputEventToBuffer(iev inputEvent) (buffer []byte, err error) {
-	buf := bytes.NewBuffer(make([]byte, 0, 24))
-	err = binary.Write(buf, binary.LittleEndian, iev)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write input event to buffer: %v", err)
+// stampFrame stamps the encoded events that carry no timestamp with the time of the clock of the device, if any. A
+// zero time of the clock leaves the events as they are.
+func stampFrame(deviceFile *uinputDevice, buf []byte) {
+	if deviceFile.clock == nil {
+		return
+	}
+	t := deviceFile.clock()
+	if t.IsZero() {
+		return
+	}
+	now := syscall.NsecToTimeval(t.UnixNano())
+	for i := 0; i+inputEventSize <= len(buf); i += inputEventSize {
+		iev := (*inputEvent)(unsafe.Pointer(&buf[i]))
+		if iev.Time.Sec == 0 && iev.Time.Usec == 0 {
+			iev.Time = now
+		}
 	}
-	return buf.Bytes(), nil
 }
 
-// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
-func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
-	_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, deviceFile.Fd(), cmd, ptr)
-	if errorCode != 0 {
-		return errorCode
-	}
-	return nil
+func syncEvent() inputEvent {
+	return inputEvent{
+		Time:  syscall.Timeval{Sec: 0, Usec: 0},
+		Type:  evSyn,
+		Code:  uint16(synReport),
+		Value: 0}
+}
+
+// appendInputEvent appends the encoded event to buf. The kernel expects events to match the layout of struct
+// input_event in native byte order, which is exactly the memory layout of inputEvent. Copying the memory as is
+// avoids the reflection (and allocations) of encoding/binary on hot paths.
+func appendInputEvent(buf []byte, iev inputEvent) []byte {
+	return append(buf, (*[inputEventSize]byte)(unsafe.Pointer(&iev))[:]...)
+}
+
+// ioctl issues a request with an integer argument on the device file (see DeviceFile.Ioctl).
+func ioctl(deviceFile DeviceFile, cmd, ptr uintptr) error {
+	return deviceFile.Ioctl(cmd, ptr)
+}
+
+// ioctlPtr is the equivalent of ioctl for requests that pass a pointer to a struct.
+func ioctlPtr(deviceFile DeviceFile, cmd uintptr, ptr unsafe.Pointer) error {
+	return deviceFile.IoctlPtr(cmd, ptr)
 }
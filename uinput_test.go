@@ -1,15 +1,39 @@
 package uinput
 
 import (
+	"errors"
+	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
+	"unsafe"
 )
 
-func TestValidateDevicePathEmptyPathPanics(t *testing.T) {
-	expected := "device path must not be empty"
+func TestValidateDevicePathDiscoversEmptyPath(t *testing.T) {
+	_, expected := DefaultPath()
 	err := validateDevicePath("")
-	if err.Error() != expected {
-		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	if (err == nil) != (expected == nil) || (err != nil && err.Error() != expected.Error()) {
+		t.Fatalf("Expected: %v\nActual: %v", expected, err)
+	}
+}
+
+// assertPathDiscovered checks that a device that has been created using an empty path uses the default path. If there
+// is no default path, creating the device is expected to fail just like DefaultPath does.
+func assertPathDiscovered(t *testing.T, device Device, err error) {
+	t.Helper()
+	path, pathErr := DefaultPath()
+	if pathErr != nil {
+		if !errors.Is(err, ErrInvalidPath) {
+			t.Fatalf("Expected ErrInvalidPath without a default path, got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Failed to create device using the default path: %v", err)
+	}
+	defer device.Close()
+	if device.Path() != path {
+		t.Fatalf("Expected the device to use %s, got %s", path, device.Path())
 	}
 }
 
@@ -28,3 +52,162 @@ func TestValidateUinputNameEmptyNamePanics(t *testing.T) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
 	}
 }
+
+func TestSetupStructsMatchKernelLayout(t *testing.T) {
+	if size := unsafe.Sizeof(uinputSetup{}); size != 92 {
+		t.Fatalf("Expected: 92\nActual: %d", size)
+	}
+	if size := unsafe.Sizeof(uinputAbsSetup{}); size != 28 {
+		t.Fatalf("Expected: 28\nActual: %d", size)
+	}
+}
+
+func TestGetSysnameRequestCode(t *testing.T) {
+	// UI_GET_SYSNAME(64) as computed by the _IOC macro
+	const expected uintptr = 0x8040552c
+	if uiGetSysname != expected {
+		t.Fatalf("Expected: %#x\nActual: %#x", expected, uiGetSysname)
+	}
+}
+
+func TestManualSyncSuppressesImplicitSyncEvents(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-sync-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	eventSize := int64(unsafe.Sizeof(inputEvent{}))
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig([]Option{WithManualSync()}))
+	err = sendBtnEvent(dev, []int{Key1, Key2}, btnStatePressed)
+	if err != nil {
+		t.Fatalf("Failed to send button events: %v", err)
+	}
+	assertFileSize(t, file, 2*eventSize)
+
+	err = writeSyncEvent(dev)
+	if err != nil {
+		t.Fatalf("Failed to send sync event: %v", err)
+	}
+	assertFileSize(t, file, 3*eventSize)
+}
+
+func TestImplicitSyncEventsAreSentByDefault(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-sync-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	err = sendBtnEvent(dev, []int{Key1}, btnStatePressed)
+	if err != nil {
+		t.Fatalf("Failed to send button event: %v", err)
+	}
+	assertFileSize(t, file, 2*int64(unsafe.Sizeof(inputEvent{})))
+}
+
+func assertFileSize(t *testing.T, file *os.File, expected int64) {
+	t.Helper()
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Size() != expected {
+		t.Fatalf("Expected: %d bytes\nActual: %d bytes", expected, info.Size())
+	}
+}
+
+func TestSendingEventsDoesNotAllocate(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-alloc-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	allocs := testing.AllocsPerRun(100, func() {
+		err = sendStickEvent(dev, AxisLeftX, AxisLeftY, 0.5, -0.5)
+		if err != nil {
+			t.Fatalf("Failed to send stick event: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("Expected no allocations, but got %v per event frame", allocs)
+	}
+}
+
+func TestEncodedEventMatchesKernelLayout(t *testing.T) {
+	iev := inputEvent{Type: evAbs, Code: absY, Value: -2}
+	buf := appendInputEvent(nil, iev)
+	if len(buf) != inputEventSize {
+		t.Fatalf("Expected: %d bytes\nActual: %d bytes", inputEventSize, len(buf))
+	}
+	decoded := *(*inputEvent)(unsafe.Pointer(&buf[0]))
+	if decoded != iev {
+		t.Fatalf("Expected: %+v\nActual: %+v", iev, decoded)
+	}
+}
+
+func TestConcurrentFramesAreNotInterleaved(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-concurrency-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	const writers = 8
+	const framesPerWriter = 100
+	dev := newUinputDevice(tempFile{file}, newDeviceConfig(nil))
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(value int32) {
+			defer wg.Done()
+			for j := 0; j < framesPerWriter; j++ {
+				_ = sendEvents(dev, []inputEvent{
+					{Type: evAbs, Code: absX, Value: value},
+					{Type: evAbs, Code: absY, Value: value},
+				})
+			}
+		}(int32(i))
+	}
+	wg.Wait()
+
+	buf, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to read tempfile: %v", err)
+	}
+	if len(buf) != writers*framesPerWriter*3*inputEventSize {
+		t.Fatalf("Expected: %d bytes\nActual: %d bytes", writers*framesPerWriter*3*inputEventSize, len(buf))
+	}
+	for offset := 0; offset < len(buf); offset += 3 * inputEventSize {
+		x := *(*inputEvent)(unsafe.Pointer(&buf[offset]))
+		y := *(*inputEvent)(unsafe.Pointer(&buf[offset+inputEventSize]))
+		syn := *(*inputEvent)(unsafe.Pointer(&buf[offset+2*inputEventSize]))
+		if x.Code != absX || y.Code != absY || x.Value != y.Value || syn != syncEvent() {
+			t.Fatalf("Frame at offset %d has been interleaved: %+v %+v %+v", offset, x, y, syn)
+		}
+	}
+}
+
+// readEvents decodes all events of the given type that have been written to the file.
+func readEvents(t *testing.T, name string, evType uint16) []inputEvent {
+	t.Helper()
+	buf, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatalf("Failed to read tempfile: %v", err)
+	}
+	var events []inputEvent
+	for offset := 0; offset+inputEventSize <= len(buf); offset += inputEventSize {
+		iev := *(*inputEvent)(unsafe.Pointer(&buf[offset]))
+		if iev.Type == evType {
+			events = append(events, inputEvent{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+		}
+	}
+	return events
+}
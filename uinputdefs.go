@@ -1,17 +1,32 @@
 package uinput
 
-import "syscall"
+import (
+	"syscall"
+	"unsafe"
+)
 
 // types needed from uinput.h
 const (
-	uinputMaxNameSize = 80
-	uiDevCreate       = 0x5501
-	uiDevDestroy      = 0x5502
-	uiSetEvBit        = 0x40045564
-	uiSetKeyBit       = 0x40045565
-	uiSetRelBit       = 0x40045566
-	uiSetAbsBit       = 0x40045567
-	busUsb            = 0x03
+	uinputMaxNameSize     = 80
+	uiDevCreate           = 0x5501
+	uiDevDestroy          = 0x5502
+	uiDevSetup            = 0x405c5503
+	uiAbsSetup            = 0x401c5504
+	uiGetVersion          = 0x8004552d
+	uinputVersionDevSetup = 5 // first version of uinput (kernel 4.5) that supports UI_DEV_SETUP and UI_ABS_SETUP
+	uiSetEvBit            = 0x40045564
+	uiSetKeyBit           = 0x40045565
+	uiSetRelBit           = 0x40045566
+	uiSetAbsBit           = 0x40045567
+	uiSetPropBit          = 0x4004556e
+	uiSetFFBit            = 0x4004556b
+	uiSetLedBit           = 0x40045569
+	uiSetSndBit           = 0x4004556a
+	uiSetMscBit           = 0x40045568
+	uiSetSwBit            = 0x4004556d
+	uiFFUpload            = 1
+	uiFFErase             = 2
+	busUsb                = 0x03
 )
 
 // input event codes as specified in input-event-codes.h
@@ -20,6 +35,8 @@ const (
 	evKey           = 0x01
 	evRel           = 0x02
 	evAbs           = 0x03
+	evFF            = 0x15
+	evUinput        = 0x0101
 	relX            = 0x0
 	relY            = 0x1
 	relHWheel       = 0x6
@@ -27,17 +44,63 @@ const (
 	relDial         = 0x7
 	absX            = 0x0
 	absY            = 0x1
+	absZ            = 0x2
+	absRX           = 0x3
+	absRY           = 0x4
+	absRZ           = 0x5
+	absHat0X        = 0x10
+	absHat0Y        = 0x11
+	absMtSlot       = 0x2f
+	absMtPositionX  = 0x35
+	absMtPositionY  = 0x36
+	absMtTrackingID = 0x39
 	synReport       = 0
 	evBtnLeft       = 0x110
 	evBtnRight      = 0x111
+	evBtnMiddle     = 0x112
 	evBtnTouch      = 0x14a
 	evBtnToolFinger = 0x145
+	ledMax          = 0x0f
+	sndMax          = 0x07
+	swMax           = 0x10
+)
+
+// force feedback codes as specified in input.h
+const (
+	ffRumble     = 0x50
+	ffPeriodic   = 0x51
+	ffConstant   = 0x52
+	ffSpring     = 0x53
+	ffFriction   = 0x54
+	ffDamper     = 0x55
+	ffSquare     = 0x58
+	ffTriangle   = 0x59
+	ffSine       = 0x5a
+	ffSawUp      = 0x5b
+	ffSawDown    = 0x5c
+	ffGain       = 0x60
+	ffAutocenter = 0x61
+	ffMax        = 0x7f
+)
+
+// input device properties as specified in input-event-codes.h
+const (
+	inputPropPointer       = 0x00
+	inputPropDirect        = 0x01
+	inputPropButtonpad     = 0x02
+	inputPropSemiMT        = 0x03
+	inputPropTopButtonpad  = 0x04
+	inputPropPointingStick = 0x05
+	inputPropAccelerometer = 0x06
+	inputPropMax           = 0x1f
 )
 
 const (
 	btnStateReleased = 0
 	btnStatePressed  = 1
 	absSize          = 64
+	keyCodeMax       = 0x2ff // KEY_MAX, the highest key or button code
+	trackingIDMax    = 0xffff
 )
 
 type inputID struct {
@@ -58,6 +121,30 @@ type uinputUserDev struct {
 	Absflat    [absSize]int32
 }
 
+// translated to go from uinput.h
+type uinputSetup struct {
+	ID           inputID
+	Name         [uinputMaxNameSize]byte
+	FFEffectsMax uint32
+}
+
+// translated to go from input.h
+type inputAbsinfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// translated to go from uinput.h
+type uinputAbsSetup struct {
+	Code    uint16
+	_       uint16
+	Absinfo inputAbsinfo
+}
+
 // translated to go from input.h
 type inputEvent struct {
 	Time  syscall.Timeval
@@ -65,3 +152,98 @@ type inputEvent struct {
 	Code  uint16
 	Value int32
 }
+
+const inputEventSize = int(unsafe.Sizeof(inputEvent{}))
+
+// translated to go from input.h
+type ffTrigger struct {
+	Button   uint16
+	Interval uint16
+}
+
+type ffReplay struct {
+	Length uint16
+	Delay  uint16
+}
+
+type ffEnvelope struct {
+	AttackLength uint16
+	AttackLevel  uint16
+	FadeLength   uint16
+	FadeLevel    uint16
+}
+
+type ffConstantEffect struct {
+	Level    int16
+	Envelope ffEnvelope
+}
+
+type ffRumbleEffect struct {
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+}
+
+// ffPeriodicEffect is the largest member of the effect union in struct ff_effect. It is therefore used to
+// represent the union itself, which makes sure that size and alignment match the C struct on all platforms.
+type ffPeriodicEffect struct {
+	Waveform   uint16
+	Period     uint16
+	Magnitude  int16
+	Offset     int16
+	Phase      uint16
+	Envelope   ffEnvelope
+	CustomLen  uint32
+	CustomData uintptr
+}
+
+type ffEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+	Trigger   ffTrigger
+	Replay    ffReplay
+	U         ffPeriodicEffect
+}
+
+// translated to go from uinput.h
+type uinputFFUpload struct {
+	RequestID uint32
+	Retval    int32
+	Effect    ffEffect
+	Old       ffEffect
+}
+
+type uinputFFErase struct {
+	RequestID uint32
+	Retval    int32
+	EffectID  uint32
+}
+
+// the ioctl request codes for force feedback handling and for setting strings depend on the size of the structs above
+// or the size of a pointer (and therefore on the platform), which is why they are computed as opposed to defined as
+// constants
+var (
+	uiSetPhys       = iow('U', 108, unsafe.Sizeof(uintptr(0)))
+	uiSetUniq       = iow('U', 111, unsafe.Sizeof(uintptr(0)))
+	uiBeginFFUpload = iowr('U', 200, unsafe.Sizeof(uinputFFUpload{}))
+	uiEndFFUpload   = iow('U', 201, unsafe.Sizeof(uinputFFUpload{}))
+	uiBeginFFErase  = iowr('U', 202, unsafe.Sizeof(uinputFFErase{}))
+	uiEndFFErase    = iow('U', 203, unsafe.Sizeof(uinputFFErase{}))
+	uiGetSysname    = ior('U', 44, sysnameSize)
+)
+
+// sysnameSize is the size of the buffer that receives the sysfs name of a device (e.g. "input23")
+const sysnameSize = 64
+
+// the following functions mirror the _IOR, _IOW and _IOWR macros as defined in ioctl.h
+func ior(typ, nr, size uintptr) uintptr {
+	return 2<<30 | size<<16 | typ<<8 | nr
+}
+
+func iow(typ, nr, size uintptr) uintptr {
+	return 1<<30 | size<<16 | typ<<8 | nr
+}
+
+func iowr(typ, nr, size uintptr) uintptr {
+	return 3<<30 | size<<16 | typ<<8 | nr
+}
@@ -0,0 +1,143 @@
+package uinputtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+
+	"github.com/bendahl/uinput"
+)
+
+// the direction bits of ioctl requests that read data from the kernel (see asm-generic/ioctl.h)
+const (
+	iocDirShift = 30
+	iocRead     = 2
+)
+
+// the path of the real devices that back fakes, which is reported by their Path method
+const backingPath = "uinputtest"
+
+// backingOptions returns the options that create a real device of package uinput on a file that discards all requests,
+// while its events are recorded by the given fake. Fakes of devices with absolute axes or contacts are backed by real
+// devices this way, so that they emit exactly the events of the real device and report its state (like the rest
+// positions of axes, see uinput.Device.ResetAll). The options are appended to the options of the caller, which is why
+// WithBackend and WithRecorder cannot be used with these fakes.
+func backingOptions(f *Fake, opts []uinput.Option) []uinput.Option {
+	return append(opts[:len(opts):len(opts)], uinput.WithBackend(discardBackend{}),
+		uinput.WithRecorder(uinput.NewRecorder(recordingWriter{f})))
+}
+
+// recordingWriter adds the events of a recording (see uinput.Recorder) to the events of a fake.
+type recordingWriter struct {
+	fake *Fake
+}
+
+func (w recordingWriter) Write(b []byte) (int, error) {
+	events, err := uinput.ReadRecording(bytes.NewReader(b))
+	if err != nil {
+		return 0, err
+	}
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+	for _, ev := range events {
+		w.fake.events = append(w.fake.events, ev.InputEvent)
+	}
+	return len(b), nil
+}
+
+// discardBackend opens files that discard all requests, as real devices that back fakes are not known to the kernel.
+type discardBackend struct{}
+
+func (discardBackend) Open(path string) (uinput.DeviceFile, error) {
+	return &discardFile{name: path, done: make(chan struct{})}, nil
+}
+
+type discardFile struct {
+	name string
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Read blocks until the file is closed, as nobody sends events to the device.
+func (f *discardFile) Read([]byte) (int, error) {
+	<-f.done
+	return 0, os.ErrClosed
+}
+
+func (f *discardFile) Write(b []byte) (int, error) {
+	select {
+	case <-f.done:
+		return 0, os.ErrClosed
+	default:
+		return len(b), nil
+	}
+}
+
+func (f *discardFile) Close() error {
+	f.closeOnce.Do(func() { close(f.done) })
+	return nil
+}
+
+func (f *discardFile) Name() string {
+	return f.name
+}
+
+func (f *discardFile) Fd() uintptr {
+	return ^uintptr(0)
+}
+
+// Ioctl accepts all requests, unless the file has been closed.
+func (f *discardFile) Ioctl(cmd uintptr, arg uintptr) error {
+	select {
+	case <-f.done:
+		return fmt.Errorf("failed to issue request %#x: %w", cmd, uinput.ErrDeviceClosed)
+	default:
+		return nil
+	}
+}
+
+// IoctlPtr accepts all requests that pass data to the kernel, while queries (like the sysfs name of the device) fail,
+// as there is no kernel to answer them.
+func (f *discardFile) IoctlPtr(cmd uintptr, arg unsafe.Pointer) error {
+	err := f.Ioctl(cmd, uintptr(arg))
+	if err != nil {
+		return err
+	}
+	if cmd>>iocDirShift&iocRead != 0 {
+		return ErrNoSysfs
+	}
+	return nil
+}
+
+// A recording provides the events of fakes that are backed by real devices.
+type recording struct {
+	fake *Fake
+}
+
+// Events returns all events that have been emitted so far, except for the sync events.
+func (r recording) Events() []uinput.InputEvent {
+	return r.fake.Events()
+}
+
+// Frames returns all frames that have been emitted so far (see Fake.Frames).
+func (r recording) Frames() [][]uinput.InputEvent {
+	return r.fake.Frames()
+}
+
+// Reset discards all recorded events.
+func (r recording) Reset() {
+	r.fake.Reset()
+}
+
+// waitReady returns immediately for fakes that are backed by the given device, unless the device has been closed.
+func waitReady(ctx context.Context, device uinput.Device) error {
+	if _, err := device.SysPath(); errors.Is(err, uinput.ErrDeviceClosed) {
+		return fmt.Errorf("failed to wait for the device: %w", err)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,33 @@
+package uinputtest
+
+import (
+	"context"
+
+	"github.com/bendahl/uinput"
+)
+
+// A Dial is a fake dial that records all emitted events. It is backed by a real dial of package uinput (see
+// uinput.CreateDial), so that it emits the same events and reports the same state. Just like real devices, creating it
+// takes about 200ms.
+type Dial struct {
+	uinput.Dial
+	recording
+}
+
+var _ uinput.Dial = (*Dial)(nil)
+
+// NewDial returns a new fake dial. All options of uinput.CreateDial may be given, except for WithBackend and
+// WithRecorder.
+func NewDial(name string, opts ...uinput.Option) (*Dial, error) {
+	fake := NewFake(name)
+	device, err := uinput.CreateDial(backingPath, []byte(name), backingOptions(fake, opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Dial{Dial: device, recording: recording{fake}}, nil
+}
+
+// WaitReady returns immediately, as fakes are ready as soon as they have been created.
+func (d *Dial) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, d.Dial)
+}
@@ -0,0 +1,333 @@
+// Package uinputtest provides test doubles for the devices of package uinput. The fakes implement the same interfaces
+// as the real devices, but record all emitted events in memory instead of writing them to /dev/uinput. This allows
+// unit testing code that depends on virtual input devices in environments without access to uinput, like most CI
+// systems.
+//
+// The fakes report the same events (and frames) that the real devices would emit, so that tests may assert on the
+// resulting event stream. Fakes of devices with absolute axes or contacts (like NewGamepad and NewTouchScreen) are
+// backed by the real devices of package uinput, which are created on a file that discards everything but the events:
+//
+//	kbd := uinputtest.NewKeyboard("test keyboard", nil)
+//	err := typeGreeting(kbd) // the code under test accepts a uinput.Keyboard
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	frames := kbd.Frames()
+package uinputtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bendahl/uinput"
+)
+
+// the code of SYN_REPORT events, which terminate frames
+const synReport = 0
+
+// the multitouch axes that select a slot and report its contact (see input-event-codes.h)
+const (
+	absMtSlot       = 0x2f
+	absMtTrackingID = 0x39
+)
+
+// ErrNoSysfs is returned by SysPath and EventPath of fakes, as they are not known to the kernel.
+var ErrNoSysfs = errors.New("fake devices have no sysfs entry")
+
+// A Fake is a generic device that records all emitted events. It is safe for concurrent use.
+type Fake struct {
+	name string
+
+	mu           sync.Mutex
+	events       []uinput.InputEvent
+	closed       bool
+	disconnected bool
+}
+
+var _ uinput.Device = (*Fake)(nil)
+
+// NewFake returns a new generic fake device with the given name.
+func NewFake(name string) *Fake {
+	return &Fake{name: name}
+}
+
+func (f *Fake) Name() string {
+	return f.name
+}
+
+// Path returns an empty string, as fakes are not backed by a device file.
+func (f *Fake) Path() string {
+	return ""
+}
+
+// Fd returns zero, as fakes are not backed by a device file.
+func (f *Fake) Fd() uintptr {
+	return 0
+}
+
+// EmitEvent will record a raw event, followed by a sync event.
+func (f *Fake) EmitEvent(evType uint16, code uint16, value int32) error {
+	return f.EmitEvents([]uinput.InputEvent{{Type: evType, Code: code, Value: value}})
+}
+
+// EmitEvents will record all given raw events, followed by a single sync event.
+func (f *Fake) EmitEvents(events []uinput.InputEvent) error {
+	if len(events) == 0 {
+		return fmt.Errorf("failed to emit events. At least one event is required: %w", uinput.ErrInvalidArgument)
+	}
+	return f.emit(events...)
+}
+
+// Sync will record a single sync event.
+func (f *Fake) Sync() error {
+	return f.emit()
+}
+
+func (f *Fake) SysPath() (string, error) {
+	return "", ErrNoSysfs
+}
+
+func (f *Fake) EventPath() (string, error) {
+	return "", ErrNoSysfs
+}
+
+// WaitReady returns immediately, as fakes are ready as soon as they have been created.
+func (f *Fake) WaitReady(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return fmt.Errorf("failed to wait for the device: %w", uinput.ErrDeviceClosed)
+	}
+	return ctx.Err()
+}
+
+// Stats returns the number of recorded events and frames. Bytes and latencies are not tracked, as nothing is written.
+func (f *Fake) Stats() uinput.Stats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := uinput.Stats{Events: uint64(len(f.events))}
+	for _, ev := range f.events {
+		if isSyncEvent(ev) {
+			stats.Frames++
+		}
+	}
+	return stats
+}
+
+// State returns the last values of the keys, axes and multitouch slots in the recorded events.
+func (f *Fake) State() uinput.State {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stateLocked()
+}
+
+// ResetAll records a frame that lifts all contacts, releases all keys and moves all axes to 0, which is the rest
+// position real devices use for axes without a range, as generic fakes do not know the ranges of their axes. Fakes of
+// devices with axes (like NewGamepad) are backed by the real device, which resets its axes to their rest positions.
+// Nothing is recorded if everything is at rest already.
+func (f *Fake) ResetAll() error {
+	f.mu.Lock()
+	events := f.resetEventsLocked()
+	f.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+	return f.emit(events...)
+}
+
+func (f *Fake) resetEventsLocked() []uinput.InputEvent {
+	state := f.stateLocked()
+	var events []uinput.InputEvent
+	for _, slot := range sortedKeys(state.Contacts) {
+		events = append(events,
+			uinput.InputEvent{Type: uinput.EvAbs, Code: absMtSlot, Value: slot},
+			uinput.InputEvent{Type: uinput.EvAbs, Code: absMtTrackingID, Value: -1})
+	}
+	for _, code := range sortedCodes(state.Keys) {
+		if state.Keys[code] != 0 {
+			events = append(events, uinput.InputEvent{Type: uinput.EvKey, Code: code})
+		}
+	}
+	for _, code := range sortedCodes(state.Axes) {
+		if state.Axes[code] != 0 {
+			events = append(events, uinput.InputEvent{Type: uinput.EvAbs, Code: code})
+		}
+	}
+	return events
+}
+
+func (f *Fake) stateLocked() uinput.State {
+	state := uinput.State{Keys: map[uint16]int32{}, Axes: map[uint16]int32{}, Contacts: map[int32]int32{}}
+	var slot int32
+	for _, ev := range f.events {
+		switch {
+		case ev.Type == uinput.EvKey:
+			state.Keys[ev.Code] = ev.Value
+		case ev.Type == uinput.EvAbs && ev.Code == absMtSlot:
+			slot = ev.Value
+		case ev.Type == uinput.EvAbs && ev.Code == absMtTrackingID:
+			if ev.Value < 0 {
+				delete(state.Contacts, slot)
+			} else {
+				state.Contacts[slot] = ev.Value
+			}
+		case ev.Type == uinput.EvAbs && ev.Code < absMtSlot:
+			state.Axes[ev.Code] = ev.Value
+		}
+	}
+	return state
+}
+
+// Disconnect marks the device as disconnected. Just like with real devices, events will be rejected until the device
+// has been reconnected.
+func (f *Fake) Disconnect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return fmt.Errorf("failed to disconnect device: %w", uinput.ErrDeviceClosed)
+	}
+	if f.disconnected {
+		return fmt.Errorf("failed to disconnect device. The device is disconnected already: %w", uinput.ErrDisconnected)
+	}
+	f.disconnected = true
+	return nil
+}
+
+// Reconnect marks a disconnected device as connected again.
+func (f *Fake) Reconnect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return fmt.Errorf("failed to reconnect device: %w", uinput.ErrDeviceClosed)
+	}
+	if !f.disconnected {
+		return fmt.Errorf("failed to reconnect device. The device is connected: %w", uinput.ErrInvalidArgument)
+	}
+	f.disconnected = false
+	return nil
+}
+
+// Connected reports whether the device is connected, which is the case unless it has been disconnected.
+func (f *Fake) Connected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.disconnected
+}
+
+// Close marks the device as closed. Just like with real devices, held keys are released first (see ResetAll) and all
+// further events will be rejected.
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return fmt.Errorf("failed to close device: %w", uinput.ErrDeviceClosed)
+	}
+	if events := f.resetEventsLocked(); len(events) > 0 && !f.disconnected {
+		f.recordLocked(events)
+	}
+	f.closed = true
+	return nil
+}
+
+// Closed reports whether the device has been closed.
+func (f *Fake) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// Events returns all events that have been emitted so far, except for the sync events.
+func (f *Fake) Events() []uinput.InputEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var events []uinput.InputEvent
+	for _, ev := range f.events {
+		if !isSyncEvent(ev) {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// Frames returns all frames that have been emitted so far. A frame is made up of the events that have been emitted
+// before a sync event, which is not part of the frame. Sync events without any prior events yield an empty frame.
+func (f *Fake) Frames() [][]uinput.InputEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var frames [][]uinput.InputEvent
+	frame := []uinput.InputEvent{}
+	for _, ev := range f.events {
+		if isSyncEvent(ev) {
+			frames = append(frames, frame)
+			frame = []uinput.InputEvent{}
+			continue
+		}
+		frame = append(frame, ev)
+	}
+	return frames
+}
+
+// Reset discards all recorded events.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	f.events = nil
+	f.mu.Unlock()
+}
+
+// emit records the given events as a single frame.
+func (f *Fake) emit(events ...uinput.InputEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return fmt.Errorf("failed to write events: %w", uinput.ErrDeviceClosed)
+	}
+	if f.disconnected {
+		return fmt.Errorf("failed to write events: %w", uinput.ErrDisconnected)
+	}
+	f.recordLocked(events)
+	return nil
+}
+
+// recordLocked records the given events, followed by a sync event. The caller must hold mu.
+func (f *Fake) recordLocked(events []uinput.InputEvent) {
+	f.events = append(f.events, events...)
+	f.events = append(f.events, uinput.InputEvent{Type: uinput.EvSyn, Code: synReport})
+}
+
+// emitKeys records a frame that sets all given keys to the given state.
+func (f *Fake) emitKeys(keys []int, pressed bool) error {
+	var value int32
+	if pressed {
+		value = 1
+	}
+	events := make([]uinput.InputEvent, len(keys))
+	for i, key := range keys {
+		events[i] = uinput.InputEvent{Type: uinput.EvKey, Code: uint16(key), Value: value}
+	}
+	return f.emit(events...)
+}
+
+func isSyncEvent(ev uinput.InputEvent) bool {
+	return ev.Type == uinput.EvSyn && ev.Code == synReport
+}
+
+func sortedCodes(values map[uint16]int32) []uint16 {
+	codes := make([]uint16, 0, len(values))
+	for code := range values {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+func sortedKeys(values map[int32]int32) []int32 {
+	keys := make([]int32, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
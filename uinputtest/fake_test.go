@@ -0,0 +1,131 @@
+package uinputtest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func TestFakeRecordsFrames(t *testing.T) {
+	f := NewFake("test")
+	err := f.EmitEvents([]uinput.InputEvent{{Type: uinput.EvKey, Code: uinput.KeyA, Value: 1}, {Type: uinput.EvKey, Code: uinput.KeyB, Value: 1}})
+	if err != nil {
+		t.Fatalf("Failed to emit events: %v", err)
+	}
+	err = f.EmitEvent(uinput.EvRel, uinput.RelX, 5)
+	if err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+	err = f.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+
+	expected := [][]uinput.InputEvent{
+		{{Type: uinput.EvKey, Code: uinput.KeyA, Value: 1}, {Type: uinput.EvKey, Code: uinput.KeyB, Value: 1}},
+		{{Type: uinput.EvRel, Code: uinput.RelX, Value: 5}},
+		{},
+	}
+	if frames := f.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+	if events := f.Events(); len(events) != 3 {
+		t.Fatalf("Expected 3 events without the sync events, got %v", events)
+	}
+
+	f.Reset()
+	if frames := f.Frames(); len(frames) != 0 {
+		t.Fatalf("Expected no frames after Reset, got %v", frames)
+	}
+}
+
+func TestFakeRejectsEmptyEvents(t *testing.T) {
+	err := NewFake("test").EmitEvents(nil)
+	if !errors.Is(err, uinput.ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestFakeRejectsEventsAfterClose(t *testing.T) {
+	f := NewFake("test")
+	err := f.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the device: %v", err)
+	}
+	if !f.Closed() {
+		t.Fatalf("Expected the device to be closed")
+	}
+	err = f.EmitEvent(uinput.EvKey, uinput.KeyA, 1)
+	if !errors.Is(err, uinput.ErrDeviceClosed) {
+		t.Fatalf("Expected ErrDeviceClosed, got %v", err)
+	}
+	err = f.Close()
+	if !errors.Is(err, uinput.ErrDeviceClosed) {
+		t.Fatalf("Expected ErrDeviceClosed when closing twice, got %v", err)
+	}
+}
+
+func TestFakeRejectsEventsWhileDisconnected(t *testing.T) {
+	f := NewFake("test")
+	err := f.Disconnect()
+	if err != nil {
+		t.Fatalf("Failed to disconnect: %v", err)
+	}
+	err = f.EmitEvent(uinput.EvKey, uinput.KeyA, 1)
+	if !errors.Is(err, uinput.ErrDisconnected) || f.Connected() {
+		t.Fatalf("Expected ErrDisconnected, got %v", err)
+	}
+	err = f.Reconnect()
+	if err != nil {
+		t.Fatalf("Failed to reconnect: %v", err)
+	}
+	err = f.EmitEvent(uinput.EvKey, uinput.KeyA, 1)
+	if err != nil || !f.Connected() {
+		t.Fatalf("Expected events to be recorded after reconnecting, got %v", err)
+	}
+}
+
+func TestFakeResetAllReleasesRecordedState(t *testing.T) {
+	f := NewFake("test")
+	err := f.EmitEvents([]uinput.InputEvent{{Type: uinput.EvKey, Code: uinput.KeyA, Value: 1}, {Type: uinput.EvAbs, Code: uinput.AbsX, Value: 7}})
+	if err != nil {
+		t.Fatalf("Failed to emit events: %v", err)
+	}
+	state := f.State()
+	if state.Keys[uinput.KeyA] != 1 || state.Axes[uinput.AbsX] != 7 {
+		t.Fatalf("Expected the recorded state, got %+v", state)
+	}
+
+	err = f.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	frames := f.Frames()
+	expected := []uinput.InputEvent{{Type: uinput.EvKey, Code: uinput.KeyA}, {Type: uinput.EvAbs, Code: uinput.AbsX}}
+	if len(frames) != 2 || !reflect.DeepEqual(frames[1], expected) {
+		t.Fatalf("Expected a frame that releases the key and the axis, got %v", frames)
+	}
+	err = f.ResetAll()
+	if err != nil || len(f.Frames()) != 2 {
+		t.Fatalf("Expected nothing to be recorded once everything is at rest, got %v", err)
+	}
+}
+
+func TestFakeCloseReleasesHeldKeys(t *testing.T) {
+	f := NewFake("test")
+	err := f.EmitEvent(uinput.EvKey, uinput.KeyLeftshift, 1)
+	if err != nil {
+		t.Fatalf("Failed to emit event: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	frames := f.Frames()
+	expected := []uinput.InputEvent{{Type: uinput.EvKey, Code: uinput.KeyLeftshift}}
+	if len(frames) != 2 || !reflect.DeepEqual(frames[1], expected) {
+		t.Fatalf("Expected a frame that releases the key, got %v", frames)
+	}
+}
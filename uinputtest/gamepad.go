@@ -0,0 +1,33 @@
+package uinputtest
+
+import (
+	"context"
+
+	"github.com/bendahl/uinput"
+)
+
+// A Gamepad is a fake gamepad that records all emitted events. It is backed by a real gamepad of package uinput (see
+// uinput.CreateGamepad), so that it emits the same events and reports the same state. Just like real devices, creating
+// it takes about 200ms.
+type Gamepad struct {
+	uinput.Gamepad
+	recording
+}
+
+var _ uinput.Gamepad = (*Gamepad)(nil)
+
+// NewGamepad returns a new fake gamepad. All options of uinput.CreateGamepad (like uinput.WithDpadButtons) may be
+// given, except for WithBackend and WithRecorder.
+func NewGamepad(name string, opts ...uinput.Option) (*Gamepad, error) {
+	fake := NewFake(name)
+	device, err := uinput.CreateGamepad(backingPath, []byte(name), backingOptions(fake, opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Gamepad{Gamepad: device, recording: recording{fake}}, nil
+}
+
+// WaitReady returns immediately, as fakes are ready as soon as they have been created.
+func (g *Gamepad) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, g.Gamepad)
+}
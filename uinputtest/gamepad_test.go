@@ -0,0 +1,75 @@
+package uinputtest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func abs(code uint16, value int32) uinput.InputEvent {
+	return uinput.InputEvent{Type: uinput.EvAbs, Code: code, Value: value}
+}
+
+func TestGamepadEmitsTheEventsOfRealGamepads(t *testing.T) {
+	g, err := NewGamepad("test gamepad")
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer g.Close()
+
+	err = g.SetLeftTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move the trigger: %v", err)
+	}
+	err = g.SetHat(uinput.HatUpRight)
+	if err != nil {
+		t.Fatalf("Failed to move the hat: %v", err)
+	}
+	err = g.ButtonDown(uinput.ButtonSouth)
+	if err != nil {
+		t.Fatalf("Failed to press the button: %v", err)
+	}
+	if state := g.State(); state.Keys[uinput.ButtonSouth] != 1 || state.Axes[uinput.AxisLeftTrigger] != 255 {
+		t.Fatalf("Expected the state of the gamepad, got %+v", state)
+	}
+
+	g.Reset()
+	err = g.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	expected := [][]uinput.InputEvent{{
+		key(uinput.ButtonSouth, 0),
+		abs(uinput.AxisLeftTrigger, 0),
+		abs(uinput.AxisHatX, 0),
+		abs(uinput.AxisHatY, 0),
+	}}
+	if frames := g.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestGamepadReportsWhatRealGamepadsReject(t *testing.T) {
+	g, err := NewGamepad("test gamepad", uinput.WithDpadButtons())
+	if err != nil {
+		t.Fatalf("Failed to create the gamepad: %v", err)
+	}
+	defer g.Close()
+
+	err = g.SetAxes(map[uint16]int32{uinput.AxisHatX: 1})
+	if !errors.Is(err, uinput.ErrInvalidArgument) {
+		t.Fatalf("Expected the hat axes to be unavailable with d-pad buttons, got %v", err)
+	}
+	if _, err = g.SysPath(); !errors.Is(err, ErrNoSysfs) {
+		t.Fatalf("Expected: %v\nActual: %v", ErrNoSysfs, err)
+	}
+	err = g.Close()
+	if err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if err = g.ButtonPress(uinput.ButtonSouth); !errors.Is(err, uinput.ErrDeviceClosed) {
+		t.Fatalf("Expected: %v\nActual: %v", uinput.ErrDeviceClosed, err)
+	}
+}
@@ -0,0 +1,221 @@
+package uinputtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+// the range of key codes that keyboards accept, see uinput.Keyboard
+const (
+	minKey = 0
+	maxKey = 248
+)
+
+// ledBufferSize matches the buffer of the LED channel of real keyboards.
+const ledBufferSize = 16
+
+// A Keyboard is a fake keyboard that records all emitted events.
+type Keyboard struct {
+	*Fake
+	layout uinput.Layout
+
+	mu       sync.Mutex
+	ledState uinput.LedState
+	leds     chan uinput.LedEvent
+}
+
+var _ uinput.Keyboard = (*Keyboard)(nil)
+
+// NewKeyboard returns a new fake keyboard, which types using the given layout. If layout is nil, uinput.LayoutUS is
+// used, just like with real keyboards. Unlike real keyboards, key repeat is always available.
+func NewKeyboard(name string, layout uinput.Layout) *Keyboard {
+	if layout == nil {
+		layout = uinput.LayoutUS
+	}
+	return &Keyboard{Fake: NewFake(name), layout: layout, leds: make(chan uinput.LedEvent, ledBufferSize)}
+}
+
+// KeyPress will record a key press, followed by a key release.
+func (k *Keyboard) KeyPress(key int) error {
+	if err := assertKey("KeyPress", key); err != nil {
+		return err
+	}
+	err := k.emitKeys([]int{key}, true)
+	if err != nil {
+		return fmt.Errorf("failed to issue the KeyDown event: %w", err)
+	}
+	return k.emitKeys([]int{key}, false)
+}
+
+// KeyDown will record a key press.
+func (k *Keyboard) KeyDown(key int) error {
+	if err := assertKey("KeyDown", key); err != nil {
+		return err
+	}
+	return k.emitKeys([]int{key}, true)
+}
+
+// KeyUp will record a key release.
+func (k *Keyboard) KeyUp(key int) error {
+	if err := assertKey("KeyUp", key); err != nil {
+		return err
+	}
+	return k.emitKeys([]int{key}, false)
+}
+
+// Type will record the key strokes that type the given string.
+func (k *Keyboard) Type(s string) error {
+	return k.TypeContext(context.Background(), s)
+}
+
+// TypeContext will record the key strokes that type the given string until all characters have been typed or the
+// context is done.
+func (k *Keyboard) TypeContext(ctx context.Context, s string) error {
+	var strokes []uinput.KeyStroke
+	for _, r := range s {
+		rs, ok := k.layout.Strokes(r)
+		if !ok {
+			return fmt.Errorf("failed to perform Type. Character %q is not available in the keyboard layout: %w", r, uinput.ErrInvalidArgument)
+		}
+		strokes = append(strokes, rs...)
+	}
+
+	for _, stroke := range strokes {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to perform Type: %w", err)
+		}
+		err := k.typeStroke(stroke)
+		if err != nil {
+			return fmt.Errorf("failed to perform Type: %w", err)
+		}
+	}
+	return nil
+}
+
+// KeyPressFor will record a key press, followed by a key release once the duration has passed.
+func (k *Keyboard) KeyPressFor(key int, d time.Duration) error {
+	return k.KeyPressForContext(context.Background(), key, d)
+}
+
+// KeyPressForContext will record a key press, followed by a key release once the duration has passed or the context
+// is done.
+func (k *Keyboard) KeyPressForContext(ctx context.Context, key int, d time.Duration) error {
+	if err := assertKey("KeyPressFor", key); err != nil {
+		return err
+	}
+	return uinput.PressForContext(ctx, k.Fake, uint16(key), d)
+}
+
+// Combo will record the presses of all given keys, followed by their releases in reverse order.
+func (k *Keyboard) Combo(keys ...int) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("failed to perform Combo. At least one key is required: %w", uinput.ErrInvalidArgument)
+	}
+	for _, key := range keys {
+		if err := assertKey("Combo", key); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := k.emitKeys([]int{key}, true); err != nil {
+			return fmt.Errorf("failed to press key %d of combo: %w", key, err)
+		}
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := k.emitKeys([]int{keys[i]}, false); err != nil {
+			return fmt.Errorf("failed to release key %d of combo: %w", keys[i], err)
+		}
+	}
+	return nil
+}
+
+// SetRepeatRate will record the new repeat rate.
+func (k *Keyboard) SetRepeatRate(delay time.Duration, period time.Duration) error {
+	if delay < 0 || period < 0 {
+		return fmt.Errorf("failed to perform SetRepeatRate. Expected a positive or zero delay and period: %w", uinput.ErrInvalidArgument)
+	}
+	return k.emit(
+		uinput.InputEvent{Type: uinput.EvRep, Code: uinput.RepDelay, Value: int32(delay / time.Millisecond)},
+		uinput.InputEvent{Type: uinput.EvRep, Code: uinput.RepPeriod, Value: int32(period / time.Millisecond)})
+}
+
+func (k *Keyboard) Leds() <-chan uinput.LedEvent {
+	return k.leds
+}
+
+func (k *Keyboard) LedState() uinput.LedState {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.ledState
+}
+
+// SetLed simulates a consumer of the keyboard switching the given LED (e.g. uinput.LedCapsl). The change is reported
+// via Leds and LedState, just like with real keyboards.
+func (k *Keyboard) SetLed(led uint16, on bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.Closed() {
+		return
+	}
+	switch led {
+	case uinput.LedNuml:
+		k.ledState.NumLock = on
+	case uinput.LedCapsl:
+		k.ledState.CapsLock = on
+	case uinput.LedScrolll:
+		k.ledState.ScrollLock = on
+	case uinput.LedCompose:
+		k.ledState.Compose = on
+	case uinput.LedKana:
+		k.ledState.Kana = on
+	}
+
+	select {
+	case k.leds <- uinput.LedEvent{Led: led, On: on}:
+	default:
+	}
+}
+
+// Close closes the device as well as the LED channel.
+func (k *Keyboard) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	err := k.Fake.Close()
+	if err != nil {
+		return err
+	}
+	close(k.leds)
+	return nil
+}
+
+func (k *Keyboard) typeStroke(stroke uinput.KeyStroke) (err error) {
+	if len(stroke.Modifiers) > 0 {
+		err = k.emitKeys(stroke.Modifiers, true)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			releaseErr := k.emitKeys(stroke.Modifiers, false)
+			if err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+
+	err = k.emitKeys([]int{stroke.Key}, true)
+	if err != nil {
+		return err
+	}
+	return k.emitKeys([]int{stroke.Key}, false)
+}
+
+func assertKey(op string, key int) error {
+	if key < minKey || key > maxKey {
+		return fmt.Errorf("failed to perform %s. Code %d is not in range: %w", op, key, uinput.ErrInvalidArgument)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package uinputtest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+func key(code int, value int32) uinput.InputEvent {
+	return uinput.InputEvent{Type: uinput.EvKey, Code: uint16(code), Value: value}
+}
+
+func TestKeyboardTypeReportsModifiers(t *testing.T) {
+	kbd := NewKeyboard("test", nil)
+	err := kbd.Type("A")
+	if err != nil {
+		t.Fatalf("Failed to type: %v", err)
+	}
+
+	expected := [][]uinput.InputEvent{
+		{key(uinput.KeyLeftshift, 1)},
+		{key(uinput.KeyA, 1)},
+		{key(uinput.KeyA, 0)},
+		{key(uinput.KeyLeftshift, 0)},
+	}
+	if frames := kbd.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestKeyboardTypeRejectsUnknownCharacters(t *testing.T) {
+	kbd := NewKeyboard("test", nil)
+	err := kbd.Type("aä")
+	if !errors.Is(err, uinput.ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument, got %v", err)
+	}
+	if frames := kbd.Frames(); len(frames) != 0 {
+		t.Fatalf("Expected nothing to be typed, got %v", frames)
+	}
+}
+
+func TestKeyboardCombo(t *testing.T) {
+	kbd := NewKeyboard("test", nil)
+	err := kbd.Combo(uinput.KeyLeftctrl, uinput.KeyC)
+	if err != nil {
+		t.Fatalf("Failed to perform combo: %v", err)
+	}
+
+	expected := [][]uinput.InputEvent{
+		{key(uinput.KeyLeftctrl, 1)},
+		{key(uinput.KeyC, 1)},
+		{key(uinput.KeyC, 0)},
+		{key(uinput.KeyLeftctrl, 0)},
+	}
+	if frames := kbd.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestKeyboardKeyPressFor(t *testing.T) {
+	kbd := NewKeyboard("test", nil)
+	err := kbd.KeyPressFor(uinput.KeyA, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+
+	expected := [][]uinput.InputEvent{{key(uinput.KeyA, 1)}, {key(uinput.KeyA, 0)}}
+	if frames := kbd.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestKeyboardSetLed(t *testing.T) {
+	kbd := NewKeyboard("test", nil)
+	kbd.SetLed(uinput.LedCapsl, true)
+
+	ev := <-kbd.Leds()
+	if ev.Led != uinput.LedCapsl || !ev.On {
+		t.Fatalf("Expected caps lock to be switched on, got %v", ev)
+	}
+	if !kbd.LedState().CapsLock {
+		t.Fatalf("Expected caps lock to be reported as on")
+	}
+
+	err := kbd.Close()
+	if err != nil {
+		t.Fatalf("Failed to close the keyboard: %v", err)
+	}
+	if _, ok := <-kbd.Leds(); ok {
+		t.Fatalf("Expected the LED channel to be closed")
+	}
+}
@@ -0,0 +1,224 @@
+package uinputtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+// the value of a single wheel notch on the high-resolution wheel axes
+const hiResNotch = 120
+
+// the largest number of notches that may be scrolled at once, as the high-resolution axes report it in 32 bit
+const maxWheelNotches = math.MaxInt32 / hiResNotch
+
+// A Mouse is a fake mouse that records all emitted events. Unlike with real mice, smooth movements do not wait
+// between their steps, so that tests do not take longer than necessary.
+type Mouse struct {
+	*Fake
+
+	mu        sync.Mutex
+	remainder [2]int32
+}
+
+var _ uinput.Mouse = (*Mouse)(nil)
+
+// NewMouse returns a new fake mouse.
+func NewMouse(name string) *Mouse {
+	return &Mouse{Fake: NewFake(name)}
+}
+
+// MoveLeft will record a move to the left by the number of pixel specified.
+func (m *Mouse) MoveLeft(pixel int32) error {
+	return m.moveRel("MoveLeft", uinput.RelX, -1, pixel)
+}
+
+// MoveRight will record a move to the right by the number of pixel specified.
+func (m *Mouse) MoveRight(pixel int32) error {
+	return m.moveRel("MoveRight", uinput.RelX, 1, pixel)
+}
+
+// MoveUp will record a move up by the number of pixel specified.
+func (m *Mouse) MoveUp(pixel int32) error {
+	return m.moveRel("MoveUp", uinput.RelY, -1, pixel)
+}
+
+// MoveDown will record a move down by the number of pixel specified.
+func (m *Mouse) MoveDown(pixel int32) error {
+	return m.moveRel("MoveDown", uinput.RelY, 1, pixel)
+}
+
+// Move will record a move along both axes within a single frame.
+func (m *Mouse) Move(x, y int32) error {
+	err := m.emit(
+		uinput.InputEvent{Type: uinput.EvRel, Code: uinput.RelX, Value: x},
+		uinput.InputEvent{Type: uinput.EvRel, Code: uinput.RelY, Value: y})
+	if err != nil {
+		return fmt.Errorf("failed to move the mouse: %w", err)
+	}
+	return nil
+}
+
+// MoveSmooth will record a movement that is split into the given number of steps. If steps is zero, the number of
+// steps is derived from the duration, just like with real mice.
+func (m *Mouse) MoveSmooth(x, y int32, duration time.Duration, steps int) error {
+	return m.MoveSmoothContext(context.Background(), x, y, duration, steps)
+}
+
+// MoveSmoothContext will record a smooth movement until all steps have been performed or the context is done.
+func (m *Mouse) MoveSmoothContext(ctx context.Context, x, y int32, duration time.Duration, steps int) error {
+	if steps < 0 {
+		return fmt.Errorf("failed to perform MoveSmooth. %d is out of range. Expected a positive or zero number of steps: %w", steps, uinput.ErrInvalidArgument)
+	}
+	if steps == 0 {
+		// real mice move at the polling rate of a typical USB mouse (125 Hz)
+		steps = int(duration / (8 * time.Millisecond))
+		if steps < 1 {
+			steps = 1
+		}
+	}
+
+	var prevX, prevY int32
+	for i := 0; i < steps; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		nextX := int32(int64(x) * int64(i+1) / int64(steps))
+		nextY := int32(int64(y) * int64(i+1) / int64(steps))
+		err := m.Move(nextX-prevX, nextY-prevY)
+		if err != nil {
+			return err
+		}
+		prevX, prevY = nextX, nextY
+	}
+	return nil
+}
+
+// LeftClick will record a click of the left button.
+func (m *Mouse) LeftClick() error {
+	return m.click(uinput.BtnLeft)
+}
+
+// RightClick will record a click of the right button.
+func (m *Mouse) RightClick() error {
+	return m.click(uinput.BtnRight)
+}
+
+// MiddleClick will record a click of the middle button.
+func (m *Mouse) MiddleClick() error {
+	return m.click(uinput.BtnMiddle)
+}
+
+// LeftPress will record a press of the left button.
+func (m *Mouse) LeftPress() error {
+	return m.emitKeys([]int{uinput.BtnLeft}, true)
+}
+
+// LeftRelease will record a release of the left button.
+func (m *Mouse) LeftRelease() error {
+	return m.emitKeys([]int{uinput.BtnLeft}, false)
+}
+
+// RightPress will record a press of the right button.
+func (m *Mouse) RightPress() error {
+	return m.emitKeys([]int{uinput.BtnRight}, true)
+}
+
+// RightRelease will record a release of the right button.
+func (m *Mouse) RightRelease() error {
+	return m.emitKeys([]int{uinput.BtnRight}, false)
+}
+
+// MiddlePress will record a press of the middle button.
+func (m *Mouse) MiddlePress() error {
+	return m.emitKeys([]int{uinput.BtnMiddle}, true)
+}
+
+// MiddleRelease will record a release of the middle button.
+func (m *Mouse) MiddleRelease() error {
+	return m.emitKeys([]int{uinput.BtnMiddle}, false)
+}
+
+// ButtonPressFor will record a press of the given button, followed by its release once the duration has passed.
+func (m *Mouse) ButtonPressFor(button int, d time.Duration) error {
+	return m.ButtonPressForContext(context.Background(), button, d)
+}
+
+// ButtonPressForContext will record a press of the given button, followed by its release once the duration has passed
+// or the context is done.
+func (m *Mouse) ButtonPressForContext(ctx context.Context, button int, d time.Duration) error {
+	if button != uinput.BtnLeft && button != uinput.BtnRight && button != uinput.BtnMiddle {
+		return fmt.Errorf("failed to perform ButtonPressFor. Code %d is not a mouse button: %w", button, uinput.ErrInvalidArgument)
+	}
+	return uinput.PressForContext(ctx, m.Fake, uint16(button), d)
+}
+
+// Wheel will record a wheel movement on the regular as well as the high-resolution wheel axes.
+func (m *Mouse) Wheel(horizontal bool, delta int32) error {
+	if delta > maxWheelNotches || delta < -maxWheelNotches {
+		return fmt.Errorf("failed to perform Wheel. %d is out of range. Expected a number of notches up to %d: %w", delta, maxWheelNotches, uinput.ErrInvalidArgument)
+	}
+	w, hiRes := uint16(uinput.RelWheel), uint16(uinput.RelWheelHiRes)
+	if horizontal {
+		w, hiRes = uinput.RelHWheel, uinput.RelHWheelHiRes
+	}
+	return m.emit(
+		uinput.InputEvent{Type: uinput.EvRel, Code: w, Value: delta},
+		uinput.InputEvent{Type: uinput.EvRel, Code: hiRes, Value: delta * hiResNotch})
+}
+
+// Scroll will record a scroll by the given number of wheel notches. Fractions of notches add up until a whole notch
+// is reported on the regular wheel axes, just like with real mice.
+func (m *Mouse) Scroll(vertical, horizontal float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []uinput.InputEvent
+	for i, axis := range []struct {
+		notches float64
+		code    uint16
+		hiRes   uint16
+	}{
+		{vertical, uinput.RelWheel, uinput.RelWheelHiRes},
+		{horizontal, uinput.RelHWheel, uinput.RelHWheelHiRes},
+	} {
+		hiRes := int32(math.Round(axis.notches * hiResNotch))
+		if hiRes == 0 {
+			continue
+		}
+		m.remainder[i] += hiRes
+		notches := m.remainder[i] / hiResNotch
+		m.remainder[i] -= notches * hiResNotch
+		if notches != 0 {
+			events = append(events, uinput.InputEvent{Type: uinput.EvRel, Code: axis.code, Value: notches})
+		}
+		events = append(events, uinput.InputEvent{Type: uinput.EvRel, Code: axis.hiRes, Value: hiRes})
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	err := m.emit(events...)
+	if err != nil {
+		return fmt.Errorf("failed to scroll: %w", err)
+	}
+	return nil
+}
+
+func (m *Mouse) moveRel(op string, code uint16, sign int32, pixel int32) error {
+	if pixel < 0 {
+		return fmt.Errorf("failed to perform %s. %d is out of range. Expected a positive value: %w", op, pixel, uinput.ErrInvalidArgument)
+	}
+	return m.emit(uinput.InputEvent{Type: uinput.EvRel, Code: code, Value: sign * pixel})
+}
+
+func (m *Mouse) click(button int) error {
+	err := m.emitKeys([]int{button}, true)
+	if err != nil {
+		return fmt.Errorf("failed to issue the press event: %w", err)
+	}
+	return m.emitKeys([]int{button}, false)
+}
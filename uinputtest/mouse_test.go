@@ -0,0 +1,65 @@
+package uinputtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bendahl/uinput"
+)
+
+func rel(code uint16, value int32) uinput.InputEvent {
+	return uinput.InputEvent{Type: uinput.EvRel, Code: code, Value: value}
+}
+
+func TestMouseMoveSmoothAddsUp(t *testing.T) {
+	m := NewMouse("test")
+	err := m.MoveSmooth(10, -5, time.Second, 3)
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+
+	var x, y int32
+	frames := m.Frames()
+	for _, frame := range frames {
+		x += frame[0].Value
+		y += frame[1].Value
+	}
+	if len(frames) != 3 || x != 10 || y != -5 {
+		t.Fatalf("Expected 3 steps adding up to (10, -5), got %v", frames)
+	}
+}
+
+func TestMouseScrollAddsUpFractions(t *testing.T) {
+	m := NewMouse("test")
+	for i := 0; i < 2; i++ {
+		err := m.Scroll(0.5, 0)
+		if err != nil {
+			t.Fatalf("Failed to scroll: %v", err)
+		}
+	}
+
+	expected := [][]uinput.InputEvent{
+		{rel(uinput.RelWheelHiRes, 60)},
+		{rel(uinput.RelWheel, 1), rel(uinput.RelWheelHiRes, 60)},
+	}
+	if frames := m.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
+
+func TestMouseClick(t *testing.T) {
+	m := NewMouse("test")
+	err := m.RightClick()
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+
+	expected := [][]uinput.InputEvent{
+		{key(uinput.BtnRight, 1)},
+		{key(uinput.BtnRight, 0)},
+	}
+	if frames := m.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Fatalf("Expected frames %v, got %v", expected, frames)
+	}
+}
@@ -0,0 +1,33 @@
+package uinputtest
+
+import (
+	"context"
+
+	"github.com/bendahl/uinput"
+)
+
+// A TouchPad is a fake touch pad that records all emitted events. It is backed by a real touch pad of package uinput
+// (see uinput.CreateTouchPad), so that it emits the same events and reports the same state. Just like real devices,
+// creating it takes about 200ms.
+type TouchPad struct {
+	uinput.TouchPad
+	recording
+}
+
+var _ uinput.TouchPad = (*TouchPad)(nil)
+
+// NewTouchPad returns a new fake touch pad. All options of uinput.CreateTouchPad may be given, except for WithBackend
+// and WithRecorder.
+func NewTouchPad(name string, minX int32, maxX int32, minY int32, maxY int32, opts ...uinput.Option) (*TouchPad, error) {
+	fake := NewFake(name)
+	device, err := uinput.CreateTouchPad(backingPath, []byte(name), minX, maxX, minY, maxY, backingOptions(fake, opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return &TouchPad{TouchPad: device, recording: recording{fake}}, nil
+}
+
+// WaitReady returns immediately, as fakes are ready as soon as they have been created.
+func (t *TouchPad) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, t.TouchPad)
+}
@@ -0,0 +1,33 @@
+package uinputtest
+
+import (
+	"context"
+
+	"github.com/bendahl/uinput"
+)
+
+// A TouchScreen is a fake touch screen that records all emitted events. It is backed by a real touch screen of package
+// uinput (see uinput.CreateTouchScreen), so that it emits the same events and reports the same state. Just like real
+// devices, creating it takes about 200ms.
+type TouchScreen struct {
+	uinput.TouchScreen
+	recording
+}
+
+var _ uinput.TouchScreen = (*TouchScreen)(nil)
+
+// NewTouchScreen returns a new fake touch screen. All options of uinput.CreateTouchScreen (like
+// uinput.WithMultitouchProtocolA) may be given, except for WithBackend and WithRecorder.
+func NewTouchScreen(name string, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...uinput.Option) (*TouchScreen, error) {
+	fake := NewFake(name)
+	device, err := uinput.CreateTouchScreen(backingPath, []byte(name), minX, maxX, minY, maxY, slots, backingOptions(fake, opts)...)
+	if err != nil {
+		return nil, err
+	}
+	return &TouchScreen{TouchScreen: device, recording: recording{fake}}, nil
+}
+
+// WaitReady returns immediately, as fakes are ready as soon as they have been created.
+func (t *TouchScreen) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, t.TouchScreen)
+}
@@ -0,0 +1,79 @@
+package uinputtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bendahl/uinput"
+)
+
+func TestTouchScreenTracksContacts(t *testing.T) {
+	ts, err := NewTouchScreen("test touch screen", 0, 1023, 0, 767, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the touch screen: %v", err)
+	}
+	defer ts.Close()
+
+	contact, err := ts.Touch(100, 200)
+	if err != nil {
+		t.Fatalf("Failed to touch: %v", err)
+	}
+	err = contact.Move(150, 250)
+	if err != nil {
+		t.Fatalf("Failed to move the contact: %v", err)
+	}
+	if state := ts.State(); len(state.Contacts) != 1 || state.Axes[uinput.AbsX] != 150 {
+		t.Fatalf("Expected a single contact at the new position, got %+v", state)
+	}
+
+	err = ts.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	if state := ts.State(); len(state.Contacts) != 0 || state.Keys[uinput.BtnTouch] != 0 {
+		t.Fatalf("Expected the contact to be lifted, got %+v", state)
+	}
+	if err = contact.Lift(); !errors.Is(err, uinput.ErrInvalidArgument) {
+		t.Fatalf("Expected the lifted contact to be rejected, got %v", err)
+	}
+	if frames := ts.Frames(); len(frames) != 3 {
+		t.Fatalf("Expected the touch, the move and the reset, got %v", frames)
+	}
+}
+
+func TestDialAndTouchPadEmitTheEventsOfRealDevices(t *testing.T) {
+	dial, err := NewDial("test dial")
+	if err != nil {
+		t.Fatalf("Failed to create the dial: %v", err)
+	}
+	defer dial.Close()
+	err = dial.Turn(-3)
+	if err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if events := dial.Events(); len(events) != 1 || events[0] != rel(uinput.RelDial, -3) {
+		t.Fatalf("Expected a single turn, got %v", events)
+	}
+
+	pad, err := NewTouchPad("test touch pad", 0, 1023, 0, 767)
+	if err != nil {
+		t.Fatalf("Failed to create the touch pad: %v", err)
+	}
+	defer pad.Close()
+	err = pad.MoveTo(10, 20)
+	if err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	err = pad.TouchDown()
+	if err != nil {
+		t.Fatalf("Failed to touch down: %v", err)
+	}
+	err = pad.ResetAll()
+	if err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	// the position of the pointer is kept, just like with real touch pads
+	if state := pad.State(); state.Keys[uinput.BtnTouch] != 0 || state.Axes[uinput.AbsX] != 10 || state.Axes[uinput.AbsY] != 20 {
+		t.Fatalf("Expected the touch to be released at the same position, got %+v", state)
+	}
+}
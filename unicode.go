@@ -0,0 +1,65 @@
+package uinput
+
+import (
+	"strconv"
+)
+
+// UnicodeLayout returns a layout that types characters of the base layout directly and all other characters as
+// Unicode hex sequences: Ctrl+Shift+U, followed by the hex digits of the codepoint and a space to commit it. Hex
+// sequences are understood by IBus and GTK applications, among others. The hex digits are typed using the base
+// layout, which is why it needs to provide them.
+func UnicodeLayout(base Layout) Layout {
+	return unicodeLayout{base: base}
+}
+
+type unicodeLayout struct {
+	base Layout
+}
+
+func (l unicodeLayout) Strokes(r rune) ([]KeyStroke, bool) {
+	if strokes, ok := l.base.Strokes(r); ok {
+		return strokes, true
+	}
+	if r < 0 || r > 0x10ffff {
+		return nil, false
+	}
+	strokes := []KeyStroke{{Key: KeyU, Modifiers: []int{KeyLeftctrl, KeyLeftshift}}}
+	for _, digit := range strconv.FormatInt(int64(r), 16) {
+		digitStrokes, ok := l.base.Strokes(digit)
+		if !ok {
+			return nil, false
+		}
+		strokes = append(strokes, digitStrokes...)
+	}
+	return append(strokes, KeyStroke{Key: KeySpace}), true
+}
+
+// ComposeLayout returns a layout that types the given characters as compose sequences (see XCompose): the compose key
+// followed by the characters of the sequence, which are typed using the base layout. E.g. with the sequence "oe"
+// for 'œ', typing 'œ' presses the compose key, 'o' and 'e'. The compose key needs to be configured in the session
+// (like KeyRightalt using the compose:ralt option of XKB). All other characters are looked up in the base layout.
+func ComposeLayout(base Layout, composeKey KeyStroke, sequences map[rune]string) Layout {
+	return composeLayout{base: base, key: composeKey, sequences: sequences}
+}
+
+type composeLayout struct {
+	base      Layout
+	key       KeyStroke
+	sequences map[rune]string
+}
+
+func (l composeLayout) Strokes(r rune) ([]KeyStroke, bool) {
+	sequence, ok := l.sequences[r]
+	if !ok {
+		return l.base.Strokes(r)
+	}
+	strokes := []KeyStroke{l.key}
+	for _, c := range sequence {
+		cStrokes, ok := l.base.Strokes(c)
+		if !ok {
+			return nil, false
+		}
+		strokes = append(strokes, cStrokes...)
+	}
+	return strokes, true
+}
@@ -0,0 +1,44 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeLayoutTypesHexSequences(t *testing.T) {
+	layout := UnicodeLayout(LayoutFR)
+	strokes, ok := layout.Strokes('a')
+	if !ok || !reflect.DeepEqual(strokes, []KeyStroke{{Key: KeyQ}}) {
+		t.Fatalf("Expected characters of the base layout to be typed directly, got %+v", strokes)
+	}
+
+	strokes, ok = layout.Strokes('→')
+	shift := []int{KeyLeftshift}
+	expected := []KeyStroke{
+		{Key: KeyU, Modifiers: []int{KeyLeftctrl, KeyLeftshift}},
+		{Key: Key2, Modifiers: shift}, {Key: Key1, Modifiers: shift}, {Key: Key9, Modifiers: shift}, {Key: Key2, Modifiers: shift},
+		{Key: KeySpace},
+	}
+	if !ok || !reflect.DeepEqual(strokes, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v (found: %v)", expected, strokes, ok)
+	}
+}
+
+func TestComposeLayoutTypesSequences(t *testing.T) {
+	compose := KeyStroke{Key: KeyRightalt}
+	layout := ComposeLayout(LayoutUS, compose, map[rune]string{'œ': "oe", '☺': ":)"})
+
+	strokes, ok := layout.Strokes('œ')
+	expected := []KeyStroke{compose, {Key: KeyO}, {Key: KeyE}}
+	if !ok || !reflect.DeepEqual(strokes, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v (found: %v)", expected, strokes, ok)
+	}
+	strokes, ok = layout.Strokes('☺')
+	expected = []KeyStroke{compose, {Key: KeySemicolon, Modifiers: []int{KeyLeftshift}}, {Key: Key0, Modifiers: []int{KeyLeftshift}}}
+	if !ok || !reflect.DeepEqual(strokes, expected) {
+		t.Fatalf("Expected: %+v\nActual: %+v (found: %v)", expected, strokes, ok)
+	}
+	if _, ok = layout.Strokes('ß'); ok {
+		t.Fatalf("Expected 'ß' not to be available without a sequence")
+	}
+}
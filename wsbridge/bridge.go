@@ -0,0 +1,236 @@
+// Package wsbridge provides a WebSocket endpoint that maps JSON messages to the events of a virtual mouse and keyboard,
+// so that web-based dashboards may remote-control a machine through this library. Every text message is a single
+// command:
+//
+//	{"type": "move", "x": 10, "y": -5}
+//	{"type": "click", "button": "left"}          (buttons are left, right and middle)
+//	{"type": "press", "button": "left"}          (and release)
+//	{"type": "scroll", "vertical": 1.5, "horizontal": 0}
+//	{"type": "keypress", "key": "KEY_A"}         (and keydown, keyup)
+//	{"type": "combo", "keys": ["KEY_LEFTCTRL", "KEY_C"]}
+//	{"type": "type", "text": "hello"}
+//
+// Commands are executed in the order they are received. Keys and buttons that are held by a connection (using keydown
+// and press) are released once the connection is gone, so that devices are not left with stuck input. Failed commands are answered with a message of the form
+// {"id": ..., "error": "..."}, where id is the id of the command, if any. Successful commands are not answered, so that
+// high-frequency pointer movements do not cause any traffic in the other direction.
+package wsbridge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bendahl/uinput"
+)
+
+// A Handler accepts WebSocket connections and executes the commands it receives on its devices. Commands for a device
+// that is nil are rejected. A Handler may serve any number of connections concurrently.
+type Handler struct {
+	Mouse    uinput.Mouse
+	Keyboard uinput.Keyboard
+
+	// CheckOrigin reports whether the connection request is accepted. If nil, only requests without an Origin header
+	// and requests from pages of the same host are accepted, which prevents arbitrary web pages that are opened in a
+	// browser on the machine from controlling it.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// A command is a single message of a client.
+type command struct {
+	ID         json.RawMessage `json:"id,omitempty"`
+	Type       string          `json:"type"`
+	X          int32           `json:"x"`
+	Y          int32           `json:"y"`
+	Button     string          `json:"button"`
+	Vertical   float64         `json:"vertical"`
+	Horizontal float64         `json:"horizontal"`
+	Key        string          `json:"key"`
+	Keys       []string        `json:"keys"`
+	Text       string          `json:"text"`
+}
+
+// a reply reports a failed command
+type reply struct {
+	ID    json.RawMessage `json:"id,omitempty"`
+	Error string          `json:"error"`
+}
+
+// heldControls are the keys and buttons that have been pressed by a connection, but not released yet.
+type heldControls struct {
+	keys    map[int]bool
+	buttons map[string]bool
+}
+
+var errNoDevice = errors.New("device not available")
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	checkOrigin := h.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	c, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer c.raw.Close()
+	held := heldControls{keys: map[int]bool{}, buttons: map[string]bool{}}
+	defer h.release(held)
+
+	for {
+		message, err := c.readMessage()
+		if err != nil {
+			return
+		}
+		var cmd command
+		err = json.Unmarshal(message, &cmd)
+		if err == nil {
+			err = h.execute(cmd, held)
+		}
+		if err != nil {
+			answer, _ := json.Marshal(reply{ID: cmd.ID, Error: err.Error()})
+			if c.writeText(answer) != nil {
+				return
+			}
+		}
+	}
+}
+
+// release releases the keys and buttons that are still held by a connection. Failures are ignored, as there is no one
+// left to report them to.
+func (h *Handler) release(held heldControls) {
+	for key := range held.keys {
+		_ = h.Keyboard.KeyUp(key)
+	}
+	for button := range held.buttons {
+		_, _, release, _ := h.buttonActions(button)
+		_ = release()
+	}
+}
+
+// execute executes the command on the respective device and keeps track of the keys and buttons that are held.
+func (h *Handler) execute(cmd command, held heldControls) error {
+	switch cmd.Type {
+	case "move", "click", "press", "release", "scroll":
+		if h.Mouse == nil {
+			return fmt.Errorf("failed to execute %s: mouse %w", cmd.Type, errNoDevice)
+		}
+		return h.executeMouse(cmd, held)
+	case "keypress", "keydown", "keyup", "combo", "type":
+		if h.Keyboard == nil {
+			return fmt.Errorf("failed to execute %s: keyboard %w", cmd.Type, errNoDevice)
+		}
+		return h.executeKeyboard(cmd, held)
+	default:
+		return fmt.Errorf("unknown command type %q", cmd.Type)
+	}
+}
+
+func (h *Handler) executeMouse(cmd command, held heldControls) error {
+	switch cmd.Type {
+	case "move":
+		return h.Mouse.Move(cmd.X, cmd.Y)
+	case "scroll":
+		return h.Mouse.Scroll(cmd.Vertical, cmd.Horizontal)
+	}
+
+	button := cmd.Button
+	if button == "" {
+		button = "left"
+	}
+	click, press, release, err := h.buttonActions(button)
+	if err != nil {
+		return err
+	}
+	switch cmd.Type {
+	case "click":
+		return click()
+	case "press":
+		err = press()
+		if err == nil {
+			held.buttons[button] = true
+		}
+		return err
+	default:
+		delete(held.buttons, button)
+		return release()
+	}
+}
+
+// buttonActions returns the methods of the mouse that click, press and release the button with the given name.
+func (h *Handler) buttonActions(button string) (click, press, release func() error, err error) {
+	switch button {
+	case "left":
+		return h.Mouse.LeftClick, h.Mouse.LeftPress, h.Mouse.LeftRelease, nil
+	case "right":
+		return h.Mouse.RightClick, h.Mouse.RightPress, h.Mouse.RightRelease, nil
+	case "middle":
+		return h.Mouse.MiddleClick, h.Mouse.MiddlePress, h.Mouse.MiddleRelease, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown button %q", button)
+	}
+}
+
+func (h *Handler) executeKeyboard(cmd command, held heldControls) error {
+	switch cmd.Type {
+	case "type":
+		return h.Keyboard.Type(cmd.Text)
+	case "combo":
+		keys := make([]int, len(cmd.Keys))
+		for i, name := range cmd.Keys {
+			key, err := keyCode(name)
+			if err != nil {
+				return err
+			}
+			keys[i] = key
+		}
+		return h.Keyboard.Combo(keys...)
+	}
+
+	key, err := keyCode(cmd.Key)
+	if err != nil {
+		return err
+	}
+	switch cmd.Type {
+	case "keypress":
+		return h.Keyboard.KeyPress(key)
+	case "keydown":
+		err = h.Keyboard.KeyDown(key)
+		if err == nil {
+			held.keys[key] = true
+		}
+		return err
+	default:
+		delete(held.keys, key)
+		return h.Keyboard.KeyUp(key)
+	}
+}
+
+// keyCode looks up the code of the key with the given kernel name (e.g. KEY_A).
+func keyCode(name string) (int, error) {
+	code, ok := uinput.CodeFromName(name)
+	if !ok || !strings.HasPrefix(name, "KEY_") {
+		return 0, fmt.Errorf("unknown key %q", name)
+	}
+	return int(code), nil
+}
+
+// sameOrigin accepts requests without an Origin header (i.e. not from browsers) and requests from the same host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,191 @@
+package wsbridge
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bendahl/uinput"
+	"github.com/bendahl/uinput/uinputtest"
+)
+
+// testClient is a minimal websocket client, which masks its frames as required by the protocol.
+type testClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, server *httptest.Server, origin string) (*testClient, *http.Response) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	err = req.Write(conn)
+	if err != nil {
+		t.Fatalf("Failed to send handshake: %v", err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatalf("Failed to read handshake response: %v", err)
+	}
+	return &testClient{conn: conn, r: r}, resp
+}
+
+func (c *testClient) send(t *testing.T, opcode byte, payload []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatalf("Failed to send frame: %v", err)
+	}
+}
+
+func (c *testClient) receive(t *testing.T) (byte, []byte) {
+	c.conn.SetReadDeadline(time.Now().Add(time.Second))
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		t.Fatalf("Failed to receive frame: %v", err)
+	}
+	payload := make([]byte, header[1]&0x7f)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		t.Fatalf("Failed to receive payload: %v", err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+func TestHandshakeAcceptKey(t *testing.T) {
+	// the example of RFC 6455
+	if key := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); key != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Expected the accept key of the RFC example, got %s", key)
+	}
+}
+
+func TestCommandsAreExecuted(t *testing.T) {
+	mouse := uinputtest.NewMouse("test mouse")
+	kbd := uinputtest.NewKeyboard("test keyboard", nil)
+	server := httptest.NewServer(&Handler{Mouse: mouse, Keyboard: kbd})
+	defer server.Close()
+
+	client, resp := dial(t, server, "")
+	defer client.conn.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected the connection to be upgraded, got %s", resp.Status)
+	}
+	client.send(t, opText, []byte(`{"type": "move", "x": 10, "y": -5}`))
+	client.send(t, opText, []byte(`{"type": "keypress", "key": "KEY_A"}`))
+	client.send(t, opText, []byte(`{"id": 7, "type": "keypress", "key": "BTN_A"}`))
+
+	opcode, payload := client.receive(t)
+	var r reply
+	if opcode != opText || json.Unmarshal(payload, &r) != nil || string(r.ID) != "7" || r.Error == "" {
+		t.Fatalf("Expected an error reply for command 7, got %q", payload)
+	}
+
+	expectedMoves := [][]uinput.InputEvent{{
+		{Type: uinput.EvRel, Code: uinput.RelX, Value: 10},
+		{Type: uinput.EvRel, Code: uinput.RelY, Value: -5},
+	}}
+	if frames := mouse.Frames(); !reflect.DeepEqual(frames, expectedMoves) {
+		t.Fatalf("Expected frames %v, got %v", expectedMoves, frames)
+	}
+	if frames := kbd.Frames(); len(frames) != 2 {
+		t.Fatalf("Expected a key press and release, got %v", frames)
+	}
+
+	client.send(t, opPing, []byte("hi"))
+	if opcode, payload := client.receive(t); opcode != opPong || string(payload) != "hi" {
+		t.Fatalf("Expected the ping to be answered, got %#x %q", opcode, payload)
+	}
+	client.send(t, opClose, nil)
+	if opcode, _ := client.receive(t); opcode != opClose {
+		t.Fatalf("Expected the close to be answered, got %#x", opcode)
+	}
+}
+
+func TestHeldControlsAreReleasedOnDisconnect(t *testing.T) {
+	mouse := uinputtest.NewMouse("test mouse")
+	kbd := uinputtest.NewKeyboard("test keyboard", nil)
+	server := httptest.NewServer(&Handler{Mouse: mouse, Keyboard: kbd})
+	defer server.Close()
+
+	client, _ := dial(t, server, "")
+	client.send(t, opText, []byte(`{"type": "keydown", "key": "KEY_B"}`))
+	client.send(t, opText, []byte(`{"type": "keydown", "key": "KEY_A"}`))
+	client.send(t, opText, []byte(`{"type": "keyup", "key": "KEY_B"}`))
+	client.send(t, opText, []byte(`{"type": "press", "button": "right"}`))
+	client.send(t, opPing, nil)
+	client.receive(t)
+	if kbd.State().Keys[uinput.KeyA] != 1 || mouse.State().Keys[uinput.BtnRight] != 1 {
+		t.Fatalf("Expected the key and the button to be held, got %v and %v", kbd.State().Keys, mouse.State().Keys)
+	}
+	// the connection is dropped without a close frame
+	client.conn.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for (kbd.State().Keys[uinput.KeyA] != 0 || mouse.State().Keys[uinput.BtnRight] != 0) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if kbd.State().Keys[uinput.KeyA] != 0 || mouse.State().Keys[uinput.BtnRight] != 0 {
+		t.Fatalf("Expected the key and the button to be released, got %v and %v", kbd.State().Keys, mouse.State().Keys)
+	}
+	if frames := kbd.Frames(); len(frames) != 4 {
+		t.Fatalf("Expected the released key to be left alone, got %v", frames)
+	}
+}
+
+func TestMissingDeviceIsReported(t *testing.T) {
+	server := httptest.NewServer(&Handler{Mouse: uinputtest.NewMouse("test mouse")})
+	defer server.Close()
+
+	client, _ := dial(t, server, "")
+	defer client.conn.Close()
+	client.send(t, opText, []byte(`{"type": "type", "text": "hello"}`))
+	_, payload := client.receive(t)
+	if !strings.Contains(string(payload), "keyboard device not available") {
+		t.Fatalf("Expected the keyboard to be reported missing, got %q", payload)
+	}
+}
+
+func TestForeignOriginIsRejected(t *testing.T) {
+	server := httptest.NewServer(&Handler{Mouse: uinputtest.NewMouse("test mouse")})
+	defer server.Close()
+
+	client, resp := dial(t, server, "http://evil.example")
+	defer client.conn.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected a foreign origin to be rejected, got %s", resp.Status)
+	}
+}
+
+func TestUnmaskedFramesAreRejected(t *testing.T) {
+	server := httptest.NewServer(&Handler{Mouse: uinputtest.NewMouse("test mouse")})
+	defer server.Close()
+
+	client, _ := dial(t, server, "")
+	defer client.conn.Close()
+	client.conn.Write([]byte{0x80 | opText, 2, '{', '}'})
+	opcode, payload := client.receive(t)
+	if opcode != opClose || binary.BigEndian.Uint16(payload) != closeProtocol {
+		t.Fatalf("Expected a protocol error, got %#x %v", opcode, payload)
+	}
+}
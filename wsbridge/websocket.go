@@ -0,0 +1,238 @@
+package wsbridge
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// the GUID that is appended to the key of the client in order to compute the accept header, as defined in RFC 6455
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize is the maximum size of a message, which is plenty for a single command
+const maxMessageSize = 64 * 1024
+
+// the opcodes of websocket frames
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// the status codes of close frames
+const (
+	closeNormal      = 1000
+	closeProtocol    = 1002
+	closeUnsupported = 1003
+	closeTooBig      = 1009
+)
+
+var errConnClosed = errors.New("connection closed by peer")
+
+// a conn is the server side of a websocket connection. Only the parts of the protocol that the bridge needs are
+// implemented: text messages (possibly fragmented), pings and closing handshakes.
+type conn struct {
+	rw  *bufio.ReadWriter
+	raw net.Conn
+}
+
+// upgrade performs the opening handshake and takes over the connection of the request.
+func upgrade(w http.ResponseWriter, r *http.Request) (*conn, error) {
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing websocket key", http.StatusBadRequest)
+		return nil, errors.New("missing websocket key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket upgrade not supported", http.StatusInternalServerError)
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	raw, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take over the connection: %w", err)
+	}
+
+	_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		raw.Close()
+		return nil, fmt.Errorf("failed to complete the handshake: %w", err)
+	}
+	return &conn{rw: rw, raw: raw}, nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept header for the key of the client.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func headerContains(h http.Header, name string, token string) bool {
+	for _, value := range h[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readMessage reads the next text message. Pings are answered as they arrive. Once the client closes the connection,
+// the closing handshake is completed and errConnClosed is returned.
+func (c *conn) readMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			err = c.writeFrame(opPong, payload)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return nil, errConnClosed
+		case opBinary:
+			c.close(closeUnsupported)
+			return nil, errors.New("binary messages are not supported")
+		case opText:
+			if started {
+				c.close(closeProtocol)
+				return nil, errors.New("unexpected start of a message")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				c.close(closeProtocol)
+				return nil, errors.New("unexpected continuation frame")
+			}
+		default:
+			c.close(closeProtocol)
+			return nil, fmt.Errorf("unknown opcode %#x", opcode)
+		}
+
+		if len(message)+len(payload) > maxMessageSize {
+			c.close(closeTooBig)
+			return nil, fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame. Frames of clients are required to be masked.
+func (c *conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.rw, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+	if header[0]&0x70 != 0 || header[1]&0x80 == 0 {
+		c.close(closeProtocol)
+		return false, 0, nil, errors.New("invalid frame header")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		c.close(closeTooBig)
+		return false, 0, nil, fmt.Errorf("frame exceeds %d bytes", maxMessageSize)
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single unfragmented, unmasked frame.
+func (c *conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch {
+	case len(payload) < 126:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xffff:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// writeText writes a text message.
+func (c *conn) writeText(message []byte) error {
+	return c.writeFrame(opText, message)
+}
+
+// close sends a close frame with the given status code. The connection itself is closed by the handler.
+func (c *conn) close(status uint16) {
+	var payload [2]byte
+	binary.BigEndian.PutUint16(payload[:], status)
+	c.writeFrame(opClose, payload[:])
+}